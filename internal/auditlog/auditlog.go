@@ -0,0 +1,169 @@
+// Package auditlog keeps the town-wide, tamper-evident audit trail.
+//
+// Audit events (events.LogAudit, beads detach/burn/squash) are appended to
+// <town>/logs/audit.jsonl as a hash chain: each record carries a sequence
+// number, the hash of the previous record and its own hash over both plus
+// the entry. Editing, deleting or reordering a record breaks the chain.
+//
+// Truncating the tail leaves a valid shorter chain, so the head is
+// periodically anchored as a checkpoint committed (and, when a signing key
+// is configured, signed) in the town's git repo; see WriteCheckpoint.
+// Verify checks the chain and every checkpoint.
+package auditlog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// LogFile is the hash-chained audit log, relative to the town root.
+const LogFile = "logs/audit.jsonl"
+
+// GenesisHash is the Prev of the first record.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// maxRecordSize bounds a single record when reading the log's head.
+const maxRecordSize = 1024 * 1024
+
+// Entry is one audited action.
+type Entry struct {
+	Time    time.Time              `json:"ts"`
+	Source  string                 `json:"source"` // "events", "beads"
+	Type    string                 `json:"type"`
+	Actor   string                 `json:"actor,omitempty"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// Record is one line of the audit log. Entry is kept as raw JSON so hashes
+// are checked against the exact bytes written.
+type Record struct {
+	Seq   int64           `json:"seq"`
+	Prev  string          `json:"prev"`
+	Hash  string          `json:"hash"`
+	Entry json.RawMessage `json:"entry"`
+}
+
+// Head identifies the last record of the chain.
+type Head struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// LogPath returns the audit log for a town.
+func LogPath(townRoot string) string {
+	return filepath.Join(townRoot, LogFile)
+}
+
+// HashRecord computes a record's hash from its sequence number, the previous
+// record's hash and its entry bytes.
+func HashRecord(seq int64, prev string, entry []byte) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatInt(seq, 10)))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(prev))
+	h.Write([]byte{'\n'})
+	h.Write(entry)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Append adds an entry to the town's audit chain under a cross-process lock.
+// A zero Time is set to now.
+func Append(townRoot string, e Entry) error {
+	if townRoot == "" {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	entry, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling audit entry: %w", err)
+	}
+
+	path := LogPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating logs directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring audit log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	head, err := readHead(f)
+	if err != nil {
+		return err
+	}
+	rec := Record{Seq: head.Seq + 1, Prev: head.Hash, Entry: entry}
+	rec.Hash = HashRecord(rec.Seq, rec.Prev, rec.Entry)
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshaling audit record: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing audit record: %w", err)
+	}
+	return nil
+}
+
+// ReadHead returns the last record of the town's chain. An empty or missing
+// log has head {0, GenesisHash}.
+func ReadHead(townRoot string) (Head, error) {
+	f, err := os.Open(LogPath(townRoot))
+	if os.IsNotExist(err) {
+		return Head{Hash: GenesisHash}, nil
+	}
+	if err != nil {
+		return Head{}, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	return readHead(f)
+}
+
+// readHead parses the last line of f without reading the whole log.
+// A malformed last line is an error: chaining past it would hide the damage.
+func readHead(f *os.File) (Head, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return Head{}, fmt.Errorf("reading audit log: %w", err)
+	}
+	size := info.Size()
+	if size == 0 {
+		return Head{Hash: GenesisHash}, nil
+	}
+
+	n := min(size, maxRecordSize)
+	buf := make([]byte, n)
+	if _, err := f.ReadAt(buf, size-n); err != nil && !errors.Is(err, io.EOF) {
+		return Head{}, fmt.Errorf("reading audit log: %w", err)
+	}
+	buf = bytes.TrimRight(buf, "\n")
+	if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+		buf = buf[i+1:]
+	} else if n < size {
+		return Head{}, fmt.Errorf("audit log head exceeds %d bytes; run 'gt audit verify'", maxRecordSize)
+	}
+
+	var rec Record
+	if err := json.Unmarshal(buf, &rec); err != nil || rec.Seq <= 0 || rec.Hash == "" {
+		return Head{}, fmt.Errorf("audit log head is malformed; run 'gt audit verify'")
+	}
+	return Head{Seq: rec.Seq, Hash: rec.Hash}, nil
+}
@@ -0,0 +1,202 @@
+package auditlog
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func appendN(t *testing.T, townRoot string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := Append(townRoot, Entry{Source: "events", Type: "detach", Actor: "gastown/polecats/nux",
+			Payload: map[string]interface{}{"n": i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func problemKinds(r *Report) []string {
+	var kinds []string
+	for _, p := range r.Problems {
+		kinds = append(kinds, p.Kind)
+	}
+	return kinds
+}
+
+func TestAppendChainsRecords(t *testing.T) {
+	townRoot := t.TempDir()
+	appendN(t, townRoot, 3)
+
+	head, err := ReadHead(townRoot)
+	if err != nil || head.Seq != 3 {
+		t.Fatalf("head = %+v, %v", head, err)
+	}
+	report, err := Verify(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || report.Records != 3 || report.Head != head {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		want   string
+	}{
+		{"edit", func(l []string) []string {
+			l[1] = strings.Replace(l[1], "nux", "toast", 1)
+			return l
+		}, ProblemEdited},
+		{"delete", func(l []string) []string { return append(l[:1], l[2:]...) }, ProblemGap},
+		{"reorder", func(l []string) []string {
+			l[1], l[2] = l[2], l[1]
+			return l
+		}, ProblemGap},
+		{"garbage", func(l []string) []string { return append(l, "not json") }, ProblemMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			appendN(t, townRoot, 4)
+			data, _ := os.ReadFile(LogPath(townRoot))
+			lines := tt.tamper(strings.Split(strings.TrimSpace(string(data)), "\n"))
+			if err := os.WriteFile(LogPath(townRoot), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := Verify(townRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kinds := problemKinds(report); len(kinds) == 0 || kinds[0] != tt.want {
+				t.Errorf("problems = %+v, want %s first", report.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendRefusesMalformedHead(t *testing.T) {
+	townRoot := t.TempDir()
+	appendN(t, townRoot, 1)
+	f, _ := os.OpenFile(LogPath(townRoot), os.O_APPEND|os.O_WRONLY, 0600)
+	_, _ = f.WriteString("{truncated\n")
+	_ = f.Close()
+
+	if err := Append(townRoot, Entry{Type: "x"}); err == nil {
+		t.Error("expected Append to refuse to chain past a malformed head")
+	}
+}
+
+func initTownRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	townRoot := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	if _, err := runGit(townRoot, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestCheckpointDetectsTruncation(t *testing.T) {
+	townRoot := initTownRepo(t)
+	if _, err := WriteCheckpoint(townRoot, time.Now()); err != nil {
+		t.Fatalf("empty log: %v", err)
+	}
+
+	appendN(t, townRoot, 3)
+	res, err := WriteCheckpoint(townRoot, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil || res.Seq != 3 || res.Commit == "" || res.Signed {
+		t.Fatalf("checkpoint = %+v", res)
+	}
+	if again, err := WriteCheckpoint(townRoot, time.Now()); err != nil || again != nil {
+		t.Errorf("re-checkpoint of same head = %+v, %v", again, err)
+	}
+
+	report, err := Verify(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || !report.Committed || len(report.Checkpoints) != 1 || len(report.Commits) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Dropping the tail leaves a valid shorter chain; only the checkpoint catches it.
+	data, _ := os.ReadFile(LogPath(townRoot))
+	lines := strings.SplitAfter(string(data), "\n")
+	if err := os.WriteFile(LogPath(townRoot), []byte(strings.Join(lines[:2], "")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = Verify(townRoot)
+	if kinds := problemKinds(report); len(kinds) != 1 || kinds[0] != ProblemTruncated {
+		t.Errorf("problems = %+v", report.Problems)
+	}
+
+	// Rewriting the working copy of the checkpoints doesn't help.
+	if err := os.WriteFile(CheckpointsPath(townRoot), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = Verify(townRoot)
+	if kinds := problemKinds(report); len(kinds) != 2 || kinds[0] != ProblemCheckpointsChanged {
+		t.Errorf("problems = %+v", report.Problems)
+	}
+}
+
+func TestWriteCheckpointRestoresFileWhenCommitFails(t *testing.T) {
+	townRoot := initTownRepo(t)
+	appendN(t, townRoot, 2)
+	if _, err := WriteCheckpoint(townRoot, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	committed, _ := os.ReadFile(CheckpointsPath(townRoot))
+
+	// A signing key whose signer always fails makes the commit fail.
+	appendN(t, townRoot, 1)
+	for _, kv := range [][]string{{"user.signingkey", "bogus"}, {"gpg.program", "false"}} {
+		if _, err := runGit(townRoot, "config", kv[0], kv[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := WriteCheckpoint(townRoot, time.Now()); err == nil {
+		t.Fatal("expected the commit to fail")
+	}
+	if data, _ := os.ReadFile(CheckpointsPath(townRoot)); string(data) != string(committed) {
+		t.Errorf("checkpoints file after failed commit = %q, want %q", data, committed)
+	}
+	if status, _ := runGit(townRoot, "status", "--porcelain", "--", CheckpointsFile); status != "" {
+		t.Errorf("checkpoints file left changed: %q", status)
+	}
+
+	// Once signing works again the head is anchored.
+	if _, err := runGit(townRoot, "config", "--unset", "user.signingkey"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := WriteCheckpoint(townRoot, time.Now())
+	if err != nil || res == nil || res.Seq != 3 {
+		t.Fatalf("checkpoint after recovery = %+v, %v", res, err)
+	}
+	if report, _ := Verify(townRoot); !report.OK() || len(report.Checkpoints) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestWriteCheckpointRequiresGit(t *testing.T) {
+	if _, err := WriteCheckpoint(t.TempDir(), time.Now()); err != ErrNotGitRepo {
+		t.Errorf("err = %v, want ErrNotGitRepo", err)
+	}
+}
@@ -0,0 +1,181 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// CheckpointsFile records anchored chain heads, relative to the town root.
+// Unlike the log it is tracked in the town's git repo.
+const CheckpointsFile = "audit/checkpoints.jsonl"
+
+// ErrNotGitRepo is returned when the town root has no git repo to anchor
+// checkpoints in.
+var ErrNotGitRepo = errors.New("town root is not a git repository (run 'gt git-init')")
+
+// Checkpoint anchors the chain head at a point in time.
+type Checkpoint struct {
+	Seq  int64     `json:"seq"`
+	Hash string    `json:"hash"`
+	Time time.Time `json:"ts"`
+}
+
+// CheckpointResult describes a checkpoint written by WriteCheckpoint.
+type CheckpointResult struct {
+	Checkpoint
+	Commit string `json:"commit"`
+	Signed bool   `json:"signed"`
+}
+
+// CheckpointsPath returns the checkpoints file for a town.
+func CheckpointsPath(townRoot string) string {
+	return filepath.Join(townRoot, CheckpointsFile)
+}
+
+// WriteCheckpoint anchors the current chain head: it appends a checkpoint to
+// the committed CheckpointsFile and commits it to the town's git repo,
+// signing the commit when user.signingkey is configured (commit.gpgsign is
+// honoured either way). If the commit fails the file is restored. Returns nil
+// if the log is empty or the committed checkpoints already anchor the head.
+func WriteCheckpoint(townRoot string, now time.Time) (*CheckpointResult, error) {
+	if !isGitRepo(townRoot) {
+		return nil, ErrNotGitRepo
+	}
+
+	// Hold the log lock so concurrent checkpointers don't both append.
+	if err := os.MkdirAll(filepath.Dir(LogPath(townRoot)), 0755); err != nil {
+		return nil, fmt.Errorf("creating logs directory: %w", err)
+	}
+	fl := flock.New(LogPath(townRoot) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring audit log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	head, err := ReadHead(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if head.Seq == 0 {
+		return nil, nil
+	}
+	// Go by the committed checkpoints: the working copy is only the staging
+	// area for the next commit.
+	committed, showErr := runGit(townRoot, "show", "HEAD:./"+CheckpointsFile)
+	if showErr != nil {
+		committed = ""
+	}
+	existing, err := parseCheckpoints([]byte(committed))
+	if err != nil {
+		return nil, err
+	}
+	if n := len(existing); n > 0 && existing[n-1].Seq == head.Seq {
+		return nil, nil
+	}
+
+	cp := Checkpoint{Seq: head.Seq, Hash: head.Hash, Time: now.UTC()}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling checkpoint: %w", err)
+	}
+	path := CheckpointsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating audit directory: %w", err)
+	}
+	previous, readErr := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	content := append([]byte(committed), '\n')
+	if committed == "" {
+		content = nil
+	}
+	content = append(append(content, data...), '\n')
+	if err := os.WriteFile(path, content, 0644); err != nil { //nolint:gosec // G306: checkpoints are committed to git
+		return nil, fmt.Errorf("writing checkpoint: %w", err)
+	}
+
+	// Put the file back as it was if the checkpoint doesn't get committed,
+	// so it doesn't linger as an uncommitted change.
+	restore := func() {
+		if readErr == nil {
+			_ = os.WriteFile(path, previous, 0644) //nolint:gosec // G306: checkpoints are committed to git
+		} else {
+			_ = os.Remove(path)
+		}
+		if showErr == nil {
+			_, _ = runGit(townRoot, "reset", "-q", "--", CheckpointsFile)
+		} else {
+			_, _ = runGit(townRoot, "rm", "-q", "--cached", "--ignore-unmatch", "--", CheckpointsFile)
+		}
+	}
+	if _, err := runGit(townRoot, "add", "--", CheckpointsFile); err != nil {
+		restore()
+		return nil, fmt.Errorf("staging checkpoint: %w", err)
+	}
+	args := []string{"commit", "--no-verify", "-m", fmt.Sprintf("audit: checkpoint seq %d", cp.Seq)}
+	if key, _ := runGit(townRoot, "config", "--get", "user.signingkey"); key != "" {
+		args = append(args, "-S")
+	}
+	if _, err := runGit(townRoot, append(args, "--", CheckpointsFile)...); err != nil {
+		restore()
+		return nil, fmt.Errorf("committing checkpoint: %w", err)
+	}
+
+	res := &CheckpointResult{Checkpoint: cp}
+	res.Commit, _ = runGit(townRoot, "rev-parse", "HEAD")
+	sig, _ := runGit(townRoot, "log", "-1", "--format=%G?", "HEAD")
+	res.Signed = sig != "" && sig != "N"
+	return res, nil
+}
+
+// parseCheckpoints decodes a checkpoints file.
+func parseCheckpoints(data []byte) ([]Checkpoint, error) {
+	var cps []Checkpoint
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var cp Checkpoint
+		if err := json.Unmarshal(text, &cp); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", CheckpointsFile, line, err)
+		}
+		cps = append(cps, cp)
+	}
+	return cps, scanner.Err()
+}
+
+func readFileIfExists(path string) []byte {
+	data, _ := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	return data
+}
+
+func isGitRepo(dir string) bool {
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// runGit runs git in dir and returns its trimmed stdout.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
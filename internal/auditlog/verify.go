@@ -0,0 +1,188 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Problem kinds reported by Verify.
+const (
+	ProblemMalformed          = "malformed"           // line is not a valid record
+	ProblemGap                = "gap"                 // sequence numbers skip or repeat
+	ProblemBrokenLink         = "broken_link"         // prev does not match the previous record's hash
+	ProblemEdited             = "edited"              // hash does not match the record's contents
+	ProblemTruncated          = "truncated"           // a checkpoint is past the end of the log
+	ProblemCheckpointMismatch = "checkpoint_mismatch" // the record at a checkpoint has a different hash
+	ProblemCheckpointsChanged = "checkpoints_changed" // checkpoints file differs from the committed one
+	ProblemBadSignature       = "bad_signature"       // a checkpoint commit's signature does not verify
+)
+
+// Problem is one integrity failure found by Verify.
+type Problem struct {
+	Kind   string `json:"kind"`
+	Seq    int64  `json:"seq,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// CheckpointCommit is a commit that changed the checkpoints file, with its
+// git signature status (%G?: G good, U good but untrusted key, N none,
+// B bad, and so on).
+type CheckpointCommit struct {
+	Commit    string `json:"commit"`
+	Signature string `json:"signature"`
+}
+
+// Signed reports whether the commit carries a valid signature.
+func (c CheckpointCommit) Signed() bool {
+	return c.Signature == "G" || c.Signature == "U"
+}
+
+// Report is the result of Verify.
+type Report struct {
+	Head        Head               `json:"head"`
+	Records     int                `json:"records"`
+	Checkpoints []Checkpoint       `json:"checkpoints"`
+	Commits     []CheckpointCommit `json:"commits,omitempty"`
+	Committed   bool               `json:"committed"` // checkpoints were read from git HEAD
+	Problems    []Problem          `json:"problems,omitempty"`
+}
+
+// OK reports whether no problems were found.
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *Report) add(kind string, seq int64, line int, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, Problem{Kind: kind, Seq: seq, Line: line, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Verify walks the town's audit chain, checking every record's sequence
+// number, link and hash, then checks each checkpoint against the chain.
+// Checkpoints are read from the town repo's HEAD when the file is committed,
+// so an uncommitted rewrite of the working copy can't vouch for an edit.
+func Verify(townRoot string) (*Report, error) {
+	report := &Report{Head: Head{Hash: GenesisHash}}
+	checkpoints, err := loadCheckpoints(townRoot, report)
+	if err != nil {
+		return nil, err
+	}
+	report.Checkpoints = checkpoints
+	anchored := make(map[int64]string, len(checkpoints))
+	for _, cp := range checkpoints {
+		anchored[cp.Seq] = ""
+	}
+
+	f, err := os.Open(LogPath(townRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	if f != nil {
+		defer f.Close()
+		if err := verifyChain(f, report, anchored); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cp := range checkpoints {
+		switch hash := anchored[cp.Seq]; {
+		case cp.Seq > report.Head.Seq:
+			report.add(ProblemTruncated, cp.Seq, 0, "checkpoint at seq %d (%s) is past the end of the log (seq %d)",
+				cp.Seq, cp.Time.Format("2006-01-02 15:04:05"), report.Head.Seq)
+		case hash == "":
+			report.add(ProblemCheckpointMismatch, cp.Seq, 0, "record %d anchored by a checkpoint is missing", cp.Seq)
+		case hash != cp.Hash:
+			report.add(ProblemCheckpointMismatch, cp.Seq, 0, "record %d hash %s does not match checkpoint %s",
+				cp.Seq, short(hash), short(cp.Hash))
+		}
+	}
+	return report, nil
+}
+
+// verifyChain checks each record in r, recording the hashes of anchored
+// sequence numbers.
+func verifyChain(r io.Reader, report *Report, anchored map[int64]string) error {
+	reader := bufio.NewReader(r)
+	expectSeq, prev := int64(1), GenesisHash
+	resync := false
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			var rec Record
+			if jsonErr := json.Unmarshal(data, &rec); jsonErr != nil || rec.Seq <= 0 || rec.Hash == "" {
+				report.add(ProblemMalformed, 0, line, "line %d is not a valid audit record", line)
+				resync = true
+			} else {
+				report.Records++
+				switch {
+				case resync:
+					// The link across a malformed line can't be checked.
+				case rec.Seq != expectSeq:
+					report.add(ProblemGap, rec.Seq, line, "expected seq %d, found %d", expectSeq, rec.Seq)
+				case rec.Prev != prev:
+					report.add(ProblemBrokenLink, rec.Seq, line, "prev %s does not match hash of record %d (%s)",
+						short(rec.Prev), rec.Seq-1, short(prev))
+				}
+				if got := HashRecord(rec.Seq, rec.Prev, rec.Entry); got != rec.Hash {
+					report.add(ProblemEdited, rec.Seq, line, "record %d contents do not match its hash", rec.Seq)
+				}
+				if _, ok := anchored[rec.Seq]; ok {
+					anchored[rec.Seq] = rec.Hash
+				}
+				expectSeq, prev, resync = rec.Seq+1, rec.Hash, false
+				report.Head = Head{Seq: rec.Seq, Hash: rec.Hash}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading audit log: %w", err)
+		}
+	}
+}
+
+// loadCheckpoints reads checkpoints from the town repo's HEAD, falling back
+// to the working copy when the file isn't committed, and records the
+// signature status of the commits that changed it.
+func loadCheckpoints(townRoot string, report *Report) ([]Checkpoint, error) {
+	working := readFileIfExists(CheckpointsPath(townRoot))
+	if !isGitRepo(townRoot) {
+		return parseCheckpoints(working)
+	}
+	committed, err := runGit(townRoot, "show", "HEAD:./"+CheckpointsFile)
+	if err != nil {
+		return parseCheckpoints(working)
+	}
+	report.Committed = true
+	if strings.TrimSpace(string(working)) != committed {
+		report.add(ProblemCheckpointsChanged, 0, 0, "%s differs from the committed version", CheckpointsFile)
+	}
+
+	out, _ := runGit(townRoot, "log", "--format=%H %G?", "--", CheckpointsFile)
+	for _, line := range strings.Split(out, "\n") {
+		commit, sig, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		c := CheckpointCommit{Commit: commit, Signature: sig}
+		report.Commits = append(report.Commits, c)
+		if sig == "B" {
+			report.add(ProblemBadSignature, 0, 0, "checkpoint commit %s has a bad signature", short(commit))
+		}
+	}
+	return parseCheckpoints([]byte(committed))
+}
+
+func short(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
 )
 
 // DetachAuditEntry represents an audit log entry for a detach operation.
@@ -83,6 +86,7 @@ func (b *Beads) DetachMoleculeWithAudit(pinnedBeadID string, opts DetachOptions)
 // LogDetachAudit appends an audit entry to the audit log file.
 // The audit log is stored in the resolved .beads directory as audit.log in JSONL format.
 // This follows any beads redirect so audit entries go to the correct location.
+// The entry is also appended to the town's hash-chained audit log.
 func (b *Beads) LogDetachAudit(entry DetachAuditEntry) error {
 	auditPath := filepath.Join(b.getResolvedBeadsDir(), "audit.log")
 
@@ -103,5 +107,20 @@ func (b *Beads) LogDetachAudit(entry DetachAuditEntry) error {
 		return fmt.Errorf("writing audit entry: %w", err)
 	}
 
+	var payload map[string]interface{}
+	_ = json.Unmarshal(data, &payload)
+	delete(payload, "timestamp")
+	delete(payload, "operation")
+	ts, _ := time.Parse(time.RFC3339, entry.Timestamp)
+	if err := auditlog.Append(b.getTownRoot(), auditlog.Entry{
+		Time:    ts,
+		Source:  "beads",
+		Type:    entry.Operation,
+		Actor:   entry.DetachedBy,
+		Payload: payload,
+	}); err != nil {
+		return fmt.Errorf("appending to audit chain: %w", err)
+	}
+
 	return nil
 }
@@ -590,13 +590,21 @@ author: someone`,
 // TestMRFieldsRoundTrip tests that parse/format round-trips correctly.
 func TestMRFieldsRoundTrip(t *testing.T) {
 	original := &MRFields{
-		Branch:      "polecat/Nux/gt-xyz",
-		Target:      "main",
-		SourceIssue: "gt-xyz",
-		Worker:      "Nux",
-		Rig:         "gastown",
-		MergeCommit: "abc123def789",
-		CloseReason: "merged",
+		Branch:        "polecat/Nux/gt-xyz",
+		Target:        "main",
+		SourceIssue:   "gt-xyz",
+		Worker:        "Nux",
+		Rig:           "gastown",
+		MergeCommit:   "abc123def789",
+		CloseReason:   "merged",
+		GateResults:   "build=pass test=fail(2)",
+		FailingTests:  "test: pkg.TestFoo; test: pkg.TestBar",
+		ParentMR:      "gt-mr1",
+		StackBase:     "0123abcd",
+		ForgePR:       42,
+		ForgeURL:      "https://github.com/acme/widgets/pull/42",
+		ForgeSyncedAt: "2026-01-02T03:04:05Z",
+		Reverts:       "fedcba9876543210",
 	}
 
 	// Format to string
@@ -26,6 +26,8 @@ type AttachmentFields struct {
 	Mode             string // Execution mode: "" (normal) or "ralph" (Ralph Wiggum loop)
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
+	Affinity         string // Why this polecat was picked (e.g., "toast score=5 dirs=internal/git")
+	TraceID          string // Trace minted by gt sling, correlating spans across stages
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -82,6 +84,12 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "merge_strategy", "merge-strategy", "mergestrategy":
 			fields.MergeStrategy = value
 			hasFields = true
+		case "affinity":
+			fields.Affinity = value
+			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
 		}
 	}
 
@@ -124,6 +132,12 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.MergeStrategy != "" {
 		lines = append(lines, "merge_strategy: "+fields.MergeStrategy)
 	}
+	if fields.Affinity != "" {
+		lines = append(lines, "affinity: "+fields.Affinity)
+	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -157,6 +171,10 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"merge_strategy":    true,
 		"merge-strategy":    true,
 		"mergestrategy":     true,
+		"affinity":          true,
+		"trace_id":          true,
+		"trace-id":          true,
+		"traceid":           true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -227,6 +245,26 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Quality gate outcome of the last merge attempt
+	GateResults  string // Per-gate summary (e.g., "build=pass test=fail(2)")
+	FailingTests string // Failing tests as "<gate>: <test>", "; "-separated
+
+	// Stacked MRs: this MR's branch was built on top of another MR's branch
+	ParentMR  string // MR this one is stacked on; merges only after it
+	StackBase string // Parent branch SHA this branch was last (re)based on
+	StackHeld string // Why the MR stays stacked after its parent closed unmerged
+
+	// Forge mirror: the pull request opened for this MR on the rig's forge
+	ForgePR       int    // Pull request number
+	ForgeURL      string // Pull request web URL
+	ForgeSyncedAt string // Last comment relay (RFC 3339); newer comments are mailed
+
+	// Reverts is the target-branch commit this MR reverts (post-merge verification)
+	Reverts string
+
+	// TraceID is the source issue's trace, so the merge is recorded on it
+	TraceID string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -301,6 +339,38 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "gate_results", "gate-results", "gateresults":
+			fields.GateResults = value
+			hasFields = true
+		case "failing_tests", "failing-tests", "failingtests":
+			fields.FailingTests = value
+			hasFields = true
+		case "parent_mr", "parent-mr", "parentmr":
+			fields.ParentMR = value
+			hasFields = true
+		case "stack_base", "stack-base", "stackbase":
+			fields.StackBase = value
+			hasFields = true
+		case "stack_held", "stack-held", "stackheld":
+			fields.StackHeld = value
+			hasFields = true
+		case "forge_pr", "forge-pr", "forgepr":
+			if n, err := parseIntField(value); err == nil {
+				fields.ForgePR = n
+				hasFields = true
+			}
+		case "forge_url", "forge-url", "forgeurl":
+			fields.ForgeURL = value
+			hasFields = true
+		case "forge_synced_at", "forge-synced-at", "forgesyncedat":
+			fields.ForgeSyncedAt = value
+			hasFields = true
+		case "reverts":
+			fields.Reverts = value
+			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
 		}
 	}
 
@@ -365,6 +435,36 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.GateResults != "" {
+		lines = append(lines, "gate_results: "+fields.GateResults)
+	}
+	if fields.FailingTests != "" {
+		lines = append(lines, "failing_tests: "+fields.FailingTests)
+	}
+	if fields.ParentMR != "" {
+		lines = append(lines, "parent_mr: "+fields.ParentMR)
+	}
+	if fields.StackBase != "" {
+		lines = append(lines, "stack_base: "+fields.StackBase)
+	}
+	if fields.StackHeld != "" {
+		lines = append(lines, "stack_held: "+fields.StackHeld)
+	}
+	if fields.ForgePR > 0 {
+		lines = append(lines, fmt.Sprintf("forge_pr: %d", fields.ForgePR))
+	}
+	if fields.ForgeURL != "" {
+		lines = append(lines, "forge_url: "+fields.ForgeURL)
+	}
+	if fields.ForgeSyncedAt != "" {
+		lines = append(lines, "forge_synced_at: "+fields.ForgeSyncedAt)
+	}
+	if fields.Reverts != "" {
+		lines = append(lines, "reverts: "+fields.Reverts)
+	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -411,6 +511,34 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"gate_results":       true,
+		"gate-results":       true,
+		"gateresults":        true,
+		"failing_tests":      true,
+		"failing-tests":      true,
+		"failingtests":       true,
+		"parent_mr":          true,
+		"parent-mr":          true,
+		"parentmr":           true,
+		"stack_base":         true,
+		"stack-base":         true,
+		"stackbase":          true,
+		"stack_held":         true,
+		"stack-held":         true,
+		"stackheld":          true,
+		"forge_pr":           true,
+		"forge-pr":           true,
+		"forgepr":            true,
+		"forge_url":          true,
+		"forge-url":          true,
+		"forgeurl":           true,
+		"forge_synced_at":    true,
+		"forge-synced-at":    true,
+		"forgesyncedat":      true,
+		"reverts":            true,
+		"trace_id":           true,
+		"trace-id":           true,
+		"traceid":            true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -267,3 +267,33 @@ func TestParseAgentFieldsFromDescription(t *testing.T) {
 		t.Errorf("NotificationLevel = %q, want %q", got.NotificationLevel, "verbose")
 	}
 }
+
+func TestAffinityFieldRoundTrip(t *testing.T) {
+	original := &AttachmentFields{
+		DispatchedBy: "mayor/",
+		Affinity:     "toast score=5 dirs=internal/git labels=area:git",
+	}
+	parsed := ParseAttachmentFields(&Issue{Description: FormatAttachmentFields(original)})
+	if parsed == nil || parsed.Affinity != original.Affinity {
+		t.Fatalf("Affinity round-trip = %+v, want %q", parsed, original.Affinity)
+	}
+
+	// Re-slinging replaces the affinity line rather than duplicating it
+	desc := SetAttachmentFields(&Issue{Description: "affinity: nux score=2\nFix the thing"}, original)
+	if strings.Count(desc, "affinity:") != 1 || !strings.Contains(desc, "Fix the thing") {
+		t.Errorf("SetAttachmentFields = %q", desc)
+	}
+}
+
+func TestTraceIDFieldRoundTrip(t *testing.T) {
+	original := &AttachmentFields{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"}
+	parsed := ParseAttachmentFields(&Issue{Description: FormatAttachmentFields(original)})
+	if parsed == nil || parsed.TraceID != original.TraceID {
+		t.Fatalf("TraceID round-trip = %+v, want %q", parsed, original.TraceID)
+	}
+
+	mr := ParseMRFields(&Issue{Description: "branch: polecat/nux\ntrace_id: " + original.TraceID})
+	if mr == nil || mr.TraceID != original.TraceID {
+		t.Errorf("MR TraceID = %+v, want %q", mr, original.TraceID)
+	}
+}
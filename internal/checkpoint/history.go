@@ -0,0 +1,219 @@
+package checkpoint
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
+)
+
+// HistoryFilename is the append-only checkpoint history within the polecat's
+// .runtime directory, which worktrees gitignore, so gt done can't commit it.
+const HistoryFilename = "checkpoints.jsonl"
+
+// RefPrefix is the git ref namespace holding uncommitted work captured by
+// checkpoints, so the stash commits survive garbage collection. Refs under
+// refs/worktree/ are private to each worktree, so polecats sharing the rig's
+// repo never overwrite or clear each other's checkpoints.
+const RefPrefix = "refs/worktree/gt/checkpoints/"
+
+// Reasons a checkpoint was taken.
+const (
+	ReasonManual     = "manual"
+	ReasonStep       = "step"
+	ReasonHandoff    = "handoff"
+	ReasonPreRestore = "pre-restore"
+)
+
+// Entry is a single checkpoint in the append-only history.
+type Entry struct {
+	Checkpoint
+
+	// Seq is the 1-based position of the entry in the history.
+	Seq int `json:"seq"`
+
+	// Reason records what triggered the checkpoint (step, handoff, manual).
+	Reason string `json:"reason"`
+
+	// DiffStat is the `git diff --shortstat HEAD` summary at capture time.
+	DiffStat string `json:"diff_stat,omitempty"`
+
+	// StashCommit holds uncommitted tracked changes (from `git stash create`),
+	// pinned under RefPrefix. Empty when the worktree was clean.
+	StashCommit string `json:"stash_commit,omitempty"`
+}
+
+// HistoryPath returns the checkpoint history path for a given polecat directory.
+func HistoryPath(polecatDir string) string {
+	return filepath.Join(polecatDir, constants.DirRuntime, HistoryFilename)
+}
+
+// ReadHistory loads all checkpoint entries, oldest first.
+// Returns nil, nil if no history exists.
+func ReadHistory(polecatDir string) ([]Entry, error) {
+	f, err := os.Open(HistoryPath(polecatDir)) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening checkpoint history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			// Skip corrupt lines rather than losing the whole history
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading checkpoint history: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns the history entry with the given sequence number.
+func Get(polecatDir string, seq int) (*Entry, error) {
+	entries, err := ReadHistory(polecatDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].Seq == seq {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("checkpoint %d not found", seq)
+}
+
+// Record captures git state into cp and appends it to the history.
+// The checkpoint's git fields are expected to come from Capture; Record adds
+// the diff stat and pins uncommitted changes so Restore can bring them back.
+func Record(polecatDir string, cp *Checkpoint, reason string) (*Entry, error) {
+	if cp.Timestamp.IsZero() {
+		cp.Timestamp = time.Now()
+	}
+	if cp.SessionID == "" {
+		cp.SessionID = runtime.SessionIDFromEnv()
+		if cp.SessionID == "" {
+			cp.SessionID = fmt.Sprintf("pid-%d", os.Getpid())
+		}
+	}
+
+	entry := &Entry{
+		Checkpoint: *cp,
+		Reason:     reason,
+		DiffStat:   gitOutput(polecatDir, "diff", "--shortstat", "HEAD"),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(HistoryPath(polecatDir)), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime dir: %w", err)
+	}
+
+	// Serialize appenders: step boundaries and handoffs can race.
+	fl := flock.New(HistoryPath(polecatDir) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring checkpoint history lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	entries, err := ReadHistory(polecatDir)
+	if err != nil {
+		return nil, err
+	}
+	entry.Seq = 1
+	if len(entries) > 0 {
+		entry.Seq = entries[len(entries)-1].Seq + 1
+	}
+
+	// git stash create records tracked changes without touching the worktree
+	if stash := gitOutput(polecatDir, "stash", "create", fmt.Sprintf("gt checkpoint %d", entry.Seq)); stash != "" {
+		ref := fmt.Sprintf("%s%d", RefPrefix, entry.Seq)
+		if err := exec.Command("git", "-C", polecatDir, "update-ref", ref, stash).Run(); err == nil { //nolint:gosec // G204: args are constructed internally
+			entry.StashCommit = stash
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling checkpoint: %w", err)
+	}
+	data = append(data, '\n')
+
+	f, err := os.OpenFile(HistoryPath(polecatDir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed from trusted polecatDir
+	if err != nil {
+		return nil, fmt.Errorf("opening checkpoint history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return nil, fmt.Errorf("writing checkpoint history: %w", err)
+	}
+
+	return entry, nil
+}
+
+// RestoreWorktree resets the worktree to the entry's commit and reapplies
+// the uncommitted changes captured with it. Untracked files are left alone.
+func RestoreWorktree(polecatDir string, e *Entry) error {
+	if e.LastCommit == "" {
+		return fmt.Errorf("checkpoint %d has no commit to restore", e.Seq)
+	}
+
+	if out, err := exec.Command("git", "-C", polecatDir, "reset", "--hard", e.LastCommit).CombinedOutput(); err != nil { //nolint:gosec // G204: commit comes from our own history file
+		return fmt.Errorf("git reset --hard %s: %s", e.LastCommit, strings.TrimSpace(string(out)))
+	}
+
+	if e.StashCommit != "" {
+		if out, err := exec.Command("git", "-C", polecatDir, "stash", "apply", e.StashCommit).CombinedOutput(); err != nil { //nolint:gosec // G204: commit comes from our own history file
+			return fmt.Errorf("reapplying uncommitted changes from %s: %s", e.StashCommit, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return nil
+}
+
+// ClearHistory removes the checkpoint history and its pinned git refs.
+func ClearHistory(polecatDir string) error {
+	entries, err := ReadHistory(polecatDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.StashCommit != "" {
+			_ = exec.Command("git", "-C", polecatDir, "update-ref", "-d", fmt.Sprintf("%s%d", RefPrefix, e.Seq)).Run() //nolint:gosec // G204: args are constructed internally
+		}
+	}
+	if err := os.Remove(HistoryPath(polecatDir)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing checkpoint history: %w", err)
+	}
+	return nil
+}
+
+// gitOutput runs a git command in dir and returns trimmed stdout, or "" on error.
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
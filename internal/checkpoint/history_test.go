@@ -0,0 +1,222 @@
+package checkpoint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initHistoryRepo creates a git repo with one committed file.
+func initHistoryRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "config", "user.name", "Test")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	runGit(t, dir, "add", "main.go")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadHistoryEmpty(t *testing.T) {
+	entries, err := ReadHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("ReadHistory: %v", err)
+	}
+	if entries != nil {
+		t.Errorf("expected nil history, got %v", entries)
+	}
+}
+
+func TestRecordKeepsHistoryOutOfCommits(t *testing.T) {
+	dir := initHistoryRepo(t)
+	// Polecat worktrees ignore .runtime/ (rig.EnsureGitignorePatterns)
+	writeFile(t, filepath.Join(dir, ".gitignore"), ".runtime/\n")
+	runGit(t, dir, "add", ".gitignore")
+	runGit(t, dir, "commit", "-q", "-m", "ignore runtime")
+
+	cp, err := Capture(dir)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if _, err := Record(dir, cp, ReasonStep); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if status := runGit(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("checkpoint history must not show up for git add -A, status:\n%s", status)
+	}
+}
+
+func TestRecordAppends(t *testing.T) {
+	dir := initHistoryRepo(t)
+
+	for i, reason := range []string{ReasonStep, ReasonHandoff, ReasonManual} {
+		cp, err := Capture(dir)
+		if err != nil {
+			t.Fatalf("Capture: %v", err)
+		}
+		cp.WithHookedBead("gt-abc")
+		entry, err := Record(dir, cp, reason)
+		if err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		if entry.Seq != i+1 {
+			t.Errorf("entry %d: Seq = %d, want %d", i, entry.Seq, i+1)
+		}
+	}
+
+	entries, err := ReadHistory(dir)
+	if err != nil {
+		t.Fatalf("ReadHistory: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[1].Reason != ReasonHandoff {
+		t.Errorf("entries[1].Reason = %q, want %q", entries[1].Reason, ReasonHandoff)
+	}
+	if entries[0].LastCommit == "" || entries[0].HookedBead != "gt-abc" {
+		t.Errorf("entry missing captured state: %+v", entries[0])
+	}
+}
+
+func TestRecordCapturesUncommittedWork(t *testing.T) {
+	dir := initHistoryRepo(t)
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+
+	cp, err := Capture(dir)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	entry, err := Record(dir, cp, ReasonStep)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	if !strings.Contains(entry.DiffStat, "1 file changed") {
+		t.Errorf("DiffStat = %q, want 1 file changed", entry.DiffStat)
+	}
+	if entry.StashCommit == "" {
+		t.Fatal("expected StashCommit for dirty worktree")
+	}
+	if ref := runGit(t, dir, "rev-parse", RefPrefix+"1"); ref != entry.StashCommit {
+		t.Errorf("pinned ref = %s, want %s", ref, entry.StashCommit)
+	}
+
+	// Recording must not disturb the worktree
+	data, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(data), "func main") {
+		t.Error("Record modified the worktree")
+	}
+}
+
+func TestRestoreWorktree(t *testing.T) {
+	dir := initHistoryRepo(t)
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\n// step one\n")
+
+	cp, err := Capture(dir)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	entry, err := Record(dir, cp, ReasonStep)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// Derail: commit something else and dirty the tree
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\n// derailed\n")
+	runGit(t, dir, "commit", "-q", "-am", "derailed")
+	writeFile(t, filepath.Join(dir, "main.go"), "garbage\n")
+
+	got, err := Get(dir, entry.Seq)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := RestoreWorktree(dir, got); err != nil {
+		t.Fatalf("RestoreWorktree: %v", err)
+	}
+
+	if head := runGit(t, dir, "rev-parse", "HEAD"); head != entry.LastCommit {
+		t.Errorf("HEAD = %s, want %s", head, entry.LastCommit)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(data), "step one") {
+		t.Errorf("uncommitted work not restored, got %q", data)
+	}
+}
+
+func TestGetMissing(t *testing.T) {
+	dir := initHistoryRepo(t)
+	if _, err := Get(dir, 7); err == nil {
+		t.Error("expected error for missing checkpoint")
+	}
+}
+
+func TestClearHistory(t *testing.T) {
+	dir := initHistoryRepo(t)
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n// dirty\n")
+
+	cp, _ := Capture(dir)
+	if _, err := Record(dir, cp, ReasonManual); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	if err := ClearHistory(dir); err != nil {
+		t.Fatalf("ClearHistory: %v", err)
+	}
+	if _, err := os.Stat(HistoryPath(dir)); !os.IsNotExist(err) {
+		t.Error("history file should be removed")
+	}
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "-q", RefPrefix+"1").Run(); err == nil {
+		t.Error("pinned checkpoint ref should be deleted")
+	}
+}
+
+func TestCheckpointRefsArePerWorktree(t *testing.T) {
+	dir := initHistoryRepo(t)
+	other := filepath.Join(t.TempDir(), "other")
+	runGit(t, dir, "worktree", "add", "-q", "--detach", other)
+
+	var stashes []string
+	for _, wt := range []string{dir, other} {
+		writeFile(t, filepath.Join(wt, "main.go"), "package main\n// "+filepath.Base(wt)+"\n")
+		cp, _ := Capture(wt)
+		entry, err := Record(wt, cp, ReasonStep)
+		if err != nil {
+			t.Fatalf("Record(%s): %v", wt, err)
+		}
+		stashes = append(stashes, entry.StashCommit)
+	}
+
+	// Both worktrees are at seq 1; neither may clobber the other's ref
+	if ref := runGit(t, dir, "rev-parse", RefPrefix+"1"); ref != stashes[0] {
+		t.Errorf("first worktree ref = %s, want %s", ref, stashes[0])
+	}
+	if err := ClearHistory(other); err != nil {
+		t.Fatalf("ClearHistory: %v", err)
+	}
+	if ref := runGit(t, dir, "rev-parse", RefPrefix+"1"); ref != stashes[0] {
+		t.Errorf("clearing another worktree's history removed this one's ref")
+	}
+}
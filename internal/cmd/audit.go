@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/krc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -38,7 +39,10 @@ Shows a unified timeline of work performed by an actor including:
   - Beads (issues) created by the actor
   - Beads closed by the actor (via assignee)
   - Town log events (spawn, done, handoff, etc.)
-  - Activity feed events
+  - Activity feed events (with --since, including events KRC has archived)
+
+Audit events are also kept in a tamper-evident hash chain; see
+'gt audit verify' and 'gt audit checkpoint'.
 
 Examples:
   gt audit --actor=greenplace/crew/joe       # Show all work by joe
@@ -399,30 +403,20 @@ func formatTownlogSummary(e townlog.Event) string {
 	}
 }
 
-// collectFeedEvents queries the activity feed for events.
+// collectFeedEvents queries the activity feed for events. With a since
+// cutoff, events KRC has moved to the archive are included.
 func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
 	var entries []AuditEntry
 
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No events file yet
-		}
-		return nil, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
+	err := krc.ScanEvents(townRoot, events.EventsFile, since, func(line []byte) error {
 		var e events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
-			continue // Skip malformed lines
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil // Skip malformed lines
 		}
 
 		// Apply actor filter
 		if actor != "" && !matchesActor(e.Actor, actor) {
-			continue
+			return nil
 		}
 
 		// Parse timestamp
@@ -430,7 +424,7 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 
 		// Apply since filter
 		if !since.IsZero() && ts.Before(since) {
-			continue
+			return nil
 		}
 
 		entries = append(entries, AuditEntry{
@@ -440,6 +434,10 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 			Actor:     e.Actor,
 			Summary:   formatFeedSummary(e),
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return entries, nil
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	auditVerifyJSON          bool
+	auditVerifyRequireSigned bool
+	auditCheckpointJSON      bool
+)
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the audit trail for gaps and edits",
+	Long: `Verify the town's hash-chained audit log (logs/audit.jsonl).
+
+Audit events (gt events with audit visibility, molecule detach/burn/squash)
+are chained: each record includes the hash of the one before it. verify
+recomputes every hash and reports records that were edited, deleted,
+reordered or corrupted.
+
+Deleting the newest records leaves a valid, shorter chain, so the head is
+anchored as checkpoints committed to the town git repo (audit/checkpoints.jsonl).
+verify reads the committed checkpoints and reports any the log no longer
+matches, plus checkpoint commits whose signature fails to verify.
+
+Exits non-zero if any problem is found.
+
+Examples:
+  gt audit verify
+  gt audit verify --require-signed
+  gt audit verify --json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditVerify,
+}
+
+var auditCheckpointCmd = &cobra.Command{
+	Use:   "checkpoint",
+	Short: "Anchor the audit trail's head in the town git repo",
+	Long: `Append the audit chain's current head to audit/checkpoints.jsonl and
+commit it to the town git repo. The commit is signed when git has a
+user.signingkey configured (or commit.gpgsign is set).
+
+The daemon writes checkpoints hourly when the audit_checkpoints patrol
+is enabled in mayor/daemon.json:
+
+  "patrols": {"audit_checkpoints": {"enabled": true}}
+
+Does nothing if the head is already anchored.`,
+	Args: cobra.NoArgs,
+	RunE: runAuditCheckpoint,
+}
+
+func init() {
+	auditVerifyCmd.Flags().BoolVar(&auditVerifyJSON, "json", false, "Output as JSON")
+	auditVerifyCmd.Flags().BoolVar(&auditVerifyRequireSigned, "require-signed", false, "Fail if any checkpoint commit is unsigned")
+	auditCheckpointCmd.Flags().BoolVar(&auditCheckpointJSON, "json", false, "Output as JSON")
+
+	auditCmd.AddCommand(auditVerifyCmd)
+	auditCmd.AddCommand(auditCheckpointCmd)
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	report, err := auditlog.Verify(townRoot)
+	if err != nil {
+		return err
+	}
+	unsigned := 0
+	for _, c := range report.Commits {
+		if !c.Signed() {
+			unsigned++
+		}
+	}
+	failed := !report.OK() || (auditVerifyRequireSigned && unsigned > 0)
+
+	if auditVerifyJSON {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printAuditReport(report, unsigned)
+	}
+	if failed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printAuditReport(report *auditlog.Report, unsigned int) {
+	fmt.Printf("%s %d records, head seq %d (%s)\n", style.Bold.Render("Audit chain:"),
+		report.Records, report.Head.Seq, shortHash(report.Head.Hash))
+
+	source := "working copy"
+	if report.Committed {
+		source = "committed"
+	}
+	switch {
+	case len(report.Checkpoints) == 0:
+		fmt.Printf("%s no checkpoints (run 'gt audit checkpoint')\n", style.Bold.Render("Checkpoints:"))
+	default:
+		last := report.Checkpoints[len(report.Checkpoints)-1]
+		fmt.Printf("%s %d %s, latest seq %d at %s\n", style.Bold.Render("Checkpoints:"),
+			len(report.Checkpoints), source, last.Seq, last.Time.Local().Format("2006-01-02 15:04:05"))
+	}
+	if len(report.Commits) > 0 {
+		fmt.Printf("%s %d of %d checkpoint commits signed\n", style.Bold.Render("Signatures:"),
+			len(report.Commits)-unsigned, len(report.Commits))
+	}
+	fmt.Println()
+
+	if report.OK() {
+		fmt.Printf("%s Audit trail intact\n", style.Success.Render("✓"))
+		if auditVerifyRequireSigned && unsigned > 0 {
+			fmt.Printf("%s %d checkpoint commit(s) unsigned\n", style.Error.Render("✗"), unsigned)
+		}
+		return
+	}
+	fmt.Printf("%s %d problem(s) found:\n", style.Error.Render("✗"), len(report.Problems))
+	for _, p := range report.Problems {
+		fmt.Printf("  %s %s\n", style.Bold.Render(fmt.Sprintf("[%s]", p.Kind)), p.Detail)
+	}
+}
+
+func runAuditCheckpoint(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	res, err := auditlog.WriteCheckpoint(townRoot, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if auditCheckpointJSON {
+		return outputJSON(res)
+	}
+	if res == nil {
+		fmt.Printf("%s Nothing new to anchor\n", style.Dim.Render("○"))
+		return nil
+	}
+	signed := "unsigned"
+	if res.Signed {
+		signed = "signed"
+	}
+	fmt.Printf("%s Checkpoint at seq %d committed (%s, %s)\n", style.Success.Render("✓"),
+		res.Seq, shortHash(res.Commit), signed)
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
+)
+
+func TestPrintAuditReport(t *testing.T) {
+	report := &auditlog.Report{
+		Head:    auditlog.Head{Seq: 4, Hash: strings.Repeat("ab", 32)},
+		Records: 4,
+		Commits: []auditlog.CheckpointCommit{{Commit: "c1", Signature: "G"}, {Commit: "c2", Signature: "N"}},
+	}
+	out := captureStdout(t, func() { printAuditReport(report, 1) })
+	for _, want := range []string{"4 records, head seq 4 (abababababab)", "no checkpoints", "1 of 2 checkpoint commits signed", "Audit trail intact"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	report.Problems = []auditlog.Problem{{Kind: auditlog.ProblemEdited, Seq: 2, Detail: "record 2 contents do not match its hash"}}
+	out = captureStdout(t, func() { printAuditReport(report, 1) })
+	if !strings.Contains(out, "1 problem(s) found") || !strings.Contains(out, "[edited] record 2") {
+		t.Errorf("output:\n%s", out)
+	}
+}
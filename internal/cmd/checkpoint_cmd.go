@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -28,7 +31,11 @@ Checkpoint data includes:
 - Git branch and last commit
 - Timestamp
 
-Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
+The latest checkpoint is stored in .polecat-checkpoint.json in the polecat
+directory. Every checkpoint is also appended to .runtime/checkpoints.jsonl,
+which is written automatically at molecule step boundaries and before
+handoffs. Use 'gt checkpoint list' and 'gt checkpoint restore <n>' to rewind
+a derailed worker instead of nuking it.`,
 }
 
 var checkpointWriteCmd = &cobra.Command{
@@ -55,20 +62,59 @@ var checkpointReadCmd = &cobra.Command{
 var checkpointClearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear the checkpoint file",
-	Long:  `Remove the checkpoint file. Use after work is complete or checkpoint is no longer needed.`,
-	RunE:  runCheckpointClear,
+	Long: `Remove the checkpoint file. Use after work is complete or checkpoint is no longer needed.
+
+With --history, also remove the checkpoint history and the git refs
+pinning its uncommitted changes.`,
+	RunE: runCheckpointClear,
+}
+
+var checkpointListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List checkpoint history",
+	Long: `List the checkpoint history for the current worktree, oldest first.
+
+Each entry shows when and why it was taken, the commit, the molecule step,
+the hooked bead, and a diff stat of uncommitted changes at the time.`,
+	RunE: runCheckpointList,
+}
+
+var checkpointRestoreCmd = &cobra.Command{
+	Use:   "restore <n>",
+	Short: "Rewind the worktree and hook to a checkpoint",
+	Long: `Restore the worktree and hook to checkpoint <n> from 'gt checkpoint list'.
+
+Restore does:
+  1. Record a pre-restore checkpoint of the current state (so restore is undoable)
+  2. git reset --hard to the checkpoint's commit
+  3. Reapply the uncommitted tracked changes captured with the checkpoint
+  4. Re-hook the bead that was hooked at the time, if it changed
+
+Untracked files are not captured by checkpoints and are left in place.
+
+Examples:
+  gt checkpoint list
+  gt checkpoint restore 3
+  gt checkpoint restore 3 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCheckpointRestore,
 }
 
 var (
 	checkpointNotes    string
 	checkpointMolecule string
 	checkpointStep     string
+	checkpointJSON     bool
+	checkpointDryRun   bool
+	checkpointHistory  bool
 )
 
 func init() {
 	checkpointCmd.AddCommand(checkpointWriteCmd)
 	checkpointCmd.AddCommand(checkpointReadCmd)
 	checkpointCmd.AddCommand(checkpointClearCmd)
+	checkpointCmd.AddCommand(checkpointListCmd)
+	checkpointCmd.AddCommand(checkpointRestoreCmd)
 
 	checkpointWriteCmd.Flags().StringVar(&checkpointNotes, "notes", "",
 		"Add notes to the checkpoint")
@@ -76,6 +122,12 @@ func init() {
 		"Override molecule ID (auto-detected if not specified)")
 	checkpointWriteCmd.Flags().StringVar(&checkpointStep, "step", "",
 		"Override step ID (auto-detected if not specified)")
+	checkpointClearCmd.Flags().BoolVar(&checkpointHistory, "history", false,
+		"Also remove the checkpoint history")
+	checkpointListCmd.Flags().BoolVar(&checkpointJSON, "json", false,
+		"Output as JSON")
+	checkpointRestoreCmd.Flags().BoolVarP(&checkpointDryRun, "dry-run", "n", false,
+		"Show what would be restored without changing anything")
 
 	rootCmd.AddCommand(checkpointCmd)
 }
@@ -104,10 +156,9 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Capture current state
-	cp, err := checkpoint.Capture(cwd)
+	cp, err := captureCheckpoint(cwd, roleInfo, checkpointMolecule, checkpointStep)
 	if err != nil {
-		return fmt.Errorf("capturing checkpoint: %w", err)
+		return err
 	}
 
 	// Add notes if provided
@@ -115,40 +166,93 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 		cp.WithNotes(checkpointNotes)
 	}
 
+	// Write checkpoint
+	if err := checkpoint.Write(cwd, cp); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+
+	entry, err := checkpoint.Record(cwd, cp, checkpoint.ReasonManual)
+	if err != nil {
+		return fmt.Errorf("recording checkpoint history: %w", err)
+	}
+
+	fmt.Printf("%s Checkpoint #%d written\n", style.Bold.Render("✓"), entry.Seq)
+	fmt.Printf("  %s\n", cp.Summary())
+
+	return nil
+}
+
+// captureCheckpoint captures git state plus molecule and hook context.
+// Empty moleculeID/stepID are auto-detected from beads.
+func captureCheckpoint(cwd string, roleInfo RoleInfo, moleculeID, stepID string) (*checkpoint.Checkpoint, error) {
+	cp, err := checkpoint.Capture(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("capturing checkpoint: %w", err)
+	}
+
 	// Try to detect molecule context if not overridden
-	if checkpointMolecule == "" || checkpointStep == "" {
-		moleculeID, stepID, stepTitle := detectMoleculeContext(cwd, roleInfo)
-		if checkpointMolecule == "" {
-			checkpointMolecule = moleculeID
-		}
-		if checkpointStep == "" {
-			checkpointStep = stepID
+	var stepTitle string
+	if moleculeID == "" || stepID == "" {
+		detectedMolecule, detectedStep, detectedTitle := detectMoleculeContext(cwd, roleInfo)
+		if moleculeID == "" {
+			moleculeID = detectedMolecule
 		}
-		if stepTitle != "" {
-			cp.WithMolecule(checkpointMolecule, checkpointStep, stepTitle)
+		if stepID == "" {
+			stepID = detectedStep
 		}
+		stepTitle = detectedTitle
 	}
-
-	// Add molecule context
-	if checkpointMolecule != "" {
-		cp.WithMolecule(checkpointMolecule, checkpointStep, "")
+	if moleculeID != "" {
+		cp.WithMolecule(moleculeID, stepID, stepTitle)
 	}
 
 	// Detect hooked bead
-	hookedBead := detectHookedBead(cwd, roleInfo)
-	if hookedBead != "" {
+	if hookedBead := detectHookedBead(cwd, roleInfo); hookedBead != "" {
 		cp.WithHookedBead(hookedBead)
 	}
 
-	// Write checkpoint
-	if err := checkpoint.Write(cwd, cp); err != nil {
-		return fmt.Errorf("writing checkpoint: %w", err)
+	return cp, nil
+}
+
+// checkpointWorktree returns the root of the git worktree containing the
+// current directory, which holds the checkpoint history, or the current
+// directory outside git.
+func checkpointWorktree() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting current directory: %w", err)
+	}
+	if root, err := findGitRoot(cwd); err == nil {
+		return root, nil
 	}
+	return cwd, nil
+}
 
-	fmt.Printf("%s Checkpoint written\n", style.Bold.Render("✓"))
-	fmt.Printf("  %s\n", cp.Summary())
+// recordAutoCheckpoint appends a checkpoint to the history of the current
+// polecat or crew worktree. Used at molecule step boundaries and before
+// handoffs. Best-effort: failures are reported as warnings, never errors.
+func recordAutoCheckpoint(reason string) {
+	cwd, err := checkpointWorktree()
+	if err != nil {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil || (roleInfo.Role != RolePolecat && roleInfo.Role != RoleCrew) {
+		return
+	}
 
-	return nil
+	cp, err := captureCheckpoint(cwd, roleInfo, "", "")
+	if err != nil {
+		style.PrintWarning("could not capture checkpoint: %v", err)
+		return
+	}
+	if _, err := checkpoint.Record(cwd, cp, reason); err != nil {
+		style.PrintWarning("could not record checkpoint history: %v", err)
+	}
 }
 
 func runCheckpointRead(cmd *cobra.Command, args []string) error {
@@ -213,11 +317,143 @@ func runCheckpointClear(cmd *cobra.Command, args []string) error {
 	if err := checkpoint.Remove(cwd); err != nil {
 		return fmt.Errorf("removing checkpoint: %w", err)
 	}
+	if checkpointHistory {
+		worktree, err := checkpointWorktree()
+		if err != nil {
+			return err
+		}
+		if err := checkpoint.ClearHistory(worktree); err != nil {
+			return fmt.Errorf("removing checkpoint history: %w", err)
+		}
+	}
 
 	fmt.Printf("%s Checkpoint cleared\n", style.Bold.Render("✓"))
 	return nil
 }
 
+func runCheckpointList(cmd *cobra.Command, args []string) error {
+	cwd, err := checkpointWorktree()
+	if err != nil {
+		return err
+	}
+
+	entries, err := checkpoint.ReadHistory(cwd)
+	if err != nil {
+		return err
+	}
+
+	if checkpointJSON {
+		if entries == nil {
+			entries = []checkpoint.Entry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("%s No checkpoint history\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Checkpoint History"))
+	for _, e := range entries {
+		commit := e.LastCommit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		fmt.Printf("  %3d  %s  %-11s %s", e.Seq, e.Timestamp.Format("2006-01-02 15:04"), e.Reason, commit)
+		if e.CurrentStep != "" {
+			fmt.Printf("  step %s", e.CurrentStep)
+		}
+		if e.HookedBead != "" {
+			fmt.Printf("  hooked %s", e.HookedBead)
+		}
+		fmt.Println()
+		if e.StepTitle != "" {
+			fmt.Printf("       %s\n", style.Dim.Render(e.StepTitle))
+		}
+		if e.DiffStat != "" {
+			fmt.Printf("       %s\n", style.Dim.Render("uncommitted: "+e.DiffStat))
+		}
+	}
+
+	return nil
+}
+
+func runCheckpointRestore(cmd *cobra.Command, args []string) error {
+	seq, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint number %q", args[0])
+	}
+
+	cwd, err := checkpointWorktree()
+	if err != nil {
+		return err
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return fmt.Errorf("detecting role: %w", err)
+	}
+	if roleInfo.Role != RolePolecat && roleInfo.Role != RoleCrew {
+		return fmt.Errorf("checkpoints only apply to polecats and crew workers")
+	}
+
+	entry, err := checkpoint.Get(cwd, seq)
+	if err != nil {
+		return err
+	}
+
+	currentHook := detectHookedBead(cwd, roleInfo)
+	rehook := entry.HookedBead != "" && entry.HookedBead != currentHook
+
+	if checkpointDryRun {
+		fmt.Printf("Would record pre-restore checkpoint of current state\n")
+		fmt.Printf("Would run: git reset --hard %s\n", entry.LastCommit)
+		if entry.StashCommit != "" {
+			fmt.Printf("Would run: git stash apply %s (%s)\n", entry.StashCommit, entry.DiffStat)
+		}
+		if rehook {
+			fmt.Printf("Would run: gt hook %s --force (currently hooked: %s)\n", entry.HookedBead, currentHook)
+		}
+		return nil
+	}
+
+	// Snapshot current state first so the restore itself can be undone
+	current, err := captureCheckpoint(cwd, roleInfo, "", "")
+	if err != nil {
+		return err
+	}
+	pre, err := checkpoint.Record(cwd, current, checkpoint.ReasonPreRestore)
+	if err != nil {
+		return fmt.Errorf("recording pre-restore checkpoint: %w", err)
+	}
+	fmt.Printf("%s Saved current state as checkpoint #%d\n", style.Dim.Render("○"), pre.Seq)
+
+	if err := checkpoint.RestoreWorktree(cwd, entry); err != nil {
+		return err
+	}
+	fmt.Printf("%s Worktree reset to %s\n", style.Bold.Render("✓"), entry.LastCommit[:min(12, len(entry.LastCommit))])
+
+	if rehook {
+		hookCmd := exec.Command("gt", "hook", entry.HookedBead, "--force")
+		hookCmd.Stdout = os.Stdout
+		hookCmd.Stderr = os.Stderr
+		if err := hookCmd.Run(); err != nil {
+			return fmt.Errorf("re-hooking %s: %w", entry.HookedBead, err)
+		}
+	}
+
+	fmt.Printf("%s Restored checkpoint #%d (%s)\n", style.Bold.Render("✓"), entry.Seq, entry.Summary())
+	return nil
+}
+
 // detectMoleculeContext tries to detect the current molecule and step from beads.
 func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, stepTitle string) {
 	b := beads.New(workDir)
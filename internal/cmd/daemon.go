@@ -27,7 +27,20 @@ The daemon is a simple Go process that:
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
 
-The daemon is a "dumb scheduler" - all intelligence is in agents.`,
+The daemon is a "dumb scheduler" - all intelligence is in agents.
+
+Edits to mayor/daemon.json and .krc.yaml apply live: the daemon watches
+its config files and reloads on change, on SIGHUP or on 'gt daemon
+reload', rejecting invalid config without stopping.
+
+To scrape town health from Prometheus, enable the metrics endpoint in
+mayor/daemon.json and restart the daemon:
+
+  "metrics": {"enabled": true, "listen": "127.0.0.1:9464"}
+
+It serves agents by state, restarts and crash loops, Dolt health, merge
+queue depth per rig, mail backlog per identity, open escalations by
+severity, quota status per account and event counters at /metrics.`,
 }
 
 var daemonStartCmd = &cobra.Command{
@@ -59,6 +72,9 @@ var daemonStatusCmd = &cobra.Command{
 
 Displays whether the daemon is running, its PID, uptime, heartbeat
 count, and whether the binary has been rebuilt since the daemon started.
+A running daemon answers over its control socket (daemon/daemon.sock),
+so the status also shows a heartbeat in progress, the next heartbeat,
+any paused rigs and a rejected config reload.
 
 Examples:
   gt daemon status`,
@@ -223,6 +239,13 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 			style.Bold.Render("running"),
 			pid)
 
+		// Prefer the live answer from the control socket; the state file
+		// is only rewritten at the end of each heartbeat.
+		if status, err := daemon.QueryStatus(townRoot); err == nil {
+			printLiveDaemonStatus(status)
+			return nil
+		}
+
 		// Load state for more details
 		state, err := daemon.LoadState(townRoot)
 		if err == nil && !state.StartedAt.IsZero() {
@@ -233,15 +256,7 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 					state.HeartbeatCount)
 			}
 
-			// Check if binary is newer than process
-			if binaryModTime, err := getBinaryModTime(); err == nil {
-				fmt.Printf("  Binary: %s\n", binaryModTime.Format("2006-01-02 15:04:05"))
-				if binaryModTime.After(state.StartedAt) {
-					fmt.Printf("  %s Binary is newer than process - consider '%s'\n",
-						style.Bold.Render("⚠"),
-						style.Dim.Render("gt daemon stop && gt daemon start"))
-				}
-			}
+			printDaemonBinaryAge(state.StartedAt)
 		}
 	} else {
 		fmt.Printf("%s Daemon is %s\n",
@@ -253,6 +268,21 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printDaemonBinaryAge warns when the gt binary was rebuilt after the
+// daemon started.
+func printDaemonBinaryAge(startedAt time.Time) {
+	binaryModTime, err := getBinaryModTime()
+	if err != nil {
+		return
+	}
+	fmt.Printf("  Binary: %s\n", binaryModTime.Format("2006-01-02 15:04:05"))
+	if binaryModTime.After(startedAt) {
+		fmt.Printf("  %s Binary is newer than process - consider '%s'\n",
+			style.Bold.Render("⚠"),
+			style.Dim.Render("gt daemon stop && gt daemon start"))
+	}
+}
+
 // getBinaryModTime returns the modification time of the current executable
 func getBinaryModTime() (time.Time, error) {
 	exePath, err := os.Executable()
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// daemonRestartTimeout covers a restart queued behind a running heartbeat
+// plus the agent's startup.
+const daemonRestartTimeout = 5 * time.Minute
+
+var daemonGoroutinesJSON bool
+
+var daemonPatrolCmd = &cobra.Command{
+	Use:   "patrol",
+	Short: "Run a daemon heartbeat now",
+	Long: `Ask the running daemon to run its heartbeat now instead of waiting
+for the next recovery interval (3 minutes).
+
+The heartbeat is queued and runs as soon as the daemon's main loop is
+free. Use 'gt daemon status' to see when it has completed.
+
+Examples:
+  gt daemon patrol`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := callDaemon(daemon.ControlRequest{Method: daemon.ControlPatrol}, 0)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), resp.Message)
+		return nil
+	},
+}
+
+var daemonRestartAgentCmd = &cobra.Command{
+	Use:   "restart-agent <identity>",
+	Short: "Restart an agent session through the daemon",
+	Long: `Kill and restart an agent's session through the running daemon.
+
+The identity is the agent's mail address, e.g. "mayor", "deacon",
+"gastown/witness", "gastown/refinery" or "gastown/crew/max". The
+restart runs between heartbeats, so the command waits for a heartbeat
+in progress to finish. Rigs that are parked, docked or paused are
+refused.
+
+Examples:
+  gt daemon restart-agent gastown/witness
+  gt daemon restart-agent deacon`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := callDaemon(daemon.ControlRequest{Method: daemon.ControlRestartAgent, Agent: args[0]}, daemonRestartTimeout)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), resp.Message)
+		return nil
+	},
+}
+
+var daemonReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload the daemon's config without restarting",
+	Long: `Ask the running daemon to re-read its config files and apply them
+without restarting agents or patrol loops:
+
+  - mayor/daemon.json: patrol toggles, heartbeat, convoy and Dolt
+    intervals, Dolt restart policy
+  - .krc.yaml: KRC TTLs and prune interval
+  - mayor/rigs.json: rig session prefixes
+  - settings/escalation.json and <rig>/settings/config.json: validated
+    only; gt reads them on each use
+
+The daemon also watches these files and reloads when they change, and
+reloads on SIGHUP. An invalid config is rejected with a config_rejected
+event and the running config stays in effect. Dolt server identity
+(host, port, data_dir, ...) and the metrics listener still need a
+daemon restart.
+
+Examples:
+  gt daemon reload`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := callDaemon(daemon.ControlRequest{Method: daemon.ControlReload}, daemonRestartTimeout)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), resp.Message)
+		return nil
+	},
+}
+
+var daemonPauseCmd = &cobra.Command{
+	Use:   "pause <rig>",
+	Short: "Stop the daemon from managing a rig's agents",
+	Long: `Pause a rig in the running daemon.
+
+While paused the daemon does not start or restart the rig's witness,
+refinery or polecats, and the convoy manager skips it. The pause lasts
+until 'gt daemon resume' or the daemon restarts; use 'gt rig park' for
+a durable stop.
+
+Examples:
+  gt daemon pause gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDaemonPauseRig(daemon.ControlPauseRig, args[0])
+	},
+}
+
+var daemonResumeCmd = &cobra.Command{
+	Use:   "resume <rig>",
+	Short: "Resume a rig paused with 'gt daemon pause'",
+	Long: `Resume a rig paused in the running daemon. The next heartbeat
+starts any of its agents that are down.
+
+Examples:
+  gt daemon resume gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDaemonPauseRig(daemon.ControlResumeRig, args[0])
+	},
+}
+
+var daemonGoroutinesCmd = &cobra.Command{
+	Use:   "goroutines",
+	Short: "List the daemon's managed goroutines",
+	Long: `List the long-running goroutines the daemon manages: the heartbeat,
+feed curator, convoy manager, KRC pruner, Dolt tickers and the control
+socket, with when each started.
+
+Examples:
+  gt daemon goroutines
+  gt daemon goroutines --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := callDaemon(daemon.ControlRequest{Method: daemon.ControlGoroutines}, 0)
+		if err != nil {
+			return err
+		}
+		if daemonGoroutinesJSON {
+			return outputJSON(resp.Goroutines)
+		}
+		for _, g := range resp.Goroutines {
+			fmt.Printf("  %-20s %s  %s\n", g.Name,
+				style.Dim.Render("up "+formatDuration(time.Since(g.StartedAt))), g.Detail)
+		}
+		fmt.Printf("\n%d managed, %d goroutines in process\n", len(resp.Goroutines), resp.NumGoroutine)
+		return nil
+	},
+}
+
+func init() {
+	daemonGoroutinesCmd.Flags().BoolVar(&daemonGoroutinesJSON, "json", false, "Output as JSON")
+
+	daemonCmd.AddCommand(daemonPatrolCmd)
+	daemonCmd.AddCommand(daemonRestartAgentCmd)
+	daemonCmd.AddCommand(daemonReloadCmd)
+	daemonCmd.AddCommand(daemonPauseCmd)
+	daemonCmd.AddCommand(daemonResumeCmd)
+	daemonCmd.AddCommand(daemonGoroutinesCmd)
+}
+
+// callDaemon sends a request to the town's daemon over its control socket.
+// A zero timeout uses a short default suited to requests answered from memory.
+func callDaemon(req daemon.ControlRequest, timeout time.Duration) (*daemon.ControlResponse, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	resp, err := daemon.CallDaemon(townRoot, req, timeout)
+	if errors.Is(err, daemon.ErrNoControlSocket) {
+		return nil, fmt.Errorf("daemon is not running (start with 'gt daemon start')")
+	}
+	return resp, err
+}
+
+func runDaemonPauseRig(method, rigName string) error {
+	resp, err := callDaemon(daemon.ControlRequest{Method: method, Rig: rigName}, 0)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("✓"), resp.Message)
+	if resp.Status != nil && len(resp.Status.PausedRigs) > 0 {
+		fmt.Printf("  Paused rigs: %v\n", resp.Status.PausedRigs)
+	}
+	return nil
+}
+
+// printLiveDaemonStatus prints the detail lines of 'gt daemon status' from
+// the daemon's control socket.
+func printLiveDaemonStatus(s *daemon.DaemonStatus) {
+	fmt.Printf("  Started: %s\n", s.StartedAt.Format("2006-01-02 15:04:05"))
+	switch {
+	case s.InHeartbeat:
+		fmt.Printf("  Heartbeat: %s (#%d)\n", style.Bold.Render("running"), s.HeartbeatCount+1)
+	case !s.LastHeartbeat.IsZero():
+		fmt.Printf("  Last heartbeat: %s (#%d)\n", s.LastHeartbeat.Format("15:04:05"), s.HeartbeatCount)
+	}
+	if s.PatrolQueued {
+		fmt.Printf("  Next heartbeat: %s\n", style.Dim.Render("queued by 'gt daemon patrol'"))
+	} else if !s.InHeartbeat && !s.NextHeartbeat.IsZero() {
+		fmt.Printf("  Next heartbeat: %s (in %s)\n", s.NextHeartbeat.Format("15:04:05"),
+			formatDuration(time.Until(s.NextHeartbeat)))
+	}
+	if len(s.PausedRigs) > 0 {
+		fmt.Printf("  Paused rigs: %v\n", s.PausedRigs)
+	}
+	if s.MetricsURL != "" {
+		fmt.Printf("  Metrics: %s\n", s.MetricsURL)
+	}
+	if s.ReloadError != "" {
+		fmt.Printf("  Config: %s at %s, keeping previous config\n  %s\n", style.Warning.Render("reload rejected"),
+			s.LastReload.Format("15:04:05"), s.ReloadError)
+	}
+	printDaemonBinaryAge(s.StartedAt)
+}
@@ -184,13 +184,21 @@ This command automates the tedious process of pushing each database individually
 Use --db to sync a single database, --dry-run to preview, or --force for force-push.
 Use --gc to purge closed ephemeral beads (wisps, convoys) before pushing.
 
+Use --merge when several towns share a remote: each database first fetches
+and merges origin/main with Dolt's three-way merge. Rows both sides edited
+are resolved field by field — status only moves forward (a close wins),
+labels are unioned, and descriptions and other text take the newer edit.
+Rows no rule can settle keep the local version and are listed by
+'gt dolt conflicts'.
+
 Examples:
   gt dolt sync                # Push all databases with remotes
   gt dolt sync --dry-run      # Preview what would be pushed
   gt dolt sync --db gastown   # Push only the gastown database
   gt dolt sync --force        # Force-push all databases
   gt dolt sync --gc           # Purge closed ephemeral beads, then push
-  gt dolt sync --gc --dry-run # Preview purge + push without changes`,
+  gt dolt sync --gc --dry-run # Preview purge + push without changes
+  gt dolt sync --merge        # Merge origin/main before pushing`,
 	RunE: runDoltSync,
 }
 
@@ -243,6 +251,7 @@ var (
 	doltSyncForce    bool
 	doltSyncDB       string
 	doltSyncGC       bool
+	doltSyncMerge    bool
 )
 
 func init() {
@@ -275,6 +284,7 @@ func init() {
 	doltSyncCmd.Flags().BoolVar(&doltSyncForce, "force", false, "Force-push to remotes")
 	doltSyncCmd.Flags().StringVar(&doltSyncDB, "db", "", "Sync a single database instead of all")
 	doltSyncCmd.Flags().BoolVar(&doltSyncGC, "gc", false, "Purge closed ephemeral beads before push (requires bd purge)")
+	doltSyncCmd.Flags().BoolVar(&doltSyncMerge, "merge", false, "Fetch and merge origin/main before pushing, resolving bead conflicts")
 
 	rootCmd.AddCommand(doltCmd)
 }
@@ -1196,6 +1206,7 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 		Force:  doltSyncForce,
 		DryRun: doltSyncDry,
 		Filter: doltSyncDB,
+		Merge:  doltSyncMerge,
 	}
 
 	results := doltserver.SyncDatabases(townRoot, opts)
@@ -1207,7 +1218,7 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\nSyncing %d database(s)...\n", len(results))
 
-	var pushed, skipped, failed, totalPurged int
+	var pushed, skipped, failed, totalPurged, unresolved int
 	for _, r := range results {
 		fmt.Println()
 		// Show purge results if --gc was used
@@ -1225,6 +1236,15 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+		if m := r.Merge; m != nil && m.Merged {
+			fmt.Printf("  %s %s ← origin main: merged, %d conflict(s) resolved\n",
+				style.Bold.Render("✓"), r.Database, m.Resolved)
+			if len(m.Unresolved) > 0 {
+				fmt.Printf("    %s %d row(s) kept local — see 'gt dolt conflicts'\n",
+					style.Warning.Render("⚠"), len(m.Unresolved))
+				unresolved += len(m.Unresolved)
+			}
+		}
 		switch {
 		case r.Pushed:
 			fmt.Printf("  %s %s → origin main\n", style.Bold.Render("✓"), r.Database)
@@ -1252,6 +1272,9 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 			summary += fmt.Sprintf(", %d purged", totalPurged)
 		}
 	}
+	if unresolved > 0 {
+		summary += fmt.Sprintf(", %d unresolved conflict(s)", unresolved)
+	}
 	fmt.Printf("\n%s\n", summary)
 
 	if failed > 0 {
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltBackupDB           string
+	doltBackupJSON         bool
+	doltBackupNoVerify     bool
+	doltBackupAt           string
+	doltBackupFromSnapshot bool
+	doltBackupDry          bool
+)
+
+var doltBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Snapshot Dolt databases and restore them to a point in time",
+	Long: `Manage Dolt-native snapshots of the town and rig databases.
+
+Snapshots are Dolt backups (all branches and working sets) stored under
+.dolt-backups/<database>/. The daemon takes them on a schedule when the
+dolt_backups patrol is enabled in mayor/daemon.json:
+
+  "patrols": {"dolt_backups": {"enabled": true, "keep": 24, "keep_daily": 7}}
+
+Each new snapshot is verified by restoring it into a scratch directory,
+and snapshots outside the retention policy are pruned.`,
+	RunE: requireSubcommand,
+}
+
+var doltBackupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Dolt snapshots",
+	Long: `List snapshots newest-first with their HEAD commit and verification state.
+
+Examples:
+  gt dolt backup list
+  gt dolt backup list --db gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoltBackupList,
+}
+
+var doltBackupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Snapshot Dolt databases now",
+	Long: `Take a snapshot of every served database (or one with --db) through
+the running Dolt server, then verify it by restoring into a scratch
+directory.
+
+Examples:
+  gt dolt backup create
+  gt dolt backup create --db hq --no-verify`,
+	Args: cobra.NoArgs,
+	RunE: runDoltBackupCreate,
+}
+
+var doltBackupRestoreCmd = &cobra.Command{
+	Use:   "restore <database> --at <time>",
+	Short: "Restore a database to a point in time",
+	Long: `Restore a database to its state at a point in time.
+
+Dolt history is used where possible: the database's main branch is reset
+to the last commit made at or before --at. The state before the restore,
+including uncommitted changes, is kept on a gt-pre-restore-* branch.
+
+If the history can't be read (e.g. a corrupted working set), has no
+commit that old, or --from-snapshot is given, the newest snapshot taken
+at or before --at is restored instead. This stops the local Dolt server,
+moves the current database aside under .dolt-backups/<database>/ and
+restarts the server.
+
+--at accepts RFC 3339 ("2026-05-01T09:30:00Z"), a local date and time
+("2026-05-01 09:30"), a local time today ("09:30") or an age ("2h", "1d").
+
+Examples:
+  gt dolt backup restore gastown --at 09:30 --dry-run
+  gt dolt backup restore hq --at 2h
+  gt dolt backup restore gastown --at "2026-05-01 09:30" --from-snapshot`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDoltBackupRestore,
+}
+
+func init() {
+	doltBackupListCmd.Flags().StringVar(&doltBackupDB, "db", "", "Only list snapshots of this database")
+	doltBackupListCmd.Flags().BoolVar(&doltBackupJSON, "json", false, "Output as JSON")
+
+	doltBackupCreateCmd.Flags().StringVar(&doltBackupDB, "db", "", "Snapshot a single database instead of all")
+	doltBackupCreateCmd.Flags().BoolVar(&doltBackupNoVerify, "no-verify", false, "Skip the verification restore")
+
+	doltBackupRestoreCmd.Flags().StringVar(&doltBackupAt, "at", "", "Point in time to restore to (required)")
+	doltBackupRestoreCmd.Flags().BoolVar(&doltBackupFromSnapshot, "from-snapshot", false, "Restore from a snapshot instead of Dolt history")
+	doltBackupRestoreCmd.Flags().BoolVar(&doltBackupDry, "dry-run", false, "Show the restore plan without making changes")
+	_ = doltBackupRestoreCmd.MarkFlagRequired("at")
+
+	doltBackupCmd.AddCommand(doltBackupListCmd)
+	doltBackupCmd.AddCommand(doltBackupCreateCmd)
+	doltBackupCmd.AddCommand(doltBackupRestoreCmd)
+	doltCmd.AddCommand(doltBackupCmd)
+}
+
+func runDoltBackupList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	snapshots, err := doltserver.ListSnapshots(townRoot, doltBackupDB)
+	if err != nil {
+		return err
+	}
+	if doltBackupJSON {
+		return outputJSON(snapshots)
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots in %s\n", doltserver.SnapshotsDir(townRoot))
+		fmt.Println(style.Dim.Render("Take one with 'gt dolt backup create' or enable the dolt_backups patrol."))
+		return nil
+	}
+
+	for _, s := range snapshots {
+		verified := style.Dim.Render("unverified")
+		switch {
+		case s.Verified:
+			verified = style.Success.Render("verified")
+		case s.VerifyError != "":
+			verified = style.Error.Render("verify failed")
+		}
+		fmt.Printf("  %-20s %s  %s  %s\n", s.Database, s.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			shortCommit(s.Head), verified)
+		if s.VerifyError != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(s.VerifyError))
+		}
+	}
+	fmt.Printf("\n%d snapshot(s) in %s\n", len(snapshots), doltserver.SnapshotsDir(townRoot))
+	return nil
+}
+
+func runDoltBackupCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	databases := []string{doltBackupDB}
+	if doltBackupDB == "" {
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+		if len(databases) == 0 {
+			return fmt.Errorf("no databases to snapshot")
+		}
+	}
+
+	failed := 0
+	for _, db := range databases {
+		s, err := doltserver.CreateSnapshot(townRoot, db)
+		if err != nil {
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), db, err)
+			failed++
+			continue
+		}
+		status := ""
+		if !doltBackupNoVerify {
+			if err := doltserver.VerifySnapshot(s); err != nil {
+				fmt.Printf("  %s %s: snapshot %s failed verification: %v\n", style.Error.Render("✗"), db, s.Path, err)
+				failed++
+				continue
+			}
+			status = " (verified)"
+		}
+		fmt.Printf("  %s %s at %s%s\n", style.Bold.Render("✓"), db, shortCommit(s.Head), status)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d snapshot(s) failed", failed, len(databases))
+	}
+	return nil
+}
+
+func runDoltBackupRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	at, err := parseRestoreTime(doltBackupAt, time.Now())
+	if err != nil {
+		return err
+	}
+
+	plan, err := doltserver.PlanRestore(townRoot, args[0], at, doltBackupFromSnapshot)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restore %s to %s\n", plan.Database, at.Local().Format("2006-01-02 15:04:05"))
+	switch plan.Method {
+	case doltserver.RestoreFromHistory:
+		fmt.Printf("  From Dolt history: reset main to %s (committed %s)\n",
+			shortCommit(plan.Commit), plan.CommitTime.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Current state kept on branch %s\n", plan.SafetyBranch)
+	case doltserver.RestoreFromSnapshot:
+		fmt.Printf("  From snapshot taken %s (HEAD %s)\n",
+			plan.Snapshot.CreatedAt.Local().Format("2006-01-02 15:04:05"), shortCommit(plan.Snapshot.Head))
+		if !plan.Snapshot.Verified {
+			fmt.Printf("  %s snapshot was never verified\n", style.Warning.Render("⚠"))
+		}
+		fmt.Printf("  Current database moved to %s\n", plan.SavedDir)
+		fmt.Printf("  %s\n", style.Dim.Render("The Dolt server is stopped and restarted."))
+	}
+
+	if doltBackupDry {
+		fmt.Printf("\n%s Dry run - no changes made\n", style.Bold.Render("!"))
+		return nil
+	}
+
+	if err := doltserver.RestoreDatabase(townRoot, plan); err != nil {
+		if plan.Method == doltserver.RestoreFromHistory {
+			// A history restore needs a readable working set; a corrupted
+			// one is exactly what snapshots are for.
+			return fmt.Errorf("restore failed: %w\n  retry from a snapshot: gt dolt backup restore %s --at %q --from-snapshot",
+				err, plan.Database, doltBackupAt)
+		}
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	fmt.Printf("\n%s Restored %s\n", style.Bold.Render("✓"), plan.Database)
+	return nil
+}
+
+// parseRestoreTime parses a --at value: RFC 3339, a local "2006-01-02 15:04"
+// date and time (seconds optional), a local "15:04" time today, or an age
+// such as "90m", "2h" or "1d".
+func parseRestoreTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			y, m, d := now.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+	if age, err := parseDuration(s); err == nil && age > 0 {
+		return now.Add(-age), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: use RFC 3339, \"2006-01-02 15:04\", \"15:04\" or an age like \"2h\"", s)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRestoreTime(t *testing.T) {
+	now := time.Date(2026, 5, 10, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-05-01T09:30:00Z", time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)},
+		{"2026-05-01 09:30", time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)},
+		{"2026-05-01 09:30:15", time.Date(2026, 5, 1, 9, 30, 15, 0, time.UTC)},
+		{"09:30", time.Date(2026, 5, 10, 9, 30, 0, 0, time.UTC)},
+		{"2h", now.Add(-2 * time.Hour)},
+		{"1d", now.Add(-24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := parseRestoreTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseRestoreTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseRestoreTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "yesterday", "-2h", "25:00"} {
+		if _, err := parseRestoreTime(bad, now); err == nil {
+			t.Errorf("parseRestoreTime(%q): expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltConflictsDB   string
+	doltConflictsJSON bool
+	doltConflictsTake string
+)
+
+var doltConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List rows 'gt dolt sync --merge' could not resolve",
+	Long: `List conflicting rows left over from merging a shared remote.
+
+'gt dolt sync --merge' resolves most conflicts field by field. Rows it
+can't settle (e.g. both sides edited a field with no merge rule, or one
+side deleted a bead the other edited) keep the local version; they are
+listed here with both values so you can choose.
+
+Examples:
+  gt dolt conflicts
+  gt dolt conflicts --db gastown --json
+  gt dolt conflicts resolve gastown gt-abc12 --take theirs`,
+	Args: cobra.NoArgs,
+	RunE: runDoltConflicts,
+}
+
+var doltConflictsResolveCmd = &cobra.Command{
+	Use:   "resolve <database> <row>",
+	Short: "Settle a recorded conflict",
+	Long: `Settle a recorded conflict by keeping the local version (--take ours,
+which the merge already applied) or writing the remote version
+(--take theirs, committed through the running Dolt server).`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDoltConflictsResolve,
+}
+
+func init() {
+	doltConflictsCmd.Flags().StringVar(&doltConflictsDB, "db", "", "Only show conflicts in this database")
+	doltConflictsCmd.Flags().BoolVar(&doltConflictsJSON, "json", false, "Output as JSON")
+	doltConflictsResolveCmd.Flags().StringVar(&doltConflictsTake, "take", "", "Version to keep: ours or theirs (required)")
+	_ = doltConflictsResolveCmd.MarkFlagRequired("take")
+
+	doltConflictsCmd.AddCommand(doltConflictsResolveCmd)
+	doltCmd.AddCommand(doltConflictsCmd)
+}
+
+func runDoltConflicts(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	all, err := doltserver.LoadConflicts(townRoot)
+	if err != nil {
+		return err
+	}
+	conflicts := all[:0:0]
+	for _, c := range all {
+		if doltConflictsDB == "" || c.Database == doltConflictsDB {
+			conflicts = append(conflicts, c)
+		}
+	}
+
+	if doltConflictsJSON {
+		return outputJSON(conflicts)
+	}
+	if len(conflicts) == 0 {
+		fmt.Println("No unresolved Dolt conflicts.")
+		return nil
+	}
+
+	for _, c := range conflicts {
+		fmt.Printf("%s %s.%s %s  %s\n", style.Warning.Render("⚠"), c.Database, c.Table,
+			style.Bold.Render(c.Row), style.Dim.Render(c.RecordedAt.Local().Format("2006-01-02 15:04")))
+		for _, col := range c.Columns {
+			if col == "*" {
+				fmt.Printf("    row:    ours %s, theirs %s\n", rowState(c.Ours), rowState(c.Theirs))
+				continue
+			}
+			fmt.Printf("    %s:\n", col)
+			fmt.Printf("      ours:   %s\n", conflictValue(c.Ours[col]))
+			fmt.Printf("      theirs: %s\n", conflictValue(c.Theirs[col]))
+		}
+	}
+	fmt.Printf("\n%d unresolved conflict(s); local versions were kept.\n", len(conflicts))
+	fmt.Println(style.Dim.Render("Settle with: gt dolt conflicts resolve <database> <row> --take ours|theirs"))
+	return nil
+}
+
+func runDoltConflictsResolve(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var takeTheirs bool
+	switch doltConflictsTake {
+	case "ours":
+	case "theirs":
+		takeTheirs = true
+	default:
+		return fmt.Errorf("invalid --take %q: must be ours or theirs", doltConflictsTake)
+	}
+
+	c, err := doltserver.ResolveRecordedConflict(townRoot, args[0], args[1], takeTheirs)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s.%s %s: kept %s version\n", style.Bold.Render("✓"), c.Database, c.Table, c.Row, doltConflictsTake)
+	return nil
+}
+
+func rowState(row map[string]any) string {
+	if row == nil {
+		return "deleted"
+	}
+	return "modified"
+}
+
+// conflictValue renders a column value on one line, truncated.
+func conflictValue(v any) string {
+	if v == nil {
+		return style.Dim.Render("NULL")
+	}
+	s := strings.ReplaceAll(fmt.Sprint(v), "\n", "⏎")
+	if len(s) > 100 {
+		s = s[:97] + "..."
+	}
+	return s
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
@@ -24,6 +25,7 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -48,7 +50,8 @@ Examples:
   gt done                              # Submit branch, notify COMPLETED, exit session
   gt done --issue gt-abc               # Explicit issue ID
   gt done --status ESCALATED           # Signal blocker, skip MR
-  gt done --status DEFERRED            # Pause work, skip MR`,
+  gt done --status DEFERRED            # Pause work, skip MR
+  gt done --parent gt-mr123            # Stack MR on an unmerged MR (see 'gt mq stack')`,
 	RunE: runDone,
 }
 
@@ -58,6 +61,7 @@ var (
 	doneStatus        string
 	doneCleanupStatus string
 	doneResume        bool
+	doneParent        string
 )
 
 // Valid exit types for gt done
@@ -73,6 +77,7 @@ func init() {
 	doneCmd.Flags().StringVar(&doneStatus, "status", ExitCompleted, "Exit status: COMPLETED, ESCALATED, or DEFERRED")
 	doneCmd.Flags().StringVar(&doneCleanupStatus, "cleanup-status", "", "Git cleanup status: clean, uncommitted, unpushed, stash, unknown (ZFC: agent-observed)")
 	doneCmd.Flags().BoolVar(&doneResume, "resume", false, "Resume from last checkpoint (auto-detected, for Witness recovery)")
+	doneCmd.Flags().StringVar(&doneParent, "parent", "", "Parent MR this branch is stacked on; merges after it")
 
 	rootCmd.AddCommand(doneCmd)
 }
@@ -93,6 +98,12 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		return fmt.Errorf("invalid exit status '%s': must be COMPLETED, ESCALATED, or DEFERRED", doneStatus)
 	}
 
+	// DEFERRED is how polecats hand off: record a checkpoint so the worker
+	// can be rewound to this point instead of nuked.
+	if exitType == ExitDeferred {
+		recordAutoCheckpoint(checkpoint.ReasonHandoff)
+	}
+
 	// Deferred session kill: ensures selfKillSession runs on ANY exit path for polecats.
 	// This is the backstop that prevents zombie sessions when push/MR failures cause
 	// early returns before the explicit selfKillSession call.
@@ -331,6 +342,17 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Join the sling's trace: GT_TRACEPARENT from the session, or the trace_id
+	// sling stored on the issue (sessions started before tracing, restarts).
+	doneSpan := tracing.Start(townRoot, "done", tracing.FromEnv())
+	if doneSpan != nil && !doneSpan.Context().IsValid() {
+		doneSpan.SetParent(tracing.Root(getTraceIDFromIssue(issueID, cwd)))
+	}
+	doneSpan.SetAttr("bead", issueID)
+	doneSpan.SetAttr("agent", sender)
+	doneSpan.SetAttr("exit", exitType)
+	defer func() { doneSpan.End(retErr) }()
+
 	// Write done-intent label EARLY, before push/MR operations.
 	// If gt done crashes after this point, the Witness can detect the intent
 	// and auto-nuke the zombie polecat.
@@ -704,6 +726,22 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			}
 		}
 
+		// Stacked MR: merges into the parent's target, after the parent.
+		// An unusable parent must not fall back to an unstacked MR, which
+		// would merge the parent's unreviewed commits along with this branch.
+		var stackLines string
+		if doneParent != "" {
+			parentFields, base, err := resolveStackParent(bd, g, doneParent, branch)
+			if err != nil {
+				errMsg := fmt.Sprintf("stacked MR not created: %v", err)
+				doneErrors = append(doneErrors, errMsg)
+				style.PrintWarning("%s\nBranch is pushed but MR bead not created. Witness will be notified.", errMsg)
+				goto notifyWitness
+			}
+			target = parentFields.Target
+			stackLines = fmt.Sprintf("\nparent_mr: %s\nstack_base: %s", doneParent, base)
+		}
+
 		// Get source issue for priority inheritance
 		var priority int
 		if donePriority >= 0 {
@@ -718,6 +756,9 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			}
 		}
 
+		// Flag likely conflicts with other in-flight branches before submitting
+		warnPredictedConflicts(g, filepath.Join(townRoot, rigName), branch, target)
+
 		// Check if MR bead already exists for this branch (idempotency)
 		existingMR, err := bd.FindMRForBranch(branch)
 		if err != nil {
@@ -741,6 +782,10 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			if traceID := doneSpan.Context().TraceID; traceID != "" {
+				description += fmt.Sprintf("\ntrace_id: %s", traceID)
+			}
+			description += stackLines
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -875,6 +920,9 @@ afterDoltMerge:
 	if len(doneErrors) > 0 {
 		bodyLines = append(bodyLines, fmt.Sprintf("Errors: %s", strings.Join(doneErrors, "; ")))
 	}
+	if traceParent := doneSpan.Context().TraceParent(); traceParent != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("Trace: %s", traceParent))
+	}
 
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
@@ -1287,6 +1335,23 @@ func getIssueFromAgentHook(bd *beads.Beads, agentBeadID string) string {
 	return agentBead.HookBead
 }
 
+// getTraceIDFromIssue returns the trace_id sling stored on an issue, or "".
+func getTraceIDFromIssue(issueID, cwd string) string {
+	if issueID == "" {
+		return ""
+	}
+	bd := beads.New(beads.ResolveBeadsDir(cwd))
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return ""
+	}
+	attachment := beads.ParseAttachmentFields(issue)
+	if attachment == nil {
+		return ""
+	}
+	return attachment.TraceID
+}
+
 // parseCleanupStatus converts a string flag value to a CleanupStatus.
 // ZFC: Agent observes git state and passes the appropriate status.
 func parseCleanupStatus(s string) polecat.CleanupStatus {
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/eventquery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var eventsQueryJSON bool
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the town's event logs",
+	RunE:    requireSubcommand,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query <query>",
+	Short: "Filter, count and bucket events from .events.jsonl and the town log",
+	Long: `Run a query over the events log (.events.jsonl) and the town log.
+
+A query is a list of filters, optionally followed by pipeline stages:
+
+  <filters> [| count [by <field>[,<field>...]] [per <duration>]] [| limit <n>]
+
+Filters (ANDed together):
+  field:value        Match a field; values may list alternatives (a,b)
+                     and use * and ? globs. Case-insensitive.
+  -field:value       Exclude matches
+  word, "a phrase"   Substring match on any field or payload value
+  since:<t>          Events at or after t: an age (30m, 2h, 3d) or a date
+  until:<t>          Events at or before t   (2026-05-01, 2026-05-01T14:00)
+
+Fields: type, actor (or agent), rig, log (events|townlog), source,
+visibility, context, or any payload key (e.g. bead, payload.target).
+The rig is the first segment of the actor, or the payload's rig.
+
+Stages:
+  count              Count matches, optionally grouped by fields and/or
+                     bucketed into time windows (per 1h)
+  limit <n>          Keep the n most recent events, or the first n groups
+
+Queries starting with a negated filter need -- so the flag parser
+leaves them alone. Archived events are read when since: is set.
+
+Examples:
+  gt events query 'type:done actor:*/polecats/* rig:gastown since:2h | count by actor'
+  gt events query 'type:crash,kill since:1d | count by rig per 1h'
+  gt events query 'bead:gt-abc12'
+  gt events query -- '-actor:mayor type:sling since:30m | limit 20'
+  gt events query --json 'type:done since:7d | count by rig'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEventsQuery,
+}
+
+func init() {
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	res, err := eventquery.Run(townRoot, strings.Join(args, " "), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if eventsQueryJSON {
+		return outputJSON(res)
+	}
+	if res.Counted {
+		printEventGroups(res)
+		return nil
+	}
+
+	if len(res.Records) == 0 {
+		fmt.Println("No matching events.")
+		return nil
+	}
+	for _, r := range res.Records {
+		fmt.Printf("%s %s %s%s\n",
+			style.Dim.Render(r.Time.Local().Format("2006-01-02 15:04:05")),
+			style.Bold.Render(fmt.Sprintf("[%s]", r.Type)),
+			r.Actor, eventRecordDetail(r))
+	}
+	if len(res.Records) < res.Matched {
+		fmt.Println(style.Dim.Render(fmt.Sprintf("(showing %d of %d matching events)", len(res.Records), res.Matched)))
+	}
+	return nil
+}
+
+func printEventGroups(res *eventquery.Result) {
+	if len(res.By) == 0 && res.Per == "" {
+		fmt.Println(res.Groups[0].Count)
+		return
+	}
+
+	var header []string
+	if res.Per != "" {
+		header = append(header, "BUCKET")
+	}
+	for _, f := range res.By {
+		header = append(header, strings.ToUpper(f))
+	}
+	header = append(header, "COUNT")
+
+	rows := [][]string{header}
+	for _, g := range res.Groups {
+		var row []string
+		if g.Bucket != nil {
+			row = append(row, g.Bucket.Local().Format("2006-01-02 15:04"))
+		}
+		for _, k := range g.Keys {
+			if k == "" {
+				k = "-"
+			}
+			row = append(row, k)
+		}
+		rows = append(rows, append(row, fmt.Sprint(g.Count)))
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for i, row := range rows {
+		var line strings.Builder
+		for j, cell := range row {
+			if j == len(row)-1 {
+				fmt.Fprintf(&line, "%*s", widths[j], cell)
+			} else {
+				fmt.Fprintf(&line, "%-*s  ", widths[j], cell)
+			}
+		}
+		if i == 0 {
+			fmt.Println(style.Bold.Render(line.String()))
+		} else {
+			fmt.Println(line.String())
+		}
+	}
+	fmt.Println(style.Dim.Render(fmt.Sprintf("%d matching events in %d groups", res.Matched, len(res.Groups))))
+}
+
+// eventRecordDetail renders a record's context or payload on one line.
+func eventRecordDetail(r eventquery.Record) string {
+	if r.Context != "" {
+		return " " + r.Context
+	}
+	if len(r.Payload) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(r.Payload))
+	for k := range r.Payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, r.Payload[k]))
+	}
+	return " " + style.Dim.Render(strings.Join(parts, " "))
+}
@@ -67,6 +67,10 @@ const HQGitignore = `# Gas Town HQ .gitignore
 **/activity.json
 .events.jsonl
 .feed.jsonl
+.krc-archive/
+.recordings/
+.seance/
+.dolt-backups/
 
 # =============================================================================
 # Runtime state directories
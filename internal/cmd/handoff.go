@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
@@ -249,6 +250,9 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Record a checkpoint so the worker can be rewound to this handoff
+	recordAutoCheckpoint(checkpoint.ReasonHandoff)
+
 	// Send handoff mail to self (defaults applied inside sendHandoffMail).
 	// The mail is auto-hooked so the next session picks it up.
 	beadID, err := sendHandoffMail(handoffSubject, handoffMessage)
@@ -338,6 +342,9 @@ func runHandoffAuto() error {
 		return nil
 	}
 
+	// Record a checkpoint so the worker can be rewound to this handoff
+	recordAutoCheckpoint(checkpoint.ReasonHandoff)
+
 	// Send handoff mail to self
 	beadID, err := sendHandoffMail(subject, message)
 	if err != nil {
@@ -441,6 +448,9 @@ func runHandoffCycle() error {
 		return nil
 	}
 
+	// Record a checkpoint so the worker can be rewound to this handoff
+	recordAutoCheckpoint(checkpoint.ReasonHandoff)
+
 	// Send handoff mail to self (auto-hooked for successor)
 	beadID, err := sendHandoffMail(subject, message)
 	if err != nil {
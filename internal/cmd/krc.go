@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/krc"
+	"github.com/steveyegge/gastown/internal/recording"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -23,15 +24,21 @@ and other operational data that decays in forensic value over days.
 
 KRC provides:
   - Configurable TTLs per event type
-  - Auto-pruning of expired events
+  - Auto-pruning of expired events into a compressed archive
   - Statistics on ephemeral data lifecycle
 
+Events past their TTL are moved to gzip segments in .krc-archive/ (one per
+day) and only deleted after the cold TTL (default 90d). gt audit, gt trail
+hooks and gt feed --since read archived events transparently.
+
 Examples:
   gt krc stats              # Show event statistics
   gt krc prune              # Remove expired events
   gt krc prune --dry-run    # Preview what would be pruned
   gt krc config             # Show TTL configuration
-  gt krc config set patrol_* 12h   # Set TTL for patrol events`,
+  gt krc config set patrol_* 12h   # Set TTL for patrol events
+  gt krc config set cold 180d      # Keep archived events for 180 days
+  gt krc config set recordings 3d  # Keep pane recordings for 3 days`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	},
@@ -51,10 +58,13 @@ Shows:
 
 var krcPruneCmd = &cobra.Command{
 	Use:   "prune",
-	Short: "Remove expired events",
+	Short: "Archive or remove expired events",
 	Long: `Prune events that have exceeded their TTL.
 
-Events are removed from both .events.jsonl and .feed.jsonl.
+Events are removed from both .events.jsonl and .feed.jsonl and moved to
+the compressed archive in .krc-archive/, unless they are also past the
+cold TTL. Archive segments past the cold TTL are deleted, as are pane
+recordings (.recordings/) past the recording TTL.
 The operation is atomic (uses temp files and rename).
 
 Use --dry-run to preview what would be pruned without making changes.`,
@@ -76,6 +86,7 @@ Examples:
   gt krc config                     # Show current config
   gt krc config set patrol_* 12h    # Set patrol TTL to 12 hours
   gt krc config set default 3d      # Set default TTL to 3 days
+  gt krc config set cold 0          # Delete expired events instead of archiving
   gt krc config reset               # Reset to defaults`,
 	RunE: runKrcConfig,
 }
@@ -86,7 +97,9 @@ var krcConfigSetCmd = &cobra.Command{
 	Long: `Set the TTL for events matching the given pattern.
 
 Patterns support glob-style matching with * (e.g., "patrol_*" matches all patrol events).
-Use "default" as the pattern to set the default TTL.
+Use "default" as the pattern to set the default TTL, "cold" to set how long
+expired events stay in the archive (0 disables archiving), or "recordings"
+to set how long pane recordings are kept (0 keeps them).
 
 TTL format: 1h, 12h, 1d, 7d, 30d, etc.`,
 	Args: cobra.ExactArgs(2),
@@ -184,6 +197,10 @@ func runKrcStats(cmd *cobra.Command, args []string) error {
 	fmt.Println(style.Bold.Render("Files:"))
 	fmt.Printf("  Events: %s (%d events)\n", formatBytes(stats.EventsFile.Size), stats.EventsFile.EventCount)
 	fmt.Printf("  Feed:   %s (%d events)\n", formatBytes(stats.FeedFile.Size), stats.FeedFile.EventCount)
+	fmt.Printf("  Archive: %s (%d events in %d segments)\n", formatBytes(stats.Archive.Bytes), stats.Archive.Events, stats.Archive.Segments)
+	if !stats.Archive.OldestEvent.IsZero() {
+		fmt.Printf("           oldest %s (%s ago)\n", stats.Archive.OldestEvent.Format(time.RFC3339), krcFormatDuration(time.Since(stats.Archive.OldestEvent)))
+	}
 	fmt.Println()
 
 	// Age distribution
@@ -286,6 +303,9 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Println()
 		fmt.Printf("Total: %d events would be pruned\n", totalExpired)
+		if config.ColdTTL > 0 {
+			fmt.Printf("Events younger than %s would be moved to %s\n", krcFormatDuration(config.ColdTTL), krc.ArchiveDir)
+		}
 		fmt.Println()
 		fmt.Println("Run without --dry-run to prune.")
 		return nil
@@ -298,7 +318,7 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("pruning: %w", err)
 	}
 
-	if result.EventsPruned == 0 {
+	if result.EventsPruned == 0 && result.SegmentsExpired == 0 && result.RecordingsExpired == 0 {
 		fmt.Println("No expired events to prune.")
 		return nil
 	}
@@ -306,7 +326,14 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 	fmt.Println(style.Bold.Render("Prune complete:"))
 	fmt.Printf("  Events processed: %d\n", result.EventsProcessed)
 	fmt.Printf("  Events pruned:    %d\n", result.EventsPruned)
+	fmt.Printf("  Events archived:  %d\n", result.EventsArchived)
 	fmt.Printf("  Events retained:  %d\n", result.EventsRetained)
+	if result.SegmentsExpired > 0 {
+		fmt.Printf("  Archive expired:  %d events in %d segments\n", result.ArchiveEventsExpired, result.SegmentsExpired)
+	}
+	if result.RecordingsExpired > 0 {
+		fmt.Printf("  Recordings:       %d segments (%s)\n", result.RecordingsExpired, formatBytes(result.RecordingBytesFreed))
+	}
 	fmt.Printf("  Space saved:      %s\n", formatBytes(result.BytesBefore-result.BytesAfter))
 	fmt.Printf("  Duration:         %s\n", result.Duration.Round(time.Millisecond))
 
@@ -349,6 +376,16 @@ func runKrcConfig(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Default TTL:     %s\n", krcFormatDuration(config.DefaultTTL))
 	fmt.Printf("Prune interval:  %s\n", krcFormatDuration(config.PruneInterval))
 	fmt.Printf("Min retain:      %d events\n", config.MinRetainCount)
+	if config.ColdTTL > 0 {
+		fmt.Printf("Cold TTL:        %s (archived in %s)\n", krcFormatDuration(config.ColdTTL), krc.ArchiveDir)
+	} else {
+		fmt.Printf("Cold TTL:        off (expired events are deleted)\n")
+	}
+	if config.RecordingTTL > 0 {
+		fmt.Printf("Recording TTL:   %s (%s)\n", krcFormatDuration(config.RecordingTTL), recording.Dir)
+	} else {
+		fmt.Printf("Recording TTL:   off (recordings are kept)\n")
+	}
 	fmt.Println()
 	fmt.Println(style.Bold.Render("TTLs by pattern:"))
 
@@ -385,10 +422,25 @@ func runKrcConfigSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	if pattern == "default" {
+	switch pattern {
+	case "default":
 		config.DefaultTTL = ttl
 		fmt.Printf("Set default TTL to %s\n", krcFormatDuration(ttl))
-	} else {
+	case "cold":
+		config.ColdTTL = ttl
+		if ttl == 0 {
+			fmt.Println("Disabled archiving: expired events will be deleted")
+		} else {
+			fmt.Printf("Set cold TTL to %s\n", krcFormatDuration(ttl))
+		}
+	case "recordings":
+		config.RecordingTTL = ttl
+		if ttl == 0 {
+			fmt.Println("Disabled recording pruning: recordings will be kept")
+		} else {
+			fmt.Printf("Set recording TTL to %s\n", krcFormatDuration(ttl))
+		}
+	default:
 		if config.TTLs == nil {
 			config.TTLs = make(map[string]time.Duration)
 		}
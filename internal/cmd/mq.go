@@ -23,6 +23,7 @@ var (
 	mqSubmitEpic      string
 	mqSubmitPriority  int
 	mqSubmitNoCleanup bool
+	mqSubmitParent    string
 
 	// Retry flags
 	mqRetryNow bool
@@ -39,6 +40,8 @@ var (
 	mqListEpic    string
 	mqListJSON    bool
 	mqListVerify  bool
+	mqListExplain bool
+	mqListPolicy  string
 
 	// Status command flags
 	mqStatusJSON bool
@@ -106,7 +109,8 @@ Examples:
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
   gt mq submit --priority 0              # Override priority (P0)
-  gt mq submit --no-cleanup              # Submit without auto-cleanup`,
+  gt mq submit --no-cleanup              # Submit without auto-cleanup
+  gt mq submit --parent gt-mr123         # Stack on an unmerged MR (see 'gt mq stack')`,
 	RunE: runMqSubmit,
 }
 
@@ -143,7 +147,20 @@ Examples:
   gt mq list greenplace
   gt mq list greenplace --ready
   gt mq list greenplace --status=open
-  gt mq list greenplace --worker=Nux`,
+  gt mq list greenplace --worker=Nux
+  gt mq list greenplace --explain
+  gt mq list greenplace --explain --policy=smallest-diff
+
+Ordering follows the rig's refinery score policy, set in the rig settings
+as merge_queue.score_policy:
+  default            convoy age, priority, retry count and MR age
+  smallest-diff      prefer MRs with the smallest diff
+  convoy-completion  prefer MRs that close out a convoy
+  critical-path      prefer MRs that unblock the longest chain of convoy work
+  fair-share         round-robin between workers
+
+Every policy builds on the default formula. Use --explain to see each
+MR's score broken down by factor.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMQList,
 }
@@ -285,6 +302,7 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().StringVar(&mqSubmitParent, "parent", "", "Parent MR this branch is stacked on; merges after it")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -296,6 +314,8 @@ func init() {
 	mqListCmd.Flags().StringVar(&mqListEpic, "epic", "", "Show MRs targeting integration/<epic>")
 	mqListCmd.Flags().BoolVar(&mqListJSON, "json", false, "Output as JSON")
 	mqListCmd.Flags().BoolVar(&mqListVerify, "verify", false, "Verify branches exist in git (shows MISSING for deleted branches)")
+	mqListCmd.Flags().BoolVar(&mqListExplain, "explain", false, "Show the score breakdown for each MR")
+	mqListCmd.Flags().StringVar(&mqListPolicy, "policy", "", "Score with this policy instead of the rig's (default, smallest-diff, convoy-completion, critical-path, fair-share)")
 
 	// Reject flags
 	mqRejectCmd.Flags().StringVarP(&mqRejectReason, "reason", "r", "", "Reason for rejection (required unless --stdin)")
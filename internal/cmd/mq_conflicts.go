@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ conflicts command flags
+var (
+	mqConflictsJSON   bool
+	mqConflictsCached bool
+	mqConflictsRecord bool
+)
+
+var mqConflictsCmd = &cobra.Command{
+	Use:   "conflicts <rig>",
+	Short: "Show predicted conflicts between in-flight branches",
+	Long: `Show the overlap graph of in-flight work in a rig.
+
+Compares the changed files and hunks of every polecat branch pushed to
+origin and every open MR against its target branch. Branches that change
+overlapping (or adjacent) lines of the same file are likely to conflict;
+branches that only change the same files are flagged as possible conflicts.
+
+The result is cached in the rig's .runtime/conflict-forecast.json. The daemon
+refreshes it periodically, and gt sling, gt done and gt mq submit use it to
+warn about overlapping work before it reaches the merge queue.
+
+Examples:
+  gt mq conflicts gastown             # Recompute and show the overlap graph
+  gt mq conflicts gastown --cached    # Show the last recorded forecast
+  gt mq conflicts gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQConflicts,
+}
+
+func init() {
+	mqConflictsCmd.Flags().BoolVar(&mqConflictsJSON, "json", false, "Output as JSON")
+	mqConflictsCmd.Flags().BoolVar(&mqConflictsCached, "cached", false, "Show the last recorded forecast instead of recomputing")
+	mqConflictsCmd.Flags().BoolVar(&mqConflictsRecord, "record", false, "Recompute and save the forecast, printing only a summary (used by the daemon)")
+
+	mqCmd.AddCommand(mqConflictsCmd)
+}
+
+func runMQConflicts(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	var forecast *refinery.ConflictForecast
+	if mqConflictsCached {
+		forecast, err = refinery.LoadConflictForecast(r.Path)
+		if err != nil {
+			return err
+		}
+		if forecast == nil {
+			return fmt.Errorf("no conflict forecast recorded for %s yet; run 'gt mq conflicts %s'", rigName, rigName)
+		}
+	} else {
+		eng := refinery.NewEngineer(r)
+		eng.SetOutput(os.Stderr)
+		forecast, err = eng.ForecastConflicts()
+		if err != nil {
+			return fmt.Errorf("forecasting conflicts: %w", err)
+		}
+		if err := refinery.SaveConflictForecast(r.Path, forecast); err != nil {
+			return fmt.Errorf("saving conflict forecast: %w", err)
+		}
+	}
+
+	if mqConflictsRecord {
+		likely := 0
+		for _, o := range forecast.Overlaps {
+			if o.Likely() {
+				likely++
+			}
+		}
+		if likely > 0 {
+			fmt.Printf("%d branches, %d overlaps, %d likely conflicts\n", len(forecast.Branches), len(forecast.Overlaps), likely)
+		}
+		return nil
+	}
+
+	if mqConflictsJSON {
+		return outputJSON(forecast)
+	}
+
+	printConflictForecast(forecast)
+	return nil
+}
+
+// printConflictForecast renders the overlap graph as an adjacency list.
+func printConflictForecast(f *refinery.ConflictForecast) {
+	fmt.Printf("%s Conflict forecast for '%s' (%d branches in flight, as of %s):\n\n",
+		style.Bold.Render("⚔"), f.Rig, len(f.Branches), f.GeneratedAt.Local().Format(time.Kitchen))
+
+	if len(f.Overlaps) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no overlapping work)"))
+		return
+	}
+
+	for _, b := range f.Branches {
+		overlaps := f.OverlapsFor(b.Branch)
+		if len(overlaps) == 0 {
+			continue
+		}
+		label := b.Branch
+		if b.MRID != "" {
+			label += " " + style.Dim.Render("("+b.MRID+")")
+		} else {
+			label += " " + style.Dim.Render("(not submitted)")
+		}
+		fmt.Printf("  %s\n", label)
+		for _, o := range overlaps {
+			marker := style.Warning.Render("~")
+			if o.Likely() {
+				marker = style.Error.Render("✗")
+			}
+			fmt.Printf("    %s %s  %s\n", marker, o.Other(b.Branch), describeOverlapFiles(o.Files))
+		}
+	}
+
+	fmt.Printf("\n  %s likely conflict (overlapping hunks)   %s same files\n",
+		style.Error.Render("✗"), style.Warning.Render("~"))
+}
+
+// describeOverlapFiles lists overlapping files, marking those with
+// overlapping hunks, e.g. "internal/git/git.go (hunks), docs/a.md".
+func describeOverlapFiles(files []refinery.FileOverlap) string {
+	const maxFiles = 4
+	parts := make([]string, 0, maxFiles+1)
+	for i, f := range files {
+		if i == maxFiles {
+			parts = append(parts, fmt.Sprintf("+%d more", len(files)-maxFiles))
+			break
+		}
+		if f.Hunks {
+			parts = append(parts, f.Path+" (hunks)")
+		} else {
+			parts = append(parts, f.Path)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// loadFreshConflictForecast returns the rig's cached forecast if it is recent
+// enough to warn from, or nil.
+func loadFreshConflictForecast(rigPath string) *refinery.ConflictForecast {
+	forecast, err := refinery.LoadConflictForecast(rigPath)
+	if err != nil || forecast == nil || time.Since(forecast.GeneratedAt) > refinery.ConflictForecastMaxAge {
+		return nil
+	}
+	return forecast
+}
+
+// warnPredictedConflicts warns when branch overlaps other in-flight work in
+// the rig's conflict forecast. Best-effort: silent when there is no recent
+// forecast or the diff can't be computed.
+func warnPredictedConflicts(g *git.Git, rigPath, branch, target string) {
+	forecast := loadFreshConflictForecast(rigPath)
+	if forecast == nil {
+		return
+	}
+	files, err := g.DiffHunks("origin/"+target, branch)
+	if err != nil || len(files) == 0 {
+		return
+	}
+	overlaps := forecast.Compare(&refinery.BranchChanges{Branch: branch, Target: target, Files: files})
+	var likely []string
+	for _, o := range overlaps {
+		if o.Likely() {
+			likely = append(likely, fmt.Sprintf("%s: %s", o.Other(branch), describeOverlapFiles(o.Files)))
+		}
+	}
+	if len(likely) == 0 {
+		return
+	}
+	style.PrintWarning("%s likely conflicts with in-flight work:\n  %s\nRebasing after those merge may be needed (see 'gt mq conflicts %s')",
+		branch, strings.Join(likely, "\n  "), filepath.Base(rigPath))
+}
+
+// warnSlingOverlap warns when the paths a bead mentions are already being
+// changed by in-flight branches in the rig. Best-effort, cache only.
+func warnSlingOverlap(townRoot, rigName string, info *beadInfo) {
+	if info == nil {
+		return
+	}
+	paths := polecat.PathTokens(info.Title + "\n" + info.Description)
+	if len(paths) == 0 {
+		return
+	}
+	forecast := loadFreshConflictForecast(filepath.Join(townRoot, rigName))
+	if forecast == nil {
+		return
+	}
+	touching := forecast.BranchesTouching(paths)
+	if len(touching) == 0 {
+		return
+	}
+	var lines []string
+	for _, b := range forecast.Branches {
+		files, ok := touching[b.Branch]
+		if !ok {
+			continue
+		}
+		owner := b.Branch
+		if b.MRID != "" {
+			owner += " (" + b.MRID + ")"
+		}
+		overlaps := make([]refinery.FileOverlap, len(files))
+		for i, f := range files {
+			overlaps[i] = refinery.FileOverlap{Path: f}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", owner, describeOverlapFiles(overlaps)))
+	}
+	style.PrintWarning("files this bead targets are already being edited:\n  %s", strings.Join(lines, "\n  "))
+}
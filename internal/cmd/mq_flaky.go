@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ flaky command flags
+var (
+	mqFlakyJSON        bool
+	mqFlakyAll         bool
+	mqFlakyReason      string
+	mqFlakyReportForce bool
+)
+
+var mqFlakyCmd = &cobra.Command{
+	Use:   "flaky",
+	Short: "Track and quarantine flaky tests in the merge queue",
+	RunE:  requireSubcommand,
+	Long: `Track and quarantine flaky tests in the merge queue.
+
+When a quality gate with a junit or tap format fails, the refinery records
+the failing tests and re-runs the gate on the target branch. Tests that fail
+there too are not the MR's fault and don't block the merge. A test that
+fails on the target branch repeatedly (merge_queue.quarantine_threshold,
+default 2) is quarantined: its failures no longer block any merge until it
+is released.
+
+A weekly report bead listing flaky tests is filed for the rig's crew.
+
+Commands:
+  list        Show flaky and quarantined tests
+  quarantine  Quarantine a test by hand
+  release     Lift a test's quarantine
+  report      File the flaky-test report bead`,
+}
+
+var mqFlakyListCmd = &cobra.Command{
+	Use:   "list <rig>",
+	Short: "Show flaky and quarantined tests",
+	Long: `Show tests that are quarantined or have failed on the target branch.
+
+Test names are "<gate>: <test>", as shown in merge failure mail.
+
+Examples:
+  gt mq flaky list gastown
+  gt mq flaky list gastown --all    # Every test that has ever failed`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQFlakyList,
+}
+
+var mqFlakyQuarantineCmd = &cobra.Command{
+	Use:   "quarantine <rig> <test>",
+	Short: "Quarantine a test so its failures don't block merges",
+	Long: `Quarantine a test so its failures don't block merges.
+
+Examples:
+  gt mq flaky quarantine gastown "test: internal/git.TestFetch" --reason "network"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQFlakyQuarantine,
+}
+
+var mqFlakyReleaseCmd = &cobra.Command{
+	Use:   "release <rig> <test>",
+	Short: "Lift a test's quarantine",
+	Long: `Lift a test's quarantine once it has been fixed. Its failures block
+merges again, and it must fail on the target branch again to be
+re-quarantined automatically.
+
+Examples:
+  gt mq flaky release gastown "test: internal/git.TestFetch"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQFlakyRelease,
+}
+
+var mqFlakyReportCmd = &cobra.Command{
+	Use:   "report <rig>",
+	Short: "File the flaky-test report bead for the rig's crew",
+	Long: `File a bead listing the rig's flaky and quarantined tests and mail the
+rig's crew about it.
+
+Does nothing if there are no flaky tests or a report was filed in the last
+week, unless --force is given. The daemon runs this periodically.
+
+Examples:
+  gt mq flaky report gastown
+  gt mq flaky report gastown --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQFlakyReport,
+}
+
+func init() {
+	mqFlakyListCmd.Flags().BoolVar(&mqFlakyJSON, "json", false, "Output as JSON")
+	mqFlakyListCmd.Flags().BoolVar(&mqFlakyAll, "all", false, "Include every test that has failed, not just flaky ones")
+	mqFlakyQuarantineCmd.Flags().StringVar(&mqFlakyReason, "reason", "", "Why the test is quarantined")
+	mqFlakyReportCmd.Flags().BoolVar(&mqFlakyReportForce, "force", false, "File a report even if one was filed this week")
+
+	mqFlakyCmd.AddCommand(mqFlakyListCmd)
+	mqFlakyCmd.AddCommand(mqFlakyQuarantineCmd)
+	mqFlakyCmd.AddCommand(mqFlakyReleaseCmd)
+	mqFlakyCmd.AddCommand(mqFlakyReportCmd)
+	mqCmd.AddCommand(mqFlakyCmd)
+}
+
+func runMQFlakyList(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	history, err := refinery.LoadFlakyHistory(r.Path)
+	if err != nil {
+		return err
+	}
+
+	tests := history.Flaky()
+	if mqFlakyAll {
+		tests = make([]*refinery.TestRecord, 0, len(history.Tests))
+		for _, rec := range history.Tests {
+			tests = append(tests, rec)
+		}
+		sort.Slice(tests, func(i, j int) bool { return tests[i].Name < tests[j].Name })
+	}
+
+	if mqFlakyJSON {
+		return outputJSON(tests)
+	}
+
+	if len(tests) == 0 {
+		fmt.Printf("%s No flaky tests recorded for '%s'\n", style.Success.Render("✓"), rigName)
+		return nil
+	}
+	fmt.Printf("%s Flaky tests in '%s':\n\n", style.Bold.Render("🎲"), rigName)
+	for _, rec := range tests {
+		marker := style.Warning.Render("~")
+		if rec.Quarantined {
+			marker = style.Dim.Render("⊘")
+		}
+		fmt.Printf("  %s %s\n", marker, rec.Name)
+		fmt.Printf("      %d/%d runs failed, %d on the target branch, last failed %s\n",
+			rec.Failures, rec.Passes+rec.Failures, rec.BaseFailures, formatFlakyTime(rec.LastFailedAt))
+		if rec.Quarantined {
+			fmt.Printf("      %s\n", style.Dim.Render(fmt.Sprintf("quarantined %s: %s", formatFlakyTime(rec.QuarantinedAt), rec.QuarantineReason)))
+		}
+	}
+	fmt.Printf("\n  %s quarantined (doesn't block merges)   %s failed on the target branch\n",
+		style.Dim.Render("⊘"), style.Warning.Render("~"))
+	return nil
+}
+
+func formatFlakyTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func runMQFlakyQuarantine(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	name := args[1]
+	reason := mqFlakyReason
+	if reason == "" {
+		reason = "quarantined by hand"
+	}
+	err = refinery.UpdateFlakyHistory(r.Path, func(h *refinery.FlakyHistory) error {
+		if h.IsQuarantined(name) {
+			return fmt.Errorf("%q is already quarantined", name)
+		}
+		h.Quarantine(name, reason, time.Now().UTC())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(name, ": ") {
+		style.PrintWarning("test names are \"<gate>: <test>\" (see 'gt mq flaky list %s --all'); %q may never match", r.Name, name)
+	}
+	fmt.Printf("%s Quarantined %s\n", style.Success.Render("✓"), name)
+	return nil
+}
+
+func runMQFlakyRelease(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	name := args[1]
+	err = refinery.UpdateFlakyHistory(r.Path, func(h *refinery.FlakyHistory) error {
+		if !h.Release(name) {
+			return fmt.Errorf("%q is not quarantined", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Released %s; its failures block merges again\n", style.Success.Render("✓"), name)
+	return nil
+}
+
+func runMQFlakyReport(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	history, err := refinery.LoadFlakyHistory(r.Path)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if len(history.Flaky()) == 0 || (!mqFlakyReportForce && !history.ReportDue(now)) {
+		return nil
+	}
+
+	id, err := fileFlakyReport(r, history, now)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Filed flaky test report %s (%d tests)\n", style.Success.Render("✓"), id, len(history.Flaky()))
+	err = refinery.UpdateFlakyHistory(r.Path, func(h *refinery.FlakyHistory) error {
+		h.LastReportAt = now
+		return nil
+	})
+	if err != nil {
+		style.PrintWarning("could not record report time: %v", err)
+	}
+
+	townRoot := filepath.Dir(r.Path)
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/refinery", rigName),
+		fmt.Sprintf("@crew/%s", rigName),
+		fmt.Sprintf("Flaky test report: %s", rigName),
+		fmt.Sprintf("The weekly flaky test report for %s is in %s.\n\n%s", rigName, id, refinery.FormatFlakyReport(rigName, history)),
+	)
+	if err := mail.NewRouter(townRoot).Send(msg); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not mail the crew about %s: %v\n", id, err)
+	}
+	return nil
+}
+
+// fileFlakyReport creates the report bead in the rig's beads.
+func fileFlakyReport(r *rig.Rig, history *refinery.FlakyHistory, now time.Time) (string, error) {
+	bd := beads.New(r.Path)
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Flaky test report: %s week of %s", r.Name, now.Format("2006-01-02")),
+		Type:        "task",
+		Priority:    3,
+		Description: refinery.FormatFlakyReport(r.Name, history),
+		Actor:       r.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating report bead: %w", err)
+	}
+	if err := bd.Update(issue.ID, beads.UpdateOptions{AddLabels: []string{"gt:flaky-report"}}); err != nil {
+		style.PrintWarning("could not label %s: %v", issue.ID, err)
+	}
+	return issue.ID, nil
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ forge command flags
+var (
+	mqForgeJSON bool
+)
+
+var mqForgeCmd = &cobra.Command{
+	Use:   "forge",
+	Short: "Mirror merge requests as pull requests on the rig's forge",
+	RunE:  requireSubcommand,
+	Long: `Mirror merge requests as pull requests on the rig's forge.
+
+A rig's forge is configured in <rig>/settings/config.json:
+
+  "forge": {
+    "provider": "github",              // github, gitlab, or gitea
+    "repo": "acme/widgets",
+    "api_url": "",                     // required for gitea and self-hosted forges
+    "token_env": "GITHUB_TOKEN",       // default: <PROVIDER>_TOKEN
+    "mirror_mrs": true,                // open a PR for every MR
+    "required_checks": ["ci/build"],   // "*" = every reported check
+    "required_approvals": 1
+  }
+
+With required checks or approvals set, the refinery only merges an MR once
+its pull request passes them. Pending checks and reviews keep the MR waiting
+in the queue; failed checks and change requests fail it like a failing gate.
+New review comments are mailed to the MR's polecat.
+
+Commands:
+  sync   Open missing PRs and relay new review comments
+  check  Show whether an MR's PR may merge
+  open   Open the PR for one MR`,
+}
+
+var mqForgeSyncCmd = &cobra.Command{
+	Use:   "sync <rig>",
+	Short: "Open missing PRs and relay new review comments",
+	Long: `Open pull requests for open MRs that have none (when mirror_mrs is set),
+and mail each MR's polecat the comments and reviews posted on its PR since
+the last sync. The refinery runs this each patrol cycle.
+
+Examples:
+  gt mq forge sync gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQForgeSync,
+}
+
+var mqForgeCheckCmd = &cobra.Command{
+	Use:   "check <rig> <mr-id>",
+	Short: "Show whether an MR's PR may merge",
+	Long: `Show an MR's pull request, its CI checks, its reviews, and the verdict
+under the rig's required checks and approvals.
+
+Exit status is 0 when the MR may merge, 1 when checks failed or changes
+were requested, and 2 while checks or reviews are pending.
+
+Examples:
+  gt mq forge check gastown gt-mr-abc
+  gt mq forge check gastown gt-mr-abc --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQForgeCheck,
+}
+
+var mqForgeOpenCmd = &cobra.Command{
+	Use:   "open <rig> <mr-id>",
+	Short: "Open the PR for one MR",
+	Long: `Open a pull request for an MR (or find the open one for its branch) and
+record it on the MR bead.
+
+Examples:
+  gt mq forge open gastown gt-mr-abc`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQForgeOpen,
+}
+
+func init() {
+	mqForgeSyncCmd.Flags().BoolVar(&mqForgeJSON, "json", false, "Output as JSON")
+	mqForgeCheckCmd.Flags().BoolVar(&mqForgeJSON, "json", false, "Output as JSON")
+	mqForgeOpenCmd.Flags().BoolVar(&mqForgeJSON, "json", false, "Output as JSON")
+
+	mqForgeCmd.AddCommand(mqForgeSyncCmd)
+	mqForgeCmd.AddCommand(mqForgeCheckCmd)
+	mqForgeCmd.AddCommand(mqForgeOpenCmd)
+	mqCmd.AddCommand(mqForgeCmd)
+}
+
+func runMQForgeSync(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	if eng.ForgeConfig() == nil {
+		if mqForgeJSON {
+			return outputJSON([]refinery.ForgeSyncResult{})
+		}
+		fmt.Printf("%s No forge configured for '%s'\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+	results, err := eng.SyncForge(context.Background())
+	if err != nil {
+		return err
+	}
+	if mqForgeJSON {
+		return outputJSON(results)
+	}
+
+	for _, res := range results {
+		switch {
+		case res.Error != "":
+			fmt.Printf("  %s %s: %s\n", style.Error.Render("✗"), res.MR.ID, res.Error)
+		case res.Opened:
+			fmt.Printf("  %s %s: opened PR #%d %s\n", style.Success.Render("✓"), res.MR.ID, res.PR, style.Dim.Render(res.URL))
+		case res.Relayed > 0:
+			fmt.Printf("  %s %s: mailed %d review update(s) to %s\n", style.Success.Render("✓"), res.MR.ID, res.Relayed, res.MR.Worker)
+		}
+	}
+	fmt.Printf("%s Synced %d MR(s) with the forge in '%s'\n", style.Success.Render("✓"), len(results), rigName)
+	return nil
+}
+
+func runMQForgeCheck(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	mrID := args[1]
+	eng := refinery.NewEngineer(r)
+	issue, err := beads.New(r.Path).Show(mrID)
+	if err != nil {
+		return fmt.Errorf("fetching MR %s: %w", mrID, err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return fmt.Errorf("%s is not a merge request", mrID)
+	}
+	status, err := eng.ForgeCheck(context.Background(), refinery.MRInfoFromIssue(issue, fields))
+	if err != nil {
+		return err
+	}
+
+	if mqForgeJSON {
+		if err := outputJSON(status); err != nil {
+			return err
+		}
+	} else {
+		printForgeStatus(status)
+	}
+
+	switch status.Verdict.State {
+	case forge.VerdictFail:
+		return NewSilentExit(1)
+	case forge.VerdictPending:
+		return NewSilentExit(2)
+	}
+	return nil
+}
+
+func printForgeStatus(s *refinery.ForgeStatus) {
+	if s.PR != nil {
+		fmt.Printf("%s PR #%d (%s) %s\n", style.Bold.Render(s.MR.ID), s.PR.Number, s.PR.State, style.Dim.Render(s.PR.URL))
+	} else {
+		fmt.Printf("%s %s\n", style.Bold.Render(s.MR.ID), style.Dim.Render("no pull request"))
+	}
+	if len(s.Checks) > 0 {
+		fmt.Println("\nChecks:")
+		for _, c := range s.Checks {
+			fmt.Printf("  %s %s\n", forgeStateIcon(c.State), c.Name)
+		}
+	}
+	if len(s.Reviews) > 0 {
+		fmt.Println("\nReviews:")
+		for _, r := range s.Reviews {
+			fmt.Printf("  %s %s %s\n", forgeStateIcon(r.State), r.Author, style.Dim.Render(r.State))
+		}
+	}
+	fmt.Printf("\n%s %s\n", forgeStateIcon(s.Verdict.State), s.Verdict.Detail)
+}
+
+func forgeStateIcon(state string) string {
+	switch state {
+	case forge.CheckSuccess, forge.ReviewApproved, forge.VerdictPass:
+		return style.Success.Render("✓")
+	case forge.CheckFailure, forge.ReviewChangesRequested, forge.VerdictFail:
+		return style.Error.Render("✗")
+	case forge.ReviewCommented:
+		return style.Dim.Render("💬")
+	default:
+		return style.Warning.Render("…")
+	}
+}
+
+func runMQForgeOpen(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	pr, err := refinery.NewEngineer(r).OpenForgePR(context.Background(), args[1])
+	if err != nil {
+		return err
+	}
+	if mqForgeJSON {
+		return outputJSON(pr)
+	}
+	fmt.Printf("%s %s: PR #%d %s\n", style.Success.Render("✓"), args[1], pr.Number, pr.URL)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -80,6 +81,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		issue          *beads.Issue
 		fields         *beads.MRFields
 		score          float64
+		breakdown      *refinery.ScoreBreakdown
 		branchMissing  bool // true if branch doesn't exist in git (when --verify is set)
 		branchVerifyErr bool // true if git check errored (corrupt repo, permission, etc.)
 	}
@@ -131,13 +133,29 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		// Check branch existence if --verify is set (local + remote-tracking refs)
 		branchMissing, branchVerifyErr := verifyBranch(mqListVerify, gitClient, fields)
 
-		// Calculate priority score
-		score := calculateMRScore(issue, fields, now)
-		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score, branchMissing: branchMissing, branchVerifyErr: branchVerifyErr})
+		scored = append(scored, scoredIssue{issue: issue, fields: fields, branchMissing: branchMissing, branchVerifyErr: branchVerifyErr})
+	}
+
+	// Score under the rig's refinery policy (or --policy)
+	toRank := make([]*beads.Issue, len(scored))
+	for i := range scored {
+		toRank[i] = scored[i].issue
+	}
+	scores, err := rankMRIssues(r, toRank, mqListPolicy)
+	if err != nil {
+		return err
+	}
+	for i := range scored {
+		if b := scores[scored[i].issue.ID]; b != nil {
+			scored[i].breakdown = b
+			scored[i].score = b.Total
+		} else {
+			scored[i].score = calculateMRScore(scored[i].issue, scored[i].fields, now)
+		}
 	}
 
 	// Sort by score descending (highest priority first)
-	sort.Slice(scored, func(i, j int) bool {
+	sort.SliceStable(scored, func(i, j int) bool {
 		return scored[i].score > scored[j].score
 	})
 
@@ -149,17 +167,21 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 	// JSON output
 	if mqListJSON {
-		if mqListVerify {
-			// Extend JSON with verification results
+		if mqListVerify || mqListExplain {
+			// Extend JSON with verification results and score breakdowns
 			type verifiedIssue struct {
 				*beads.Issue
-				BranchExists *bool  `json:"branch_exists,omitempty"`
-				VerifyError  bool   `json:"verify_error,omitempty"`
+				BranchExists *bool                    `json:"branch_exists,omitempty"`
+				VerifyError  bool                     `json:"verify_error,omitempty"`
+				Score        *refinery.ScoreBreakdown `json:"score,omitempty"`
 			}
 			var verified []verifiedIssue
 			for _, s := range scored {
 				vi := verifiedIssue{Issue: s.issue}
-				if s.fields != nil && s.fields.Branch != "" {
+				if mqListExplain {
+					vi.Score = s.breakdown
+				}
+				if mqListVerify && s.fields != nil && s.fields.Branch != "" {
 					if s.branchVerifyErr {
 						vi.VerifyError = true
 					} else {
@@ -301,6 +323,29 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Show why each MR is where it is
+	if mqListExplain {
+		policy := ""
+		if len(scored) > 0 && scored[0].breakdown != nil {
+			policy = scored[0].breakdown.Policy
+		}
+		fmt.Printf("\n%s Score breakdown (policy: %s):\n", style.Bold.Render("🧮"), policy)
+		for i, item := range scored {
+			if item.breakdown == nil {
+				continue
+			}
+			fmt.Printf("  %d. %s %.1f\n", i+1, item.issue.ID, item.breakdown.Total)
+			for _, f := range item.breakdown.Factors {
+				line := fmt.Sprintf("%-18s %+8.1f", f.Name, f.Points)
+				if f.Detail != "" {
+					line += "  " + f.Detail
+				}
+				fmt.Printf("     %s\n", style.Dim.Render(line))
+			}
+		}
+		fmt.Println()
+	}
+
 	// Show blocking details below table
 	for _, item := range scored {
 		issue := item.issue
@@ -356,6 +401,11 @@ func outputJSON(data interface{}) error {
 // calculateMRScore computes the priority score for an MR using the refinery scoring function.
 // Higher scores mean higher priority (process first).
 func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time) float64 {
+	return refinery.ScoreMRWithDefaults(mrScoreInput(issue, fields, now))
+}
+
+// mrScoreInput builds the refinery base score input for an MR issue.
+func mrScoreInput(issue *beads.Issue, fields *beads.MRFields, now time.Time) refinery.ScoreInput {
 	// Parse MR creation time
 	mrCreatedAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
 	if err != nil {
@@ -384,7 +434,38 @@ func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time)
 		}
 	}
 
-	return refinery.ScoreMRWithDefaults(input)
+	return input
+}
+
+// rankMRIssues scores MR issues under the rig's refinery score policy (or
+// policy, if set) and returns each issue's breakdown keyed by issue ID.
+func rankMRIssues(r *rig.Rig, issues []*beads.Issue, policy string) (map[string]*refinery.ScoreBreakdown, error) {
+	eng := refinery.NewEngineer(r)
+	eng.SetOutput(os.Stderr) // keep warnings out of --json output
+
+	now := time.Now()
+	mrs := make([]*refinery.MRInfo, 0, len(issues))
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			fields = &beads.MRFields{}
+		}
+		mr := refinery.MRInfoFromIssue(issue, fields)
+		if mr.CreatedAt.IsZero() {
+			mr.CreatedAt = mrScoreInput(issue, fields, now).MRCreatedAt
+		}
+		mrs = append(mrs, mr)
+	}
+
+	ranked, err := eng.RankMRs(mrs, policy)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[string]*refinery.ScoreBreakdown, len(ranked))
+	for i := range ranked {
+		scores[ranked[i].MR.ID] = &ranked[i].Score
+	}
+	return scores, nil
 }
 
 // branchVerifier abstracts git branch existence checks for testability.
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -28,6 +29,9 @@ The priority scoring function considers:
   - Retry count: MRs that fail repeatedly get deprioritized
   - MR age: FIFO tiebreaker for same priority/convoy
 
+The rig's merge_queue.score_policy can add a policy factor on top (see
+gt mq list --help); gt mq list --explain shows the breakdown.
+
 Use --strategy=fifo for first-in-first-out ordering instead.
 
 Examples:
@@ -92,6 +96,18 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 
 	now := time.Now()
 
+	// Score under the rig's refinery score policy
+	scores, err := rankMRIssues(r, ready, "")
+	if err != nil {
+		return err
+	}
+	scoreOf := func(issue *beads.Issue) float64 {
+		if b := scores[issue.ID]; b != nil {
+			return b.Total
+		}
+		return calculateMRScore(issue, beads.ParseMRFields(issue), now)
+	}
+
 	// Sort based on strategy
 	if mqNextStrategy == "fifo" {
 		// FIFO: oldest first by creation time
@@ -102,25 +118,9 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		})
 	} else {
 		// Priority: highest score first
-		type scoredIssue struct {
-			issue *beads.Issue
-			score float64
-		}
-		scored := make([]scoredIssue, len(ready))
-		for i, issue := range ready {
-			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
-			scored[i] = scoredIssue{issue: issue, score: score}
-		}
-
-		sort.Slice(scored, func(i, j int) bool {
-			return scored[i].score > scored[j].score
+		sort.SliceStable(ready, func(i, j int) bool {
+			return scoreOf(ready[i]) > scoreOf(ready[j])
 		})
-
-		// Rebuild ready slice in sorted order
-		for i, s := range scored {
-			ready[i] = s.issue
-		}
 	}
 
 	// Get the top MR
@@ -140,10 +140,11 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
-	score := calculateMRScore(next, fields, now)
-
 	fmt.Printf("  ID:       %s\n", next.ID)
-	fmt.Printf("  Score:    %.1f\n", score)
+	fmt.Printf("  Score:    %.1f\n", scoreOf(next))
+	if b := scores[next.ID]; b != nil && b.Policy != refinery.PolicyDefault {
+		fmt.Printf("  Policy:   %s\n", b.Policy)
+	}
 	fmt.Printf("  Priority: P%d\n", next.Priority)
 
 	if fields != nil {
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ stack command flags
+var (
+	mqStackJSON bool
+)
+
+var mqStackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Show and maintain stacked merge requests",
+	RunE:  requireSubcommand,
+	Long: `Show and maintain stacked merge requests.
+
+A stacked MR is built on another MR's unmerged branch. To stack work, branch
+from the parent's branch and name the parent MR when submitting:
+
+  git fetch origin
+  git checkout -b polecat/nux/gt-def origin/polecat/toast/gt-abc
+  ...
+  gt done --parent gt-mr123          # or: gt mq submit --parent gt-mr123
+
+The refinery holds a stacked MR until its parent merges, then rebases it onto
+the target with only its own commits, so stacks merge bottom-up. If the
+parent is reworked, its children are rebased onto the new parent tip and
+their owners are mailed. If the parent closes without merging, its children
+stay held and their owners are mailed.
+
+Commands:
+  show  Show the rig's MR stacks
+  sync  Rebase stacked MRs onto their parents' current branches`,
+}
+
+var mqStackShowCmd = &cobra.Command{
+	Use:   "show <rig>",
+	Short: "Show the rig's MR stacks",
+	Long: `Show open MRs that are stacked on other MRs, as trees.
+
+Examples:
+  gt mq stack show gastown
+  gt mq stack show gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQStackShow,
+}
+
+var mqStackSyncCmd = &cobra.Command{
+	Use:   "sync <rig>",
+	Short: "Rebase stacked MRs onto their parents' current branches",
+	Long: `Rebase every stacked MR whose parent branch moved, and release stacked
+MRs whose parent merged by rebasing them onto their target.
+
+Owners of rebased MRs are mailed. Conflicting rebases are aborted, the
+owner is mailed, and the MR stays held. So does an MR whose parent closed
+without merging. The refinery runs this each patrol
+cycle and after every merge.
+
+Examples:
+  gt mq stack sync gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQStackSync,
+}
+
+func init() {
+	mqStackShowCmd.Flags().BoolVar(&mqStackJSON, "json", false, "Output as JSON")
+	mqStackSyncCmd.Flags().BoolVar(&mqStackJSON, "json", false, "Output as JSON")
+
+	mqStackCmd.AddCommand(mqStackShowCmd)
+	mqStackCmd.AddCommand(mqStackSyncCmd)
+	mqCmd.AddCommand(mqStackCmd)
+}
+
+func runMQStackShow(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	stacks, err := refinery.NewEngineer(r).ListStacks()
+	if err != nil {
+		return err
+	}
+	if mqStackJSON {
+		return outputJSON(stacks)
+	}
+	if len(stacks) == 0 {
+		fmt.Printf("%s No stacked MRs in '%s'\n", style.Success.Render("✓"), rigName)
+		return nil
+	}
+	fmt.Printf("%s MR stacks in '%s':\n\n", style.Bold.Render("📚"), rigName)
+	for _, root := range stacks {
+		printStackNode(root, 0)
+		fmt.Println()
+	}
+	return nil
+}
+
+func printStackNode(n *refinery.StackNode, depth int) {
+	indent := strings.Repeat("   ", depth)
+	marker := "●"
+	if depth > 0 {
+		marker = "└─"
+	}
+	line := fmt.Sprintf("%s %s %s", n.MR.ID, n.MR.Branch, style.Dim.Render("("+n.MR.Worker+")"))
+	if depth == 0 && n.MR.ParentMR != "" {
+		line += style.Warning.Render(fmt.Sprintf("  waiting on closed parent %s", n.MR.ParentMR))
+	}
+	fmt.Printf("  %s%s %s\n", indent, marker, line)
+	for _, c := range n.Children {
+		printStackNode(c, depth+1)
+	}
+}
+
+func runMQStackSync(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	if !mqStackJSON {
+		eng.SetOutput(cmd.ErrOrStderr())
+	}
+	results, err := eng.SyncStacks(context.Background())
+	if err != nil {
+		return err
+	}
+	if mqStackJSON {
+		return outputJSON(results)
+	}
+
+	changed := 0
+	for _, res := range results {
+		if res.Outcome == refinery.RestackUpToDate {
+			continue
+		}
+		changed++
+		icon := style.Success.Render("✓")
+		if res.Outcome == refinery.RestackConflict || res.Outcome == refinery.RestackError {
+			icon = style.Error.Render("✗")
+		} else if res.Outcome == refinery.RestackHeld {
+			icon = style.Warning.Render("⚠")
+		}
+		fmt.Printf("  %s %s (%s): %s %s\n", icon, res.MR.ID, res.MR.Branch, res.Outcome, res.Detail)
+	}
+	if changed == 0 {
+		fmt.Printf("%s %d stacked MR(s) in '%s' up to date\n", style.Success.Render("✓"), len(results), rigName)
+	}
+	return nil
+}
+
+// resolveStackParent checks that parentID is an open MR and returns its
+// fields along with the stack base for branch: the parent branch commit the
+// branch was built on. The stacked MR must target the parent's target.
+func resolveStackParent(bd *beads.Beads, g *git.Git, parentID, branch string) (*beads.MRFields, string, error) {
+	parent, err := bd.Show(parentID)
+	if err != nil {
+		return nil, "", fmt.Errorf("looking up parent MR %s: %w", parentID, err)
+	}
+	fields := beads.ParseMRFields(parent)
+	if fields == nil || fields.Branch == "" || !beads.HasLabel(parent, "gt:merge-request") {
+		return nil, "", fmt.Errorf("%s is not a merge request", parentID)
+	}
+	if parent.Status == "closed" {
+		return nil, "", fmt.Errorf("parent MR %s is already closed; branch from %s instead", parentID, fields.Target)
+	}
+	if fields.Branch == branch {
+		return nil, "", fmt.Errorf("%s is the MR for %s itself", parentID, branch)
+	}
+	if err := g.FetchBranch("origin", fields.Branch); err != nil {
+		return nil, "", fmt.Errorf("fetching parent branch %s: %w", fields.Branch, err)
+	}
+	base, err := g.MergeBase(branch, "origin/"+fields.Branch)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s does not share history with parent branch %s: %w", branch, fields.Branch, err)
+	}
+	return fields, base, nil
+}
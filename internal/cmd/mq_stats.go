@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ stats command flags
+var (
+	mqStatsSince string
+	mqStatsSLA   string
+	mqStatsJSON  bool
+)
+
+var mqStatsCmd = &cobra.Command{
+	Use:   "stats <rig>",
+	Short: "Show merge queue lead time, throughput and failure rate",
+	Long: `Show merge queue performance over a time window.
+
+The refinery records every MR it processes: when it was submitted, when it
+was picked up, how long each quality gate took, and how the attempt ended.
+From that history this shows:
+
+  Lead time    Submission to merge (p50/p95), merges only
+  Queue wait   Submission to pickup (p50/p95)
+  Throughput   Merges per day
+  Failure rate Failed attempts / (merges + failed attempts); attempts held
+               for forge checks or merge slot contention don't count
+  Gates        Per-gate run time (p50/p95) and failures
+
+With merge_queue.lead_time_sla set in the rig's config.json (or --sla),
+the share of merges that met it is shown too. History is kept for 90 days
+in <rig>/.runtime/mq-metrics.jsonl.
+
+Examples:
+  gt mq stats gastown
+  gt mq stats gastown --since 30d --sla 4h
+  gt mq stats gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQStats,
+}
+
+func init() {
+	mqStatsCmd.Flags().StringVar(&mqStatsSince, "since", "7d", "Window to report (e.g. 24h, 7d)")
+	mqStatsCmd.Flags().StringVar(&mqStatsSLA, "sla", "", "Lead time target (default: merge_queue.lead_time_sla)")
+	mqStatsCmd.Flags().BoolVar(&mqStatsJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqStatsCmd)
+}
+
+func runMQStats(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	window, err := parseDuration(mqStatsSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q: expected a duration like 24h or 7d", mqStatsSince)
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	sla := eng.Config().LeadTimeSLA
+	if mqStatsSLA != "" {
+		if sla, err = parseDuration(mqStatsSLA); err != nil {
+			return fmt.Errorf("invalid --sla %q: %w", mqStatsSLA, err)
+		}
+	}
+
+	now := time.Now()
+	since := now.Add(-window)
+	attempts, err := refinery.LoadMQAttempts(r.Path, since)
+	if err != nil {
+		return err
+	}
+	stats := refinery.ComputeMQStats(attempts, since, now, sla)
+
+	if mqStatsJSON {
+		return outputJSON(stats)
+	}
+
+	fmt.Printf("%s Merge queue stats for '%s' (last %s)\n\n", style.Bold.Render("📈"), rigName, mqStatsSince)
+	if stats.Attempts == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No MRs processed in this window"))
+		return nil
+	}
+	fmt.Printf("  Lead time     p50 %-10s p95 %s\n", formatStatDuration(stats.LeadTimeP50), formatStatDuration(stats.LeadTimeP95))
+	fmt.Printf("  Queue wait    p50 %-10s p95 %s\n", formatStatDuration(stats.QueueWaitP50), formatStatDuration(stats.QueueWaitP95))
+	fmt.Printf("  Throughput    %.1f merges/day (%d merged)\n", stats.ThroughputPerDay, stats.Merged)
+
+	rate := fmt.Sprintf("%.0f%%", stats.FailureRate*100)
+	switch {
+	case stats.FailureRate >= 0.5:
+		rate = style.Error.Render(rate)
+	case stats.FailureRate >= 0.2:
+		rate = style.Warning.Render(rate)
+	}
+	fmt.Printf("  Failure rate  %s (%d failed", rate, stats.Failed)
+	if len(stats.FailureReasons) > 0 {
+		reasons := make([]string, 0, len(stats.FailureReasons))
+		for r := range stats.FailureReasons {
+			reasons = append(reasons, r)
+		}
+		sort.Strings(reasons)
+		for i, r := range reasons {
+			sep := ": "
+			if i > 0 {
+				sep = ", "
+			}
+			fmt.Printf("%s%d %s", sep, stats.FailureReasons[r], r)
+		}
+	}
+	fmt.Println(")")
+	if stats.Retried > 0 {
+		fmt.Printf("  Retried       %d merge(s) needed more than one attempt\n", stats.Retried)
+	}
+	if stats.Waiting > 0 {
+		fmt.Printf("  Held          %d attempt(s) waited on forge checks or the merge slot\n", stats.Waiting)
+	}
+	if stats.SLA > 0 && stats.Merged > 0 {
+		within := fmt.Sprintf("%.0f%%", stats.WithinSLA*100)
+		if stats.WithinSLA < 0.9 {
+			within = style.Warning.Render(within)
+		}
+		fmt.Printf("  SLA           %s of merges within %s\n", within, formatStatDuration(stats.SLA))
+	}
+
+	if len(stats.Gates) > 0 {
+		fmt.Printf("\n  %-20s %6s %8s %10s %10s\n", "GATE", "RUNS", "FAILED", "P50", "P95")
+		for _, g := range stats.Gates {
+			fmt.Printf("  %-20s %6d %8d %10s %10s\n", g.Name, g.Runs, g.Failures, formatStatDuration(g.P50), formatStatDuration(g.P95))
+		}
+	}
+	return nil
+}
+
+// formatStatDuration formats a duration compactly ("42s", "3m 5s", "2h 10m").
+func formatStatDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return formatDuration(d)
+}
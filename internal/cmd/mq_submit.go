@@ -187,6 +187,17 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Stacked MR: built on an unmerged parent branch, merges into the parent's target
+	var stackLines string
+	if mqSubmitParent != "" {
+		parentFields, base, err := resolveStackParent(bd, g, mqSubmitParent, branch)
+		if err != nil {
+			return err
+		}
+		target = parentFields.Target
+		stackLines = fmt.Sprintf("\nparent_mr: %s\nstack_base: %s", mqSubmitParent, base)
+	}
+
 	// Get source issue for priority inheritance
 	var priority int
 	if mqSubmitPriority >= 0 {
@@ -209,6 +220,10 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
+	description += stackLines
+
+	// Flag likely conflicts with other in-flight branches before submitting
+	warnPredictedConflicts(g, filepath.Join(townRoot, rigName), branch, target)
 
 	// Check if MR bead already exists for this branch (idempotency)
 	var mrIssue *beads.Issue
@@ -246,6 +261,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if mqSubmitParent != "" {
+		fmt.Printf("  Stacked on: %s (merges after it)\n", mqSubmitParent)
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ verify-main command flags
+var (
+	mqVerifyMainDryRun bool
+	mqVerifyMainJSON   bool
+)
+
+var mqVerifyMainCmd = &cobra.Command{
+	Use:   "verify-main <rig>",
+	Short: "Check the default branch after merges and revert the commit that broke it",
+	Long: `Run the rig's post-merge check on the tip of its default branch.
+
+Quality gates run on each MR before it merges, but two MRs that pass on
+their own can still break the branch together. Configure a check in the
+rig's config.json:
+
+  "merge_queue": {
+    "verify_main": {"cmd": "make test", "timeout": "30m"},
+    "bisect_depth": 20,     // recent commits searched for the culprit
+    "auto_revert": true     // revert and re-sling, not just escalate
+  }
+
+When the tip fails, the commits since the last passing tip are bisected to
+find the first failing one. The refinery then:
+
+  1. Opens a P0 revert MR for it (branch revert/<sha>)
+  2. Files a high-severity escalation with the failure log
+  3. Reopens the culprit's bead and slings it back to its author
+
+Each culprit is handled once. A failure older than the bisect window, or
+one that persists after the revert lands, is escalated without a revert.
+The refinery runs this each patrol cycle after merging.
+
+Exit status is 0 when the branch passes and 1 when it fails.
+
+Examples:
+  gt mq verify-main gastown
+  gt mq verify-main gastown --dry-run   # find the culprit, change nothing`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQVerifyMain,
+}
+
+func init() {
+	mqVerifyMainCmd.Flags().BoolVarP(&mqVerifyMainDryRun, "dry-run", "n", false, "Find the culprit without reverting, escalating or re-slinging")
+	mqVerifyMainCmd.Flags().BoolVar(&mqVerifyMainJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqVerifyMainCmd)
+}
+
+func runMQVerifyMain(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if eng.Config().VerifyMain == nil {
+		if mqVerifyMainJSON {
+			return outputJSON(nil)
+		}
+		fmt.Printf("%s No verify_main check configured for '%s'\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+	if !mqVerifyMainJSON {
+		eng.SetOutput(cmd.ErrOrStderr())
+	}
+
+	result, err := eng.VerifyMain(context.Background(), mqVerifyMainDryRun)
+	if err != nil {
+		return err
+	}
+
+	if mqVerifyMainJSON {
+		if err := outputJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printVerifyMainResult(result)
+	}
+
+	switch result.Outcome {
+	case refinery.VerifyGreen:
+		return nil
+	case refinery.VerifyUnchanged:
+		if result.Detail == "passed" {
+			return nil
+		}
+	}
+	return NewSilentExit(1)
+}
+
+func printVerifyMainResult(res *refinery.MainVerifyResult) {
+	at := fmt.Sprintf("%s@%s", res.Branch, shortCommit(res.Head))
+	switch res.Outcome {
+	case refinery.VerifyGreen:
+		fmt.Printf("%s %s passes\n", style.Success.Render("✓"), at)
+		return
+	case refinery.VerifyUnchanged:
+		fmt.Printf("%s %s already checked: %s\n", style.Dim.Render("○"), at, res.Detail)
+		return
+	}
+
+	fmt.Printf("%s %s fails\n", style.Error.Render("✗"), at)
+	if len(res.Steps) > 1 {
+		fmt.Println("\nBisect:")
+		for _, s := range res.Steps {
+			mark := style.Error.Render("✗")
+			if s.Passed {
+				mark = style.Success.Render("✓")
+			}
+			fmt.Printf("  %s %s\n", mark, shortCommit(s.Commit))
+		}
+	}
+
+	if c := res.Culprit; c != nil {
+		fmt.Printf("\nCulprit: %s %s\n", style.Bold.Render(shortCommit(c.Commit)), c.Subject)
+		if c.MR != "" {
+			fmt.Printf("  MR:         %s (worker %s, issue %s)\n", c.MR, c.Worker, c.SourceIssue)
+		}
+		if c.RevertMR != "" {
+			fmt.Printf("  Revert MR:  %s\n", c.RevertMR)
+		}
+		if c.Escalation != "" {
+			fmt.Printf("  Escalation: %s\n", c.Escalation)
+		}
+		if c.Reslung {
+			fmt.Printf("  Re-slung:   %s\n", c.SourceIssue)
+		}
+	}
+	if res.Outcome == refinery.VerifyRevertPending || res.Outcome == refinery.VerifyUnattributed {
+		fmt.Printf("\n%s\n", style.Dim.Render(res.Detail))
+	}
+	for _, w := range res.Warnings {
+		style.PrintWarning("%s", w)
+	}
+}
+
+// shortCommit abbreviates a commit SHA for display.
+func shortCommit(sha string) string {
+	return sha[:min(8, len(sha))]
+}
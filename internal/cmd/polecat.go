@@ -740,6 +740,10 @@ type GitState struct {
 	UncommittedFiles []string `json:"uncommitted_files"`
 	UnpushedCommits  int      `json:"unpushed_commits"`
 	StashCount       int      `json:"stash_count"`
+
+	// Checkout shape (reported by git-state only)
+	SparseCheckout     []string `json:"sparse_checkout,omitempty"`      // cone-mode directories; empty = full checkout
+	PartialCloneFilter string   `json:"partial_clone_filter,omitempty"` // e.g. "blob:none"
 }
 
 func runPolecatGitState(cmd *cobra.Command, args []string) error {
@@ -764,6 +768,9 @@ func runPolecatGitState(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("getting git state: %w", err)
 	}
+	wtGit := git.NewGit(p.ClonePath)
+	state.SparseCheckout, _ = wtGit.SparseCheckoutList()
+	state.PartialCloneFilter = wtGit.PartialCloneFilter("origin")
 
 	// JSON output
 	if polecatGitStateJSON {
@@ -800,6 +807,16 @@ func runPolecatGitState(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Stashes:       %s\n", style.Warning.Render(fmt.Sprintf("%d", state.StashCount)))
 	}
 
+	// Checkout shape
+	if state.SparseCheckout != nil {
+		fmt.Printf("  Checkout:      %s\n", style.Dim.Render("sparse: "+strings.Join(state.SparseCheckout, ", ")))
+	} else {
+		fmt.Printf("  Checkout:      %s\n", style.Dim.Render("full"))
+	}
+	if state.PartialCloneFilter != "" {
+		fmt.Printf("  Clone Filter:  %s\n", style.Dim.Render(state.PartialCloneFilter))
+	}
+
 	// Verdict
 	fmt.Println()
 	if state.Clean {
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Polecat pool command flags
+var (
+	polecatPoolJSON  bool
+	polecatPoolFill  bool
+	polecatPoolDrain bool
+	polecatPoolQuiet bool
+)
+
+var polecatPoolCmd = &cobra.Command{
+	Use:   "pool <rig>",
+	Short: "Show or manage the rig's pool of pre-warmed worktrees",
+	Long: `Show or manage the pool of pre-warmed polecat worktrees for a rig.
+
+Creating a worktree (and priming its dependencies) on every spawn is slow on
+large repos. With a pool configured, the rig keeps worktrees checked out on
+the default branch and warmed up ahead of time; a spawn claims one, moves it
+into place and resets it onto the polecat's branch. The pool is refilled in
+the background after each claim.
+
+Configure in <rig>/settings/config.json:
+
+  "worktree_pool": {
+    "size": 3,
+    "warmup": "npm ci",
+    "warmup_timeout": "10m"
+  }
+
+The warmup command runs in each new pool worktree with GT_RIG set. Pool
+worktrees live in <rig>/.worktree-pool/.
+
+Examples:
+  gt polecat pool greenplace           # Show pool status
+  gt polecat pool greenplace --fill    # Top the pool up now
+  gt polecat pool greenplace --drain   # Remove all pool worktrees
+  gt polecat pool greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPool,
+}
+
+func init() {
+	polecatPoolCmd.Flags().BoolVar(&polecatPoolJSON, "json", false, "Output as JSON")
+	polecatPoolCmd.Flags().BoolVar(&polecatPoolFill, "fill", false, "Create and warm worktrees up to the configured size")
+	polecatPoolCmd.Flags().BoolVar(&polecatPoolDrain, "drain", false, "Remove all pool worktrees")
+	polecatPoolCmd.Flags().BoolVar(&polecatPoolQuiet, "quiet", false, "Suppress output (for background refills)")
+	polecatCmd.AddCommand(polecatPoolCmd)
+}
+
+func runPolecatPool(cmd *cobra.Command, args []string) error {
+	if polecatPoolFill && polecatPoolDrain {
+		return fmt.Errorf("--fill and --drain are mutually exclusive")
+	}
+
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	if polecatPoolDrain {
+		removed, err := mgr.DrainWorktreePool()
+		if err != nil {
+			return err
+		}
+		if !polecatPoolQuiet {
+			fmt.Printf("%s Drained %d worktree(s) from %s pool\n", style.Bold.Render("✓"), removed, r.Name)
+		}
+		return nil
+	}
+
+	if polecatPoolFill {
+		if !mgr.WorktreePoolEnabled() {
+			return fmt.Errorf("no worktree pool configured for %s (set worktree_pool.size in settings/config.json)", r.Name)
+		}
+		start := time.Now()
+		created, err := mgr.FillWorktreePool()
+		if errors.Is(err, polecat.ErrWorktreePoolBusy) {
+			if !polecatPoolQuiet {
+				fmt.Printf("%s\n", style.Dim.Render("Pool is already being filled by another process"))
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("filling pool (%d created): %w", created, err)
+		}
+		if !polecatPoolQuiet {
+			fmt.Printf("%s Warmed %d worktree(s) for %s in %s\n", style.Bold.Render("✓"),
+				created, r.Name, time.Since(start).Round(time.Second))
+		}
+		if polecatPoolJSON || polecatPoolQuiet {
+			return nil
+		}
+	}
+
+	status, err := mgr.WorktreePoolStatus()
+	if err != nil {
+		return err
+	}
+
+	if polecatPoolJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	if status.Size == 0 {
+		fmt.Printf("No worktree pool configured for %s\n", r.Name)
+		fmt.Printf("%s\n", style.Dim.Render("Set worktree_pool.size in settings/config.json to enable"))
+		if len(status.Slots) == 0 {
+			return nil
+		}
+	} else {
+		fmt.Printf("%s %s: %d/%d ready\n", style.Bold.Render("Worktree pool"), r.Name, status.ReadyCount(), status.Size)
+		if status.Warmup != "" {
+			fmt.Printf("  Warmup: %s\n", style.Dim.Render(status.Warmup))
+		}
+	}
+
+	for _, slot := range status.Slots {
+		if slot.Ready {
+			commit := slot.Commit
+			if len(commit) > 8 {
+				commit = commit[:8]
+			}
+			fmt.Printf("  %s %s  %s  warmed %s ago\n", style.Success.Render("●"), slot.Name, commit,
+				time.Since(slot.ReadyAt).Round(time.Minute))
+		} else {
+			fmt.Printf("  %s %s  %s\n", style.Warning.Render("○"), slot.Name, style.Dim.Render("warming (or failed)"))
+		}
+	}
+	return nil
+}
+
+// refillWorktreePoolAsync starts a detached `gt polecat pool <rig> --fill`
+// so a spawn that claimed a warm worktree doesn't wait for its replacement.
+func refillWorktreePoolAsync(rigName string) {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	fillCmd := exec.Command(gtPath, "polecat", "pool", rigName, "--fill", "--quiet")
+	fillCmd.Stdin = nil
+	fillCmd.Stdout = nil
+	fillCmd.Stderr = nil
+	if err := fillCmd.Start(); err != nil {
+		return
+	}
+	// Don't wait; the fill lock serializes concurrent refills
+	_ = fillCmd.Process.Release()
+}
@@ -30,6 +30,8 @@ type SpawnedPolecatInfo struct {
 	Pane        string // Tmux pane ID (empty until StartSession is called)
 	DoltBranch  string // Dolt branch for write isolation (empty if not created)
 	BaseBranch  string // Effective base branch (e.g., "main", "integration/epic-id")
+	Affinity    string // Affinity match that picked this identity (empty if none)
+	TraceParent string // Span the session start is recorded under (W3C traceparent)
 
 	// Internal fields for deferred session start
 	account string
@@ -54,6 +56,8 @@ type SlingSpawnOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent      string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
 	BaseBranch string // Override base branch for polecat worktree (e.g., "develop", "release/v2")
+	Formula    string // Formula being slung (selects checkout overrides)
+	Affinity   bool   // Prefer the identity whose history best matches HookBead
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -97,12 +101,25 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("admission control: %w", err)
 	}
 
-	// Allocate a new polecat name
-	polecatName, err := polecatMgr.AllocateName()
+	// Allocate a new polecat name, by affinity if requested or configured
+	// for the rig: follow-up work goes to the identity with relevant history.
+	useAffinity := opts.Affinity
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil && settings.PolecatAffinity {
+		useAffinity = true
+	}
+	var polecatName, affinity string
+	if useAffinity && opts.HookBead != "" {
+		polecatName, affinity, err = allocateNameByAffinity(polecatMgr, r.Path, opts.HookBead)
+	} else {
+		polecatName, err = polecatMgr.AllocateName()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("allocating polecat name: %w", err)
 	}
 	fmt.Printf("Allocated polecat: %s\n", polecatName)
+	if affinity != "" {
+		fmt.Printf("  Affinity: %s\n", affinity)
+	}
 
 	// Check if polecat already exists (shouldn't happen - indicates stale state needing repair)
 	existingPolecat, err := polecatMgr.Get(polecatName)
@@ -136,6 +153,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	addOpts := polecat.AddOptions{
 		HookBead:   opts.HookBead,
 		BaseBranch: baseBranch,
+		Formula:    opts.Formula,
 	}
 
 	if err == nil {
@@ -172,6 +190,9 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
 			return nil, fmt.Errorf("creating polecat: %w", err)
 		}
+		if polecatMgr.WorktreePoolEnabled() {
+			refillWorktreePoolAsync(rigName)
+		}
 	} else {
 		return nil, fmt.Errorf("getting polecat: %w", err)
 	}
@@ -220,11 +241,29 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		Pane:        "", // Empty until StartSession is called
 		DoltBranch:  doltBranch,
 		BaseBranch:  effectiveBranch,
+		Affinity:    affinity,
 		account:     opts.Account,
 		agent:       opts.Agent,
 	}, nil
 }
 
+// allocateNameByAffinity allocates a polecat name preferring the identity whose
+// past work best overlaps the bead. Returns the affinity match description,
+// or "" if the name came from the pool as usual.
+func allocateNameByAffinity(mgr *polecat.Manager, rigPath, beadID string) (string, string, error) {
+	issue, err := beads.New(rigPath).Show(beadID)
+	if err != nil {
+		style.PrintWarning("affinity: could not read %s, allocating from pool: %v", beadID, err)
+		name, err := mgr.AllocateName()
+		return name, "", err
+	}
+	name, match, err := mgr.AllocateNameWithAffinity(mgr.BeadWorkProfile(issue))
+	if err != nil || match == nil {
+		return name, "", err
+	}
+	return name, match.String(), nil
+}
+
 // StartSession starts the tmux session for a spawned polecat.
 // This is called after the molecule/bead is attached, so the polecat
 // sees its work when gt prime runs on session start.
@@ -268,6 +307,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
 		DoltBranch:       s.DoltBranch,
+		TraceParent:      s.TraceParent,
 	}
 	if s.agent != "" {
 		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent)
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -35,6 +36,8 @@ Aggregates ready issues from:
 
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
+Items queued with 'gt sling --queue' show their position in the
+scheduler queue (see 'gt schedule').
 
 Examples:
   gt ready              # Show all ready work
@@ -61,6 +64,10 @@ type ReadyResult struct {
 	Sources  []ReadySource `json:"sources"`
 	Summary  ReadySummary  `json:"summary"`
 	TownRoot string        `json:"town_root,omitempty"`
+
+	// QueuePositions maps bead IDs queued with gt sling --queue to their
+	// 1-based dispatch position in the scheduler queue.
+	QueuePositions map[string]int `json:"queue_positions,omitempty"`
 }
 
 // ReadySummary provides counts for the ready report.
@@ -222,6 +229,9 @@ func runReady(cmd *cobra.Command, args []string) error {
 		Summary:  summary,
 		TownRoot: townRoot,
 	}
+	if q, err := scheduler.NewManager(townRoot).Load(); err == nil && len(q.Entries) > 0 {
+		result.QueuePositions = q.Positions()
+	}
 
 	// Check for source errors
 	var failedSources []string
@@ -294,7 +304,12 @@ func printReadyHuman(result ReadyResult) error {
 				title = title[:57] + "..."
 			}
 
-			fmt.Printf("  [%s] %s %s\n", priorityStyled, style.Dim.Render(issue.ID), title)
+			queued := ""
+			if pos, ok := result.QueuePositions[issue.ID]; ok {
+				queued = " " + style.Dim.Render(fmt.Sprintf("⏳ queued #%d", pos))
+			}
+
+			fmt.Printf("  [%s] %s %s%s\n", priorityStyled, style.Dim.Render(issue.ID), title, queued)
 		}
 		fmt.Println()
 	}
@@ -65,6 +65,8 @@ var beadsExemptCommands = map[string]bool{
 	"krc":           true, // KRC doesn't require beads
 	"run-migration":       true, // Migration orchestrator handles its own beads checks
 	"migrate-bead-labels": true, // Label migration handles its own beads access
+	"record-pipe":         true, // Pane recorder started by tmux pipe-pane for every session
+	"replay":              true, // Replays local recording files
 }
 
 // Commands exempt from the town root branch warning.
@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Schedule command flags
+var (
+	scheduleJSON   bool
+	scheduleDryRun bool
+	scheduleQuiet  bool
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	GroupID: GroupWork,
+	Short:   "Show and dispatch work queued with gt sling --queue",
+	Long: `Show the town-wide queue of sling work waiting for polecat capacity.
+
+Work slung with --queue waits here instead of spawning a polecat right away.
+Entries are dispatched in bead priority order (then oldest first) whenever:
+  - the town, the target rig and the entry's account are under their limits
+  - the Dolt server has connection capacity
+  - the entry's account is not rate-limited
+
+The daemon runs 'gt schedule run' on every heartbeat, so queued work starts
+on its own as polecats finish. Configure limits in settings/config.json:
+
+  "scheduler": {
+    "max_polecats": 12,
+    "max_polecats_per_rig": 4,
+    "max_polecats_per_account": 6,
+    "rigs": {"gastown": 8}
+  }
+
+A limit of 0 (or unset) means unlimited.
+
+Examples:
+  gt sling gt-abc gastown --queue   # Enqueue instead of spawning now
+  gt schedule                       # Show queue, positions and capacity
+  gt schedule run                   # Dispatch whatever fits now
+  gt schedule remove gt-abc         # Drop a bead from the queue`,
+	RunE: runScheduleStatus,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Dispatch queued work that fits within current capacity",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleRun,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:     "remove <bead>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove beads from the sling queue",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runScheduleRemove,
+}
+
+func init() {
+	scheduleCmd.Flags().BoolVar(&scheduleJSON, "json", false, "Output as JSON")
+	scheduleRunCmd.Flags().BoolVarP(&scheduleDryRun, "dry-run", "n", false, "Show what would be dispatched")
+	scheduleRunCmd.Flags().BoolVar(&scheduleQuiet, "quiet", false, "Only print dispatched work (for the daemon)")
+
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+// ScheduleStatus is the JSON output of gt schedule.
+type ScheduleStatus struct {
+	Usage     scheduler.Usage         `json:"usage"`
+	Limits    *config.SchedulerConfig `json:"limits,omitempty"`
+	Decisions []scheduler.Decision    `json:"queue"`
+}
+
+func runScheduleStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	q, err := scheduler.NewManager(townRoot).Load()
+	if err != nil {
+		return err
+	}
+	usage, constraints := schedulerSnapshot(townRoot)
+	status := ScheduleStatus{
+		Usage:     usage,
+		Limits:    constraints.Limits,
+		Decisions: scheduler.Plan(q, usage, constraints),
+	}
+
+	if scheduleJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	fmt.Printf("%s %d polecat(s) running", style.Bold.Render("Capacity:"), usage.Total)
+	if constraints.Limits != nil && constraints.Limits.MaxPolecats > 0 {
+		fmt.Printf(" (town limit %d)", constraints.Limits.MaxPolecats)
+	}
+	fmt.Println()
+	if constraints.DoltAtCapacity {
+		fmt.Printf("  %s\n", style.Warning.Render("Dolt server at connection capacity"))
+	}
+
+	if len(status.Decisions) == 0 {
+		fmt.Println("\nNo work queued.")
+		return nil
+	}
+
+	fmt.Printf("\n%s (%d)\n", style.Bold.Render("Sling queue"), len(status.Decisions))
+	for i, d := range status.Decisions {
+		e := d.Entry
+		state := style.Success.Render("ready")
+		if !d.Dispatch {
+			state = style.Dim.Render("waiting: " + d.Reason)
+		}
+		fmt.Printf("  %2d. [P%d] %s → %s  %s\n", i+1, e.Priority, e.BeadID, e.Rig, state)
+		fmt.Printf("      %s\n", style.Dim.Render("queued "+time.Since(e.EnqueuedAt).Round(time.Minute).String()+" ago"))
+		if e.LastError != "" {
+			fmt.Printf("      %s\n", style.Warning.Render(fmt.Sprintf("last attempt failed (%d): %s", e.Attempts, e.LastError)))
+		}
+	}
+	return nil
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	dispatched, err := dispatchQueuedSlings(townRoot, scheduleDryRun)
+	if errors.Is(err, scheduler.ErrDispatchBusy) {
+		if !scheduleQuiet {
+			fmt.Printf("%s\n", style.Dim.Render("Queue is already being dispatched by another process"))
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dispatched == 0 && !scheduleQuiet {
+		fmt.Println("Nothing to dispatch (queue empty or no capacity).")
+	}
+	return nil
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	mgr := scheduler.NewManager(townRoot)
+	for _, beadID := range args {
+		removed, err := mgr.Remove(beadID)
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Printf("%s Removed %s from the sling queue\n", style.Bold.Render("✓"), beadID)
+		} else {
+			fmt.Printf("%s %s is not queued\n", style.Dim.Render("○"), beadID)
+		}
+	}
+	return nil
+}
+
+// enqueueSling records a bead for later dispatch to rigName instead of
+// spawning a polecat now. slingArgs is the `gt sling` argument list replayed
+// by the scheduler.
+func enqueueSling(townRoot, beadID, rigName string, info *beadInfo, slingArgs []string) error {
+	if slingDryRun {
+		fmt.Printf("Would queue %s for %s\n", beadID, rigName)
+		return nil
+	}
+	entry := &scheduler.Entry{
+		BeadID:     beadID,
+		Rig:        rigName,
+		Priority:   info.Priority,
+		Account:    slingAccount,
+		EnqueuedBy: detectActor(),
+		SlingArgs:  slingArgs,
+	}
+	position, err := scheduler.NewManager(townRoot).Enqueue(entry)
+	if err != nil {
+		return fmt.Errorf("queueing %s: %w", beadID, err)
+	}
+	fmt.Printf("%s Queued %s for %s (position %d)\n", style.Bold.Render("⏳"), beadID, rigName, position)
+	return nil
+}
+
+// runQueueSling handles gt sling --queue: each bead is added to the
+// town-wide queue for the target rig instead of spawning a polecat now.
+// The scheduler replays the sling once there is capacity.
+func runQueueSling(args []string, townRoot string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("--queue requires a rig target (e.g., gt sling gt-abc gastown --queue)")
+	}
+	rigName, isRig := IsRigName(args[len(args)-1])
+	if !isRig {
+		return fmt.Errorf("--queue only applies to rig targets (polecat spawns), got %q", args[len(args)-1])
+	}
+
+	formulaName := ""
+	beadIDs := args[:len(args)-1]
+	if slingOnTarget != "" {
+		formulaName = args[0]
+		beadIDs = []string{slingOnTarget}
+		if err := verifyFormulaExists(formulaName); err != nil {
+			return err
+		}
+	}
+
+	for _, beadID := range beadIDs {
+		if err := verifyBeadExists(beadID); err != nil {
+			return fmt.Errorf("bead '%s' not found (--queue takes beads, not standalone formulas)", beadID)
+		}
+		info, err := getBeadInfo(beadID)
+		if err != nil {
+			return fmt.Errorf("checking bead status: %w", err)
+		}
+		if info.Status != "open" {
+			return fmt.Errorf("bead %s is %s; only open beads can be queued", beadID, info.Status)
+		}
+		if !slingForce {
+			if err := checkCrossRigGuard(beadID, rigName+"/polecats/_", townRoot); err != nil {
+				return err
+			}
+		}
+		if err := enqueueSling(townRoot, beadID, rigName, info, queuedSlingArgs(formulaName, beadID, rigName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queuedSlingArgs builds the `gt sling` argument list that replays the
+// current invocation for one bead, minus --queue.
+func queuedSlingArgs(formulaName, beadID, rigName string) []string {
+	var args []string
+	if formulaName != "" {
+		args = append(args, formulaName, "--on", beadID)
+	} else {
+		args = append(args, beadID)
+	}
+	args = append(args, rigName)
+
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"--subject", slingSubject},
+		{"--message", slingMessage},
+		{"--args", slingArgs},
+		{"--account", slingAccount},
+		{"--agent", slingAgent},
+		{"--merge", slingMerge},
+		{"--base-branch", slingBaseBranch},
+	} {
+		if f.value != "" {
+			args = append(args, f.name, f.value)
+		}
+	}
+	for _, v := range slingVars {
+		args = append(args, "--var", v)
+	}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"--create", slingCreate},
+		{"--no-convoy", slingNoConvoy},
+		{"--owned", slingOwned},
+		{"--no-merge", slingNoMerge},
+		{"--no-boot", slingNoBoot},
+		{"--ralph", slingRalph},
+		{"--hook-raw-bead", slingHookRawBead},
+		{"--affinity", slingAffinity},
+	} {
+		if f.set {
+			args = append(args, f.name)
+		}
+	}
+	return args
+}
+
+// schedulerSnapshot gathers current polecat usage and the constraints the
+// scheduler checks queued work against. Failures degrade to "no limit"
+// except the Dolt capacity check, which fails closed like spawn admission.
+func schedulerSnapshot(townRoot string) (scheduler.Usage, scheduler.Constraints) {
+	var c scheduler.Constraints
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		c.Limits = settings.Scheduler
+	}
+
+	accounts, _ := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if accounts != nil {
+		c.DefaultAccount = accounts.Default
+	}
+
+	qm := quota.NewManager(townRoot)
+	if state, err := qm.Load(); err == nil {
+		c.LimitedAccounts = make(map[string]bool)
+		for _, handle := range qm.LimitedAccounts(state) {
+			c.LimitedAccounts[handle] = true
+		}
+	}
+
+	if hasCapacity, _, err := doltserver.HasConnectionCapacity(townRoot); err != nil || !hasCapacity {
+		c.DoltAtCapacity = true
+	}
+
+	return runningPolecatUsage(tmux.NewTmux(), accounts), c
+}
+
+// runningPolecatUsage counts live polecat sessions by rig and by account.
+// The account is derived from the session's CLAUDE_CONFIG_DIR; sessions
+// without one count against the default account.
+func runningPolecatUsage(t *tmux.Tmux, accounts *config.AccountsConfig) scheduler.Usage {
+	usage := scheduler.Usage{ByRig: make(map[string]int), ByAccount: make(map[string]int)}
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return usage
+	}
+	for _, sess := range sessions {
+		identity, err := session.ParseSessionName(sess)
+		if err != nil || identity.Role != session.RolePolecat {
+			continue
+		}
+		usage.Total++
+		usage.ByRig[identity.Rig]++
+
+		if accounts == nil {
+			continue
+		}
+		handle := accounts.Default
+		if configDir, err := t.GetEnvironment(sess, "CLAUDE_CONFIG_DIR"); err == nil {
+			configDir = strings.TrimSpace(configDir)
+			for h, acct := range accounts.Accounts {
+				if acct.ConfigDir == configDir || util.ExpandHome(acct.ConfigDir) == configDir {
+					handle = h
+					break
+				}
+			}
+		}
+		if handle != "" {
+			usage.ByAccount[handle]++
+		}
+	}
+	return usage
+}
+
+// dispatchQueuedSlings slings every queued entry that fits within current
+// capacity, one `gt sling` subprocess at a time. Entries whose bead is no
+// longer open are dropped; failed entries stay queued with their error and
+// back off before the next attempt, and are parked after MaxAttempts.
+func dispatchQueuedSlings(townRoot string, dryRun bool) (int, error) {
+	mgr := scheduler.NewManager(townRoot)
+	unlock, err := mgr.LockDispatch()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	q, err := mgr.Load()
+	if err != nil {
+		return 0, err
+	}
+	if len(q.Entries) == 0 {
+		return 0, nil
+	}
+
+	usage, constraints := schedulerSnapshot(townRoot)
+	gtPath, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("resolving gt binary: %w", err)
+	}
+
+	dispatched := 0
+	for _, d := range scheduler.Plan(q, usage, constraints) {
+		if !d.Dispatch {
+			continue
+		}
+		e := d.Entry
+
+		if info, err := getBeadInfo(e.BeadID); err == nil && info.Status != "open" {
+			fmt.Printf("%s Dropping %s from queue (now %s)\n", style.Dim.Render("○"), e.BeadID, info.Status)
+			if !dryRun {
+				_, _ = mgr.Remove(e.BeadID)
+			}
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("Would sling %s to %s: gt sling %s\n", e.BeadID, e.Rig, strings.Join(e.SlingArgs, " "))
+			dispatched++
+			continue
+		}
+
+		fmt.Printf("%s Dispatching %s to %s...\n", style.Bold.Render("🎯"), e.BeadID, e.Rig)
+		slingCmd := exec.Command(gtPath, append([]string{"sling"}, e.SlingArgs...)...) //nolint:gosec // G204: args were recorded by gt sling --queue
+		slingCmd.Dir = townRoot
+		out, err := slingCmd.CombinedOutput()
+		if err != nil {
+			msg := lastLine(string(out))
+			if msg == "" {
+				msg = err.Error()
+			}
+			style.PrintWarning("dispatching %s failed: %s", e.BeadID, msg)
+			_ = mgr.Update(func(q *scheduler.Queue) error {
+				if entry := q.Find(e.BeadID); entry != nil {
+					entry.RecordFailure(msg, time.Now())
+					if entry.Parked {
+						style.PrintWarning("parking %s after %d failed attempts", e.BeadID, entry.Attempts)
+					}
+				}
+				return nil
+			})
+			// Spawn admission control rejected us: nothing else will fit either
+			if strings.Contains(string(out), "admission control") {
+				break
+			}
+			continue
+		}
+
+		_, _ = mgr.Remove(e.BeadID)
+		dispatched++
+		fmt.Printf("%s Dispatched %s to %s\n", style.Bold.Render("✓"), e.BeadID, e.Rig)
+	}
+	return dispatched, nil
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQueuedSlingArgs(t *testing.T) {
+	prevArgs, prevMerge, prevVars, prevNoConvoy := slingArgs, slingMerge, slingVars, slingNoConvoy
+	t.Cleanup(func() {
+		slingArgs, slingMerge, slingVars, slingNoConvoy = prevArgs, prevMerge, prevVars, prevNoConvoy
+	})
+
+	slingArgs = "focus on tests"
+	slingMerge = "direct"
+	slingVars = []string{"disks=3"}
+	slingNoConvoy = true
+
+	got := queuedSlingArgs("", "gt-abc", "gastown")
+	want := []string{"gt-abc", "gastown", "--args", "focus on tests", "--merge", "direct", "--var", "disks=3", "--no-convoy"}
+	if !slices.Equal(got, want) {
+		t.Errorf("queuedSlingArgs = %q, want %q", got, want)
+	}
+
+	got = queuedSlingArgs("mol-review", "gt-abc", "gastown")
+	if !slices.Equal(got[:4], []string{"mol-review", "--on", "gt-abc", "gastown"}) {
+		t.Errorf("formula-on-bead args = %q", got)
+	}
+}
+
+func TestLastLine(t *testing.T) {
+	if got := lastLine("Allocated polecat: Toast\nError: admission control: at capacity\n\n"); got != "Error: admission control: at capacity" {
+		t.Errorf("lastLine = %q", got)
+	}
+	if got := lastLine(""); got != "" {
+		t.Errorf("lastLine(empty) = %q", got)
+	}
+}
@@ -28,6 +28,16 @@ var (
 	seanceTalk   string
 	seancePrompt string
 	seanceJSON   bool
+
+	// Archive query flags
+	seanceGrep      string
+	seanceTools     bool
+	seanceFiles     bool
+	seanceDecisions bool
+	seanceIndex     bool
+	seanceSession   string
+	seancePolecat   string
+	seanceBead      string
 )
 
 var seanceCmd = &cobra.Command{
@@ -57,6 +67,19 @@ THE SEANCE (talk to predecessor):
 The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
+ASK THE ARCHIVE (no LLM, no tokens):
+  gt seance --grep "migration"               # Search predecessor transcripts
+  gt seance --tools --session <id>           # Which tools/commands were run
+  gt seance --files-touched --polecat Toast  # Files written or edited
+  gt seance --decisions --bead gt-abc        # Decisions the predecessor recorded
+  gt seance --index                          # Refresh the archive only
+
+Transcripts of discovered sessions are archived in ~/gt/.seance/ and indexed
+by role, rig, polecat and bead. The daemon refreshes the archive hourly and
+every gt seance run refreshes it too, so answers survive transcript cleanup
+for sessions archived before it, and work for runtimes that cannot resume
+sessions. When the archive can't answer, fall back to --talk.
+
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
   2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
@@ -70,6 +93,14 @@ func init() {
 	seanceCmd.Flags().StringVarP(&seanceTalk, "talk", "t", "", "Session ID to commune with")
 	seanceCmd.Flags().StringVarP(&seancePrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
 	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
+	seanceCmd.Flags().StringVar(&seanceGrep, "grep", "", "Search archived transcripts (case-insensitive regex)")
+	seanceCmd.Flags().BoolVar(&seanceTools, "tools", false, "Show tool calls from archived transcripts")
+	seanceCmd.Flags().BoolVar(&seanceFiles, "files-touched", false, "Show files written or edited")
+	seanceCmd.Flags().BoolVar(&seanceDecisions, "decisions", false, "Show decisions recorded in archived transcripts")
+	seanceCmd.Flags().BoolVar(&seanceIndex, "index", false, "Refresh the transcript archive")
+	seanceCmd.Flags().StringVar(&seanceSession, "session", "", "Limit archive queries to a session ID (prefix match)")
+	seanceCmd.Flags().StringVar(&seancePolecat, "polecat", "", "Filter by polecat or crew member name")
+	seanceCmd.Flags().StringVar(&seanceBead, "bead", "", "Filter by bead hooked during the session")
 
 	rootCmd.AddCommand(seanceCmd)
 }
@@ -88,6 +119,11 @@ func runSeance(cmd *cobra.Command, args []string) error {
 		return runSeanceTalk(seanceTalk, seancePrompt)
 	}
 
+	// Archive queries answer from stored transcripts without an LLM
+	if seanceArchiveQuery() || seanceIndex {
+		return runSeanceArchive()
+	}
+
 	// Otherwise, list discoverable sessions
 	return runSeanceList()
 }
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// seanceArchiveQuery reports whether any archive query flag is set.
+func seanceArchiveQuery() bool {
+	return seanceGrep != "" || seanceTools || seanceFiles || seanceDecisions
+}
+
+// runSeanceArchive answers handoff questions from the local transcript
+// archive, without resuming the predecessor session in an LLM.
+func runSeanceArchive() error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+
+	indexed, err := syncSeanceArchive(townRoot)
+	if err != nil {
+		return fmt.Errorf("indexing transcripts: %w", err)
+	}
+	if seanceIndex {
+		fmt.Printf("%s Indexed %d session(s) into %s\n", style.Bold.Render("✓"), indexed,
+			filepath.Join(townRoot, transcript.ArchiveDir))
+		if !seanceArchiveQuery() {
+			return nil
+		}
+	}
+
+	var re *regexp.Regexp
+	if seanceGrep != "" {
+		re, err = regexp.Compile("(?i)" + seanceGrep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+	}
+
+	archive := transcript.Open(townRoot)
+	records, err := archive.List()
+	if err != nil {
+		return err
+	}
+	records = filterSeanceRecords(records)
+
+	var results []seanceResult
+	for _, rec := range records {
+		entries, err := archive.Entries(rec.SessionID)
+		if err != nil {
+			continue
+		}
+		res := seanceResult{Record: rec}
+		if re != nil {
+			res.Matches = transcript.Grep(entries, re)
+		}
+		if seanceTools {
+			for _, e := range entries {
+				if e.Kind == transcript.KindTool {
+					res.ToolCalls = append(res.ToolCalls, e)
+				}
+			}
+		}
+		if seanceDecisions {
+			res.Decisions = transcript.Decisions(entries)
+		}
+		if re != nil && len(res.Matches) == 0 {
+			continue
+		}
+		results = append(results, res)
+	}
+
+	if seanceJSON {
+		if results == nil {
+			results = []seanceResult{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No archived sessions matched.")
+		fmt.Println(style.Dim.Render("Fall back to the predecessor itself: gt seance --talk <session-id>"))
+		return nil
+	}
+
+	for _, res := range results {
+		printSeanceResult(res)
+	}
+	return nil
+}
+
+// seanceResult is the per-session answer to an archive query.
+type seanceResult struct {
+	transcript.Record
+	Matches   []transcript.Entry `json:"matches,omitempty"`
+	ToolCalls []transcript.Entry `json:"tool_calls,omitempty"`
+	Decisions []transcript.Entry `json:"decisions,omitempty"`
+}
+
+func printSeanceResult(res seanceResult) {
+	header := fmt.Sprintf("%s  %s  %s", shortSessionID(res.SessionID), res.Actor, res.Started.Local().Format("2006-01-02 15:04"))
+	if res.Bead != "" {
+		header += "  " + res.Bead
+	}
+	fmt.Printf("%s\n", style.Bold.Render(header))
+
+	for _, e := range res.Matches {
+		fmt.Printf("  %s %s\n", style.Dim.Render(entryLabel(e)), oneLine(e.Text))
+	}
+	if seanceTools {
+		var names []string
+		for name := range res.Tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var counts []string
+		for _, name := range names {
+			counts = append(counts, fmt.Sprintf("%s×%d", name, res.Tools[name]))
+		}
+		if len(counts) > 0 {
+			fmt.Printf("  %s\n", style.Dim.Render(strings.Join(counts, "  ")))
+		}
+		for _, e := range res.ToolCalls {
+			fmt.Printf("  %s %s\n", style.Dim.Render(entryLabel(e)), oneLine(e.Text))
+		}
+	}
+	if seanceFiles {
+		for _, f := range res.FilesTouched {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	for _, e := range res.Decisions {
+		fmt.Printf("  • %s\n", oneLine(e.Text))
+	}
+	fmt.Println()
+}
+
+// filterSeanceRecords applies --role/--rig/--polecat/--bead/--session and --recent.
+func filterSeanceRecords(records []transcript.Record) []transcript.Record {
+	var out []transcript.Record
+	for _, r := range records {
+		if seanceSession != "" && !strings.HasPrefix(r.SessionID, seanceSession) {
+			continue
+		}
+		if seanceRole != "" && !strings.EqualFold(r.Role, seanceRole) &&
+			!strings.Contains(strings.ToLower(r.Actor), strings.ToLower(seanceRole)) {
+			continue
+		}
+		if seanceRig != "" && !strings.EqualFold(r.Rig, seanceRig) {
+			continue
+		}
+		if seancePolecat != "" && !strings.EqualFold(r.Polecat, seancePolecat) {
+			continue
+		}
+		if seanceBead != "" && r.Bead != seanceBead {
+			continue
+		}
+		out = append(out, r)
+	}
+	if seanceRecent > 0 && len(out) > seanceRecent {
+		out = out[:seanceRecent]
+	}
+	return out
+}
+
+// syncSeanceArchive archives transcripts for sessions discovered from
+// session_start events. Transcripts are only re-parsed when they changed
+// since the last sync, and the index is rewritten once at the end. Returns
+// the number of sessions (re)indexed.
+func syncSeanceArchive(townRoot string) (_ int, err error) {
+	sessions, err := discoverSessions(townRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	archive := transcript.Open(townRoot)
+	records, err := archive.List()
+	if err != nil {
+		return 0, err
+	}
+	known := make(map[string]transcript.Record, len(records))
+	for _, r := range records {
+		known[r.SessionID] = r
+	}
+
+	hooks := readHookEvents(townRoot)
+
+	// Index whatever was archived, even if a later session failed.
+	var written []transcript.Record
+	defer func() {
+		if indexErr := archive.Index(written...); indexErr != nil && err == nil {
+			err = indexErr
+		}
+	}()
+
+	for _, s := range sessions {
+		sessionID := getPayloadString(s.Payload, "session_id")
+		if sessionID == "" {
+			continue
+		}
+		cwd := getPayloadString(s.Payload, "cwd")
+
+		path := locateTranscript(townRoot, sessionID, cwd)
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if prev, ok := known[sessionID]; ok && !info.ModTime().After(prev.SourceModTime) {
+			continue
+		}
+
+		entries, err := transcript.Parse(path)
+		if err != nil && len(entries) == 0 {
+			continue
+		}
+
+		role, rig, name := parseRoleString(s.Actor)
+		started, _ := time.Parse(time.RFC3339, s.Timestamp)
+		rec := transcript.Record{
+			SessionID:     sessionID,
+			Actor:         s.Actor,
+			Role:          string(role),
+			Rig:           rig,
+			Polecat:       name,
+			Cwd:           cwd,
+			Started:       started,
+			Source:        path,
+			SourceModTime: info.ModTime(),
+		}
+		ended := started
+		for _, e := range entries {
+			if e.Timestamp.After(ended) {
+				ended = e.Timestamp
+			}
+		}
+		rec.Bead = beadForSession(hooks, s.Actor, ended)
+
+		rec, err = archive.WriteSession(rec, entries)
+		if err != nil {
+			return len(written), err
+		}
+		written = append(written, rec)
+	}
+
+	return len(written), nil
+}
+
+// locateTranscript finds the Claude Code transcript for a session, first in
+// the current account's project dir for the session's cwd, then across all
+// configured accounts.
+func locateTranscript(townRoot, sessionID, cwd string) string {
+	if cwd != "" {
+		if projectDir, err := getClaudeProjectDir(cwd); err == nil {
+			path := filepath.Join(projectDir, sessionID+".jsonl")
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	if loc := findSessionLocation(townRoot, sessionID); loc != nil {
+		path := filepath.Join(loc.configDir, "projects", loc.projectDir, sessionID+".jsonl")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// readHookEvents reads hook and sling events, used to attribute sessions to beads.
+func readHookEvents(townRoot string) []sessionEvent {
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var out []sessionEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event sessionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if event.Type == events.TypeHook || event.Type == events.TypeSling {
+			out = append(out, event)
+		}
+	}
+	return out
+}
+
+// beadForSession returns the bead most recently hooked to (or slung at)
+// actor before the session ended.
+func beadForSession(hooks []sessionEvent, actor string, ended time.Time) string {
+	var bead string
+	var latest time.Time
+	for _, h := range hooks {
+		if h.Actor != actor && getPayloadString(h.Payload, "target") != actor {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, h.Timestamp)
+		if err != nil || (!ended.IsZero() && ts.After(ended)) || ts.Before(latest) {
+			continue
+		}
+		if b := getPayloadString(h.Payload, "bead"); b != "" {
+			bead = b
+			latest = ts
+		}
+	}
+	return bead
+}
+
+func entryLabel(e transcript.Entry) string {
+	if e.Kind == transcript.KindTool {
+		return "[" + e.Tool + "]"
+	}
+	return "[" + e.Kind + "]"
+}
+
+func shortSessionID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// oneLine collapses whitespace and trims text for single-line display.
+func oneLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 160 {
+		s = util.TruncateUTF8(s, 159) + "…"
+	}
+	return s
+}
@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/steveyegge/gastown/internal/config"
 )
@@ -373,3 +376,33 @@ func TestCleanupOrphanedSessionSymlinks(t *testing.T) {
 		}
 	})
 }
+
+func TestBeadForSession(t *testing.T) {
+	hooks := []sessionEvent{
+		{Timestamp: "2026-01-02T09:00:00Z", Type: "sling", Actor: "mayor", Payload: map[string]interface{}{"bead": "gt-old", "target": "gastown/polecats/Toast"}},
+		{Timestamp: "2026-01-02T10:00:00Z", Type: "hook", Actor: "gastown/polecats/Toast", Payload: map[string]interface{}{"bead": "gt-abc"}},
+		{Timestamp: "2026-01-02T10:30:00Z", Type: "hook", Actor: "gastown/polecats/Nux", Payload: map[string]interface{}{"bead": "gt-nux"}},
+		{Timestamp: "2026-01-02T12:00:00Z", Type: "hook", Actor: "gastown/polecats/Toast", Payload: map[string]interface{}{"bead": "gt-later"}},
+	}
+	ended := time.Date(2026, 1, 2, 11, 0, 0, 0, time.UTC)
+
+	if got := beadForSession(hooks, "gastown/polecats/Toast", ended); got != "gt-abc" {
+		t.Errorf("beadForSession = %q, want gt-abc", got)
+	}
+	if got := beadForSession(hooks, "gastown/polecats/Toast", time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)); got != "gt-old" {
+		t.Errorf("beadForSession via sling target = %q, want gt-old", got)
+	}
+	if got := beadForSession(hooks, "gastown/crew/max", ended); got != "" {
+		t.Errorf("beadForSession for unknown actor = %q, want empty", got)
+	}
+}
+
+func TestOneLineKeepsUTF8(t *testing.T) {
+	got := oneLine("a" + strings.Repeat("日", 100))
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "…") || len(got) > 160+len("…") {
+		t.Errorf("oneLine = %q", got)
+	}
+	if got := oneLine("  several\n words\t here "); got != "several words here" {
+		t.Errorf("oneLine = %q", got)
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	replayAt        string
+	replaySegment   string
+	replaySpeed     float64
+	replayIdleLimit time.Duration
+	replayList      bool
+	replayJSON      bool
+
+	recordPipeTown            string
+	recordPipeAgent           string
+	recordPipeWidth           int
+	recordPipeHeight          int
+	recordPipeSegmentBytes    int64
+	recordPipeSegmentDuration time.Duration
+)
+
+var sessionReplayCmd = &cobra.Command{
+	Use:   "replay [<rig>/<polecat>]",
+	Short: "Replay a recorded polecat session",
+	Long: `Play back a polecat's recorded terminal output.
+
+Recording is off by default. Enable it in settings/config.json; sessions
+started afterwards pipe their pane into asciicast v2 files under
+.recordings/<rig>/<polecat>/:
+
+  "recording": {"enabled": true, "segment_mb": 8, "segment_duration": "1h"}
+
+Recordings outlive the session, so they can be replayed after a crash or
+a warrant. Segments are pruned by 'gt krc prune' after the recording TTL
+(default 7d, see 'gt krc config set recordings').
+
+Without --at the latest segment is played from the start. With --at the
+segment recording at that time is fast-forwarded to it and played from
+there. The .cast files also play in asciinema and in the dashboard.
+
+Examples:
+  gt session replay gastown/nux                 # Latest segment
+  gt session replay gastown/nux --at 14:05      # From 14:05 today
+  gt session replay gastown/nux --at 2h --speed 4
+  gt session replay gastown/nux --list          # List segments
+  gt session replay --list                      # List recorded agents`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSessionReplay,
+}
+
+var sessionRecordPipeCmd = &cobra.Command{
+	Use:    "record-pipe",
+	Short:  "Record a pane's output from stdin (used by tmux pipe-pane)",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runSessionRecordPipe,
+}
+
+func init() {
+	sessionReplayCmd.Flags().StringVar(&replayAt, "at", "", "Start at this time (RFC 3339, \"2006-01-02 15:04\", \"15:04\" or an age like \"2h\")")
+	sessionReplayCmd.Flags().StringVar(&replaySegment, "segment", "", "Play this segment (see --list)")
+	sessionReplayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "Playback speed multiplier")
+	sessionReplayCmd.Flags().DurationVar(&replayIdleLimit, "idle-limit", 2*time.Second, "Cap pauses between output at this long (0 = no cap)")
+	sessionReplayCmd.Flags().BoolVar(&replayList, "list", false, "List recordings instead of playing")
+	sessionReplayCmd.Flags().BoolVar(&replayJSON, "json", false, "Output --list as JSON")
+
+	sessionRecordPipeCmd.Flags().StringVar(&recordPipeTown, "town", "", "Town root")
+	sessionRecordPipeCmd.Flags().StringVar(&recordPipeAgent, "agent", "", "Agent being recorded (<rig>/<polecat>)")
+	sessionRecordPipeCmd.Flags().IntVar(&recordPipeWidth, "width", 80, "Pane width")
+	sessionRecordPipeCmd.Flags().IntVar(&recordPipeHeight, "height", 24, "Pane height")
+	sessionRecordPipeCmd.Flags().Int64Var(&recordPipeSegmentBytes, "segment-bytes", recording.DefaultSegmentBytes, "Rotate after this many bytes")
+	sessionRecordPipeCmd.Flags().DurationVar(&recordPipeSegmentDuration, "segment-duration", recording.DefaultSegmentDuration, "Rotate after this long")
+
+	sessionCmd.AddCommand(sessionReplayCmd)
+	sessionCmd.AddCommand(sessionRecordPipeCmd)
+}
+
+func runSessionReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 0 {
+		if !replayList {
+			return fmt.Errorf("specify <rig>/<polecat> to replay, or --list to list recordings")
+		}
+		agents, err := recording.ListAgents(townRoot)
+		if err != nil {
+			return err
+		}
+		if replayJSON {
+			return outputJSON(agents)
+		}
+		if len(agents) == 0 {
+			fmt.Println("No recordings. Enable with \"recording\": {\"enabled\": true} in settings/config.json.")
+			return nil
+		}
+		for _, a := range agents {
+			fmt.Println(a)
+		}
+		return nil
+	}
+
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	agent := rigName + "/" + polecatName
+	segs, err := recording.ListSegments(townRoot, agent)
+	if err != nil {
+		return err
+	}
+
+	if replayList {
+		if replayJSON {
+			if segs == nil {
+				segs = []recording.Segment{}
+			}
+			return outputJSON(segs)
+		}
+		printRecordingSegments(agent, segs)
+		return nil
+	}
+	if len(segs) == 0 {
+		return fmt.Errorf("no recordings for %s", agent)
+	}
+
+	seg, offset, err := selectReplaySegment(segs, replaySegment, replayAt, time.Now())
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(seg.Path)
+	if err != nil {
+		return fmt.Errorf("opening recording: %w", err)
+	}
+	defer f.Close()
+
+	fmt.Fprintf(os.Stderr, "%s %s %s (started %s)\n", style.Dim.Render("▶"), agent, seg.Name,
+		seg.Start.Local().Format("2006-01-02 15:04:05"))
+	err = recording.Replay(os.Stdout, f, recording.ReplayOptions{
+		Speed:     replaySpeed,
+		IdleLimit: replayIdleLimit,
+		Offset:    offset,
+	})
+	fmt.Print("\x1b[0m\n")
+	fmt.Fprintf(os.Stderr, "%s end of %s\n", style.Dim.Render("■"), seg.Name)
+	return err
+}
+
+// selectReplaySegment picks the segment to play and how far into it to start:
+// the named segment, the one recording at the --at time, or the latest.
+func selectReplaySegment(segs []recording.Segment, name, at string, now time.Time) (recording.Segment, time.Duration, error) {
+	if name != "" {
+		for _, s := range segs {
+			if s.Name == name {
+				return s, 0, nil
+			}
+		}
+		return recording.Segment{}, 0, fmt.Errorf("no segment %q (see --list)", name)
+	}
+	if at == "" {
+		return segs[len(segs)-1], 0, nil
+	}
+
+	t, err := parseRestoreTime(at, now)
+	if err != nil {
+		return recording.Segment{}, 0, err
+	}
+	seg, ok := recording.SegmentAt(segs, t)
+	if !ok {
+		return recording.Segment{}, 0, fmt.Errorf("nothing recorded at %s (recordings cover %s to %s, with gaps; see --list)",
+			t.Local().Format("2006-01-02 15:04:05"),
+			segs[0].Start.Local().Format("2006-01-02 15:04:05"),
+			segs[len(segs)-1].End.Local().Format("2006-01-02 15:04:05"))
+	}
+	return seg, t.Sub(seg.Start), nil
+}
+
+func printRecordingSegments(agent string, segs []recording.Segment) {
+	if len(segs) == 0 {
+		fmt.Printf("No recordings for %s.\n", agent)
+		return
+	}
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Recordings:"), agent)
+	for _, s := range segs {
+		fmt.Printf("  %-26s %s – %s  %s\n", s.Name,
+			s.Start.Local().Format("2006-01-02 15:04:05"),
+			s.End.Local().Format("15:04:05"),
+			style.Dim.Render(formatBytes(s.Bytes)))
+	}
+}
+
+func runSessionRecordPipe(cmd *cobra.Command, args []string) error {
+	townRoot := recordPipeTown
+	if townRoot == "" {
+		var err error
+		if townRoot, err = workspace.FindFromCwdOrError(); err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+	}
+	rec, err := recording.NewRecorder(townRoot, recordPipeAgent, recording.Options{
+		Width:           recordPipeWidth,
+		Height:          recordPipeHeight,
+		SegmentBytes:    recordPipeSegmentBytes,
+		SegmentDuration: recordPipeSegmentDuration,
+	})
+	if err != nil {
+		return err
+	}
+	_, copyErr := io.Copy(rec, os.Stdin)
+	if err := rec.Close(); copyErr == nil {
+		copyErr = err
+	}
+	return copyErr
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/recording"
+)
+
+func TestSelectReplaySegment(t *testing.T) {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.Local)
+	segs := []recording.Segment{
+		{Name: "20260501T090000Z.cast", Start: base, End: base.Add(time.Hour)},
+		{Name: "20260501T100000Z.cast", Start: base.Add(time.Hour), End: base.Add(90 * time.Minute)},
+	}
+	now := base.Add(2 * time.Hour)
+
+	tests := []struct {
+		name, segment, at string
+		want              string
+		offset            time.Duration
+		wantErr           bool
+	}{
+		{name: "latest", want: "20260501T100000Z.cast"},
+		{name: "by name", segment: "20260501T090000Z.cast", want: "20260501T090000Z.cast"},
+		{name: "unknown name", segment: "nope.cast", wantErr: true},
+		{name: "clock time", at: "2026-05-01 09:20", want: "20260501T090000Z.cast", offset: 20 * time.Minute},
+		{name: "age", at: "45m", want: "20260501T100000Z.cast", offset: 15 * time.Minute},
+		{name: "after recording", at: "10m", wantErr: true},
+		{name: "bad time", at: "yesterday-ish", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg, offset, err := selectReplaySegment(segs, tt.segment, tt.at, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", seg.Name)
+				}
+				return
+			}
+			if err != nil || seg.Name != tt.want || offset != tt.offset {
+				t.Errorf("got %s +%v, %v; want %s +%v", seg.Name, offset, err, tt.want, tt.offset)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -57,6 +58,7 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --create               # Create polecat if missing
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
+  gt sling gp-abc greenplace --affinity             # Prefer the polecat with relevant history
 
 Natural Language Args:
   gt sling gt-abc --args "patch release"
@@ -97,7 +99,14 @@ Batch Slinging:
 
   When multiple beads are provided with a rig target, each bead gets its own
   polecat. This parallelizes work dispatch without running gt sling N times.
-  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.`,
+  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.
+
+Queued Slinging (--queue):
+  gt sling gt-abc gastown --queue          # Wait for capacity instead of spawning now
+  gt sling gt-abc gt-def gastown --queue   # Queue several beads
+
+  Queued work is dispatched by priority as town, rig and account polecat
+  limits, Dolt capacity and account quota allow. See 'gt schedule'.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSling,
 }
@@ -125,6 +134,8 @@ var (
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
+	slingQueue         bool   // --queue: enqueue for the capacity-aware scheduler instead of spawning now
+	slingAffinity      bool   // --affinity: pick the polecat identity with the most relevant history
 )
 
 func init() {
@@ -150,11 +161,13 @@ func init() {
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
+	slingCmd.Flags().BoolVar(&slingQueue, "queue", false, "Queue for the scheduler instead of spawning now (starts when rig/account capacity frees up)")
+	slingCmd.Flags().BoolVar(&slingAffinity, "affinity", false, "Pick the polecat identity whose past beads and commits best match the bead")
 
 	rootCmd.AddCommand(slingCmd)
 }
 
-func runSling(cmd *cobra.Command, args []string) error {
+func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	// Polecats cannot sling - check early before writing anything.
 	// Check GT_ROLE first: coordinators (mayor, witness, etc.) may have a stale
 	// GT_POLECAT in their environment from spawning polecats. Only block if the
@@ -236,6 +249,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Queue mode: hand rig-targeted work to the scheduler (see gt schedule)
+	if slingQueue {
+		return runQueueSling(args, townRoot)
+	}
+
 	// Batch mode detection: multiple beads with rig target
 	// Pattern: gt sling gt-abc gt-def gt-ghi gastown
 	// When len(args) > 2 and last arg is a rig, sling each bead to its own polecat
@@ -377,6 +395,17 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Mint a trace for this assignment. It is stored on the bead and passed
+	// to the polecat, so spawn, done, merge and convoy stages join it.
+	var traceID string
+	var slingSpan *tracing.Span
+	if !slingDryRun {
+		traceID = tracing.NewTraceID()
+		slingSpan = tracing.StartRoot(townRoot, "sling", traceID)
+		slingSpan.SetAttr("bead", beadID)
+		defer func() { slingSpan.End(retErr) }()
+	}
+
 	// Resolve target agent using shared dispatch logic.
 	// Note: args[1] == args[len(args)-1] here because batch mode (len(args) > 2
 	// with rig last arg) exits at line 234. The only remaining case is len(args) <= 2.
@@ -384,6 +413,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		target = args[1]
 	}
+	resolveSpan := tracing.Start(townRoot, "sling.resolve_target", slingSpan.Context())
+	resolveSpan.SetAttr("target", target)
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:     slingDryRun,
 		Force:      force,
@@ -395,11 +426,15 @@ func runSling(cmd *cobra.Command, args []string) error {
 		BeadID:     beadID,
 		TownRoot:   townRoot,
 		BaseBranch: slingBaseBranch,
+		Formula:    formulaName,
+		Affinity:   slingAffinity,
 	})
+	resolveSpan.End(err)
 	if err != nil {
 		return err
 	}
 	targetAgent := resolved.Agent
+	slingSpan.SetAttr("agent", targetAgent)
 	targetPane := resolved.Pane
 	hookWorkDir := resolved.WorkDir
 	hookSetAtomically := resolved.HookSetAtomically
@@ -428,6 +463,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Slinging %s to %s...\n", style.Bold.Render("🎯"), beadID, targetAgent)
 	}
 
+	// Warn when the bead's files are already being edited on in-flight branches
+	if rigName, _, ok := strings.Cut(targetAgent, "/polecats/"); ok {
+		warnSlingOverlap(townRoot, rigName, info)
+	}
+
 	// Handle --force when bead is already hooked: send shutdown to old polecat and unhook
 	if info.Status == "hooked" && force && info.Assignee != "" {
 		fmt.Printf("%s Bead already hooked to %s, forcing reassignment...\n", style.Warning.Render("⚠"), info.Assignee)
@@ -619,7 +659,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// Hook the bead with retry and verification.
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-	if err := hookBeadWithRetry(beadID, targetAgent, hookDir); err != nil {
+	hookSpan := tracing.Start(townRoot, "hook", slingSpan.Context())
+	hookSpan.SetAttr("bead", beadID)
+	err = hookBeadWithRetry(beadID, targetAgent, hookDir)
+	hookSpan.End(err)
+	if err != nil {
 		if newPolecatInfo != nil {
 			rollbackSlingArtifactsFn(newPolecatInfo, beadID, hookWorkDir)
 			if force && originalStatus == "pinned" {
@@ -660,6 +704,10 @@ func runSling(cmd *cobra.Command, args []string) error {
 		Mode:             slingMode,
 		ConvoyID:         slingConvoyID,
 		MergeStrategy:    slingConvoyMergeStrategy,
+		TraceID:          traceID,
+	}
+	if newPolecatInfo != nil {
+		fieldUpdates.Affinity = newPolecatInfo.Affinity
 	}
 	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
 		// Warn but don't fail - polecat will still complete work
@@ -705,6 +753,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// This ensures polecat sees the molecule when gt prime runs on session start.
 	freshlySpawned := newPolecatInfo != nil
 	if freshlySpawned {
+		newPolecatInfo.TraceParent = slingSpan.Context().TraceParent()
 		pane, err := newPolecatInfo.StartSession()
 		if err != nil {
 			// Rollback: session failed, clean up zombie artifacts (worktree, hooked bead).
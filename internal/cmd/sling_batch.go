@@ -125,6 +125,8 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			}
 		}
 
+		warnSlingOverlap(townRoot, rigName, info)
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:      slingForce,
@@ -133,6 +135,8 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			HookBead:   beadID, // Set atomically at spawn time
 			Agent:      slingAgent,
 			BaseBranch: slingBaseBranch,
+			Formula:    formulaName,
+			Affinity:   slingAffinity,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -220,6 +224,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Args:             slingArgs,
 			AttachedMolecule: attachedMoleculeID,
 			NoMerge:          slingNoMerge,
+			Affinity:         spawnInfo.Affinity,
 		}
 		// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
 		if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
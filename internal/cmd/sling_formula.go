@@ -96,6 +96,7 @@ func runSlingFormula(args []string) error {
 		NoBoot:   slingNoBoot,
 		WorkDesc: formulaName,
 		TownRoot: townRoot,
+		Formula:  formulaName,
 	})
 	if err != nil {
 		return err
@@ -75,6 +75,7 @@ type beadInfo struct {
 	Title        string          `json:"title"`
 	Status       string          `json:"status"`
 	Assignee     string          `json:"assignee"`
+	Priority     int             `json:"priority"`
 	Description  string          `json:"description"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
 }
@@ -220,6 +221,8 @@ type beadFieldUpdates struct {
 	Mode             string // Execution mode: "" (normal) or "ralph"
 	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local"
+	Affinity         string // Affinity match that picked the polecat
+	TraceID          string // Trace minted for this sling
 }
 
 // storeFieldsInBead performs a single read-modify-write to update all attachment fields
@@ -283,6 +286,12 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.MergeStrategy != "" {
 		fields.MergeStrategy = updates.MergeStrategy
 	}
+	if updates.Affinity != "" {
+		fields.Affinity = updates.Affinity
+	}
+	if updates.TraceID != "" {
+		fields.TraceID = updates.TraceID
+	}
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -107,6 +107,8 @@ type ResolveTargetOptions struct {
 	TownRoot   string
 	WorkDesc   string // Description for dog dispatch (defaults to HookBead if empty)
 	BaseBranch string // Override base branch for polecat worktree
+	Formula    string // Formula being slung (selects checkout overrides for new polecats)
+	Affinity   bool   // Pick the polecat identity whose history best matches the bead
 }
 
 // ResolvedTarget holds the results of target resolution.
@@ -195,6 +197,8 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 			HookBead:   opts.HookBead,
 			Agent:      opts.Agent,
 			BaseBranch: opts.BaseBranch,
+			Formula:    opts.Formula,
+			Affinity:   opts.Affinity,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -232,6 +236,8 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 					HookBead:   opts.HookBead,
 					Agent:      opts.Agent,
 					BaseBranch: opts.BaseBranch,
+					Formula:    opts.Formula,
+					Affinity:   opts.Affinity,
 				}
 				spawnInfo, spawnErr := spawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 var tapGuardCmd = &cobra.Command{
@@ -20,12 +21,20 @@ forbidden operation entirely.
 
 Available guards:
   pr-workflow      - Block PR creation and feature branches
+  policy           - Evaluate declarative rules per role and rig
+
+Policy tools:
+  list             - Show the guard rules in effect
+  test <rule>      - Check a rule against a sample tool call
 
 Example hook configuration:
   {
     "PreToolUse": [{
       "matcher": "Bash(gh pr create*)",
       "hooks": [{"command": "gt tap guard pr-workflow"}]
+    }, {
+      "matcher": "Bash",
+      "hooks": [{"command": "gt tap guard policy"}]
     }]
   }`,
 }
@@ -76,6 +85,8 @@ func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, "║  See: ~/gt/docs/PRIMING.md (GUPP principle)                     ║")
 		fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
 		fmt.Fprintln(os.Stderr, "")
+		_ = events.LogFeed(events.TypeGuardBlock, detectActor(),
+			events.GuardBlockPayload("pr-workflow", "Bash", "agent context", ""))
 		return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
 	}
 
@@ -92,6 +103,8 @@ func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, "║  Do this:     git push origin main                              ║")
 		fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
 		fmt.Fprintln(os.Stderr, "")
+		_ = events.LogFeed(events.TypeGuardBlock, detectActor(),
+			events.GuardBlockPayload("pr-workflow", "Bash", "maintainer origin", ""))
 		return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
 	}
 
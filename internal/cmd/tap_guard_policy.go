@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/guard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	tapGuardTestInput string
+	tapGuardTestRole  string
+	tapGuardTestRig   string
+)
+
+var tapGuardPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Evaluate declarative guard rules (PreToolUse hook)",
+	Long: `Evaluate the tool call on stdin against the guard policy.
+
+Reads the PreToolUse hook payload from stdin and checks it against every
+rule in scope for the current role and rig. The first matching rule blocks
+the call and the block is logged to the events feed.
+
+Policy layers (later layers replace rules with the same name):
+  1. Built-in defaults (embedded in binary)
+  2. Town-level policy (<town>/guards/policy.toml)
+  3. Rig-level policy (<rig>/guards/policy.toml)
+
+Built-in rules block force pushes to main/master and recursive deletes of
+/ or $HOME for everyone, and keep polecats inside their own worktree and
+off network tools (curl, wget, ssh, ...). Relative paths resolve against the
+directory the tool call runs in; a polecat's worktree comes from its identity
+(GT_ROLE, GT_RIG, GT_POLECAT), not from where it has cd'd.
+
+Example rule:
+  [[rule]]
+  name = "no-secrets"
+  description = "Keep agents out of key material"
+  paths = ["*.pem", "~/.ssh/**"]
+
+Exit codes:
+  0 - Operation allowed
+  2 - Operation BLOCKED`,
+	RunE: runTapGuardPolicy,
+}
+
+var tapGuardTestCmd = &cobra.Command{
+	Use:   "test <rule>",
+	Short: "Test a guard rule against a tool call",
+	Long: `Evaluate a single guard rule against a sample tool call.
+
+The input file holds a PreToolUse hook payload. Role and rig scoping are
+ignored unless --role or --rig is given, so a rule can be exercised from
+any directory.
+
+Examples:
+  gt tap guard test no-force-push --input tool.json
+  gt tap guard test worktree-only --input write.json --role polecat
+
+Exit codes:
+  0 - The rule allows the call
+  2 - The rule blocks the call`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTapGuardTest,
+}
+
+var tapGuardListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List guard rules in effect",
+	Long: `List the merged guard policy for the current town and rig.
+
+Disabled rules and rules scoped to other roles are shown dimmed.`,
+	RunE: runTapGuardList,
+}
+
+func init() {
+	tapGuardCmd.AddCommand(tapGuardPolicyCmd)
+	tapGuardCmd.AddCommand(tapGuardTestCmd)
+	tapGuardCmd.AddCommand(tapGuardListCmd)
+
+	tapGuardTestCmd.Flags().StringVar(&tapGuardTestInput, "input", "", "Path to PreToolUse JSON payload (- for stdin)")
+	tapGuardTestCmd.Flags().StringVar(&tapGuardTestRole, "role", "", "Evaluate as this role")
+	tapGuardTestCmd.Flags().StringVar(&tapGuardTestRig, "rig", "", "Evaluate as this rig")
+	_ = tapGuardTestCmd.MarkFlagRequired("input")
+}
+
+func runTapGuardPolicy(cmd *cobra.Command, args []string) error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("reading hook input: %w", err)
+	}
+	in, err := parseGuardInput(data)
+	if err != nil {
+		// Malformed input must not wedge the agent; allow and report.
+		fmt.Fprintf(os.Stderr, "gt tap guard policy: %v\n", err)
+		return nil
+	}
+
+	ctx, townRoot, rigPath := guardContext(in.Cwd)
+	if townRoot == "" {
+		// Outside Gas Town there is no policy to enforce.
+		return nil
+	}
+
+	policy, err := guard.LoadPolicy(townRoot, rigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt tap guard policy: %v\n", err)
+		return nil
+	}
+
+	decision, err := guard.Evaluate(policy.Rules, in, ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt tap guard policy: %v\n", err)
+		return nil
+	}
+	if !decision.Blocked {
+		return nil
+	}
+
+	_ = events.LogFeed(events.TypeGuardBlock, detectActor(),
+		events.GuardBlockPayload(decision.Rule.Name, in.ToolName, decision.Reason, guardTarget(in)))
+
+	printGuardBlock(decision)
+	return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
+}
+
+func runTapGuardTest(cmd *cobra.Command, args []string) error {
+	ruleName := args[0]
+
+	var data []byte
+	var err error
+	if tapGuardTestInput == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(tapGuardTestInput)
+	}
+	if err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	in, err := parseGuardInput(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, townRoot, rigPath := guardContext(in.Cwd)
+	policy, err := guard.LoadPolicy(townRoot, rigPath)
+	if err != nil {
+		return err
+	}
+	rule := policy.Find(ruleName)
+	if rule == nil {
+		return fmt.Errorf("unknown guard rule %q (see 'gt tap guard list')", ruleName)
+	}
+
+	if tapGuardTestRole != "" || tapGuardTestRig != "" {
+		ctx.Role = tapGuardTestRole
+		ctx.Rig = tapGuardTestRig
+		if !rule.AppliesTo(ctx) {
+			fmt.Printf("%s %s: not in scope for role=%q rig=%q\n", style.Dim.Render("○"), rule.Name, ctx.Role, ctx.Rig)
+			return nil
+		}
+	}
+
+	reason, err := rule.Match(in, ctx)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		fmt.Printf("%s %s: allow\n", style.Success.Render("✓"), rule.Name)
+		return nil
+	}
+
+	fmt.Printf("%s %s: BLOCK (%s)\n", style.Error.Render("✗"), rule.Name, reason)
+	return NewSilentExit(2)
+}
+
+func runTapGuardList(cmd *cobra.Command, args []string) error {
+	ctx, townRoot, rigPath := guardContext("")
+	policy, err := guard.LoadPolicy(townRoot, rigPath)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Role != "" {
+		fmt.Printf("Guard rules for %s\n\n", style.Bold.Render(guardScopeLabel(ctx)))
+	}
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		line := fmt.Sprintf("  %-24s %s", rule.Name, rule.Description)
+		switch {
+		case rule.Disabled:
+			fmt.Println(style.Dim.Render(line + " (disabled)"))
+		case ctx.Role != "" && !rule.AppliesTo(ctx):
+			fmt.Println(style.Dim.Render(line + " (out of scope)"))
+		default:
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// parseGuardInput decodes a PreToolUse hook payload.
+func parseGuardInput(data []byte) (*guard.Input, error) {
+	var in guard.Input
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("parsing hook input: %w", err)
+	}
+	if in.ToolName == "" {
+		return nil, fmt.Errorf("hook input missing tool_name")
+	}
+	return &in, nil
+}
+
+// guardContext resolves the agent context for guard evaluation. The town
+// comes from GT_ROOT, else from cwd (the directory the tool call runs in),
+// else from the process working directory. The agent's worktree always
+// comes from its identity (GT_ROLE, GT_RIG, GT_POLECAT), never from cwd, so
+// changing directory can't move the worktree boundary.
+// Returns empty townRoot when not running inside a Gas Town workspace.
+func guardContext(cwd string) (guard.Context, string, string) {
+	var ctx guard.Context
+
+	wd, _ := os.Getwd()
+	var townRoot string
+	for _, candidate := range []string{os.Getenv("GT_ROOT"), cwd, wd} {
+		if candidate == "" {
+			continue
+		}
+		if root, _ := workspace.Find(candidate); root != "" {
+			townRoot = root
+			break
+		}
+	}
+	if townRoot == "" {
+		return ctx, "", ""
+	}
+
+	dir := cwd
+	if dir == "" {
+		dir = wd
+	}
+	if roleInfo, err := GetRoleWithContext(dir, townRoot); err == nil {
+		ctx.Role = string(roleInfo.Role)
+		ctx.Rig = roleInfo.Rig
+		ctx.Worktree = agentWorktree(townRoot, roleInfo)
+	}
+
+	var rigPath string
+	if ctx.Rig != "" {
+		rigPath = filepath.Join(townRoot, ctx.Rig)
+	}
+	return ctx, townRoot, rigPath
+}
+
+// agentWorktree returns the git worktree an agent owns: polecats/<name>/<rig>
+// for polecats (or polecats/<name> for polecats cloned before that layout),
+// the role's home directory otherwise.
+func agentWorktree(townRoot string, info RoleInfo) string {
+	if info.Role != RolePolecat {
+		return info.Home
+	}
+	if info.Rig == "" || info.Polecat == "" {
+		return ""
+	}
+	dir := filepath.Join(townRoot, info.Rig, "polecats", info.Polecat)
+	if _, err := os.Stat(filepath.Join(dir, info.Rig)); os.IsNotExist(err) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+	}
+	return filepath.Join(dir, info.Rig)
+}
+
+// guardTarget returns the command or path a tool call acts on, for logging.
+func guardTarget(in *guard.Input) string {
+	if cmd := in.Command(); cmd != "" {
+		return cmd
+	}
+	return in.FilePath()
+}
+
+func guardScopeLabel(ctx guard.Context) string {
+	if ctx.Rig != "" {
+		return ctx.Rig + "/" + ctx.Role
+	}
+	return ctx.Role
+}
+
+func printGuardBlock(d guard.Decision) {
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintf(os.Stderr, "❌ BLOCKED by guard rule %q\n", d.Rule.Name)
+	if d.Rule.Description != "" {
+		fmt.Fprintf(os.Stderr, "   %s\n", d.Rule.Description)
+	}
+	fmt.Fprintf(os.Stderr, "   Matched: %s\n", d.Reason)
+	if d.Rule.Message != "" {
+		fmt.Fprintln(os.Stderr, "")
+		for _, line := range strings.Split(strings.TrimSpace(d.Rule.Message), "\n") {
+			fmt.Fprintf(os.Stderr, "   %s\n", line)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "")
+}
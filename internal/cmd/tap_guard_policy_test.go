@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGuardContext_WorktreeFromIdentity(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	worktree := filepath.Join(townRoot, "gastown", "polecats", "toast", "gastown")
+	other := filepath.Join(townRoot, "gastown", "polecats", "nux", "gastown")
+	for _, dir := range []string{worktree, other} {
+		if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+			t.Fatalf("git init: %v\n%s", err, out)
+		}
+	}
+	t.Setenv("GT_ROOT", townRoot)
+	t.Setenv(EnvGTRole, "gastown/polecats/toast")
+	t.Setenv("GT_RIG", "gastown")
+	t.Setenv("GT_POLECAT", "toast")
+	t.Chdir(t.TempDir())
+
+	// Changing into another polecat's worktree must not move the boundary.
+	for _, cwd := range []string{worktree, other, t.TempDir(), ""} {
+		ctx, gotTown, rigPath := guardContext(cwd)
+		if gotTown != townRoot {
+			t.Fatalf("cwd %q: townRoot = %q, want %q", cwd, gotTown, townRoot)
+		}
+		if ctx.Worktree != worktree {
+			t.Errorf("cwd %q: Worktree = %q, want %q", cwd, ctx.Worktree, worktree)
+		}
+		if ctx.Role != string(RolePolecat) || ctx.Rig != "gastown" || rigPath != filepath.Join(townRoot, "gastown") {
+			t.Errorf("cwd %q: ctx = %+v, rigPath = %q", cwd, ctx, rigPath)
+		}
+	}
+}
+
+func TestGuardContext_TownFromHookCwd(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GT_ROOT", "")
+	t.Setenv(EnvGTRole, "")
+	t.Chdir(t.TempDir())
+
+	if _, gotTown, _ := guardContext(filepath.Join(townRoot, "mayor")); gotTown != townRoot {
+		t.Errorf("townRoot = %q, want %q", gotTown, townRoot)
+	}
+	if _, gotTown, _ := guardContext(t.TempDir()); gotTown != "" {
+		t.Errorf("outside any town: townRoot = %q, want empty", gotTown)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tracing"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// traceBarWidth is the width of the waterfall's timeline column.
+const traceBarWidth = 40
+
+var traceJSON bool
+
+var traceCmd = &cobra.Command{
+	Use:     "trace <bead-id|trace-id>",
+	GroupID: GroupDiag,
+	Short:   "Show where a bead's wall-clock time went",
+	Long: `Show the spans recorded for one piece of work, from sling to merge.
+
+gt sling mints a trace ID and stores it on the bead (trace_id). The polecat
+session inherits it via GT_TRACEPARENT, and gt done, the Witness, the Refinery
+and convoy checks each record a span under it. Spans are written to
+logs/traces.jsonl in the town root.
+
+The waterfall shows each span's offset from the sling, its duration, and any
+gap between stages (time spent working or waiting in a queue).
+
+To also export spans to an OpenTelemetry collector (OTLP/HTTP JSON), set
+OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) and,
+if needed, OTEL_EXPORTER_OTLP_HEADERS. Set GT_TRACE=off to disable tracing.
+
+Examples:
+  gt trace gt-abc12
+  gt trace 4bf92f3577b34da6a3ce929d0e0e4736
+  gt trace gt-abc12 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output spans as JSON")
+	rootCmd.AddCommand(traceCmd)
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	spans, err := tracing.ReadSpans(townRoot)
+	if err != nil {
+		return err
+	}
+
+	traceID := args[0]
+	if !tracing.IsTraceID(traceID) {
+		traceID = tracing.TraceForBead(spans, args[0])
+		if traceID == "" {
+			return fmt.Errorf("no trace recorded for %s", args[0])
+		}
+	}
+	trace := tracing.Trace(spans, traceID)
+	if len(trace) == 0 {
+		return fmt.Errorf("no spans recorded for trace %s", traceID)
+	}
+
+	if traceJSON {
+		return outputJSON(trace)
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Trace"), traceID)
+	for _, line := range formatTraceWaterfall(trace) {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// formatTraceWaterfall renders spans (ordered by start) as a waterfall:
+// offset from the first span, duration, a timeline bar, name and attributes.
+// Children are indented under their parents, and idle time between the end
+// of one stage and the start of the next is shown as a gap line.
+func formatTraceWaterfall(trace []tracing.SpanRecord) []string {
+	start, end := trace[0].Start, trace[0].End
+	depth := make(map[string]int, len(trace))
+	for _, s := range trace {
+		if s.End.After(end) {
+			end = s.End
+		}
+		if d, ok := depth[s.ParentID]; ok {
+			depth[s.SpanID] = d + 1
+		} else {
+			depth[s.SpanID] = 0
+		}
+	}
+	total := end.Sub(start)
+
+	var lines []string
+	var lastEnd time.Time
+	for _, s := range trace {
+		if !lastEnd.IsZero() && s.Start.Sub(lastEnd) >= time.Second {
+			lines = append(lines, style.Dim.Render(fmt.Sprintf("%10s  %10s  %s  (gap)",
+				"", formatSpanDuration(s.Start.Sub(lastEnd)), strings.Repeat(" ", traceBarWidth))))
+		}
+		if s.End.After(lastEnd) {
+			lastEnd = s.End
+		}
+
+		name := strings.Repeat("  ", depth[s.SpanID]) + s.Name
+		line := fmt.Sprintf("%10s  %10s  %s  %s",
+			"+"+formatSpanDuration(s.Start.Sub(start)),
+			formatSpanDuration(s.Duration()),
+			traceBar(s.Start.Sub(start), s.Duration(), total),
+			name)
+		if attrs := formatSpanAttrs(s.Attrs); attrs != "" {
+			line += "  " + style.Dim.Render(attrs)
+		}
+		if s.Error != "" {
+			line += "  " + style.Error.Render("error: "+s.Error)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", fmt.Sprintf("Total: %s across %d spans", formatSpanDuration(total), len(trace)))
+	return lines
+}
+
+// traceBar draws a span's position within the trace's total duration.
+func traceBar(offset, dur, total time.Duration) string {
+	if total <= 0 {
+		return strings.Repeat("█", traceBarWidth)
+	}
+	from := int(int64(offset) * traceBarWidth / int64(total))
+	width := int(int64(dur) * traceBarWidth / int64(total))
+	from = min(max(from, 0), traceBarWidth-1)
+	width = min(max(width, 1), traceBarWidth-from)
+	return strings.Repeat("·", from) + strings.Repeat("█", width) + strings.Repeat("·", traceBarWidth-from-width)
+}
+
+// formatSpanDuration renders a duration at a precision suited to its size.
+func formatSpanDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+func formatSpanAttrs(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+attrs[k])
+	}
+	return strings.Join(parts, " ")
+}
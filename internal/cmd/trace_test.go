@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tracing"
+)
+
+func TestFormatTraceWaterfall(t *testing.T) {
+	t0 := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	trace := []tracing.SpanRecord{
+		{SpanID: "a", Name: "sling", Start: t0, End: t0.Add(2 * time.Second), Attrs: map[string]string{"bead": "gt-1"}},
+		{SpanID: "b", ParentID: "a", Name: "hook", Start: t0.Add(time.Second), End: t0.Add(2 * time.Second)},
+		{SpanID: "c", ParentID: "a", Name: "refinery.merge", Start: t0.Add(10 * time.Minute), End: t0.Add(11 * time.Minute), Error: "conflict"},
+	}
+	lines := formatTraceWaterfall(trace)
+
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[0], "sling") || !strings.Contains(lines[0], "bead=gt-1") {
+		t.Errorf("root line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "  hook") {
+		t.Errorf("child should be indented: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "(gap)") || !strings.Contains(lines[2], "9m58s") {
+		t.Errorf("gap line = %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "+10m0s") || !strings.Contains(lines[3], "error: conflict") {
+		t.Errorf("merge line = %q", lines[3])
+	}
+	if !strings.Contains(lines[5], "11m0s across 3 spans") {
+		t.Errorf("total line = %q", lines[5])
+	}
+}
+
+func TestTraceBar(t *testing.T) {
+	if got := traceBar(0, time.Minute, time.Minute); got != strings.Repeat("█", traceBarWidth) {
+		t.Errorf("full bar = %q", got)
+	}
+	// A zero-length span at the very end still shows one cell.
+	got := traceBar(time.Minute, 0, time.Minute)
+	if strings.Count(got, "█") != 1 || !strings.HasSuffix(got, "█") {
+		t.Errorf("end bar = %q", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/krc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -38,7 +39,8 @@ Subcommands:
   hooks      Recent hook activity
 
 Flags:
-  --since    Show activity since this time (e.g., "1h", "24h", "7d")
+  --since    Show activity since this time (e.g., "1h", "24h", "7d");
+             hook activity includes events archived by KRC
   --limit    Maximum number of items to show (default: 20)
   --json     Output as JSON
   --all      Include all activity (not just agents)
@@ -401,20 +403,19 @@ func readHookTrailEntries(eventsPath string, since time.Time, limit int) ([]Hook
 		return []HookEntry{}, nil
 	}
 
-	data, err := os.ReadFile(eventsPath)
+	// With --since, hooks KRC has moved to the archive are included.
+	var lines []string
+	err := krc.ScanEvents(filepath.Dir(eventsPath), filepath.Base(eventsPath), since, func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
 		return nil, fmt.Errorf("reading events file: %w", err)
 	}
-
-	trimmed := strings.TrimSpace(string(data))
-	if trimmed == "" {
+	if len(lines) == 0 {
 		return nil, nil
 	}
 
-	lines := strings.Split(trimmed, "\n")
 	entryCap := len(lines)
 	if limit < entryCap {
 		entryCap = limit
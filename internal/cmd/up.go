@@ -193,6 +193,8 @@ func runUp(cmd *cobra.Command, args []string) error {
 		defer startupWg.Done()
 		if err := ensureDaemon(townRoot); err != nil {
 			daemonErr = err
+		} else if status, err := daemon.QueryStatus(townRoot); err == nil {
+			daemonPID = status.PID
 		} else {
 			running, pid, _ := daemon.IsRunning(townRoot)
 			if running {
@@ -410,8 +412,15 @@ func ensureDaemon(townRoot string) error {
 		return err
 	}
 
-	// Wait for daemon to initialize
-	time.Sleep(300 * time.Millisecond)
+	// Wait for daemon to initialize: it is up once its control socket
+	// answers. Older daemons have no socket, so fall back to the PID file.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := daemon.QueryStatus(townRoot); err == nil {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
 
 	// Verify it started
 	running, _, err = daemon.IsRunning(townRoot)
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CheckoutConfig configures sparse checkout and partial clone for agent
+// workspaces: polecat and dog worktrees, and crew clones.
+//
+// Example (settings/config.json):
+//
+//	"checkout": {
+//	  "filter": "blob:none",
+//	  "sparse": ["services/billing", "libs/common"],
+//	  "overrides": [
+//	    {"label": "area:web", "sparse": ["services/web", "libs/common"]},
+//	    {"formula": "mol-repo-audit", "full": true}
+//	  ]
+//	}
+type CheckoutConfig struct {
+	// Sparse lists cone-mode sparse-checkout directories, relative to the
+	// repo root. Top-level files are always included. Empty means a full checkout.
+	Sparse []string `json:"sparse,omitempty"`
+
+	// Filter is a partial-clone filter (e.g., "blob:none", "tree:0") applied
+	// to crew clones and to the rig's shared repo before creating worktrees.
+	// Objects outside the filter are fetched on demand.
+	Filter string `json:"filter,omitempty"`
+
+	// Overrides select different sparse patterns for work matching a formula
+	// or bead label. The first matching override wins. The partial-clone
+	// filter is repo-wide and cannot be overridden.
+	Overrides []CheckoutOverride `json:"overrides,omitempty"`
+}
+
+// CheckoutOverride replaces the rig's sparse patterns for matching work.
+type CheckoutOverride struct {
+	Formula string   `json:"formula,omitempty"` // formula name the work was slung with
+	Label   string   `json:"label,omitempty"`   // label on the hooked bead
+	Sparse  []string `json:"sparse,omitempty"`  // sparse directories for matching work
+	Full    bool     `json:"full,omitempty"`    // full checkout for matching work
+}
+
+// CheckoutSpec is the resolved checkout shape for one workspace.
+type CheckoutSpec struct {
+	Sparse []string `json:"sparse,omitempty"`
+	Filter string   `json:"filter,omitempty"`
+}
+
+// IsSparse reports whether the spec restricts the checkout.
+func (s CheckoutSpec) IsSparse() bool {
+	return len(s.Sparse) > 0
+}
+
+// Resolve returns the checkout spec for work slung with formula on a bead
+// carrying labels. A nil config resolves to a full checkout.
+func (c *CheckoutConfig) Resolve(formula string, labels []string) CheckoutSpec {
+	if c == nil {
+		return CheckoutSpec{}
+	}
+	spec := CheckoutSpec{Sparse: c.Sparse, Filter: c.Filter}
+	for _, o := range c.Overrides {
+		if !o.matches(formula, labels) {
+			continue
+		}
+		if o.Full {
+			spec.Sparse = nil
+		} else {
+			spec.Sparse = o.Sparse
+		}
+		break
+	}
+	return spec
+}
+
+func (o *CheckoutOverride) matches(formula string, labels []string) bool {
+	if o.Formula == "" && o.Label == "" {
+		return false
+	}
+	if o.Formula != "" && o.Formula != formula {
+		return false
+	}
+	if o.Label != "" {
+		for _, l := range labels {
+			if l == o.Label {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// validateCheckoutConfig validates a CheckoutConfig.
+func validateCheckoutConfig(c *CheckoutConfig) error {
+	if err := validateSparseDirs(c.Sparse); err != nil {
+		return err
+	}
+	if c.Filter != "" && !strings.HasPrefix(c.Filter, "blob:") && !strings.HasPrefix(c.Filter, "tree:") &&
+		!strings.HasPrefix(c.Filter, "object:") && !strings.HasPrefix(c.Filter, "sparse:") &&
+		!strings.HasPrefix(c.Filter, "combine:") {
+		return fmt.Errorf("invalid checkout.filter %q (expected e.g. blob:none or tree:0)", c.Filter)
+	}
+	for i, o := range c.Overrides {
+		if o.Formula == "" && o.Label == "" {
+			return fmt.Errorf("%w: checkout.overrides[%d] needs a formula or label", ErrMissingField, i)
+		}
+		if o.Full && len(o.Sparse) > 0 {
+			return fmt.Errorf("checkout.overrides[%d]: full and sparse are mutually exclusive", i)
+		}
+		if err := validateSparseDirs(o.Sparse); err != nil {
+			return fmt.Errorf("checkout.overrides[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateSparseDirs checks that sparse entries are plain repo-relative
+// directories, as cone mode requires.
+func validateSparseDirs(dirs []string) error {
+	for _, d := range dirs {
+		clean := path.Clean(d)
+		if d == "" || strings.HasPrefix(d, "/") || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("invalid sparse directory %q (must be a repo-relative directory)", d)
+		}
+		if strings.ContainsAny(d, "*?[!") {
+			return fmt.Errorf("invalid sparse directory %q (cone mode takes directories, not patterns)", d)
+		}
+	}
+	return nil
+}
+
+// LoadRigCheckout returns the checkout config from a rig's settings, or nil
+// if none is configured (full checkouts).
+func LoadRigCheckout(rigPath string) *CheckoutConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Checkout
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCheckoutConfigResolve(t *testing.T) {
+	cfg := &CheckoutConfig{
+		Sparse: []string{"services/billing"},
+		Filter: "blob:none",
+		Overrides: []CheckoutOverride{
+			{Label: "area:web", Sparse: []string{"services/web", "libs/common"}},
+			{Formula: "mol-repo-audit", Full: true},
+			{Formula: "mol-polecat-work", Label: "area:infra", Sparse: []string{"infra"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		formula string
+		labels  []string
+		want    []string
+	}{
+		{"default", "mol-polecat-work", nil, []string{"services/billing"}},
+		{"label override", "mol-polecat-work", []string{"p1", "area:web"}, []string{"services/web", "libs/common"}},
+		{"formula full checkout", "mol-repo-audit", nil, nil},
+		{"formula and label both required", "mol-polecat-work", []string{"area:infra"}, []string{"infra"}},
+		{"formula and label mismatch", "mol-other", []string{"area:infra"}, []string{"services/billing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := cfg.Resolve(tt.formula, tt.labels)
+			if !slices.Equal(spec.Sparse, tt.want) {
+				t.Errorf("Sparse = %v, want %v", spec.Sparse, tt.want)
+			}
+			if spec.Filter != "blob:none" {
+				t.Errorf("Filter = %q, want blob:none (filter is repo-wide)", spec.Filter)
+			}
+		})
+	}
+
+	var nilCfg *CheckoutConfig
+	if spec := nilCfg.Resolve("x", nil); spec.IsSparse() || spec.Filter != "" {
+		t.Errorf("nil config should resolve to a full checkout, got %+v", spec)
+	}
+}
+
+func TestValidateCheckoutConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CheckoutConfig
+		wantErr bool
+	}{
+		{"valid", CheckoutConfig{Sparse: []string{"services/billing"}, Filter: "blob:none"}, false},
+		{"tree filter", CheckoutConfig{Filter: "tree:0"}, false},
+		{"bad filter", CheckoutConfig{Filter: "everything"}, true},
+		{"absolute dir", CheckoutConfig{Sparse: []string{"/services"}}, true},
+		{"parent dir", CheckoutConfig{Sparse: []string{"../other"}}, true},
+		{"glob pattern", CheckoutConfig{Sparse: []string{"services/*"}}, true},
+		{"override without selector", CheckoutConfig{Overrides: []CheckoutOverride{{Sparse: []string{"a"}}}}, true},
+		{"override full and sparse", CheckoutConfig{Overrides: []CheckoutOverride{{Label: "x", Full: true, Sparse: []string{"a"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCheckoutConfig(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCheckoutConfig() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRigCheckout(t *testing.T) {
+	rigPath := t.TempDir()
+	if cfg := LoadRigCheckout(rigPath); cfg != nil {
+		t.Fatalf("expected nil without settings, got %+v", cfg)
+	}
+
+	settingsDir := filepath.Join(rigPath, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"rig-settings","version":1,"checkout":{"sparse":["services/billing"],"filter":"blob:none"}}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := LoadRigCheckout(rigPath)
+	if cfg == nil || cfg.Filter != "blob:none" || !slices.Equal(cfg.Sparse, []string{"services/billing"}) {
+		t.Errorf("LoadRigCheckout = %+v", cfg)
+	}
+}
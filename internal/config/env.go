@@ -31,6 +31,10 @@ type AgentEnvConfig struct {
 	// SessionIDEnv is the environment variable name that holds the session ID.
 	// Sets GT_SESSION_ID_ENV so the runtime knows where to find the session ID.
 	SessionIDEnv string
+
+	// TraceParent is the span the agent's work is traced under.
+	// Sets GT_TRACEPARENT so gt done joins the trace.
+	TraceParent string
 }
 
 // AgentEnv returns all environment variables for an agent based on the config.
@@ -114,6 +118,11 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["GT_SESSION_ID_ENV"] = cfg.SessionIDEnv
 	}
 
+	// Add trace context so the agent's spans join the sling's trace
+	if cfg.TraceParent != "" {
+		env["GT_TRACEPARENT"] = cfg.TraceParent
+	}
+
 	// Clear NODE_OPTIONS to prevent debugger flags (e.g., --inspect from VSCode)
 	// from being inherited through tmux into Claude's Node.js runtime.
 	// This is the PRIMARY guard: setting it here (the single source of truth
@@ -140,6 +140,19 @@ func TestAgentEnv_WithoutRuntimeConfigDir(t *testing.T) {
 	assertNotSet(t, env, "CLAUDE_CONFIG_DIR")
 }
 
+func TestAgentEnv_WithTraceParent(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{
+		Role:        "polecat",
+		Rig:         "myrig",
+		AgentName:   "Toast",
+		TownRoot:    "/town",
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+
+	assertEnv(t, env, "GT_TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+}
+
 func TestAgentEnvSimple(t *testing.T) {
 	t.Parallel()
 	env := AgentEnvSimple("polecat", "myrig", "Toast")
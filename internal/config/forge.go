@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Forge providers.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+// ForgeConfig connects a rig to the code forge hosting its repository, so
+// the refinery can mirror MRs as pull requests, relay review comments to
+// polecats, and hold merges on CI and human review.
+//
+// Example (settings/config.json):
+//
+//	"forge": {
+//	  "provider": "github",
+//	  "repo": "acme/widgets",
+//	  "mirror_mrs": true,
+//	  "required_checks": ["ci/build", "lint"],
+//	  "required_approvals": 1
+//	}
+type ForgeConfig struct {
+	// Provider is "github", "gitlab", or "gitea".
+	Provider string `json:"provider"`
+
+	// Repo is the repository path on the forge: "owner/name" on GitHub and
+	// Gitea, the full project path ("group/subgroup/name") on GitLab.
+	Repo string `json:"repo"`
+
+	// APIURL overrides the provider's API base URL, for GitHub Enterprise
+	// and self-hosted GitLab or Gitea (e.g., "https://git.acme.dev/api/v1").
+	// Required for Gitea.
+	APIURL string `json:"api_url,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Defaults to GITHUB_TOKEN, GITLAB_TOKEN, or GITEA_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// MirrorMRs opens a pull request for every MR bead.
+	MirrorMRs bool `json:"mirror_mrs,omitempty"`
+
+	// RequiredChecks are CI check or status names that must succeed on the
+	// MR's head commit before the refinery merges it. "*" requires every
+	// reported check to succeed.
+	RequiredChecks []string `json:"required_checks,omitempty"`
+
+	// RequiredApprovals is the number of approving reviews an MR's pull
+	// request needs before the refinery merges it. Outstanding change
+	// requests always hold the merge when this is set.
+	RequiredApprovals int `json:"required_approvals,omitempty"`
+}
+
+// DefaultTokenEnv returns the environment variable the token is read from.
+func (c *ForgeConfig) DefaultTokenEnv() string {
+	if c.TokenEnv != "" {
+		return c.TokenEnv
+	}
+	return strings.ToUpper(c.Provider) + "_TOKEN"
+}
+
+// Gated reports whether merges wait on the forge (CI checks or reviews).
+func (c *ForgeConfig) Gated() bool {
+	return c != nil && (len(c.RequiredChecks) > 0 || c.RequiredApprovals > 0)
+}
+
+// validateForgeConfig validates a ForgeConfig.
+func validateForgeConfig(c *ForgeConfig) error {
+	switch c.Provider {
+	case ForgeGitHub, ForgeGitLab, ForgeGitea:
+	case "":
+		return fmt.Errorf("%w: forge.provider", ErrMissingField)
+	default:
+		return fmt.Errorf("invalid forge.provider %q (expected github, gitlab, or gitea)", c.Provider)
+	}
+	if c.Repo == "" {
+		return fmt.Errorf("%w: forge.repo", ErrMissingField)
+	}
+	if c.Provider != ForgeGitLab && strings.Count(c.Repo, "/") != 1 {
+		return fmt.Errorf("invalid forge.repo %q (expected owner/name)", c.Repo)
+	}
+	if c.Provider == ForgeGitea && c.APIURL == "" {
+		return fmt.Errorf("%w: forge.api_url (gitea has no default host)", ErrMissingField)
+	}
+	if c.APIURL != "" {
+		u, err := url.Parse(c.APIURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid forge.api_url %q", c.APIURL)
+		}
+	}
+	if c.RequiredApprovals < 0 {
+		return fmt.Errorf("forge.required_approvals must be non-negative")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateForgeConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ForgeConfig
+		wantErr bool
+	}{
+		{"github", ForgeConfig{Provider: ForgeGitHub, Repo: "acme/widgets"}, false},
+		{"gitlab subgroup", ForgeConfig{Provider: ForgeGitLab, Repo: "acme/platform/widgets"}, false},
+		{"gitea", ForgeConfig{Provider: ForgeGitea, Repo: "acme/widgets", APIURL: "https://git.acme.dev/api/v1"}, false},
+		{"missing provider", ForgeConfig{Repo: "acme/widgets"}, true},
+		{"unknown provider", ForgeConfig{Provider: "bitbucket", Repo: "acme/widgets"}, true},
+		{"missing repo", ForgeConfig{Provider: ForgeGitHub}, true},
+		{"github repo without owner", ForgeConfig{Provider: ForgeGitHub, Repo: "widgets"}, true},
+		{"gitea without api_url", ForgeConfig{Provider: ForgeGitea, Repo: "acme/widgets"}, true},
+		{"bad api_url", ForgeConfig{Provider: ForgeGitHub, Repo: "acme/widgets", APIURL: "ghe.acme.dev"}, true},
+		{"negative approvals", ForgeConfig{Provider: ForgeGitHub, Repo: "acme/widgets", RequiredApprovals: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateForgeConfig(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateForgeConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForgeConfigDefaults(t *testing.T) {
+	cfg := &ForgeConfig{Provider: ForgeGitLab, Repo: "acme/widgets"}
+	if got := cfg.DefaultTokenEnv(); got != "GITLAB_TOKEN" {
+		t.Errorf("DefaultTokenEnv() = %q, want GITLAB_TOKEN", got)
+	}
+	if cfg.Gated() {
+		t.Error("forge without required checks or approvals should not gate merges")
+	}
+	cfg.RequiredApprovals = 1
+	if !cfg.Gated() {
+		t.Error("required approvals should gate merges")
+	}
+	var nilCfg *ForgeConfig
+	if nilCfg.Gated() {
+		t.Error("nil forge config should not gate merges")
+	}
+}
+
+func TestLoadRigSettings_Forge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"type":"rig-settings","version":1,"forge":{"provider":"gitea","repo":"acme/widgets"}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRigSettings(path); err == nil {
+		t.Error("LoadRigSettings accepted a gitea forge without api_url")
+	}
+}
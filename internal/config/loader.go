@@ -219,6 +219,38 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.WorktreePool != nil {
+		if err := validateWorktreePoolConfig(c.WorktreePool); err != nil {
+			return err
+		}
+	}
+	if c.Checkout != nil {
+		if err := validateCheckoutConfig(c.Checkout); err != nil {
+			return err
+		}
+	}
+	if c.Forge != nil {
+		if err := validateForgeConfig(c.Forge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateWorktreePoolConfig validates a WorktreePoolConfig.
+func validateWorktreePoolConfig(c *WorktreePoolConfig) error {
+	if c.Size < 0 {
+		return fmt.Errorf("%w: worktree_pool.size must be non-negative", ErrMissingField)
+	}
+	if c.WarmupTimeout != "" {
+		dur, err := time.ParseDuration(c.WarmupTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid worktree_pool.warmup_timeout: %w", err)
+		}
+		if dur <= 0 {
+			return fmt.Errorf("worktree_pool.warmup_timeout must be positive, got %v", dur)
+		}
+	}
 	return nil
 }
 
@@ -82,6 +82,12 @@ type TownSettings struct {
 	// Convoy configures convoy behavior settings.
 	Convoy *ConvoyConfig `json:"convoy,omitempty"`
 
+	// Scheduler configures concurrency limits for queued sling work (gt sling --queue).
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+
+	// Recording configures asciicast recording of polecat panes (gt session replay).
+	Recording *RecordingConfig `json:"recording,omitempty"`
+
 	// CostTier tracks which cost tier preset was applied (informational).
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
@@ -182,6 +188,83 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
+// SchedulerConfig limits how many polecats the scheduler keeps running when
+// dispatching queued work. A limit of 0 means unlimited. Work slung without
+// --queue is not subject to these limits.
+type SchedulerConfig struct {
+	// MaxPolecats caps running polecats across the whole town.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+
+	// MaxPolecatsPerRig caps running polecats in each rig, unless overridden in Rigs.
+	MaxPolecatsPerRig int `json:"max_polecats_per_rig,omitempty"`
+
+	// MaxPolecatsPerAccount caps running polecats per Claude account, unless
+	// overridden in Accounts.
+	MaxPolecatsPerAccount int `json:"max_polecats_per_account,omitempty"`
+
+	// Rigs overrides MaxPolecatsPerRig for specific rigs (rig name -> limit).
+	Rigs map[string]int `json:"rigs,omitempty"`
+
+	// Accounts overrides MaxPolecatsPerAccount for specific accounts (handle -> limit).
+	Accounts map[string]int `json:"accounts,omitempty"`
+}
+
+// RigLimit returns the polecat limit for rig (0 = unlimited).
+func (c *SchedulerConfig) RigLimit(rig string) int {
+	if c == nil {
+		return 0
+	}
+	if n, ok := c.Rigs[rig]; ok {
+		return n
+	}
+	return c.MaxPolecatsPerRig
+}
+
+// AccountLimit returns the polecat limit for account handle (0 = unlimited).
+func (c *SchedulerConfig) AccountLimit(handle string) int {
+	if c == nil {
+		return 0
+	}
+	if n, ok := c.Accounts[handle]; ok {
+		return n
+	}
+	return c.MaxPolecatsPerAccount
+}
+
+// RecordingConfig configures recording of polecat panes to asciicast files
+// in <town>/.recordings/. Segments are pruned by KRC (recording_ttl).
+type RecordingConfig struct {
+	// Enabled turns on recording for polecat sessions started from now on.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SegmentMB rotates to a new segment after this many megabytes. Default: 8.
+	SegmentMB int `json:"segment_mb,omitempty"`
+
+	// SegmentDuration rotates to a new segment after this long. Default: "1h".
+	SegmentDuration string `json:"segment_duration,omitempty"`
+}
+
+// IsEnabled reports whether pane recording is on (false for a nil config).
+func (c *RecordingConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// SegmentBytes returns the size at which segments rotate.
+func (c *RecordingConfig) SegmentBytes() int64 {
+	if c == nil || c.SegmentMB <= 0 {
+		return 8 * 1024 * 1024
+	}
+	return int64(c.SegmentMB) * 1024 * 1024
+}
+
+// SegmentAge returns the age at which segments rotate.
+func (c *RecordingConfig) SegmentAge() time.Duration {
+	if c == nil {
+		return time.Hour
+	}
+	return ParseDurationOrDefault(c.SegmentDuration, time.Hour)
+}
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -352,6 +435,22 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// WorktreePool keeps pre-warmed polecat worktrees ready so spawns don't
+	// pay for worktree creation and dependency setup. Nil disables the pool.
+	WorktreePool *WorktreePoolConfig `json:"worktree_pool,omitempty"`
+
+	// PolecatAffinity makes sling pick the polecat identity whose past beads
+	// and commits best match the slung bead (as if --affinity were passed).
+	PolecatAffinity bool `json:"polecat_affinity,omitempty"`
+
+	// Checkout configures sparse checkout and partial clone for polecat,
+	// dog and crew workspaces. Nil means full checkouts.
+	Checkout *CheckoutConfig `json:"checkout,omitempty"`
+
+	// Forge connects the rig to GitHub, GitLab, or Gitea for pull request
+	// mirroring, review relay, and CI/review merge gating. Nil disables it.
+	Forge *ForgeConfig `json:"forge,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -884,6 +983,11 @@ type MergeQueueConfig struct {
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// ScorePolicy names the policy the refinery uses to order ready MRs:
+	// "default", "smallest-diff", "convoy-completion", "critical-path" or
+	// "fair-share". Empty means "default".
+	ScorePolicy string `json:"score_policy,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -983,6 +1087,36 @@ func DefaultNamepoolConfig() *NamepoolConfig {
 	}
 }
 
+// WorktreePoolConfig configures the per-rig pool of pre-warmed polecat worktrees.
+// Spawns claim a warm worktree (already checked out on the default branch,
+// dependencies primed) instead of creating one from scratch.
+type WorktreePoolConfig struct {
+	// Size is the number of warm worktrees to keep ready. 0 disables the pool.
+	Size int `json:"size,omitempty"`
+
+	// Warmup is a shell command run in each new pool worktree to prime
+	// dependencies (e.g., "npm ci" or "go mod download"). Optional.
+	Warmup string `json:"warmup,omitempty"`
+
+	// WarmupTimeout bounds the warmup command (Go duration, default "10m").
+	WarmupTimeout string `json:"warmup_timeout,omitempty"`
+}
+
+// DefaultWorktreeWarmupTimeout is used when WarmupTimeout is unset or invalid.
+const DefaultWorktreeWarmupTimeout = 10 * time.Minute
+
+// GetWarmupTimeout returns the warmup timeout as a duration.
+func (c *WorktreePoolConfig) GetWarmupTimeout() time.Duration {
+	if c == nil || c.WarmupTimeout == "" {
+		return DefaultWorktreeWarmupTimeout
+	}
+	d, err := time.ParseDuration(c.WarmupTimeout)
+	if err != nil || d <= 0 {
+		return DefaultWorktreeWarmupTimeout
+	}
+	return d
+}
+
 // AccountsConfig represents Claude Code account configuration (mayor/accounts.json).
 // This enables Gas Town to manage multiple Claude Code accounts with easy switching.
 type AccountsConfig struct {
@@ -104,6 +104,9 @@ const (
 
 	// FileQuotaJSON is the quota state file in mayor/.
 	FileQuotaJSON = "quota.json"
+
+	// FileSlingQueueJSON is the scheduler's queue of work awaiting capacity in mayor/.
+	FileSlingQueueJSON = "sling-queue.json"
 )
 
 // Beads configuration constants.
@@ -288,6 +291,11 @@ func MayorQuotaPath(townRoot string) string {
 	return townRoot + "/" + DirMayor + "/" + FileQuotaJSON
 }
 
+// MayorSlingQueuePath returns the path to mayor/sling-queue.json within a town root.
+func MayorSlingQueuePath(townRoot string) string {
+	return townRoot + "/" + DirMayor + "/" + FileSlingQueueJSON
+}
+
 // DefaultRateLimitPatterns are the default patterns that indicate a session
 // is rate-limited. These are matched against tmux pane content.
 // Note: patterns are compiled with (?i) for case-insensitive matching.
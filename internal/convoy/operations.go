@@ -11,6 +11,7 @@ import (
 
 	beadsdk "github.com/steveyegge/beads"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -51,6 +52,12 @@ func CheckConvoysForIssue(ctx context.Context, store beadsdk.Storage, townRoot,
 
 	logger("%s: %s tracked by %d convoy(s): %v", caller, issueID, len(convoyIDs), convoyIDs)
 
+	// Convoy checks join the issue's trace (trace_id stored by gt sling)
+	var traceParent tracing.SpanContext
+	if tracing.Enabled() {
+		traceParent = tracing.Root(getIssueTraceID(ctx, store, issueID))
+	}
+
 	// Run convoy check for each tracking convoy
 	// Note: gt convoy check is idempotent and handles already-closed convoys
 	for _, convoyID := range convoyIDs {
@@ -60,7 +67,12 @@ func CheckConvoysForIssue(ctx context.Context, store beadsdk.Storage, townRoot,
 		}
 
 		logger("%s: checking convoy %s", caller, convoyID)
-		if err := runConvoyCheck(ctx, townRoot, convoyID, gtPath); err != nil {
+		span := tracing.Start(townRoot, "convoy.check", traceParent)
+		span.SetAttr("bead", issueID)
+		span.SetAttr("convoy", convoyID)
+		err := runConvoyCheck(ctx, townRoot, convoyID, gtPath)
+		span.End(err)
+		if err != nil {
 			logger("%s: convoy %s check failed: %s", caller, convoyID, util.FirstLine(err.Error()))
 		}
 
@@ -104,6 +116,19 @@ func isConvoyClosed(ctx context.Context, store beadsdk.Storage, convoyID string)
 	return string(issue.Status) == "closed"
 }
 
+// getIssueTraceID returns the trace_id gt sling stored on an issue, or "".
+func getIssueTraceID(ctx context.Context, store beadsdk.Storage, issueID string) string {
+	issue, err := store.GetIssue(ctx, issueID)
+	if err != nil || issue == nil {
+		return ""
+	}
+	fields := beads.ParseAttachmentFields(&beads.Issue{Description: issue.Description})
+	if fields == nil {
+		return ""
+	}
+	return fields.TraceID
+}
+
 // runConvoyCheck runs `gt convoy check <convoy-id>` to check a specific convoy.
 // This is idempotent and handles already-closed convoys gracefully.
 // The context parameter enables cancellation on daemon shutdown.
@@ -202,16 +202,20 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		return nil, fmt.Errorf("creating crew dir: %w", err)
 	}
 
-	// Clone the rig repo
+	// Clone the rig repo, honoring the rig's sparse checkout / partial clone settings
+	spec := config.LoadRigCheckout(m.rig.Path).Resolve("", nil)
+	cloneOpts := git.CloneOptions{Filter: spec.Filter, Sparse: spec.Sparse}
 	if m.rig.LocalRepo != "" {
-		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
+		refOpts := cloneOpts
+		refOpts.Reference = m.rig.LocalRepo
+		if err := m.git.CloneWithOptions(m.rig.GitURL, crewPath, refOpts); err != nil {
 			style.PrintWarning("could not clone with local repo reference: %v", err)
-			if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
+			if err := m.git.CloneWithOptions(m.rig.GitURL, crewPath, cloneOpts); err != nil {
 				return nil, fmt.Errorf("cloning rig: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
+		if err := m.git.CloneWithOptions(m.rig.GitURL, crewPath, cloneOpts); err != nil {
 			return nil, fmt.Errorf("cloning rig: %w", err)
 		}
 	}
@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
+)
+
+const defaultAuditCheckpointsInterval = time.Hour
+
+// auditCheckpointsInterval returns the configured checkpoint interval, or the default (1h).
+func auditCheckpointsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AuditCheckpoints != nil {
+		if config.Patrols.AuditCheckpoints.Interval > 0 {
+			return config.Patrols.AuditCheckpoints.Interval
+		}
+	}
+	return defaultAuditCheckpointsInterval
+}
+
+// checkpointAuditLog anchors the audit chain's head in the town git repo.
+// Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) checkpointAuditLog() {
+	if !IsPatrolEnabled(d.patrolConfig, "audit_checkpoints") {
+		return
+	}
+	res, err := auditlog.WriteCheckpoint(d.config.TownRoot, time.Now())
+	if err != nil {
+		d.logger.Printf("audit_checkpoints: checkpoint failed: %v", err)
+		return
+	}
+	if res == nil {
+		return
+	}
+	signed := "unsigned"
+	if res.Signed {
+		signed = "signed"
+	}
+	d.logger.Printf("audit_checkpoints: anchored seq %d in commit %.12s (%s)", res.Seq, res.Commit, signed)
+}
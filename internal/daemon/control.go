@@ -0,0 +1,429 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Control methods understood by the daemon's control socket.
+const (
+	// ControlStatus reports the daemon's live status.
+	ControlStatus = "status"
+
+	// ControlRestartAgent kills and restarts an agent session (Agent is required).
+	ControlRestartAgent = "restart-agent"
+
+	// ControlPatrol runs a heartbeat now instead of waiting for the timer.
+	ControlPatrol = "patrol"
+
+	// ControlReload re-reads and validates the daemon's config files and
+	// applies them live; an invalid config is rejected and the current one kept.
+	ControlReload = "reload"
+
+	// ControlPauseRig stops the daemon from starting or restarting a rig's
+	// agents until it is resumed or the daemon restarts (Rig is required).
+	ControlPauseRig = "pause-rig"
+
+	// ControlResumeRig undoes ControlPauseRig (Rig is required).
+	ControlResumeRig = "resume-rig"
+
+	// ControlGoroutines lists the long-running goroutines the daemon manages.
+	ControlGoroutines = "goroutines"
+
+	// ControlStop shuts the daemon down gracefully.
+	ControlStop = "stop"
+)
+
+// ErrNoControlSocket is returned by CallDaemon when no daemon is listening.
+// Callers fall back to the PID and state files.
+var ErrNoControlSocket = errors.New("daemon control socket not available")
+
+const (
+	// controlIOTimeout bounds reading a request and writing a response.
+	controlIOTimeout = 5 * time.Second
+
+	// controlStopTimeout is how long StopDaemon waits for a daemon that
+	// acknowledged ControlStop to exit before killing it.
+	controlStopTimeout = 10 * time.Second
+)
+
+// ControlRequest is one call on the control socket.
+type ControlRequest struct {
+	Method string `json:"method"`
+	Agent  string `json:"agent,omitempty"` // identity for restart-agent, e.g. "gastown/witness"
+	Rig    string `json:"rig,omitempty"`   // rig for pause-rig/resume-rig
+}
+
+// ControlResponse is the daemon's answer to a ControlRequest.
+type ControlResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// Status is set for ControlStatus and ControlPauseRig/ControlResumeRig.
+	Status *DaemonStatus `json:"status,omitempty"`
+
+	// Goroutines is set for ControlGoroutines.
+	Goroutines []GoroutineInfo `json:"goroutines,omitempty"`
+
+	// NumGoroutine is the process-wide goroutine count (ControlGoroutines).
+	NumGoroutine int `json:"num_goroutine,omitempty"`
+}
+
+// DaemonStatus is the live status reported over the control socket.
+// Unlike State it is answered from memory, so it is current even while
+// a heartbeat is running.
+type DaemonStatus struct {
+	PID            int       `json:"pid"`
+	StartedAt      time.Time `json:"started_at"`
+	LastHeartbeat  time.Time `json:"last_heartbeat,omitempty"`
+	HeartbeatCount int64     `json:"heartbeat_count"`
+
+	// InHeartbeat is true while a heartbeat cycle is running.
+	InHeartbeat bool `json:"in_heartbeat"`
+
+	// NextHeartbeat is when the recovery timer fires next.
+	NextHeartbeat time.Time `json:"next_heartbeat,omitempty"`
+
+	// PatrolQueued is true when a patrol was requested and has not run yet.
+	PatrolQueued bool `json:"patrol_queued,omitempty"`
+
+	// PausedRigs are rigs paused over the control socket.
+	PausedRigs []string `json:"paused_rigs,omitempty"`
+
+	// MetricsURL is the Prometheus endpoint, if enabled.
+	MetricsURL string `json:"metrics_url,omitempty"`
+
+	// LastReload is when the config was last reloaded or a reload rejected;
+	// ReloadError is set if that reload was rejected.
+	LastReload  time.Time `json:"last_reload,omitempty"`
+	ReloadError string    `json:"reload_error,omitempty"`
+}
+
+// GoroutineInfo describes a long-running goroutine started by the daemon.
+type GoroutineInfo struct {
+	Name      string    `json:"name"`
+	Detail    string    `json:"detail,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ControlSocketPath returns the path of the daemon's control socket.
+func ControlSocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "daemon.sock")
+}
+
+// CallDaemon sends one request to the daemon's control socket and waits up
+// to timeout for the answer. It wraps ErrNoControlSocket when no daemon is
+// listening, and returns the response with an error when the daemon
+// rejected the request.
+func CallDaemon(townRoot string, req ControlRequest, timeout time.Duration) (*ControlResponse, error) {
+	conn, err := net.DialTimeout("unix", ControlSocketPath(townRoot), time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoControlSocket, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("sending %s request: %w", req.Method, err)
+	}
+	var resp ControlResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading %s response: %w", req.Method, err)
+	}
+	if !resp.OK {
+		return &resp, fmt.Errorf("daemon: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+// QueryStatus asks a running daemon for its live status.
+func QueryStatus(townRoot string) (*DaemonStatus, error) {
+	resp, err := CallDaemon(townRoot, ControlRequest{Method: ControlStatus}, controlIOTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// controlServer accepts control connections and answers one request per
+// connection with handle.
+type controlServer struct {
+	listener net.Listener
+	handle   func(ControlRequest) ControlResponse
+	wg       sync.WaitGroup
+}
+
+// listenControl listens on the socket at path. Any socket file already
+// there is stale: the caller holds the daemon lock.
+func listenControl(path string, handle func(ControlRequest) ControlResponse) (*controlServer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating socket directory: %w", err)
+	}
+	_ = os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", path, err)
+	}
+	// The socket can restart agents and stop the daemon: owner only.
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("restricting socket permissions: %w", err)
+	}
+
+	s := &controlServer{listener: l, handle: handle}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+func (s *controlServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+func (s *controlServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(controlIOTimeout))
+
+	var req ControlRequest
+	var resp ControlResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		resp = controlError("malformed request: %v", err)
+	} else {
+		resp = s.handle(req)
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(controlIOTimeout))
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// Close stops accepting connections, waits for in-flight requests and
+// removes the socket file.
+func (s *controlServer) Close() {
+	path := s.listener.Addr().String()
+	_ = s.listener.Close()
+	s.wg.Wait()
+	_ = os.Remove(path)
+}
+
+func controlError(format string, args ...interface{}) ControlResponse {
+	return ControlResponse{Error: fmt.Sprintf(format, args...)}
+}
+
+// controlCall is a request handed to the main loop, which owns the
+// heartbeat-only state that restart and reload touch.
+type controlCall struct {
+	req   ControlRequest
+	reply chan ControlResponse
+}
+
+// goroutineRegistry tracks the daemon's long-running goroutines for
+// ControlGoroutines. The zero value is ready to use.
+type goroutineRegistry struct {
+	mu      sync.Mutex
+	entries map[string]GoroutineInfo
+}
+
+func (r *goroutineRegistry) add(name, detail string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[string]GoroutineInfo)
+	}
+	r.entries[name] = GoroutineInfo{Name: name, Detail: detail, StartedAt: time.Now()}
+}
+
+func (r *goroutineRegistry) remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+func (r *goroutineRegistry) list() []GoroutineInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]GoroutineInfo, 0, len(r.entries))
+	for _, g := range r.entries {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// handleControl answers a control request. It runs on the socket's
+// connection goroutines: status, pause/resume and patrol are answered from
+// memory without waiting for a heartbeat, while restart and reload are
+// handed to the main loop so they never overlap a heartbeat.
+func (d *Daemon) handleControl(req ControlRequest) ControlResponse {
+	switch req.Method {
+	case ControlStatus:
+		return ControlResponse{OK: true, Status: d.statusSnapshot()}
+
+	case ControlGoroutines:
+		return ControlResponse{OK: true, Goroutines: d.goroutines.list(), NumGoroutine: runtime.NumGoroutine()}
+
+	case ControlPauseRig, ControlResumeRig:
+		if req.Rig == "" {
+			return controlError("%s requires a rig", req.Method)
+		}
+		if !d.isKnownRig(req.Rig) {
+			return controlError("unknown rig %q", req.Rig)
+		}
+		paused := req.Method == ControlPauseRig
+		d.setRigPaused(req.Rig, paused)
+		verb := "resumed"
+		if paused {
+			verb = "paused"
+		}
+		d.logger.Printf("Control: rig %s %s", req.Rig, verb)
+		return ControlResponse{OK: true, Message: fmt.Sprintf("rig %s %s", req.Rig, verb), Status: d.statusSnapshot()}
+
+	case ControlPatrol:
+		// Mark before queueing so the heartbeat that consumes it clears the flag.
+		d.controlMu.Lock()
+		d.status.PatrolQueued = true
+		d.controlMu.Unlock()
+		select {
+		case d.patrolNow <- struct{}{}:
+			d.logger.Println("Control: patrol requested")
+			return ControlResponse{OK: true, Message: "patrol queued"}
+		default:
+			return ControlResponse{OK: true, Message: "patrol already queued"}
+		}
+
+	case ControlRestartAgent, ControlReload:
+		if req.Method == ControlRestartAgent && req.Agent == "" {
+			return controlError("%s requires an agent identity", req.Method)
+		}
+		call := controlCall{req: req, reply: make(chan ControlResponse, 1)}
+		select {
+		case d.controlCalls <- call:
+		case <-d.ctx.Done():
+			return controlError("daemon is shutting down")
+		}
+		select {
+		case resp := <-call.reply:
+			return resp
+		case <-d.ctx.Done():
+			return controlError("daemon is shutting down")
+		}
+
+	case ControlStop:
+		d.logger.Println("Control: stop requested")
+		d.Stop()
+		return ControlResponse{OK: true, Message: "daemon stopping"}
+
+	default:
+		return controlError("unknown method %q", req.Method)
+	}
+}
+
+// runControlCall performs a control request on the main loop.
+func (d *Daemon) runControlCall(req ControlRequest) ControlResponse {
+	switch req.Method {
+	case ControlReload:
+		pending, err := d.reloadConfig(reloadTriggerControl)
+		if err != nil {
+			return controlError("config rejected, keeping current config: %v", err)
+		}
+		msg := "reloaded " + PatrolConfigFile(d.config.TownRoot)
+		if d.patrolConfig == nil {
+			msg = "reloaded config (no mayor/daemon.json: all patrols use defaults)"
+		}
+		if len(pending) > 0 {
+			msg += "; restart the daemon to apply " + strings.Join(pending, ", ")
+		}
+		return ControlResponse{OK: true, Message: msg}
+
+	case ControlRestartAgent:
+		if d.isShutdownInProgress() {
+			return controlError("shutdown in progress")
+		}
+		d.logger.Printf("Control: restarting %s", req.Agent)
+		if err := d.executeLifecycleAction(&LifecycleRequest{From: req.Agent, Action: ActionRestart, Timestamp: time.Now()}); err != nil {
+			return controlError("restarting %s: %v", req.Agent, err)
+		}
+		return ControlResponse{OK: true, Message: "restarted " + d.identityToSession(req.Agent)}
+	}
+	return controlError("unknown method %q", req.Method)
+}
+
+// statusSnapshot returns a copy of the live status.
+func (d *Daemon) statusSnapshot() *DaemonStatus {
+	d.controlMu.Lock()
+	defer d.controlMu.Unlock()
+	s := d.status
+	s.PausedRigs = nil
+	for rig := range d.pausedRigs {
+		s.PausedRigs = append(s.PausedRigs, rig)
+	}
+	sort.Strings(s.PausedRigs)
+	return &s
+}
+
+// recordHeartbeat updates the live status around a heartbeat cycle.
+func (d *Daemon) recordHeartbeat(state *State, running bool) {
+	d.controlMu.Lock()
+	defer d.controlMu.Unlock()
+	d.status.PID = state.PID
+	d.status.StartedAt = state.StartedAt
+	d.status.LastHeartbeat = state.LastHeartbeat
+	d.status.HeartbeatCount = state.HeartbeatCount
+	d.status.InHeartbeat = running
+	if running {
+		d.status.PatrolQueued = false
+	} else {
+		d.status.NextHeartbeat = time.Now().Add(heartbeatInterval(d.patrolConfig))
+	}
+}
+
+// setRigPaused pauses or resumes a rig for this daemon process.
+func (d *Daemon) setRigPaused(rigName string, paused bool) {
+	d.controlMu.Lock()
+	defer d.controlMu.Unlock()
+	if !paused {
+		delete(d.pausedRigs, rigName)
+		return
+	}
+	if d.pausedRigs == nil {
+		d.pausedRigs = make(map[string]bool)
+	}
+	d.pausedRigs[rigName] = true
+}
+
+// isRigPaused reports whether a rig was paused over the control socket.
+func (d *Daemon) isRigPaused(rigName string) bool {
+	d.controlMu.Lock()
+	defer d.controlMu.Unlock()
+	return d.pausedRigs[rigName]
+}
+
+func (d *Daemon) isKnownRig(rigName string) bool {
+	for _, name := range d.getKnownRigs() {
+		if name == rigName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,303 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/scheduler"
+)
+
+// newControlDaemon returns a daemon with just enough state to answer
+// control requests for a town with one rig, "gastown".
+func newControlDaemon(t *testing.T) *Daemon {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(`{"rigs":{"gastown":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Feed and audit events locate the town from cwd; without this they
+	// land in the source tree, which looks like a town from internal/.
+	t.Chdir(townRoot)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	d := &Daemon{
+		config:       &Config{TownRoot: townRoot},
+		logger:       log.New(io.Discard, "", 0),
+		ctx:          ctx,
+		cancel:       cancel,
+		controlCalls: make(chan controlCall),
+		patrolNow:    make(chan struct{}, 1),
+	}
+	t.Cleanup(func() {
+		// A reload starts the KRC pruner if it isn't running.
+		if d.krcPruner != nil {
+			d.krcPruner.Stop()
+		}
+	})
+	return d
+}
+
+func TestControlSocket_RoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	path := ControlSocketPath(townRoot)
+	srv, err := listenControl(path, func(req ControlRequest) ControlResponse {
+		if req.Method == "fail" {
+			return controlError("no such thing")
+		}
+		return ControlResponse{OK: true, Message: req.Method + ":" + req.Rig}
+	})
+	if err != nil {
+		t.Fatalf("listenControl: %v", err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	resp, err := CallDaemon(townRoot, ControlRequest{Method: ControlPauseRig, Rig: "gastown"}, time.Second)
+	if err != nil {
+		t.Fatalf("CallDaemon: %v", err)
+	}
+	if resp.Message != "pause-rig:gastown" {
+		t.Errorf("Message = %q", resp.Message)
+	}
+
+	resp, err = CallDaemon(townRoot, ControlRequest{Method: "fail"}, time.Second)
+	if err == nil || resp == nil || resp.Error != "no such thing" {
+		t.Errorf("rejected request: resp %+v, err %v", resp, err)
+	}
+
+	srv.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left behind after Close: %v", err)
+	}
+	if _, err := CallDaemon(townRoot, ControlRequest{Method: ControlStatus}, time.Second); !errors.Is(err, ErrNoControlSocket) {
+		t.Errorf("CallDaemon after Close = %v, want ErrNoControlSocket", err)
+	}
+}
+
+func TestControlSocket_ReplacesStaleSocket(t *testing.T) {
+	townRoot := t.TempDir()
+	path := ControlSocketPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := listenControl(path, func(ControlRequest) ControlResponse { return ControlResponse{OK: true} })
+	if err != nil {
+		t.Fatalf("listenControl over stale file: %v", err)
+	}
+	srv.Close()
+}
+
+func TestHandleControl_PauseResume(t *testing.T) {
+	d := newControlDaemon(t)
+
+	resp := d.handleControl(ControlRequest{Method: ControlPauseRig, Rig: "gastown"})
+	if !resp.OK || resp.Status == nil || len(resp.Status.PausedRigs) != 1 {
+		t.Fatalf("pause: %+v", resp)
+	}
+	if ok, reason := d.isRigOperational("gastown"); ok || !strings.Contains(reason, "paused") {
+		t.Errorf("isRigOperational while paused = %v, %q", ok, reason)
+	}
+
+	if resp := d.handleControl(ControlRequest{Method: ControlPauseRig, Rig: "nope"}); resp.OK {
+		t.Error("pausing an unknown rig should fail")
+	}
+	if resp := d.handleControl(ControlRequest{Method: ControlPauseRig}); resp.OK {
+		t.Error("pause without a rig should fail")
+	}
+
+	resp = d.handleControl(ControlRequest{Method: ControlResumeRig, Rig: "gastown"})
+	if !resp.OK || len(resp.Status.PausedRigs) != 0 || d.isRigPaused("gastown") {
+		t.Errorf("resume: %+v", resp)
+	}
+}
+
+func TestHandleControl_Patrol(t *testing.T) {
+	d := newControlDaemon(t)
+
+	if resp := d.handleControl(ControlRequest{Method: ControlPatrol}); resp.Message != "patrol queued" {
+		t.Errorf("first patrol: %+v", resp)
+	}
+	if resp := d.handleControl(ControlRequest{Method: ControlPatrol}); resp.Message != "patrol already queued" {
+		t.Errorf("second patrol: %+v", resp)
+	}
+	if !d.statusSnapshot().PatrolQueued {
+		t.Error("status should show the queued patrol")
+	}
+
+	// The heartbeat that consumes the request clears the flag.
+	<-d.patrolNow
+	state := &State{PID: 42, StartedAt: time.Now()}
+	d.recordHeartbeat(state, true)
+	s := d.statusSnapshot()
+	if s.PatrolQueued || !s.InHeartbeat || s.PID != 42 {
+		t.Errorf("status during heartbeat = %+v", s)
+	}
+	state.HeartbeatCount = 1
+	d.recordHeartbeat(state, false)
+	if s := d.statusSnapshot(); s.InHeartbeat || s.HeartbeatCount != 1 || s.NextHeartbeat.IsZero() {
+		t.Errorf("status after heartbeat = %+v", s)
+	}
+}
+
+func TestHandleControl_MainLoopCalls(t *testing.T) {
+	d := newControlDaemon(t)
+
+	// Stand in for the Run loop.
+	go func() {
+		for call := range d.controlCalls {
+			call.reply <- d.runControlCall(call.req)
+		}
+	}()
+	defer close(d.controlCalls)
+
+	resp := d.handleControl(ControlRequest{Method: ControlReload})
+	if !resp.OK || !strings.Contains(resp.Message, "defaults") {
+		t.Errorf("reload without daemon.json: %+v", resp)
+	}
+
+	if resp := d.handleControl(ControlRequest{Method: ControlRestartAgent}); resp.OK {
+		t.Error("restart-agent without an identity should fail")
+	}
+	if resp := d.handleControl(ControlRequest{Method: "bogus"}); resp.OK || !strings.Contains(resp.Error, "unknown method") {
+		t.Errorf("unknown method: %+v", resp)
+	}
+}
+
+func TestHandleControl_ShutdownUnblocksCalls(t *testing.T) {
+	d := newControlDaemon(t)
+	d.cancel() // no main loop will ever take the call
+
+	if resp := d.handleControl(ControlRequest{Method: ControlReload}); resp.OK || !strings.Contains(resp.Error, "shutting down") {
+		t.Errorf("reload during shutdown: %+v", resp)
+	}
+}
+
+func TestGoroutineRegistry(t *testing.T) {
+	var r goroutineRegistry
+	r.add("heartbeat", "every 3m0s")
+	r.add("convoy manager", "")
+	got := r.list()
+	if len(got) != 2 || got[0].Name != "convoy manager" || got[1].Detail != "every 3m0s" {
+		t.Errorf("list = %+v", got)
+	}
+}
+
+func TestDispatchQueuedWork_RunsInBackground(t *testing.T) {
+	d := newControlDaemon(t)
+	if _, err := scheduler.NewManager(d.config.TownRoot).Enqueue(&scheduler.Entry{BeadID: "gt-1", Rig: "gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	// A stand-in gt whose `schedule run` takes a while and counts its runs.
+	runs := filepath.Join(d.config.TownRoot, "runs")
+	d.gtPath = filepath.Join(d.config.TownRoot, "gt")
+	script := "#!/bin/sh\necho run >> " + runs + "\nsleep 1\n"
+	if err := os.WriteFile(d.gtPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	d.dispatchQueuedWork()
+	d.dispatchQueuedWork() // previous dispatch still in flight: skipped
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("dispatch blocked the caller for %v", elapsed)
+	}
+	if len(d.goroutines.list()) != 1 {
+		t.Errorf("goroutines = %+v, want the in-flight dispatch", d.goroutines.list())
+	}
+
+	d.background.Wait()
+	data, _ := os.ReadFile(runs)
+	if n := strings.Count(string(data), "run"); n != 1 {
+		t.Errorf("schedule run invoked %d times, want 1", n)
+	}
+	if d.queueDispatching.Load() || len(d.goroutines.list()) != 0 {
+		t.Error("dispatch should be cleared once finished")
+	}
+}
+
+func TestSnapshotDoltDatabases_SkipsWhileRunning(t *testing.T) {
+	d := newControlDaemon(t)
+	d.patrolConfig = &DaemonPatrolConfig{Patrols: &PatrolsConfig{
+		DoltBackups: &DoltBackupsConfig{Enabled: true},
+	}}
+
+	d.snapshotting.Store(true) // a previous pass is still in flight
+	d.snapshotDoltDatabases()
+	if len(d.goroutines.list()) != 0 {
+		t.Errorf("goroutines = %+v, want the pass skipped", d.goroutines.list())
+	}
+
+	d.snapshotting.Store(false)
+	d.snapshotDoltDatabases()
+	d.background.Wait()
+	if d.snapshotting.Load() || len(d.goroutines.list()) != 0 {
+		t.Error("snapshot pass should be cleared once finished")
+	}
+}
+
+func TestForecastConflicts_BacksOffAfterFailure(t *testing.T) {
+	d := newControlDaemon(t)
+	// A stand-in gt whose forecast always fails and counts its runs.
+	runs := filepath.Join(d.config.TownRoot, "runs")
+	d.gtPath = filepath.Join(d.config.TownRoot, "gt")
+	script := "#!/bin/sh\necho run >> " + runs + "\nexit 1\n"
+	if err := os.WriteFile(d.gtPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	d.forecastConflicts()
+	d.background.Wait()
+	d.forecastConflicts() // still backing off: not retried
+	d.background.Wait()
+
+	data, _ := os.ReadFile(runs)
+	if n := strings.Count(string(data), "run"); n != 1 {
+		t.Errorf("mq conflicts invoked %d times, want 1", n)
+	}
+	if d.forecasting.Load() || len(d.goroutines.list()) != 0 {
+		t.Error("forecast should be cleared once finished")
+	}
+}
+
+func TestRigBackoff(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := rigBackoff{}
+	if !b.ready("gastown", now) {
+		t.Error("a rig with no failures should be ready")
+	}
+
+	b.record("gastown", errors.New("boom"), now)
+	if b.ready("gastown", now.Add(baseRigPatrolBackoff-time.Second)) || !b.ready("gastown", now.Add(baseRigPatrolBackoff)) {
+		t.Error("first failure should back off for baseRigPatrolBackoff")
+	}
+	b.record("gastown", errors.New("boom"), now)
+	if b.ready("gastown", now.Add(baseRigPatrolBackoff)) || !b.ready("gastown", now.Add(2*baseRigPatrolBackoff)) {
+		t.Error("backoff should double per consecutive failure")
+	}
+	for i := 0; i < 10; i++ {
+		b.record("gastown", errors.New("boom"), now)
+	}
+	if !b.ready("gastown", now.Add(maxRigPatrolBackoff)) {
+		t.Error("backoff should be capped at maxRigPatrolBackoff")
+	}
+
+	b.record("gastown", nil, now)
+	if !b.ready("gastown", now) {
+		t.Error("success should clear the backoff")
+	}
+}
@@ -62,6 +62,10 @@ type ConvoyManager struct {
 
 	gtPath string
 
+	// intervalCh carries a scan interval changed by a daemon reload to
+	// the stranded scan loop.
+	intervalCh chan time.Duration
+
 	// started guards against double-call of Start() which would spawn duplicate goroutines.
 	started atomic.Bool
 
@@ -101,7 +105,22 @@ func NewConvoyManager(townRoot string, logger func(format string, args ...interf
 		openStores:   openStores,
 		isRigParked:  isRigParked,
 		gtPath:       gtPath,
+		intervalCh:   make(chan time.Duration, 1),
+	}
+}
+
+// SetScanInterval changes the stranded scan interval; 0 restores the
+// default. The next scan runs one interval after the change is applied.
+// Only the daemon's main loop calls this.
+func (m *ConvoyManager) SetScanInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultStrandedScanInterval
+	}
+	select {
+	case <-m.intervalCh: // replace an update the loop hasn't taken yet
+	default:
 	}
+	m.intervalCh <- interval
 }
 
 // Start begins the convoy manager goroutines (event poll + stranded scan).
@@ -254,6 +273,12 @@ func (m *ConvoyManager) runStrandedScan() {
 		select {
 		case <-m.ctx.Done():
 			return
+		case interval := <-m.intervalCh:
+			if interval != m.scanInterval {
+				m.scanInterval = interval
+				ticker.Reset(interval)
+				m.logger("Convoy: stranded scan interval now %v", interval)
+			}
 		case <-ticker.C:
 			m.scan()
 		}
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -75,6 +77,46 @@ type Daemon struct {
 
 	// Restart tracking with exponential backoff to prevent crash loops
 	restartTracker *RestartTracker
+
+	// Control socket (daemon/daemon.sock). Status, pause/resume and patrol
+	// are answered on the socket's goroutines from the state below;
+	// restart and reload are handed to the main loop via controlCalls.
+	control      *controlServer
+	controlCalls chan controlCall
+	patrolNow    chan struct{}
+	goroutines   goroutineRegistry
+
+	// Sling queue dispatch, Dolt snapshots, conflict forecasts, flaky
+	// reports and transcript archiving run off the main loop; the flags keep at most one of each in
+	// flight and background lets shutdown wait for them.
+	queueDispatching atomic.Bool
+	snapshotting     atomic.Bool
+	forecasting      atomic.Bool
+	flakyReporting   atomic.Bool
+	archiving        atomic.Bool
+	background       sync.WaitGroup
+
+	// When the transcript archive was last refreshed. Only accessed from
+	// the main loop.
+	lastTranscriptArchive time.Time
+
+	// Per-rig failure backoff for the forecast and flaky report commands.
+	// Only touched by the goroutine holding forecasting/flakyReporting.
+	forecastBackoff rigBackoff
+	flakyBackoff    rigBackoff
+
+	// Metrics endpoint (mayor/daemon.json "metrics"); nil when disabled.
+	metrics *metricsExporter
+
+	// Main loop timers and the config file stamps the watcher compares
+	// against. Only accessed from the main loop - no sync needed.
+	timers       patrolTimers
+	configStamps map[string]fileStamp
+
+	// controlMu guards the live status and the rigs paused over the socket.
+	controlMu  sync.Mutex
+	status     DaemonStatus
+	pausedRigs map[string]bool
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -160,6 +202,8 @@ func New(config *Config) (*Daemon, error) {
 		gtPath:         gtPath,
 		bdPath:         bdPath,
 		restartTracker: restartTracker,
+		controlCalls:   make(chan controlCall),
+		patrolNow:      make(chan struct{}, 1),
 	}, nil
 }
 
@@ -214,16 +258,50 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	// Listen for control requests (gt daemon status, gt up/down, ...).
+	// Non-fatal: without the socket the CLI falls back to the state files.
+	d.controlMu.Lock()
+	d.status.PID = state.PID
+	d.status.StartedAt = state.StartedAt
+	d.controlMu.Unlock()
+	socketPath := ControlSocketPath(d.config.TownRoot)
+	if control, err := listenControl(socketPath, d.handleControl); err != nil {
+		d.logger.Printf("Warning: control socket unavailable: %v", err)
+	} else {
+		d.control = control
+		d.goroutines.add("control socket", socketPath)
+		d.logger.Printf("Control socket listening on %s", socketPath)
+	}
+
+	// Serve Prometheus metrics if enabled in mayor/daemon.json.
+	if addr := metricsListenAddr(d.patrolConfig); addr != "" {
+		exporter := d.newMetricsExporter()
+		if err := exporter.start(addr); err != nil {
+			d.logger.Printf("Warning: metrics endpoint unavailable: %v", err)
+		} else {
+			d.metrics = exporter
+			url := "http://" + exporter.Addr() + "/metrics"
+			d.controlMu.Lock()
+			d.status.MetricsURL = url
+			d.controlMu.Unlock()
+			d.goroutines.add("metrics exporter", url)
+			d.logger.Printf("Metrics endpoint serving %s", url)
+		}
+	}
+
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
 
-	// Fixed recovery-focused heartbeat (no activity-based backoff)
+	// Recovery-focused heartbeat (no activity-based backoff), 3 minutes
+	// unless heartbeat.interval is set in mayor/daemon.json.
 	// Normal wake is handled by feed subscription (bd activity --follow)
-	timer := time.NewTimer(recoveryHeartbeatInterval)
-	defer timer.Stop()
+	defer d.timers.stop()
+	d.timers.heartbeatInterval = heartbeatInterval(d.patrolConfig)
+	d.timers.heartbeat = time.NewTimer(d.timers.heartbeatInterval)
 
-	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	d.logger.Printf("Daemon running, recovery heartbeat interval %v", d.timers.heartbeatInterval)
+	d.goroutines.add("heartbeat", fmt.Sprintf("every %v", d.timers.heartbeatInterval))
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
@@ -231,6 +309,7 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Warning: failed to start feed curator: %v", err)
 	} else {
 		d.logger.Println("Feed curator started")
+		d.goroutines.add("feed curator", "")
 	}
 
 	// Start convoy manager (event-driven + periodic stranded scan)
@@ -245,51 +324,38 @@ func (d *Daemon) Run() error {
 	if len(d.beadsStores) == 0 {
 		storeOpener = d.openBeadsStores
 	}
-	d.convoyManager = NewConvoyManager(d.config.TownRoot, d.logger.Printf, d.gtPath, 0, d.beadsStores, storeOpener, isRigParked)
+	d.convoyManager = NewConvoyManager(d.config.TownRoot, d.logger.Printf, d.gtPath, convoyScanInterval(d.patrolConfig), d.beadsStores, storeOpener, isRigParked)
 	if err := d.convoyManager.Start(); err != nil {
 		d.logger.Printf("Warning: failed to start convoy manager: %v", err)
 	} else {
 		d.logger.Println("Convoy manager started")
+		d.goroutines.add("convoy manager", "")
 	}
 
 	// Start KRC pruner for automatic ephemeral data cleanup
-	krcPruner, err := NewKRCPruner(d.config.TownRoot, d.logger.Printf)
-	if err != nil {
-		d.logger.Printf("Warning: failed to create KRC pruner: %v", err)
-	} else {
-		d.krcPruner = krcPruner
-		if err := d.krcPruner.Start(); err != nil {
-			d.logger.Printf("Warning: failed to start KRC pruner: %v", err)
-		} else {
-			d.logger.Println("KRC pruner started")
-		}
-	}
+	d.startKRCPruner()
 
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
-	var doltHealthTicker *time.Ticker
-	var doltHealthChan <-chan time.Time
 	if d.doltServer != nil && d.doltServer.IsEnabled() {
 		interval := d.doltServer.HealthCheckInterval()
-		doltHealthTicker = time.NewTicker(interval)
-		doltHealthChan = doltHealthTicker.C
-		defer doltHealthTicker.Stop()
+		d.timers.doltHealth = time.NewTicker(interval)
+		d.timers.doltHealthInterval = interval
 		d.logger.Printf("Dolt health check ticker started (interval %v)", interval)
+		d.goroutines.add("dolt health check", fmt.Sprintf("every %v", interval))
 	}
 
-	// Start dedicated Dolt remotes push ticker if configured.
-	// This runs at a lower frequency (default 15 min) than the heartbeat (3 min)
-	// to periodically push databases to their git remotes.
-	var doltRemotesTicker *time.Ticker
-	var doltRemotesChan <-chan time.Time
-	if IsPatrolEnabled(d.patrolConfig, "dolt_remotes") {
-		interval := doltRemotesInterval(d.patrolConfig)
-		doltRemotesTicker = time.NewTicker(interval)
-		doltRemotesChan = doltRemotesTicker.C
-		defer doltRemotesTicker.Stop()
-		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
-	}
+	// Start dedicated Dolt remotes push and backup tickers if configured.
+	// These run at a lower frequency (default 15 min and 1 hour) than the
+	// heartbeat (3 min) to push databases to their git remotes and take
+	// snapshots. retuneTimers starts them, and restarts or stops them on a
+	// reload.
+	d.retuneTimers()
+
+	// Watch the config files so edits apply without a restart.
+	d.configStamps = d.statConfigFiles()
+	d.timers.configWatch = time.NewTicker(configWatchInterval)
 
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
@@ -309,35 +375,81 @@ func (d *Daemon) Run() error {
 				// Lifecycle signal: immediate lifecycle processing (from gt handoff)
 				d.logger.Println("Received lifecycle signal, processing lifecycle requests immediately")
 				d.processLifecycleRequests()
+			} else if isReloadSignal(sig) {
+				d.logger.Printf("Received signal %v, reloading config", sig)
+				_, _ = d.reloadConfig(reloadTriggerSignal)
 			} else {
 				d.logger.Printf("Received signal %v, shutting down", sig)
 				return d.shutdown(state)
 			}
 
-		case <-doltHealthChan:
+		case <-tickerC(d.timers.configWatch):
+			d.checkConfigFiles()
+
+		case <-tickerC(d.timers.doltHealth):
 			// Dedicated Dolt health check — fast crash detection independent
 			// of the 3-minute general heartbeat.
 			if !d.isShutdownInProgress() {
 				d.ensureDoltServerRunning()
 			}
 
-		case <-doltRemotesChan:
+		case <-tickerC(d.timers.doltRemotes):
 			// Periodic Dolt remote push — pushes databases to their configured
 			// git remotes on a 15-minute cadence (independent of heartbeat).
 			if !d.isShutdownInProgress() {
 				d.pushDoltRemotes()
 			}
 
-		case <-timer.C:
+		case <-tickerC(d.timers.doltBackups):
+			// Scheduled Dolt snapshots for point-in-time restore
+			// (default hourly), with verification and retention pruning.
+			if !d.isShutdownInProgress() {
+				d.snapshotDoltDatabases()
+			}
+
+		case <-tickerC(d.timers.auditCheckpoints):
+			// Anchor the audit chain's head in the town git repo
+			// (default hourly) so truncation is detectable.
+			if !d.isShutdownInProgress() {
+				d.checkpointAuditLog()
+			}
+
+		case <-d.timers.heartbeat.C:
 			d.heartbeat(state)
 
 			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
+			d.timers.heartbeat.Reset(d.timers.heartbeatInterval)
+
+		case <-d.patrolNow:
+			// Patrol requested over the control socket: run the heartbeat
+			// now and restart the recovery interval from here.
+			d.heartbeat(state)
+			d.timers.heartbeat.Reset(d.timers.heartbeatInterval)
+
+		case call := <-d.controlCalls:
+			call.reply <- d.runControlCall(call.req)
 		}
 	}
 }
 
-// recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
+// startKRCPruner starts the KRC pruner for automatic ephemeral data cleanup.
+// Non-fatal: an invalid .krc.yaml leaves pruning off until a reload fixes it.
+func (d *Daemon) startKRCPruner() {
+	krcPruner, err := NewKRCPruner(d.config.TownRoot, d.logger.Printf)
+	if err != nil {
+		d.logger.Printf("Warning: failed to create KRC pruner: %v", err)
+		return
+	}
+	if err := krcPruner.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start KRC pruner: %v", err)
+		return
+	}
+	d.krcPruner = krcPruner
+	d.logger.Println("KRC pruner started")
+	d.goroutines.add("krc pruner", "")
+}
+
+// recoveryHeartbeatInterval is the default interval for recovery-focused daemon.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
@@ -356,10 +468,13 @@ func (d *Daemon) heartbeat(state *State) {
 	// The shutdown.lock file is created by gt down before terminating sessions.
 	if d.isShutdownInProgress() {
 		d.logger.Println("Shutdown in progress, skipping heartbeat")
+		d.recordHeartbeat(state, false)
 		return
 	}
 
 	d.logger.Println("Heartbeat starting (recovery-focused)")
+	d.recordHeartbeat(state, true)
+	defer d.recordHeartbeat(state, false)
 
 	// 0. Ensure Dolt server is running (if configured)
 	// This must happen before beads operations that depend on Dolt.
@@ -422,6 +537,16 @@ func (d *Daemon) heartbeat(state *State) {
 	// 8. Process lifecycle requests
 	d.processLifecycleRequests()
 
+	// 8b. Dispatch queued sling work (gt sling --queue) that now fits capacity
+	d.dispatchQueuedWork()
+
+	// 8c. Refresh conflict forecasts for in-flight polecat branches
+	if IsPatrolEnabled(d.patrolConfig, "refinery") {
+		d.forecastConflicts()
+		// 8d. File weekly flaky-test reports for the rig crews
+		d.fileFlakyReports()
+	}
+
 	// 9. (Removed) Stale agent check - violated "discover, don't track"
 
 	// 10. Check for GUPP violations (agents with work-on-hook not progressing)
@@ -445,6 +570,10 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 14. Archive agent transcripts so gt seance can answer from them after
+	// the runtime cleans its transcripts up.
+	d.archiveTranscripts()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -989,6 +1118,10 @@ func (d *Daemon) getPatrolRigs(patrol string) []string {
 // Returns true if the rig can have agents auto-started.
 // Returns false (with reason) if the rig is parked, docked, or has auto_restart blocked/disabled.
 func (d *Daemon) isRigOperational(rigName string) (bool, string) {
+	if d.isRigPaused(rigName) {
+		return false, "rig is paused (daemon control)"
+	}
+
 	cfg := wisp.NewConfig(d.config.TownRoot, rigName)
 
 	// Warn if wisp config is missing - parked/docked state may have been lost
@@ -1091,6 +1224,233 @@ func (d *Daemon) triggerPendingSpawns() {
 	}
 }
 
+// queueDispatchTimeout bounds one `gt schedule run`, which slings queued
+// entries one at a time.
+const queueDispatchTimeout = 5 * time.Minute
+
+// dispatchQueuedWork starts `gt schedule run` in the background when work is
+// waiting in the sling queue. The scheduler decides what fits within polecat
+// limits, Dolt capacity and account quota; the daemon only provides the
+// periodic trigger. Dispatch slings one entry at a time, so it runs off the
+// main loop to keep control requests and other patrols responsive. A
+// heartbeat that finds the previous dispatch still running skips it.
+func (d *Daemon) dispatchQueuedWork() {
+	q, err := scheduler.NewManager(d.config.TownRoot).Load()
+	if err != nil {
+		d.logger.Printf("Error loading sling queue: %v", err)
+		return
+	}
+	if len(q.Entries) == 0 {
+		return
+	}
+	if !d.queueDispatching.CompareAndSwap(false, true) {
+		return
+	}
+
+	queued := len(q.Entries)
+	d.background.Add(1)
+	d.goroutines.add("sling queue dispatch", fmt.Sprintf("%d queued", queued))
+	go func() {
+		defer d.background.Done()
+		defer d.queueDispatching.Store(false)
+		defer d.goroutines.remove("sling queue dispatch")
+
+		ctx, cancel := context.WithTimeout(d.ctx, queueDispatchTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, d.gtPath, "schedule", "run", "--quiet") //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			d.logger.Printf("Sling queue dispatch failed: %v: %s", err, strings.TrimSpace(string(out)))
+			return
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			d.logger.Printf("Sling queue (%d queued): %s", queued, msg)
+		}
+	}()
+}
+
+// conflictForecastInterval is how often the daemon recomputes each rig's
+// conflict forecast. Recomputing fetches origin and diffs every in-flight
+// branch, so it runs less often than the heartbeat.
+const conflictForecastInterval = 10 * time.Minute
+
+// Per-rig patrol commands that fail are retried after a delay that doubles
+// per consecutive failure, up to maxRigPatrolBackoff.
+const (
+	baseRigPatrolBackoff = 5 * time.Minute
+	maxRigPatrolBackoff  = 2 * time.Hour
+)
+
+// rigFailure records a rig's consecutive patrol command failures.
+type rigFailure struct {
+	attempts int
+	last     time.Time
+}
+
+// rigBackoff tracks failing rigs for one per-rig patrol command so a broken
+// rig is not retried on every heartbeat.
+type rigBackoff map[string]rigFailure
+
+// ready reports whether rig is due for another attempt at now.
+func (b rigBackoff) ready(rig string, now time.Time) bool {
+	f, ok := b[rig]
+	if !ok {
+		return true
+	}
+	delay := baseRigPatrolBackoff
+	for i := 1; i < f.attempts && delay < maxRigPatrolBackoff; i++ {
+		delay *= 2
+	}
+	return now.Sub(f.last) >= min(delay, maxRigPatrolBackoff)
+}
+
+// record notes the outcome of an attempt for rig at now.
+func (b rigBackoff) record(rig string, err error, now time.Time) {
+	if err == nil {
+		delete(b, rig)
+		return
+	}
+	f := b[rig]
+	f.attempts++
+	f.last = now
+	b[rig] = f
+}
+
+// forecastConflicts starts `gt mq conflicts <rig> --record` in the background
+// for each operational rig whose cached forecast is older than
+// conflictForecastInterval, so sling, done and submit can warn about
+// overlapping work without diffing inline. A heartbeat that finds the
+// previous pass still running skips it.
+func (d *Daemon) forecastConflicts() {
+	if !d.forecasting.CompareAndSwap(false, true) {
+		return
+	}
+	d.background.Add(1)
+	d.goroutines.add("conflict forecast", "")
+	go func() {
+		defer d.background.Done()
+		defer d.forecasting.Store(false)
+		defer d.goroutines.remove("conflict forecast")
+		if d.forecastBackoff == nil {
+			d.forecastBackoff = rigBackoff{}
+		}
+
+		for _, rigName := range d.getKnownRigs() {
+			if d.ctx.Err() != nil {
+				return
+			}
+			if ok, _ := d.isRigOperational(rigName); !ok || !d.forecastBackoff.ready(rigName, time.Now()) {
+				continue
+			}
+			rigPath := filepath.Join(d.config.TownRoot, rigName)
+			if info, err := os.Stat(refinery.ConflictForecastPath(rigPath)); err == nil && time.Since(info.ModTime()) < conflictForecastInterval {
+				continue
+			}
+
+			out, err := d.runRigPatrolCommand(2*time.Minute, "mq", "conflicts", rigName, "--record")
+			d.forecastBackoff.record(rigName, err, time.Now())
+			if err != nil {
+				d.logger.Printf("Conflict forecast failed for %s: %v: %s", rigName, err, out)
+				continue
+			}
+			if out != "" {
+				d.logger.Printf("Conflict forecast for %s: %s", rigName, out)
+			}
+		}
+	}()
+}
+
+// fileFlakyReports starts `gt mq flaky report <rig>` in the background for
+// each operational rig whose test history has flaky tests and no report in
+// the last week. A heartbeat that finds the previous pass still running
+// skips it.
+func (d *Daemon) fileFlakyReports() {
+	if !d.flakyReporting.CompareAndSwap(false, true) {
+		return
+	}
+	d.background.Add(1)
+	d.goroutines.add("flaky test reports", "")
+	go func() {
+		defer d.background.Done()
+		defer d.flakyReporting.Store(false)
+		defer d.goroutines.remove("flaky test reports")
+		if d.flakyBackoff == nil {
+			d.flakyBackoff = rigBackoff{}
+		}
+
+		now := time.Now()
+		for _, rigName := range d.getKnownRigs() {
+			if d.ctx.Err() != nil {
+				return
+			}
+			if ok, _ := d.isRigOperational(rigName); !ok || !d.flakyBackoff.ready(rigName, now) {
+				continue
+			}
+			history, err := refinery.LoadFlakyHistory(filepath.Join(d.config.TownRoot, rigName))
+			if err != nil || !history.ReportDue(now) {
+				continue
+			}
+
+			out, err := d.runRigPatrolCommand(time.Minute, "mq", "flaky", "report", rigName)
+			d.flakyBackoff.record(rigName, err, time.Now())
+			if err != nil {
+				d.logger.Printf("Flaky test report failed for %s: %v: %s", rigName, err, out)
+				continue
+			}
+			if out != "" {
+				d.logger.Printf("Flaky test report for %s: %s", rigName, out)
+			}
+		}
+	}()
+}
+
+// transcriptArchiveInterval is how often the daemon refreshes the seance
+// transcript archive. Refreshing only re-parses transcripts that changed.
+const transcriptArchiveInterval = time.Hour
+
+// archiveTranscripts starts `gt seance --index` in the background once per
+// transcriptArchiveInterval, so session transcripts are archived while they
+// still exist rather than only when someone runs gt seance.
+func (d *Daemon) archiveTranscripts() {
+	if time.Since(d.lastTranscriptArchive) < transcriptArchiveInterval {
+		return
+	}
+	if !d.archiving.CompareAndSwap(false, true) {
+		return
+	}
+	d.lastTranscriptArchive = time.Now()
+
+	d.background.Add(1)
+	d.goroutines.add("transcript archive", "")
+	go func() {
+		defer d.background.Done()
+		defer d.archiving.Store(false)
+		defer d.goroutines.remove("transcript archive")
+
+		out, err := d.runRigPatrolCommand(5*time.Minute, "seance", "--index")
+		if err != nil {
+			d.logger.Printf("Transcript archive failed: %v: %s", err, out)
+			return
+		}
+		if out != "" {
+			d.logger.Printf("Transcript archive: %s", out)
+		}
+	}()
+}
+
+// runRigPatrolCommand runs gt with args from the town root, bounded by
+// timeout and cancelled when the daemon shuts down. It returns the trimmed
+// combined output.
+func (d *Daemon) runRigPatrolCommand(timeout time.Duration, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	out, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
 // processLifecycleRequests checks for and processes lifecycle requests.
 func (d *Daemon) processLifecycleRequests() {
 	d.ProcessLifecycleRequests()
@@ -1100,6 +1460,21 @@ func (d *Daemon) processLifecycleRequests() {
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
 
+	// Stop the control socket first so no request races the teardown.
+	// Cancel the context so handlers waiting on the main loop return.
+	d.cancel()
+	if d.control != nil {
+		d.control.Close()
+		d.logger.Println("Control socket closed")
+	}
+	if d.metrics != nil {
+		d.metrics.stop()
+		d.logger.Println("Metrics endpoint stopped")
+	}
+
+	// The cancelled context kills any in-flight queue dispatch.
+	d.background.Wait()
+
 	// Stop feed curator
 	if d.curator != nil {
 		d.curator.Stop()
@@ -1286,13 +1661,25 @@ func StopDaemon(townRoot string) error {
 		return fmt.Errorf("finding process: %w", err)
 	}
 
-	// Send SIGTERM for graceful shutdown
-	if err := process.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("sending SIGTERM: %w", err)
-	}
+	if _, err := CallDaemon(townRoot, ControlRequest{Method: ControlStop}, controlIOTimeout); err == nil {
+		// Stop acknowledged over the control socket: wait for the daemon to
+		// finish its shutdown instead of guessing with a fixed delay.
+		deadline := time.Now().Add(controlStopTimeout)
+		for time.Now().Before(deadline) {
+			if err := process.Signal(syscall.Signal(0)); err != nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	} else {
+		// Send SIGTERM for graceful shutdown
+		if err := process.Signal(syscall.SIGTERM); err != nil {
+			return fmt.Errorf("sending SIGTERM: %w", err)
+		}
 
-	// Wait a bit for graceful shutdown
-	time.Sleep(constants.ShutdownNotifyDelay)
+		// Wait a bit for graceful shutdown
+		time.Sleep(constants.ShutdownNotifyDelay)
+	}
 
 	// Check if still running
 	if err := process.Signal(syscall.Signal(0)); err == nil {
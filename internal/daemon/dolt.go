@@ -109,6 +109,18 @@ type DoltServerStatus struct {
 	Error     string    `json:"error,omitempty"`
 }
 
+// DoltHealth is the outcome of the most recent Dolt health check.
+type DoltHealth struct {
+	CheckedAt      time.Time     `json:"checked_at,omitempty"`
+	Healthy        bool          `json:"healthy"`
+	ReadOnly       bool          `json:"read_only"`
+	Latency        time.Duration `json:"latency,omitempty"`
+	Connections    int           `json:"connections"`     // -1 if the count query failed
+	DiskBytes      int64         `json:"disk_bytes"`      // size of the data directory
+	RecentRestarts int           `json:"recent_restarts"` // restarts within the backoff window
+	Escalated      bool          `json:"escalated"`       // restart cap hit and escalated
+}
+
 // DoltServerManager manages the Dolt SQL server lifecycle.
 type DoltServerManager struct {
 	config   *DoltServerConfig
@@ -127,6 +139,11 @@ type DoltServerManager struct {
 	escalated       bool          // Whether we've already escalated (avoid spamming)
 	restarting      bool          // Whether a restart is in progress (guards against concurrent restarts)
 
+	// Outcome of the latest health check, exported as metrics. Guarded by
+	// healthMu rather than mu so scrapes never wait on a restart.
+	healthMu sync.Mutex
+	health   DoltHealth
+
 	// Test hooks (nil = use real implementations; set only in tests)
 	healthCheckFn      func() error
 	writeProbeCheckFn  func() error
@@ -242,6 +259,47 @@ func (m *DoltServerManager) HealthCheckInterval() time.Duration {
 	return DefaultDoltHealthCheckInterval
 }
 
+// UpdateConfig applies the restart policy and health check settings of a
+// reloaded config. Settings that identify the server (enabled, external,
+// host, port, user, password, data_dir, log_file) keep their running values
+// until the daemon restarts; the names of any that differ are returned.
+// Must be called from the daemon's main loop, like the health checks.
+func (m *DoltServerManager) UpdateConfig(config *DoltServerConfig) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cur := m.config
+	var pending []string
+	for _, f := range []struct {
+		name    string
+		changed bool
+	}{
+		{"enabled", config.Enabled != cur.Enabled},
+		{"external", config.External != cur.External},
+		{"host", config.Host != cur.Host},
+		{"port", config.Port != cur.Port},
+		{"user", config.User != cur.User},
+		{"password", config.Password != cur.Password},
+		{"data_dir", config.DataDir != cur.DataDir},
+		{"log_file", config.LogFile != cur.LogFile},
+	} {
+		if f.changed {
+			pending = append(pending, "dolt_server."+f.name)
+		}
+	}
+
+	next := *cur
+	next.AutoRestart = config.AutoRestart
+	next.RestartDelay = config.RestartDelay
+	next.MaxRestartDelay = config.MaxRestartDelay
+	next.MaxRestartsInWindow = config.MaxRestartsInWindow
+	next.RestartWindow = config.RestartWindow
+	next.HealthyResetInterval = config.HealthyResetInterval
+	next.HealthCheckInterval = config.HealthCheckInterval
+	m.config = &next
+	return pending
+}
+
 // Status returns the current status of the Dolt server.
 func (m *DoltServerManager) Status() *DoltServerStatus {
 	m.mu.Lock()
@@ -363,6 +421,7 @@ func (m *DoltServerManager) EnsureRunning() error {
 		// Already running, check health
 		m.lastCheck = m.now()
 		if err := m.checkHealthLocked(); err != nil {
+			m.recordHealth(func(h *DoltHealth) { h.Healthy = false })
 			m.logger("Dolt server unhealthy: %v, restarting...", err)
 			m.sendUnhealthyAlert(err)
 			m.writeUnhealthySignal("health_check_failed", err.Error())
@@ -374,6 +433,7 @@ func (m *DoltServerManager) EnsureRunning() error {
 		// Under concurrent write load, Dolt can enter a persistent read-only
 		// state that requires a server restart to clear.
 		if err := m.checkWriteHealthLocked(); err != nil {
+			m.recordHealth(func(h *DoltHealth) { h.ReadOnly = true })
 			m.logger("Dolt server read-only: %v, restarting...", err)
 			m.sendReadOnlyAlert(err)
 			m.writeUnhealthySignal("read_only", err.Error())
@@ -381,12 +441,14 @@ func (m *DoltServerManager) EnsureRunning() error {
 			return m.restartWithBackoff()
 		}
 		// Server is healthy — clear any stale unhealthy signal and reset backoff
+		m.recordHealth(func(h *DoltHealth) { h.Healthy, h.ReadOnly = true, false })
 		m.clearUnhealthySignal()
 		m.maybeResetBackoff()
 		return nil
 	}
 
 	// Not running, start it
+	m.recordHealth(func(h *DoltHealth) { h.Healthy = false })
 	if pid > 0 {
 		m.logger("Dolt server PID %d is dead, cleaning up and restarting...", pid)
 		m.sendCrashAlert(pid)
@@ -847,7 +909,27 @@ func (m *DoltServerManager) stopLocked() {
 func (m *DoltServerManager) checkHealth() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return m.checkHealthLocked()
+	err := m.checkHealthLocked()
+	m.recordHealth(func(h *DoltHealth) { h.Healthy = err == nil })
+	return err
+}
+
+// Health returns the outcome of the most recent health check.
+func (m *DoltServerManager) Health() DoltHealth {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	return m.health
+}
+
+// recordHealth updates the exported health under healthMu. Restart
+// bookkeeping is copied in each time; callers hold m.mu.
+func (m *DoltServerManager) recordHealth(update func(*DoltHealth)) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	update(&m.health)
+	m.health.CheckedAt = m.now()
+	m.health.RecentRestarts = len(m.restartTimes)
+	m.health.Escalated = m.escalated
 }
 
 // checkHealthLocked checks health. Must be called with m.mu held.
@@ -873,6 +955,7 @@ func (m *DoltServerManager) checkHealthLocked() error {
 	}
 
 	latency := time.Since(start)
+	m.recordHealth(func(h *DoltHealth) { h.Latency = latency })
 	if latency > 1*time.Second {
 		m.logger("Warning: Dolt health check latency %v exceeds 1s threshold — server may be under stress", latency.Round(time.Millisecond))
 	}
@@ -896,6 +979,9 @@ func (m *DoltServerManager) checkConnectionCount() {
 		"-q", "SELECT COUNT(*) AS cnt FROM information_schema.PROCESSLIST",
 	)
 
+	count := -1
+	defer func() { m.recordHealth(func(h *DoltHealth) { h.Connections = count }) }()
+
 	output, err := cmd.Output()
 	if err != nil {
 		return // non-fatal
@@ -905,8 +991,9 @@ func (m *DoltServerManager) checkConnectionCount() {
 	if len(lines) < 2 {
 		return
 	}
-	count, err := strconv.Atoi(strings.TrimSpace(lines[len(lines)-1]))
+	count, err = strconv.Atoi(strings.TrimSpace(lines[len(lines)-1]))
 	if err != nil {
+		count = -1
 		return
 	}
 
@@ -937,6 +1024,7 @@ func (m *DoltServerManager) checkDiskUsage() {
 		}
 		return nil
 	})
+	m.recordHealth(func(h *DoltHealth) { h.DiskBytes = total })
 
 	const gb = 1024 * 1024 * 1024
 	if total > gb {
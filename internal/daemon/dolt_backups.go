@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const defaultDoltBackupsInterval = time.Hour
+
+// doltBackupsInterval returns the configured snapshot interval, or the default (1h).
+func doltBackupsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DoltBackups != nil {
+		if config.Patrols.DoltBackups.Interval > 0 {
+			return config.Patrols.DoltBackups.Interval
+		}
+	}
+	return defaultDoltBackupsInterval
+}
+
+// doltBackupsRetention returns the configured retention policy, filling
+// unset fields from doltserver.DefaultRetentionPolicy.
+func doltBackupsRetention(config *DaemonPatrolConfig) doltserver.RetentionPolicy {
+	policy := doltserver.DefaultRetentionPolicy()
+	if config != nil && config.Patrols != nil && config.Patrols.DoltBackups != nil {
+		if keep := config.Patrols.DoltBackups.Keep; keep > 0 {
+			policy.Keep = keep
+		}
+		if keepDaily := config.Patrols.DoltBackups.KeepDaily; keepDaily > 0 {
+			policy.KeepDaily = keepDaily
+		}
+	}
+	return policy
+}
+
+// snapshotDoltDatabases starts a snapshot pass in the background. Each
+// backup and verify restore can take minutes per database, so the pass runs
+// off the main loop to keep Dolt crash detection, the heartbeat and control
+// requests responsive. A tick that finds the previous pass still running
+// skips it.
+func (d *Daemon) snapshotDoltDatabases() {
+	if !IsPatrolEnabled(d.patrolConfig, "dolt_backups") {
+		return
+	}
+	if !d.snapshotting.CompareAndSwap(false, true) {
+		d.logger.Printf("dolt_backups: previous snapshot pass still running, skipping")
+		return
+	}
+
+	d.background.Add(1)
+	d.goroutines.add("dolt snapshots", "")
+	go func() {
+		defer d.background.Done()
+		defer d.snapshotting.Store(false)
+		defer d.goroutines.remove("dolt snapshots")
+		d.runDoltSnapshots()
+	}()
+}
+
+// runDoltSnapshots takes a snapshot of every served database, verifies
+// each by restoring it into a scratch directory, and prunes snapshots the
+// retention policy no longer keeps. It stops between databases once the
+// daemon is shutting down.
+// Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) runDoltSnapshots() {
+	townRoot := d.config.TownRoot
+
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		d.logger.Printf("dolt_backups: error listing databases: %v", err)
+		return
+	}
+	if len(databases) == 0 {
+		d.logger.Printf("dolt_backups: no databases to snapshot")
+		return
+	}
+
+	verify := !d.patrolConfig.Patrols.DoltBackups.SkipVerify
+	taken := 0
+	for _, db := range databases {
+		if d.ctx.Err() != nil {
+			d.logger.Printf("dolt_backups: shutting down, stopped after %d/%d database(s)", taken, len(databases))
+			return
+		}
+		s, err := doltserver.CreateSnapshot(townRoot, db)
+		if err != nil {
+			d.logger.Printf("dolt_backups: %s: snapshot failed: %v", db, err)
+			continue
+		}
+		taken++
+		if !verify || d.ctx.Err() != nil {
+			continue
+		}
+		if err := doltserver.VerifySnapshot(s); err != nil {
+			d.logger.Printf("dolt_backups: WARNING: %s: snapshot %s failed verification: %v", db, s.Path, err)
+		}
+	}
+	d.logger.Printf("dolt_backups: snapshotted %d/%d database(s)", taken, len(databases))
+
+	removed, err := doltserver.PruneSnapshots(townRoot, doltBackupsRetention(d.patrolConfig))
+	if err != nil {
+		d.logger.Printf("dolt_backups: prune failed: %v", err)
+	} else if len(removed) > 0 {
+		d.logger.Printf("dolt_backups: pruned %d expired snapshot(s)", len(removed))
+	}
+}
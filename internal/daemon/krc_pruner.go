@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -12,11 +13,17 @@ import (
 // It runs as a background goroutine within the daemon.
 type KRCPruner struct {
 	townRoot string
-	config   *krc.Config
 	logger   func(format string, args ...interface{})
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
+
+	// mu guards config, which UpdateConfig replaces on a daemon reload.
+	mu     sync.Mutex
+	config *krc.Config
+
+	// intervalCh carries a changed prune interval to the run loop.
+	intervalCh chan time.Duration
 }
 
 // NewKRCPruner creates a new KRC pruner.
@@ -25,15 +32,19 @@ func NewKRCPruner(townRoot string, logger func(format string, args ...interface{
 	if err != nil {
 		return nil, err
 	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid krc config: %w", err)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &KRCPruner{
-		townRoot: townRoot,
-		config:   config,
-		logger:   logger,
-		ctx:      ctx,
-		cancel:   cancel,
+		townRoot:   townRoot,
+		config:     config,
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+		intervalCh: make(chan time.Duration, 1),
 	}, nil
 }
 
@@ -55,17 +66,45 @@ func (p *KRCPruner) Stop() {
 	p.wg.Wait()
 }
 
+// UpdateConfig replaces the TTLs and prune interval of a running pruner.
+// The config must already be validated. A changed interval restarts the
+// countdown to the next prune from now.
+func (p *KRCPruner) UpdateConfig(config *krc.Config) {
+	p.mu.Lock()
+	old := p.config
+	p.config = config
+	p.mu.Unlock()
+
+	if config.PruneInterval != old.PruneInterval {
+		select {
+		case <-p.intervalCh: // replace an update the loop hasn't taken yet
+		default:
+		}
+		p.intervalCh <- config.PruneInterval
+	}
+}
+
+// currentConfig returns the config in effect.
+func (p *KRCPruner) currentConfig() *krc.Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.config
+}
+
 // run is the main pruner loop.
 func (p *KRCPruner) run() {
 	defer p.wg.Done()
 
-	ticker := time.NewTicker(p.config.PruneInterval)
+	ticker := time.NewTicker(p.currentConfig().PruneInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-p.ctx.Done():
 			return
+		case interval := <-p.intervalCh:
+			ticker.Reset(interval)
+			p.logger("KRC prune interval now %v", interval)
 		case <-ticker.C:
 			p.prune()
 		}
@@ -74,17 +113,20 @@ func (p *KRCPruner) run() {
 
 // prune runs a single prune operation.
 func (p *KRCPruner) prune() {
-	pruner := krc.NewPruner(p.townRoot, p.config)
+	pruner := krc.NewPruner(p.townRoot, p.currentConfig())
 	result, err := pruner.Prune()
 	if err != nil {
 		p.logger("KRC prune error: %v", err)
 		return
 	}
 
-	if result.EventsPruned > 0 {
-		p.logger("KRC pruned %d events (saved %d bytes) in %v",
+	if result.EventsPruned > 0 || result.SegmentsExpired > 0 || result.RecordingsExpired > 0 {
+		p.logger("KRC pruned %d events (%d archived, saved %d bytes), expired %d archive segments and %d recordings in %v",
 			result.EventsPruned,
+			result.EventsArchived,
 			result.BytesBefore-result.BytesAfter,
+			result.SegmentsExpired,
+			result.RecordingsExpired,
 			result.Duration.Round(time.Millisecond))
 	}
 }
@@ -0,0 +1,515 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/quota"
+)
+
+const (
+	// defaultMetricsListen is where the metrics endpoint listens by default.
+	// Loopback only: the metrics name agents, rigs and accounts.
+	defaultMetricsListen = "127.0.0.1:9464"
+
+	// metricsCacheTTL is how long the beads-backed collectors are reused.
+	// Each one spawns bd, so scrapes inside the window share one result.
+	metricsCacheTTL = 30 * time.Second
+
+	// metricsContentType is the Prometheus text exposition format, which
+	// OpenMetrics scrapers also accept.
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// metricsCollector adds one group of metrics to a set.
+type metricsCollector struct {
+	name    string
+	collect func(*metricSet) error
+}
+
+// metricsExporter serves /metrics for the daemon. Cheap collectors (daemon
+// state, restart tracking, Dolt health, quota, event counters) run on every
+// scrape; collectors that query beads are cached for metricsCacheTTL.
+type metricsExporter struct {
+	server   *http.Server
+	listener net.Listener
+
+	live   []metricsCollector
+	cached []metricsCollector
+
+	mu       sync.Mutex
+	cachedAt time.Time
+	cache    *metricSet
+	now      func() time.Time
+}
+
+// metricsListenAddr returns the configured listen address, or "" when the
+// endpoint is disabled.
+func metricsListenAddr(config *DaemonPatrolConfig) string {
+	if config == nil || config.Metrics == nil || !config.Metrics.Enabled {
+		return ""
+	}
+	if config.Metrics.Listen != "" {
+		return config.Metrics.Listen
+	}
+	return defaultMetricsListen
+}
+
+// newMetricsExporter returns an exporter wired to the daemon's collectors.
+func (d *Daemon) newMetricsExporter() *metricsExporter {
+	eventCounts := newEventCounter(filepath.Join(d.config.TownRoot, events.EventsFile))
+	return &metricsExporter{
+		live: []metricsCollector{
+			{"daemon", d.collectDaemonMetrics},
+			{"restarts", d.collectRestartMetrics},
+			{"dolt", d.collectDoltMetrics},
+			{"quota", d.collectQuotaMetrics},
+			{"events", eventCounts.collect},
+		},
+		cached: []metricsCollector{
+			{"agents", d.collectAgentMetrics},
+			{"merge_queue", d.collectMergeQueueMetrics},
+			{"mail", d.collectMailMetrics},
+			{"escalations", d.collectEscalationMetrics},
+		},
+		now: time.Now,
+	}
+}
+
+// start listens on addr and serves /metrics in the background.
+func (e *metricsExporter) start(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	e.listener = l
+	e.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = e.server.Serve(l) }()
+	return nil
+}
+
+// Addr returns the address the exporter is listening on.
+func (e *metricsExporter) Addr() string {
+	return e.listener.Addr().String()
+}
+
+// stop shuts the HTTP server down, waiting briefly for in-flight scrapes.
+func (e *metricsExporter) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = e.server.Shutdown(ctx)
+}
+
+func (e *metricsExporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	set := e.gather()
+	w.Header().Set("Content-Type", metricsContentType)
+	_, _ = set.WriteTo(w)
+}
+
+// gather runs the live collectors and merges in the cached ones, refreshing
+// them when the cache has expired. Each collector reports success in
+// gastown_metrics_collector_success so a failing bd query shows up as a
+// series rather than a scrape error.
+func (e *metricsExporter) gather() *metricSet {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	if e.cache == nil || now.Sub(e.cachedAt) >= metricsCacheTTL {
+		e.cache = runCollectors(e.cached)
+		e.cachedAt = now
+	}
+	set := runCollectors(e.live)
+	set.merge(e.cache)
+	return set
+}
+
+func runCollectors(collectors []metricsCollector) *metricSet {
+	set := newMetricSet()
+	for _, c := range collectors {
+		ok := 1.0
+		if err := c.collect(set); err != nil {
+			ok = 0
+		}
+		set.gauge("gastown_metrics_collector_success", "Whether the collector succeeded on the last scrape.", ok, "collector", c.name)
+	}
+	return set
+}
+
+// collectDaemonMetrics reports the daemon's own liveness and heartbeat.
+func (d *Daemon) collectDaemonMetrics(s *metricSet) error {
+	st := d.statusSnapshot()
+	s.gauge("gastown_daemon_up", "Whether the Gas Town daemon is running.", 1)
+	s.gauge("gastown_daemon_start_time_seconds", "When the daemon started, as a Unix timestamp.", unixSeconds(st.StartedAt))
+	s.counter("gastown_daemon_heartbeats_total", "Heartbeat cycles completed since the daemon started.", float64(st.HeartbeatCount))
+	s.gauge("gastown_daemon_last_heartbeat_seconds", "When the last heartbeat completed, as a Unix timestamp.", unixSeconds(st.LastHeartbeat))
+	s.gauge("gastown_daemon_heartbeat_running", "Whether a heartbeat cycle is running now.", boolValue(st.InHeartbeat))
+	for _, rig := range st.PausedRigs {
+		s.gauge("gastown_daemon_rig_paused", "Rigs paused over the daemon control socket.", 1, "rig", rig)
+	}
+	return nil
+}
+
+// collectRestartMetrics reports the restart tracker's per-agent state.
+func (d *Daemon) collectRestartMetrics(s *metricSet) error {
+	if d.restartTracker == nil {
+		return nil
+	}
+	now := time.Now()
+	for agent, info := range d.restartTracker.Snapshot() {
+		s.counter("gastown_agent_restarts_total", "Restarts recorded for the agent by the daemon.", float64(info.TotalRestarts), "agent", agent)
+		s.gauge("gastown_agent_crash_loop", "Whether the agent is flagged as crash-looping.", boolValue(!info.CrashLoopSince.IsZero()), "agent", agent)
+		backoff := info.BackoffUntil.Sub(now)
+		if backoff < 0 {
+			backoff = 0
+		}
+		s.gauge("gastown_agent_restart_backoff_seconds", "Time until the agent may be restarted again.", backoff.Seconds(), "agent", agent)
+	}
+	return nil
+}
+
+// collectDoltMetrics reports the latest Dolt health check. Nothing is
+// reported when the daemon does not manage a Dolt server.
+func (d *Daemon) collectDoltMetrics(s *metricSet) error {
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		return nil
+	}
+	h := d.doltServer.Health()
+	if h.CheckedAt.IsZero() {
+		return nil // no health check yet
+	}
+	s.gauge("gastown_dolt_healthy", "Whether the last Dolt health check passed.", boolValue(h.Healthy))
+	s.gauge("gastown_dolt_read_only", "Whether the Dolt server was found in read-only mode.", boolValue(h.ReadOnly))
+	s.gauge("gastown_dolt_health_check_seconds", "When Dolt was last checked, as a Unix timestamp.", unixSeconds(h.CheckedAt))
+	s.gauge("gastown_dolt_latency_seconds", "Latency of the last Dolt SELECT 1.", h.Latency.Seconds())
+	if h.Connections >= 0 {
+		s.gauge("gastown_dolt_connections", "Open connections on the Dolt server.", float64(h.Connections))
+	}
+	s.gauge("gastown_dolt_disk_bytes", "Size of the Dolt data directory.", float64(h.DiskBytes))
+	s.gauge("gastown_dolt_recent_restarts", "Dolt restarts within the backoff window.", float64(h.RecentRestarts))
+	s.gauge("gastown_dolt_escalated", "Whether the Dolt restart cap was hit and escalated.", boolValue(h.Escalated))
+	return nil
+}
+
+// collectQuotaMetrics reports the rate-limit status of each account.
+func (d *Daemon) collectQuotaMetrics(s *metricSet) error {
+	state, err := quota.NewManager(d.config.TownRoot).Load()
+	if err != nil {
+		return err
+	}
+	for handle, acct := range state.Accounts {
+		status := string(acct.Status)
+		if status == "" {
+			status = "unknown"
+		}
+		s.gauge("gastown_quota_account", "Account quota status (1 for the account's current status).", 1, "account", handle, "status", status)
+	}
+	return nil
+}
+
+// collectAgentMetrics counts agent beads by rig, role and state across the
+// town and every rig.
+func (d *Daemon) collectAgentMetrics(s *metricSet) error {
+	type key struct{ rig, role, state string }
+	counts := make(map[key]int)
+	var firstErr error
+	for _, dir := range d.beadsWorkDirs() {
+		issues, err := beads.New(dir).ListAgentBeads()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, issue := range issues {
+			f := beads.ParseAgentFields(issue.Description)
+			k := key{f.Rig, f.RoleType, f.AgentState}
+			if k.state == "" {
+				k.state = "unknown"
+			}
+			counts[k]++
+		}
+	}
+	for k, n := range counts {
+		s.gauge("gastown_agents", "Agents by rig, role and self-reported state.", float64(n), "rig", k.rig, "role", k.role, "state", k.state)
+	}
+	return firstErr
+}
+
+// collectMergeQueueMetrics reports open merge requests per rig.
+func (d *Daemon) collectMergeQueueMetrics(s *metricSet) error {
+	var firstErr error
+	for _, rigName := range d.getKnownRigs() {
+		issues, err := beads.New(filepath.Join(d.config.TownRoot, rigName)).List(beads.ListOptions{
+			Label:    "gt:merge-request",
+			Status:   "open",
+			Priority: -1,
+		})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		s.gauge("gastown_merge_queue_depth", "Open merge requests waiting in the rig's queue.", float64(len(issues)), "rig", rigName)
+	}
+	return firstErr
+}
+
+// collectMailMetrics reports unread mail per recipient. All mail lives in
+// the town beads, so one query covers every identity.
+func (d *Daemon) collectMailMetrics(s *metricSet) error {
+	issues, err := beads.New(d.config.TownRoot).List(beads.ListOptions{
+		Label:    "gt:message",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		return err
+	}
+	backlog := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Assignee != "" {
+			backlog[issue.Assignee]++
+		}
+	}
+	for identity, n := range backlog {
+		s.gauge("gastown_mail_backlog", "Open (unread) messages per recipient.", float64(n), "identity", identity)
+	}
+	return nil
+}
+
+// collectEscalationMetrics reports open escalations by severity.
+func (d *Daemon) collectEscalationMetrics(s *metricSet) error {
+	issues, err := beads.New(d.config.TownRoot).ListEscalations()
+	if err != nil {
+		return err
+	}
+	bySeverity := make(map[string]int)
+	for _, issue := range issues {
+		severity := "unknown"
+		for _, label := range issue.Labels {
+			if v, ok := strings.CutPrefix(label, "severity:"); ok {
+				severity = v
+				break
+			}
+		}
+		bySeverity[severity]++
+	}
+	for severity, n := range bySeverity {
+		s.gauge("gastown_escalations_open", "Open escalations by severity.", float64(n), "severity", severity)
+	}
+	return nil
+}
+
+// beadsWorkDirs returns the town root followed by each known rig's path.
+func (d *Daemon) beadsWorkDirs() []string {
+	dirs := []string{d.config.TownRoot}
+	for _, rigName := range d.getKnownRigs() {
+		dirs = append(dirs, filepath.Join(d.config.TownRoot, rigName))
+	}
+	return dirs
+}
+
+// eventCounter tails the town's events file and counts events by type.
+// Counting starts at the end of the file when the daemon starts, so the
+// counters cover this daemon's lifetime like any other Prometheus counter.
+type eventCounter struct {
+	path   string
+	offset int64
+	counts map[string]int
+}
+
+func newEventCounter(path string) *eventCounter {
+	c := &eventCounter{path: path, counts: make(map[string]int)}
+	if info, err := os.Stat(path); err == nil {
+		c.offset = info.Size()
+	}
+	return c
+}
+
+// collect reads events appended since the last scrape. Only called with
+// the exporter's lock held.
+func (c *eventCounter) collect(s *metricSet) error {
+	if err := c.advance(); err != nil {
+		return err
+	}
+	for typ, n := range c.counts {
+		s.counter("gastown_events_total", "Events logged since the daemon started, by type.", float64(n), "type", typ)
+	}
+	return nil
+}
+
+func (c *eventCounter) advance() error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < c.offset {
+		// Rewritten by pruning: skip what is there rather than recount it.
+		c.offset = info.Size()
+		return nil
+	}
+	if _, err := f.Seek(c.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// A partial trailing line is read again next time.
+			break
+		}
+		c.offset += int64(len(line))
+		var ev struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(line, &ev) == nil && ev.Type != "" {
+			c.counts[ev.Type]++
+		}
+	}
+	return nil
+}
+
+// metricSet is a set of metric families rendered in the Prometheus text
+// exposition format.
+type metricSet struct {
+	families map[string]*metricFamily
+}
+
+type metricFamily struct {
+	name, help, typ string
+	samples         map[string]float64 // rendered label set -> value
+}
+
+func newMetricSet() *metricSet {
+	return &metricSet{families: make(map[string]*metricFamily)}
+}
+
+// gauge records a gauge sample. labels are name/value pairs.
+func (s *metricSet) gauge(name, help string, value float64, labels ...string) {
+	s.add("gauge", name, help, value, labels)
+}
+
+// counter records a counter sample. Names end in _total by convention.
+func (s *metricSet) counter(name, help string, value float64, labels ...string) {
+	s.add("counter", name, help, value, labels)
+}
+
+func (s *metricSet) add(typ, name, help string, value float64, labels []string) {
+	f, ok := s.families[name]
+	if !ok {
+		f = &metricFamily{name: name, help: help, typ: typ, samples: make(map[string]float64)}
+		s.families[name] = f
+	}
+	f.samples[renderLabels(labels)] = value
+}
+
+// merge copies other's samples into s.
+func (s *metricSet) merge(other *metricSet) {
+	for name, of := range other.families {
+		f, ok := s.families[name]
+		if !ok {
+			f = &metricFamily{name: of.name, help: of.help, typ: of.typ, samples: make(map[string]float64)}
+			s.families[name] = f
+		}
+		for labels, v := range of.samples {
+			f.samples[labels] = v
+		}
+	}
+}
+
+// WriteTo renders the set with families and samples in sorted order, so
+// output is stable between scrapes.
+func (s *metricSet) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := s.families[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.typ)
+		labelSets := make([]string, 0, len(f.samples))
+		for labels := range f.samples {
+			labelSets = append(labelSets, labels)
+		}
+		sort.Strings(labelSets)
+		for _, labels := range labelSets {
+			fmt.Fprintf(&b, "%s%s %s\n", f.name, labels, strconv.FormatFloat(f.samples[labels], 'g', -1, 64))
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// renderLabels formats name/value pairs as {a="x",b="y"}, or "" for none.
+func renderLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(v string) string { return labelValueEscaper.Replace(v) }
+func escapeHelp(v string) string       { return helpEscaper.Replace(v) }
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// unixSeconds returns t as Unix seconds, or 0 for the zero time.
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1e9
+}
@@ -0,0 +1,218 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func renderSet(t *testing.T, s *metricSet) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestMetricSet_WriteTo(t *testing.T) {
+	s := newMetricSet()
+	s.gauge("gastown_b", "Second family.", 2, "rig", "zeta")
+	s.gauge("gastown_b", "Second family.", 1.5, "rig", "alpha")
+	s.counter("gastown_a_total", "First\nfamily.", 3)
+	s.gauge("gastown_c", "Escaped labels.", 1, "identity", `gastown/"crew"\max`+"\n")
+
+	want := `# HELP gastown_a_total First\nfamily.
+# TYPE gastown_a_total counter
+gastown_a_total 3
+# HELP gastown_b Second family.
+# TYPE gastown_b gauge
+gastown_b{rig="alpha"} 1.5
+gastown_b{rig="zeta"} 2
+# HELP gastown_c Escaped labels.
+# TYPE gastown_c gauge
+gastown_c{identity="gastown/\"crew\"\\max\n"} 1
+`
+	if got := renderSet(t, s); got != want {
+		t.Errorf("WriteTo:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMetricsListenAddr(t *testing.T) {
+	if got := metricsListenAddr(nil); got != "" {
+		t.Errorf("nil config: %q, want disabled", got)
+	}
+	if got := metricsListenAddr(&DaemonPatrolConfig{Metrics: &MetricsConfig{}}); got != "" {
+		t.Errorf("metrics not enabled: %q, want disabled", got)
+	}
+	if got := metricsListenAddr(&DaemonPatrolConfig{Metrics: &MetricsConfig{Enabled: true}}); got != defaultMetricsListen {
+		t.Errorf("enabled without listen: %q, want %q", got, defaultMetricsListen)
+	}
+	if got := metricsListenAddr(&DaemonPatrolConfig{Metrics: &MetricsConfig{Enabled: true, Listen: ":9999"}}); got != ":9999" {
+		t.Errorf("explicit listen: %q", got)
+	}
+}
+
+func TestEventCounter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".events.jsonl")
+	appendEvents := func(lines ...string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		for _, l := range lines {
+			_, _ = f.WriteString(l)
+		}
+	}
+	appendEvents(`{"type":"sling"}` + "\n") // before the daemon started: not counted
+
+	c := newEventCounter(path)
+	appendEvents(`{"type":"sling"}`+"\n", `{"type":"merged"}`+"\n", "not json\n", `{"type":"sling"}`+"\n", `{"type":"mer`)
+
+	s := newMetricSet()
+	if err := c.collect(s); err != nil {
+		t.Fatal(err)
+	}
+	out := renderSet(t, s)
+	if !strings.Contains(out, `gastown_events_total{type="sling"} 2`) || !strings.Contains(out, `gastown_events_total{type="merged"} 1`) {
+		t.Errorf("after first scrape:\n%s", out)
+	}
+
+	// The torn line completes and is counted once.
+	appendEvents(`ged"}` + "\n")
+	s = newMetricSet()
+	_ = c.collect(s)
+	if out := renderSet(t, s); !strings.Contains(out, `gastown_events_total{type="merged"} 2`) {
+		t.Errorf("after completing the torn line:\n%s", out)
+	}
+
+	// Pruning rewrites the file smaller: nothing is recounted.
+	if err := os.WriteFile(path, []byte(`{"type":"sling"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s = newMetricSet()
+	_ = c.collect(s)
+	if out := renderSet(t, s); !strings.Contains(out, `gastown_events_total{type="sling"} 2`) {
+		t.Errorf("after rewrite:\n%s", out)
+	}
+}
+
+func TestMetricsExporter_CachesSlowCollectors(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var liveCalls, cachedCalls int
+	e := &metricsExporter{
+		live: []metricsCollector{{"live", func(s *metricSet) error {
+			liveCalls++
+			s.gauge("gastown_live", "Live.", float64(liveCalls))
+			return nil
+		}}},
+		cached: []metricsCollector{
+			{"slow", func(s *metricSet) error {
+				cachedCalls++
+				s.gauge("gastown_slow", "Slow.", float64(cachedCalls))
+				return nil
+			}},
+			{"broken", func(*metricSet) error { return fmt.Errorf("bd unavailable") }},
+		},
+		now: func() time.Time { return now },
+	}
+
+	out := renderSet(t, e.gather())
+	now = now.Add(metricsCacheTTL / 2)
+	out = renderSet(t, e.gather())
+	if liveCalls != 2 || cachedCalls != 1 {
+		t.Errorf("within TTL: live %d, cached %d calls; want 2 and 1", liveCalls, cachedCalls)
+	}
+	for _, want := range []string{
+		"gastown_live 2",
+		"gastown_slow 1",
+		`gastown_metrics_collector_success{collector="broken"} 0`,
+		`gastown_metrics_collector_success{collector="live"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	now = now.Add(metricsCacheTTL)
+	_ = e.gather()
+	if cachedCalls != 2 {
+		t.Errorf("after TTL: cached collectors ran %d times, want 2", cachedCalls)
+	}
+}
+
+func TestMetricsExporter_ServesHTTP(t *testing.T) {
+	d := newControlDaemon(t)
+	d.restartTracker = NewRestartTracker(d.config.TownRoot)
+	d.restartTracker.RecordRestart("deacon")
+	d.setRigPaused("gastown", true)
+	d.recordHeartbeat(&State{PID: 7, StartedAt: time.Now(), HeartbeatCount: 4}, false)
+
+	e := d.newMetricsExporter()
+	e.cached = nil // no bd in tests
+	if err := e.start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer e.stop()
+
+	resp, err := http.Get("http://" + e.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	for _, want := range []string{
+		"gastown_daemon_up 1",
+		"gastown_daemon_heartbeats_total 4",
+		`gastown_daemon_rig_paused{rig="gastown"} 1`,
+		`gastown_agent_restarts_total{agent="deacon"} 1`,
+		`gastown_agent_crash_loop{agent="deacon"} 0`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("missing %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestDoltHealth_RecordedByEnsureRunning(t *testing.T) {
+	m := newTestManager(t)
+	m.runningFn = func() (int, bool) { return 1234, true }
+	m.writeProbeCheckFn = func() error { return nil }
+
+	if h := m.Health(); !h.CheckedAt.IsZero() {
+		t.Errorf("health before any check = %+v", h)
+	}
+	if err := m.EnsureRunning(); err != nil {
+		t.Fatal(err)
+	}
+	if h := m.Health(); !h.Healthy || h.ReadOnly || h.CheckedAt.IsZero() {
+		t.Errorf("after healthy check: %+v", h)
+	}
+
+	m.writeProbeCheckFn = func() error { return fmt.Errorf("database is read only") }
+	m.readOnlyAlertFn = func(error) {}
+	_ = m.EnsureRunning()
+	if h := m.Health(); !h.ReadOnly {
+		t.Errorf("after read-only probe: %+v", h)
+	}
+
+	d := &Daemon{doltServer: m}
+	s := newMetricSet()
+	if err := d.collectDoltMetrics(s); err != nil {
+		t.Fatal(err)
+	}
+	if out := renderSet(t, s); !strings.Contains(out, "gastown_dolt_read_only 1") {
+		t.Errorf("dolt metrics:\n%s", out)
+	}
+}
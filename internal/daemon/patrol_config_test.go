@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -98,3 +99,48 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestDoltBackupsConfig(t *testing.T) {
+	// Opt-in like dolt_remotes
+	if IsPatrolEnabled(nil, "dolt_backups") || IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "dolt_backups") {
+		t.Error("expected dolt_backups to be disabled by default")
+	}
+	if doltBackupsInterval(nil) != defaultDoltBackupsInterval {
+		t.Errorf("default interval = %v", doltBackupsInterval(nil))
+	}
+	if p := doltBackupsRetention(nil); p.Keep != 24 || p.KeepDaily != 7 {
+		t.Errorf("default retention = %+v", p)
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{
+		DoltBackups: &DoltBackupsConfig{Enabled: true, Interval: 30 * time.Minute, KeepDaily: 14},
+	}}
+	if !IsPatrolEnabled(config, "dolt_backups") {
+		t.Error("expected dolt_backups to be enabled when configured")
+	}
+	if got := doltBackupsInterval(config); got != 30*time.Minute {
+		t.Errorf("interval = %v", got)
+	}
+	if p := doltBackupsRetention(config); p.Keep != 24 || p.KeepDaily != 14 {
+		t.Errorf("retention = %+v, want default keep and 14 dailies", p)
+	}
+}
+
+func TestAuditCheckpointsConfig(t *testing.T) {
+	if IsPatrolEnabled(nil, "audit_checkpoints") {
+		t.Error("expected audit_checkpoints to be disabled by default")
+	}
+	if auditCheckpointsInterval(nil) != defaultAuditCheckpointsInterval {
+		t.Errorf("default interval = %v", auditCheckpointsInterval(nil))
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{
+		AuditCheckpoints: &AuditCheckpointsConfig{Enabled: true, Interval: 10 * time.Minute},
+	}}
+	if !IsPatrolEnabled(config, "audit_checkpoints") {
+		t.Error("expected audit_checkpoints to be enabled when configured")
+	}
+	if got := auditCheckpointsInterval(config); got != 10*time.Minute {
+		t.Errorf("interval = %v", got)
+	}
+}
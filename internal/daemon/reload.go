@@ -0,0 +1,320 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/krc"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// configWatchInterval is how often the main loop checks the config files
+// for changes. Stat-only, so cheap enough to run well inside a heartbeat.
+const configWatchInterval = 10 * time.Second
+
+// Reload triggers, recorded in config reload events.
+const (
+	reloadTriggerControl = "control" // gt daemon reload
+	reloadTriggerSignal  = "signal"  // SIGHUP
+	reloadTriggerWatch   = "watch"   // a config file changed on disk
+)
+
+// reloadedConfig is a validated set of config files ready to apply.
+type reloadedConfig struct {
+	patrol *DaemonPatrolConfig
+	krc    *krc.Config
+}
+
+// patrolTimers are the main loop's timers. Only the main loop touches
+// them, so a reload can retune them without locking.
+type patrolTimers struct {
+	heartbeat                *time.Timer
+	heartbeatInterval        time.Duration
+	doltHealth               *time.Ticker
+	doltHealthInterval       time.Duration
+	doltRemotes              *time.Ticker
+	doltRemotesInterval      time.Duration
+	doltBackups              *time.Ticker
+	doltBackupsInterval      time.Duration
+	auditCheckpoints         *time.Ticker
+	auditCheckpointsInterval time.Duration
+	configWatch              *time.Ticker
+}
+
+// tickerC returns the ticker's channel, or nil (never ready) if t is nil.
+func tickerC(t *time.Ticker) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
+// stop stops all timers.
+func (t *patrolTimers) stop() {
+	if t.heartbeat != nil {
+		t.heartbeat.Stop()
+	}
+	for _, ticker := range []*time.Ticker{t.doltHealth, t.doltRemotes, t.doltBackups, t.auditCheckpoints, t.configWatch} {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}
+}
+
+// fileStamp identifies a version of a config file. A missing file has
+// the zero stamp, so creating or deleting a file counts as a change.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// configFiles lists the files a reload reads: the patrol config, KRC TTLs,
+// escalation routes, the rig registry and each known rig's settings.
+func (d *Daemon) configFiles() []string {
+	townRoot := d.config.TownRoot
+	files := []string{
+		PatrolConfigFile(townRoot),
+		krc.ConfigFile(townRoot),
+		config.EscalationConfigPath(townRoot),
+		filepath.Join(townRoot, "mayor", "rigs.json"),
+	}
+	for _, rigName := range d.getKnownRigs() {
+		files = append(files, config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+	}
+	return files
+}
+
+// statConfigFiles stamps every config file.
+func (d *Daemon) statConfigFiles() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, path := range d.configFiles() {
+		var s fileStamp
+		if info, err := os.Stat(path); err == nil {
+			s = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+		stamps[path] = s
+	}
+	return stamps
+}
+
+// changedConfigFiles returns the files whose stamps differ, relative to
+// the town root.
+func (d *Daemon) changedConfigFiles(old, cur map[string]fileStamp) []string {
+	var changed []string
+	for path, s := range cur {
+		if prev, ok := old[path]; !ok || prev != s {
+			changed = append(changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := cur[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	for i, path := range changed {
+		if rel, err := filepath.Rel(d.config.TownRoot, path); err == nil {
+			changed[i] = rel
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// checkConfigFiles reloads if any config file changed since the last
+// reload. Rejected changes are not retried until the files change again.
+func (d *Daemon) checkConfigFiles() {
+	cur := d.statConfigFiles()
+	if len(d.changedConfigFiles(d.configStamps, cur)) == 0 {
+		return
+	}
+	_, _ = d.reloadConfig(reloadTriggerWatch)
+}
+
+// loadReloadConfig reads and validates every config file the daemon
+// depends on. Any error rejects the whole reload.
+func (d *Daemon) loadReloadConfig() (*reloadedConfig, error) {
+	townRoot := d.config.TownRoot
+
+	patrol, err := ReadPatrolConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	krcConfig, err := krc.LoadConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := krcConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", krc.ConfigFile(townRoot), err)
+	}
+
+	// Escalation routes and rig settings are read by the agents and gt
+	// commands on each use; validating them here reports a bad edit
+	// before anything trips over it.
+	escalationPath := config.EscalationConfigPath(townRoot)
+	if _, err := config.LoadEscalationConfig(escalationPath); err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", escalationPath, err)
+	}
+	for _, rigName := range d.getKnownRigs() {
+		settingsPath := config.RigSettingsPath(filepath.Join(townRoot, rigName))
+		if _, err := config.LoadRigSettings(settingsPath); err != nil && !errors.Is(err, config.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", settingsPath, err)
+		}
+	}
+
+	return &reloadedConfig{patrol: patrol, krc: krcConfig}, nil
+}
+
+// reloadConfig validates the config files and applies them to the running
+// daemon: patrol toggles and intervals, the convoy scan interval, KRC TTLs,
+// Dolt restart policy and the session prefix registry. An invalid config is
+// rejected with a config_rejected event and the current config stays in
+// effect. Settings that need a restart are reported in the returned list.
+// Must be called from the main loop.
+func (d *Daemon) reloadConfig(trigger string) ([]string, error) {
+	stamps := d.statConfigFiles()
+	changed := d.changedConfigFiles(d.configStamps, stamps)
+	d.configStamps = stamps
+
+	loaded, err := d.loadReloadConfig()
+	if err != nil {
+		d.logger.Printf("Config reload (%s) rejected, keeping current config: %v", trigger, err)
+		d.recordReload(err)
+		_ = events.LogFeed(events.TypeConfigRejected, "daemon",
+			events.ConfigReloadPayload(trigger, changed, err.Error()))
+		return nil, err
+	}
+
+	pending := d.applyConfig(loaded)
+	if err := session.InitRegistry(d.config.TownRoot); err != nil {
+		d.logger.Printf("Warning: reloading session registry: %v", err)
+	}
+	d.retuneTimers()
+	d.recordReload(nil)
+
+	detail := ""
+	if len(pending) > 0 {
+		detail = "restart the daemon to apply: " + strings.Join(pending, ", ")
+		d.logger.Printf("Config reload (%s): %s", trigger, detail)
+	}
+	d.logger.Printf("Config reloaded (%s), changed: %v", trigger, changed)
+	_ = events.LogAudit(events.TypeConfigReloaded, "daemon",
+		events.ConfigReloadPayload(trigger, changed, detail))
+	return pending, nil
+}
+
+// applyConfig swaps in a validated config and pushes it to the convoy
+// manager, KRC pruner and Dolt server manager. Returns the settings that
+// only take effect after a daemon restart.
+func (d *Daemon) applyConfig(loaded *reloadedConfig) []string {
+	old := d.patrolConfig
+	d.patrolConfig = loaded.patrol
+
+	var pending []string
+
+	if d.convoyManager != nil {
+		d.convoyManager.SetScanInterval(convoyScanInterval(loaded.patrol))
+	}
+
+	if d.krcPruner != nil {
+		d.krcPruner.UpdateConfig(loaded.krc)
+	} else {
+		// The previous config was invalid, so the pruner never started.
+		d.startKRCPruner()
+	}
+
+	newDolt := doltServerConfig(loaded.patrol)
+	switch {
+	case d.doltServer != nil && newDolt != nil:
+		pending = append(pending, d.doltServer.UpdateConfig(newDolt)...)
+	case d.doltServer != nil || (newDolt != nil && newDolt.Enabled):
+		pending = append(pending, "patrols.dolt_server")
+	}
+
+	if metricsListenAddr(old) != metricsListenAddr(loaded.patrol) {
+		pending = append(pending, "metrics")
+	}
+	return pending
+}
+
+// doltServerConfig returns the patrols.dolt_server section, or nil.
+func doltServerConfig(config *DaemonPatrolConfig) *DoltServerConfig {
+	if config == nil || config.Patrols == nil {
+		return nil
+	}
+	return config.Patrols.DoltServer
+}
+
+// retuneTimers resets the main loop's timers whose intervals changed and
+// starts or stops the tickers of the opt-in patrols.
+func (d *Daemon) retuneTimers() {
+	t := &d.timers
+
+	if t.heartbeat != nil {
+		if interval := heartbeatInterval(d.patrolConfig); interval != t.heartbeatInterval {
+			t.heartbeatInterval = interval
+			t.heartbeat.Reset(interval)
+			d.goroutines.add("heartbeat", fmt.Sprintf("every %v", interval))
+			d.controlMu.Lock()
+			d.status.NextHeartbeat = time.Now().Add(interval)
+			d.controlMu.Unlock()
+			d.logger.Printf("Recovery heartbeat interval now %v", interval)
+		}
+	}
+
+	if t.doltHealth != nil && d.doltServer != nil {
+		if interval := d.doltServer.HealthCheckInterval(); interval != t.doltHealthInterval {
+			t.doltHealthInterval = interval
+			t.doltHealth.Reset(interval)
+			d.goroutines.add("dolt health check", fmt.Sprintf("every %v", interval))
+			d.logger.Printf("Dolt health check interval now %v", interval)
+		}
+	}
+
+	d.retuneOptInTicker(&t.doltRemotes, &t.doltRemotesInterval, "dolt remotes push",
+		IsPatrolEnabled(d.patrolConfig, "dolt_remotes"), doltRemotesInterval(d.patrolConfig))
+	d.retuneOptInTicker(&t.doltBackups, &t.doltBackupsInterval, "dolt backups",
+		IsPatrolEnabled(d.patrolConfig, "dolt_backups"), doltBackupsInterval(d.patrolConfig))
+	d.retuneOptInTicker(&t.auditCheckpoints, &t.auditCheckpointsInterval, "audit checkpoints",
+		IsPatrolEnabled(d.patrolConfig, "audit_checkpoints"), auditCheckpointsInterval(d.patrolConfig))
+}
+
+// retuneOptInTicker starts, stops or resets the ticker of an opt-in patrol
+// to match its config.
+func (d *Daemon) retuneOptInTicker(ticker **time.Ticker, current *time.Duration, name string, enabled bool, interval time.Duration) {
+	switch {
+	case enabled && *ticker == nil:
+		*ticker = time.NewTicker(interval)
+		*current = interval
+		d.goroutines.add(name, fmt.Sprintf("every %v", interval))
+		d.logger.Printf("%s ticker started (interval %v)", name, interval)
+	case !enabled && *ticker != nil:
+		(*ticker).Stop()
+		*ticker = nil
+		d.goroutines.remove(name)
+		d.logger.Printf("%s ticker stopped", name)
+	case enabled && interval != *current:
+		(*ticker).Reset(interval)
+		*current = interval
+		d.goroutines.add(name, fmt.Sprintf("every %v", interval))
+		d.logger.Printf("%s interval now %v", name, interval)
+	}
+}
+
+// recordReload records the outcome of a reload in the live status.
+func (d *Daemon) recordReload(err error) {
+	d.controlMu.Lock()
+	defer d.controlMu.Unlock()
+	d.status.LastReload = time.Now()
+	d.status.ReloadError = ""
+	if err != nil {
+		d.status.ReloadError = err.Error()
+	}
+}
@@ -0,0 +1,196 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTownFile(t *testing.T, townRoot, rel, content string) {
+	t.Helper()
+	path := filepath.Join(townRoot, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadPatrolConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	if cfg, err := ReadPatrolConfig(townRoot); cfg != nil || err != nil {
+		t.Errorf("missing file = %v, %v; want nil, nil", cfg, err)
+	}
+
+	writeTownFile(t, townRoot, "mayor/daemon.json",
+		`{"heartbeat":{"interval":"5m"},"patrols":{"convoy":{"interval":"1m"}}}`)
+	cfg, err := ReadPatrolConfig(townRoot)
+	if err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+	if heartbeatInterval(cfg) != 5*time.Minute || convoyScanInterval(cfg) != time.Minute {
+		t.Errorf("intervals = %v, %v", heartbeatInterval(cfg), convoyScanInterval(cfg))
+	}
+
+	for name, content := range map[string]string{
+		"not json":             `{"patrols":`,
+		"bad duration":         `{"heartbeat":{"interval":"soon"}}`,
+		"heartbeat too fast":   `{"heartbeat":{"interval":"1s"}}`,
+		"convoy too fast":      `{"patrols":{"convoy":{"interval":"1ms"}}}`,
+		"port out of range":    `{"patrols":{"dolt_server":{"port":70000}}}`,
+		"negative delay":       `{"patrols":{"dolt_server":{"restart_delay":-1}}}`,
+		"negative remotes":     `{"patrols":{"dolt_remotes":{"interval":-1}}}`,
+		"negative backup keep": `{"patrols":{"dolt_backups":{"keep":-1}}}`,
+		"negative audit":       `{"patrols":{"audit_checkpoints":{"interval":-1}}}`,
+		"bad metrics address":  `{"metrics":{"enabled":true,"listen":"9464"}}`,
+	} {
+		writeTownFile(t, townRoot, "mayor/daemon.json", content)
+		if _, err := ReadPatrolConfig(townRoot); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestReloadConfig_AppliesLive(t *testing.T) {
+	d := newControlDaemon(t)
+	townRoot := d.config.TownRoot
+
+	d.timers.heartbeatInterval = recoveryHeartbeatInterval
+	d.timers.heartbeat = time.NewTimer(time.Hour)
+	d.doltServer = NewDoltServerManager(townRoot, &DoltServerConfig{Enabled: true, Port: 3306, HealthCheckInterval: 30 * time.Second}, d.logger.Printf)
+	d.timers.doltHealthInterval = 30 * time.Second
+	d.timers.doltHealth = time.NewTicker(time.Hour)
+	defer d.timers.stop()
+	d.convoyManager = NewConvoyManager(townRoot, d.logger.Printf, "", 0, nil, nil, nil)
+	pruner, err := NewKRCPruner(townRoot, d.logger.Printf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.krcPruner = pruner
+
+	writeTownFile(t, townRoot, "mayor/daemon.json", `{
+		"heartbeat": {"interval": "5m"},
+		"patrols": {
+			"convoy": {"interval": "1m"},
+			"dolt_server": {"enabled": true, "port": 3307, "health_check_interval": 10000000000, "max_restarts_in_window": 9},
+			"dolt_remotes": {"enabled": true}
+		}
+	}`)
+	writeTownFile(t, townRoot, ".krc.yaml", `{"prune_interval": 7200000000000}`)
+
+	pending, err := d.reloadConfig(reloadTriggerControl)
+	if err != nil {
+		t.Fatalf("reloadConfig: %v", err)
+	}
+	if len(pending) != 1 || pending[0] != "dolt_server.port" {
+		t.Errorf("pending = %v, want the port change only", pending)
+	}
+
+	if d.timers.heartbeatInterval != 5*time.Minute {
+		t.Errorf("heartbeat interval = %v", d.timers.heartbeatInterval)
+	}
+	if d.timers.doltHealthInterval != 10*time.Second || d.doltServer.HealthCheckInterval() != 10*time.Second {
+		t.Errorf("dolt health interval = %v", d.timers.doltHealthInterval)
+	}
+	if d.doltServer.config.MaxRestartsInWindow != 9 || d.doltServer.config.Port != 3306 {
+		t.Errorf("dolt config = %+v; want new restart policy, old port", d.doltServer.config)
+	}
+	if d.timers.doltRemotes == nil || d.timers.doltRemotesInterval != defaultDoltRemotesInterval {
+		t.Error("enabling dolt_remotes should start its ticker")
+	}
+	select {
+	case interval := <-d.convoyManager.intervalCh:
+		if interval != time.Minute {
+			t.Errorf("convoy scan interval = %v", interval)
+		}
+	default:
+		t.Error("convoy manager did not receive the new scan interval")
+	}
+	if got := d.krcPruner.currentConfig().PruneInterval; got != 2*time.Hour {
+		t.Errorf("KRC prune interval = %v", got)
+	}
+	if s := d.statusSnapshot(); s.LastReload.IsZero() || s.ReloadError != "" {
+		t.Errorf("status after reload = %+v", s)
+	}
+
+	// Disabling dolt_remotes stops the ticker again.
+	writeTownFile(t, townRoot, "mayor/daemon.json", `{"patrols":{"dolt_server":{"enabled":true,"port":3306}}}`)
+	if _, err := d.reloadConfig(reloadTriggerSignal); err != nil {
+		t.Fatal(err)
+	}
+	if d.timers.doltRemotes != nil {
+		t.Error("disabling dolt_remotes should stop its ticker")
+	}
+	if d.timers.heartbeatInterval != recoveryHeartbeatInterval {
+		t.Errorf("heartbeat interval after removing it = %v", d.timers.heartbeatInterval)
+	}
+}
+
+func TestReloadConfig_RejectsInvalid(t *testing.T) {
+	d := newControlDaemon(t)
+	townRoot := d.config.TownRoot
+	writeTownFile(t, townRoot, "mayor/daemon.json", `{"patrols":{"witness":{"enabled":false}}}`)
+	if _, err := d.reloadConfig(reloadTriggerControl); err != nil {
+		t.Fatal(err)
+	}
+	good := d.patrolConfig
+
+	for name, write := range map[string]func(){
+		"daemon.json": func() { writeTownFile(t, townRoot, "mayor/daemon.json", `{"patrols":{"witness":`) },
+		"krc":         func() { writeTownFile(t, townRoot, ".krc.yaml", `{"prune_interval":0}`) },
+		"escalation":  func() { writeTownFile(t, townRoot, "settings/escalation.json", `{"routes":{"urgent":[]}}`) },
+		"rig settings": func() {
+			writeTownFile(t, townRoot, "gastown/settings/config.json", `{"type":"not-rig-settings"}`)
+		},
+	} {
+		write()
+		_, err := d.reloadConfig(reloadTriggerWatch)
+		if err == nil {
+			t.Errorf("%s: invalid config was accepted", name)
+		}
+		if d.patrolConfig != good {
+			t.Errorf("%s: rejected reload replaced the running config", name)
+		}
+		if s := d.statusSnapshot(); s.ReloadError == "" {
+			t.Errorf("%s: status should carry the rejection", name)
+		}
+		if resp := d.runControlCall(ControlRequest{Method: ControlReload}); resp.OK || !strings.Contains(resp.Error, "rejected") {
+			t.Errorf("%s: control reload = %+v", name, resp)
+		}
+		for _, f := range []string{"mayor/daemon.json", ".krc.yaml", "settings/escalation.json", "gastown/settings/config.json"} {
+			_ = os.Remove(filepath.Join(townRoot, f))
+		}
+	}
+}
+
+func TestCheckConfigFiles_ReloadsOnChange(t *testing.T) {
+	d := newControlDaemon(t)
+	townRoot := d.config.TownRoot
+	d.configStamps = d.statConfigFiles()
+
+	d.checkConfigFiles()
+	if !d.statusSnapshot().LastReload.IsZero() {
+		t.Fatal("reloaded without a change")
+	}
+
+	writeTownFile(t, townRoot, "mayor/daemon.json", `{"patrols":{"deacon":{"enabled":false}}}`)
+	d.checkConfigFiles()
+	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+		t.Error("watcher did not apply the new daemon.json")
+	}
+
+	// A rejected edit is reported once, not on every tick.
+	writeTownFile(t, townRoot, ".krc.yaml", `{"default_ttl":-1}`)
+	d.checkConfigFiles()
+	first := d.statusSnapshot()
+	if first.ReloadError == "" {
+		t.Fatal("invalid .krc.yaml was accepted")
+	}
+	d.checkConfigFiles()
+	if again := d.statusSnapshot(); !again.LastReload.Equal(first.LastReload) {
+		t.Error("unchanged rejected config was reloaded again")
+	}
+}
@@ -27,6 +27,10 @@ type AgentRestartInfo struct {
 	RestartCount   int       `json:"restart_count"`
 	BackoffUntil   time.Time `json:"backoff_until"`
 	CrashLoopSince time.Time `json:"crash_loop_since,omitempty"`
+
+	// TotalRestarts counts every restart ever recorded. Unlike RestartCount
+	// it is never reset, so it can be exported as a counter.
+	TotalRestarts int `json:"total_restarts,omitempty"`
 }
 
 // Backoff parameters
@@ -121,6 +125,7 @@ func (rt *RestartTracker) RecordRestart(agentID string) {
 
 	info.LastRestart = now
 	info.RestartCount++
+	info.TotalRestarts++
 
 	// Calculate backoff with exponential increase
 	backoffDuration := initialBackoff
@@ -201,3 +206,15 @@ func (rt *RestartTracker) ClearCrashLoop(agentID string) {
 		info.BackoffUntil = time.Time{}
 	}
 }
+
+// Snapshot returns a copy of the restart info for every tracked agent.
+func (rt *RestartTracker) Snapshot() map[string]AgentRestartInfo {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	out := make(map[string]AgentRestartInfo, len(rt.state.Agents))
+	for id, info := range rt.state.Agents {
+		out[id] = *info
+	}
+	return out
+}
@@ -12,9 +12,14 @@ func daemonSignals() []os.Signal {
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGUSR1,
+		syscall.SIGHUP,
 	}
 }
 
 func isLifecycleSignal(sig os.Signal) bool {
 	return sig == syscall.SIGUSR1
 }
+
+func isReloadSignal(sig os.Signal) bool {
+	return sig == syscall.SIGHUP
+}
@@ -10,6 +10,8 @@ package daemon
 
 import (
 	"encoding/json"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"time"
@@ -101,7 +103,8 @@ type PatrolConfig struct {
 	// Enabled controls whether this patrol runs during heartbeat.
 	Enabled bool `json:"enabled"`
 
-	// Interval is how often to run this patrol (not used yet).
+	// Interval is how often to run this patrol, as a Go duration ("5m").
+	// Only the heartbeat and the convoy stranded scan honor it so far.
 	Interval string `json:"interval,omitempty"`
 
 	// Agent is the agent type for this patrol (not used yet).
@@ -116,8 +119,12 @@ type PatrolsConfig struct {
 	Refinery    *PatrolConfig      `json:"refinery,omitempty"`
 	Witness     *PatrolConfig      `json:"witness,omitempty"`
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
+	Convoy      *PatrolConfig      `json:"convoy,omitempty"` // interval of the stranded convoy scan
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	DoltBackups *DoltBackupsConfig `json:"dolt_backups,omitempty"`
+
+	AuditCheckpoints *AuditCheckpointsConfig `json:"audit_checkpoints,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -140,12 +147,55 @@ type DoltRemotesConfig struct {
 	Branch string `json:"branch,omitempty"`
 }
 
+// DoltBackupsConfig holds configuration for the dolt_backups patrol.
+// This patrol periodically snapshots every served database with Dolt's
+// native backups (see doltserver.CreateSnapshot) and prunes old snapshots.
+type DoltBackupsConfig struct {
+	// Enabled controls whether snapshots are taken.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to snapshot (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Keep is the number of most recent snapshots kept per database (default 24).
+	Keep int `json:"keep,omitempty"`
+
+	// KeepDaily keeps one snapshot per day for this many days (default 7).
+	KeepDaily int `json:"keep_daily,omitempty"`
+
+	// SkipVerify disables the verification restore of each new snapshot
+	// into a scratch directory.
+	SkipVerify bool `json:"skip_verify,omitempty"`
+}
+
+// AuditCheckpointsConfig holds configuration for the audit_checkpoints patrol.
+// This patrol periodically anchors the head of the hash-chained audit log
+// in the town git repo (see auditlog.WriteCheckpoint).
+type AuditCheckpointsConfig struct {
+	// Enabled controls whether checkpoints are written.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to checkpoint (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// MetricsConfig holds configuration for the daemon's metrics endpoint.
+// The endpoint serves Prometheus/OpenMetrics text at /metrics.
+type MetricsConfig struct {
+	// Enabled controls whether the endpoint is served (default false).
+	Enabled bool `json:"enabled"`
+
+	// Listen is the address to listen on (default "127.0.0.1:9464").
+	Listen string `json:"listen,omitempty"`
+}
+
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
 	Type      string         `json:"type"`
 	Version   int            `json:"version"`
 	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
 	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+	Metrics   *MetricsConfig `json:"metrics,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -169,9 +219,145 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 	return &config
 }
 
+// Lower bounds for configured intervals. Anything faster would have the
+// daemon spend its time patrolling rather than waiting for work.
+const (
+	minHeartbeatInterval  = 30 * time.Second
+	minConvoyScanInterval = 5 * time.Second
+	minDoltHealthInterval = time.Second
+)
+
+// ReadPatrolConfig loads and validates mayor/daemon.json. Unlike
+// LoadPatrolConfig it reports parse and validation errors, so a bad edit
+// can be rejected while the daemon keeps its current config. Returns
+// nil, nil if the file doesn't exist.
+func ReadPatrolConfig(townRoot string) (*DaemonPatrolConfig, error) {
+	configFile := PatrolConfigFile(townRoot)
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", configFile, err)
+	}
+
+	var config DaemonPatrolConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", configFile, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+	return &config, nil
+}
+
+// Validate checks intervals, ports and addresses in the config.
+func (c *DaemonPatrolConfig) Validate() error {
+	if c.Heartbeat != nil {
+		if err := validatePatrolInterval("heartbeat", c.Heartbeat.Interval, minHeartbeatInterval); err != nil {
+			return err
+		}
+	}
+	if p := c.Patrols; p != nil {
+		for name, patrol := range map[string]*PatrolConfig{
+			"refinery": p.Refinery,
+			"witness":  p.Witness,
+			"deacon":   p.Deacon,
+			"convoy":   p.Convoy,
+		} {
+			if patrol == nil {
+				continue
+			}
+			if err := validatePatrolInterval("patrols."+name, patrol.Interval, minConvoyScanInterval); err != nil {
+				return err
+			}
+		}
+		if ds := p.DoltServer; ds != nil {
+			if ds.Port < 0 || ds.Port > 65535 {
+				return fmt.Errorf("patrols.dolt_server.port %d out of range", ds.Port)
+			}
+			if ds.MaxRestartsInWindow < 0 {
+				return fmt.Errorf("patrols.dolt_server.max_restarts_in_window must be non-negative")
+			}
+			for name, d := range map[string]time.Duration{
+				"restart_delay":          ds.RestartDelay,
+				"max_restart_delay":      ds.MaxRestartDelay,
+				"restart_window":         ds.RestartWindow,
+				"healthy_reset_interval": ds.HealthyResetInterval,
+				"health_check_interval":  ds.HealthCheckInterval,
+			} {
+				if d < 0 {
+					return fmt.Errorf("patrols.dolt_server.%s must be non-negative, got %v", name, d)
+				}
+			}
+			if ds.HealthCheckInterval > 0 && ds.HealthCheckInterval < minDoltHealthInterval {
+				return fmt.Errorf("patrols.dolt_server.health_check_interval %v is below the minimum %v", ds.HealthCheckInterval, minDoltHealthInterval)
+			}
+		}
+		if dr := p.DoltRemotes; dr != nil && dr.Interval < 0 {
+			return fmt.Errorf("patrols.dolt_remotes.interval must be non-negative, got %v", dr.Interval)
+		}
+		if db := p.DoltBackups; db != nil {
+			if db.Interval < 0 {
+				return fmt.Errorf("patrols.dolt_backups.interval must be non-negative, got %v", db.Interval)
+			}
+			if db.Keep < 0 || db.KeepDaily < 0 {
+				return fmt.Errorf("patrols.dolt_backups.keep and keep_daily must be non-negative")
+			}
+		}
+		if ac := p.AuditCheckpoints; ac != nil && ac.Interval < 0 {
+			return fmt.Errorf("patrols.audit_checkpoints.interval must be non-negative, got %v", ac.Interval)
+		}
+	}
+	if m := c.Metrics; m != nil && m.Listen != "" {
+		if _, _, err := net.SplitHostPort(m.Listen); err != nil {
+			return fmt.Errorf("metrics.listen: %w", err)
+		}
+	}
+	return nil
+}
+
+// validatePatrolInterval checks an optional PatrolConfig.Interval.
+func validatePatrolInterval(name, interval string, min time.Duration) error {
+	if interval == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return fmt.Errorf("%s.interval: %w", name, err)
+	}
+	if d < min {
+		return fmt.Errorf("%s.interval %v is below the minimum %v", name, d, min)
+	}
+	return nil
+}
+
+// heartbeatInterval returns the recovery heartbeat interval from
+// heartbeat.interval, or recoveryHeartbeatInterval if unset or invalid.
+func heartbeatInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Heartbeat != nil {
+		if d, err := time.ParseDuration(config.Heartbeat.Interval); err == nil && d >= minHeartbeatInterval {
+			return d
+		}
+	}
+	return recoveryHeartbeatInterval
+}
+
+// convoyScanInterval returns the stranded convoy scan interval from
+// patrols.convoy.interval, or 0 (the manager's default) if unset or invalid.
+func convoyScanInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Convoy != nil {
+		if d, err := time.ParseDuration(config.Patrols.Convoy.Interval); err == nil && d >= minConvoyScanInterval {
+			return d
+		}
+	}
+	return 0
+}
+
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, dolt_backups, audit_checkpoints)
+// default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -182,6 +368,18 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "dolt_backups" {
+		if config == nil || config.Patrols == nil || config.Patrols.DoltBackups == nil {
+			return false
+		}
+		return config.Patrols.DoltBackups.Enabled
+	}
+	if patrol == "audit_checkpoints" {
+		if config == nil || config.Patrols == nil || config.Patrols.AuditCheckpoints == nil {
+			return false
+		}
+		return config.Patrols.AuditCheckpoints.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
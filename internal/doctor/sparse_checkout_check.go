@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 )
 
@@ -119,11 +120,50 @@ func (c *SparseCheckoutCheck) discoverRigPaths(townRoot string) []string {
 }
 
 // checkRig checks all worktree repos within a single rig for legacy sparse checkout.
+// Rigs that declare sparse checkout in settings (checkout.sparse) have intentional
+// sparse polecat and crew workspaces; only mayor/rig and refinery/rig are checked.
 func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 	repoPaths := []string{
 		filepath.Join(rigPath, "mayor", "rig"),
 		filepath.Join(rigPath, "refinery", "rig"),
 	}
+	if !rigDeclaresSparse(rigPath) {
+		repoPaths = append(repoPaths, agentWorkspacePaths(rigPath)...)
+	}
+
+	for _, repoPath := range repoPaths {
+		// Skip if not a git repo
+		if _, err := os.Stat(filepath.Join(repoPath, ".git")); os.IsNotExist(err) {
+			continue
+		}
+
+		// Check if sparse checkout is configured (legacy configuration to remove)
+		if git.IsSparseCheckoutConfigured(repoPath) {
+			c.affectedRepos = append(c.affectedRepos, repoPath)
+		}
+	}
+}
+
+// rigDeclaresSparse reports whether the rig's settings configure sparse checkout.
+func rigDeclaresSparse(rigPath string) bool {
+	cfg := config.LoadRigCheckout(rigPath)
+	if cfg == nil {
+		return false
+	}
+	if len(cfg.Sparse) > 0 {
+		return true
+	}
+	for _, o := range cfg.Overrides {
+		if len(o.Sparse) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// agentWorkspacePaths returns the crew clones and polecat worktrees of a rig.
+func agentWorkspacePaths(rigPath string) []string {
+	var repoPaths []string
 
 	// Add crew clones
 	crewDir := filepath.Join(rigPath, "crew")
@@ -153,18 +193,7 @@ func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 			}
 		}
 	}
-
-	for _, repoPath := range repoPaths {
-		// Skip if not a git repo
-		if _, err := os.Stat(filepath.Join(repoPath, ".git")); os.IsNotExist(err) {
-			continue
-		}
-
-		// Check if sparse checkout is configured (legacy configuration to remove)
-		if git.IsSparseCheckoutConfigured(repoPath) {
-			c.affectedRepos = append(c.affectedRepos, repoPath)
-		}
-	}
+	return repoPaths
 }
 
 // Fix removes sparse checkout configuration from affected repos.
@@ -186,8 +186,14 @@ func (m *Manager) createRigWorktree(dogPath, dogName, rigName string) (string, e
 	// Unique branch per dog-rig combination
 	branchName := fmt.Sprintf("dog/%s-%s-%d", dogName, rigName, time.Now().UnixMilli())
 
+	// Honor the rig's sparse checkout / partial clone settings
+	spec := config.LoadRigCheckout(rigPath).Resolve("", nil)
+	if err := repoGit.EnablePartialClone("origin", spec.Filter); err != nil {
+		return "", fmt.Errorf("enabling partial clone for %s: %w", rigName, err)
+	}
+
 	// Create worktree with new branch from default branch
-	if err := repoGit.WorktreeAddFromRef(worktreePath, branchName, startPoint); err != nil {
+	if err := repoGit.WorktreeAddSparse(worktreePath, branchName, startPoint, spec.Sparse); err != nil {
 		return "", fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
 
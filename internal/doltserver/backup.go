@@ -0,0 +1,533 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Scheduled snapshots are Dolt-native backups (CALL DOLT_BACKUP('sync-url'))
+// of each served database, written under <townRoot>/.dolt-backups:
+//
+//	.dolt-backups/
+//	└── <database>/
+//	    ├── 20260501-140000/        → dolt backup (file:// remote)
+//	    └── 20260501-140000.json    → Snapshot metadata
+//
+// Unlike the migration-backup-* directories handled by FindBackups, these
+// capture the whole Dolt database (all branches and working sets), so a
+// snapshot can be restored with `dolt backup restore` even when the live
+// database's history is unusable.
+
+// snapshotTimeFormat is the directory name format of a snapshot.
+const snapshotTimeFormat = "20060102-150405"
+
+// backupTimeout bounds one backup sync or restore. Large databases with
+// long histories take a while to copy.
+const backupTimeout = 10 * time.Minute
+
+// validDatabaseNameRe matches database names safe to interpolate into SQL
+// and use as directory names.
+var validDatabaseNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Snapshot describes one scheduled backup of a database.
+type Snapshot struct {
+	// Database is the Dolt database that was backed up.
+	Database string `json:"database"`
+
+	// Path is the backup directory (a file:// Dolt remote).
+	Path string `json:"path"`
+
+	// CreatedAt is when the backup was taken.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Head is the database's HEAD commit when the backup was taken.
+	Head string `json:"head"`
+
+	// Verified is true once the backup was restored into a scratch
+	// directory and its HEAD matched Head.
+	Verified    bool      `json:"verified"`
+	VerifiedAt  time.Time `json:"verified_at,omitempty"`
+	VerifyError string    `json:"verify_error,omitempty"`
+}
+
+// metadataPath returns the path of the snapshot's metadata file.
+func (s *Snapshot) metadataPath() string {
+	return s.Path + ".json"
+}
+
+// save writes the snapshot's metadata file.
+func (s *Snapshot) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding snapshot metadata: %w", err)
+	}
+	if err := os.WriteFile(s.metadataPath(), data, 0644); err != nil { //nolint:gosec // G306: no secrets
+		return fmt.Errorf("writing snapshot metadata: %w", err)
+	}
+	return nil
+}
+
+// SnapshotsDir returns the directory holding scheduled Dolt snapshots.
+func SnapshotsDir(townRoot string) string {
+	return filepath.Join(townRoot, ".dolt-backups")
+}
+
+// ListSnapshots returns the snapshots of a database, or of all databases
+// if database is empty, sorted newest-first. Snapshots whose metadata is
+// missing or unreadable (e.g. a backup interrupted mid-sync) are skipped.
+func ListSnapshots(townRoot, database string) ([]Snapshot, error) {
+	root := SnapshotsDir(townRoot)
+	var databases []string
+	if database != "" {
+		databases = []string{database}
+	} else {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("reading snapshots: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				databases = append(databases, e.Name())
+			}
+		}
+	}
+
+	var snapshots []Snapshot
+	for _, db := range databases {
+		matches, err := filepath.Glob(filepath.Join(root, db, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town's snapshot dir
+			if err != nil {
+				continue
+			}
+			var s Snapshot
+			if err := json.Unmarshal(data, &s); err != nil {
+				continue
+			}
+			s.Path = strings.TrimSuffix(path, ".json") // still valid if the town moved
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// CreateSnapshot takes a Dolt-native backup of a database through the
+// running server and records its HEAD commit.
+func CreateSnapshot(townRoot, database string) (*Snapshot, error) {
+	if !validDatabaseNameRe.MatchString(database) {
+		return nil, fmt.Errorf("invalid database name %q", database)
+	}
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — snapshots require local server access", config.HostPort())
+	}
+
+	head, err := queryHead(townRoot, database)
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD of %s: %w", database, err)
+	}
+
+	now := time.Now()
+	s := &Snapshot{
+		Database:  database,
+		Path:      filepath.Join(SnapshotsDir(townRoot), database, now.UTC().Format(snapshotTimeFormat)),
+		CreatedAt: now,
+		Head:      head,
+	}
+	if err := os.MkdirAll(s.Path, 0755); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	query := fmt.Sprintf("USE `%s`; CALL DOLT_BACKUP('sync-url', '%s')", database, fileURL(s.Path))
+	if _, err := runBackupCmd(config, "-q", query); err != nil {
+		_ = os.RemoveAll(s.Path)
+		return nil, fmt.Errorf("backing up %s: %w", database, err)
+	}
+
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// VerifySnapshot restores a snapshot into a scratch directory and checks
+// that the restored HEAD matches the one recorded when it was taken. The
+// result is saved in the snapshot's metadata.
+func VerifySnapshot(s *Snapshot) error {
+	verifyErr := verifySnapshot(s)
+	s.Verified = verifyErr == nil
+	s.VerifiedAt = time.Now()
+	s.VerifyError = ""
+	if verifyErr != nil {
+		s.VerifyError = verifyErr.Error()
+	}
+	if err := s.save(); err != nil {
+		return err
+	}
+	return verifyErr
+}
+
+func verifySnapshot(s *Snapshot) error {
+	scratch, err := os.MkdirTemp("", "gt-dolt-verify-*")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := restoreBackup(scratch, s); err != nil {
+		return err
+	}
+
+	// The scratch copy has no server; dolt sql runs embedded.
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "csv", "-q", "SELECT HASHOF('HEAD') AS head")
+	cmd.Dir = filepath.Join(scratch, s.Database)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("reading restored HEAD: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	head := parseSingleValue(string(output))
+	if head != s.Head {
+		return fmt.Errorf("restored HEAD %s does not match snapshot HEAD %s", head, s.Head)
+	}
+	return nil
+}
+
+// restoreBackup restores a snapshot as <dir>/<database> with the dolt CLI.
+func restoreBackup(dir string, s *Snapshot) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", "backup", "restore", fileURL(s.Path), s.Database)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("dolt backup restore: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RetentionPolicy decides which snapshots PruneSnapshots keeps.
+type RetentionPolicy struct {
+	// Keep is the number of most recent snapshots kept per database.
+	Keep int
+
+	// KeepDaily keeps the newest snapshot of each of the last KeepDaily
+	// days that have one, per database.
+	KeepDaily int
+}
+
+// DefaultRetentionPolicy keeps a day of hourly snapshots and a week of dailies.
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{Keep: 24, KeepDaily: 7}
+}
+
+// ExpiredSnapshots returns the snapshots the policy does not keep. The
+// newest verified snapshot of each database is always kept, so pruning
+// never leaves a database with only unverified backups.
+func ExpiredSnapshots(snapshots []Snapshot, policy RetentionPolicy) []Snapshot {
+	byDB := make(map[string][]Snapshot)
+	for _, s := range snapshots {
+		byDB[s.Database] = append(byDB[s.Database], s)
+	}
+
+	var expired []Snapshot
+	for _, list := range byDB {
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+
+		keep := make(map[int]bool)
+		for i := 0; i < len(list) && i < policy.Keep; i++ {
+			keep[i] = true
+		}
+		days := make(map[string]bool)
+		for i, s := range list {
+			day := s.CreatedAt.Format("2006-01-02")
+			if days[day] {
+				continue
+			}
+			if len(days) >= policy.KeepDaily {
+				break
+			}
+			days[day] = true
+			keep[i] = true
+		}
+		for i, s := range list {
+			if s.Verified {
+				keep[i] = true
+				break
+			}
+		}
+
+		for i, s := range list {
+			if !keep[i] {
+				expired = append(expired, s)
+			}
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].CreatedAt.Before(expired[j].CreatedAt) })
+	return expired
+}
+
+// PruneSnapshots deletes the snapshots the policy does not keep and
+// returns them.
+func PruneSnapshots(townRoot string, policy RetentionPolicy) ([]Snapshot, error) {
+	snapshots, err := ListSnapshots(townRoot, "")
+	if err != nil {
+		return nil, err
+	}
+	expired := ExpiredSnapshots(snapshots, policy)
+	for _, s := range expired {
+		if err := os.RemoveAll(s.Path); err != nil {
+			return nil, fmt.Errorf("removing snapshot %s: %w", s.Path, err)
+		}
+		if err := os.Remove(s.metadataPath()); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("removing snapshot metadata: %w", err)
+		}
+	}
+	return expired, nil
+}
+
+// SnapshotAt returns the newest snapshot taken at or before t, preferring
+// verified ones, or nil if there is none.
+func SnapshotAt(snapshots []Snapshot, t time.Time) *Snapshot {
+	var best *Snapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.CreatedAt.After(t) {
+			continue
+		}
+		switch {
+		case best == nil:
+			best = s
+		case s.Verified && !best.Verified:
+			best = s
+		case s.Verified == best.Verified && s.CreatedAt.After(best.CreatedAt):
+			best = s
+		}
+	}
+	return best
+}
+
+// Restore methods.
+const (
+	// RestoreFromHistory resets the database to a commit in its own history.
+	RestoreFromHistory = "history"
+
+	// RestoreFromSnapshot replaces the database with a scheduled snapshot.
+	RestoreFromSnapshot = "snapshot"
+)
+
+// RestorePlan describes how RestoreDatabase will restore a database to a
+// point in time.
+type RestorePlan struct {
+	Database string
+	At       time.Time
+	Method   string
+
+	// Commit and CommitTime are the target of a history restore.
+	Commit     string
+	CommitTime time.Time
+
+	// SafetyBranch keeps the pre-restore state (including uncommitted
+	// changes) of a history restore.
+	SafetyBranch string
+
+	// Snapshot is the source of a snapshot restore.
+	Snapshot *Snapshot
+
+	// SavedDir is where a snapshot restore moves the replaced database.
+	SavedDir string
+}
+
+// PlanRestore decides how to restore a database to its state at t. Dolt
+// history is preferred: every bd write is a commit, so resetting to the
+// last commit before t loses nothing that happened before it. Snapshots
+// are used when the history can't be read (a corrupted working set or
+// manifest), when it has no commit that old, or when fromSnapshot is set.
+func PlanRestore(townRoot, database string, t time.Time, fromSnapshot bool) (*RestorePlan, error) {
+	if !validDatabaseNameRe.MatchString(database) {
+		return nil, fmt.Errorf("invalid database name %q", database)
+	}
+	stamp := time.Now().UTC().Format(snapshotTimeFormat)
+	plan := &RestorePlan{Database: database, At: t}
+
+	var historyErr error
+	if !fromSnapshot {
+		commit, when, err := commitAt(townRoot, database, t)
+		if err == nil {
+			plan.Method = RestoreFromHistory
+			plan.Commit = commit
+			plan.CommitTime = when
+			plan.SafetyBranch = "gt-pre-restore-" + stamp
+			return plan, nil
+		}
+		historyErr = err
+	}
+
+	snapshots, err := ListSnapshots(townRoot, database)
+	if err != nil {
+		return nil, err
+	}
+	s := SnapshotAt(snapshots, t)
+	if s == nil {
+		if historyErr != nil {
+			return nil, fmt.Errorf("no snapshot of %s at or before %s, and history unusable: %w",
+				database, t.Format(time.RFC3339), historyErr)
+		}
+		return nil, fmt.Errorf("no snapshot of %s at or before %s", database, t.Format(time.RFC3339))
+	}
+	plan.Method = RestoreFromSnapshot
+	plan.Snapshot = s
+	plan.SavedDir = filepath.Join(SnapshotsDir(townRoot), database, "replaced-"+stamp)
+	return plan, nil
+}
+
+// RestoreDatabase carries out a restore plan.
+//
+// A history restore runs through the server: it branches SafetyBranch off
+// the current state, commits any uncommitted changes there, and resets
+// main to Commit. A snapshot restore stops the local server, moves the
+// database to SavedDir, restores the snapshot in its place and starts the
+// server again; if the restore fails the original database is put back.
+func RestoreDatabase(townRoot string, plan *RestorePlan) error {
+	switch plan.Method {
+	case RestoreFromHistory:
+		script := fmt.Sprintf(`USE %s;
+CALL DOLT_CHECKOUT('-b', '%s');
+CALL DOLT_ADD('-A');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'gt dolt backup restore: state before restoring to %s');
+CALL DOLT_CHECKOUT('main');
+CALL DOLT_RESET('--hard', '%s');
+`, sqlIdent(plan.Database), plan.SafetyBranch, plan.At.UTC().Format(time.RFC3339), plan.Commit)
+		if err := doltSQLScript(townRoot, script); err != nil {
+			return fmt.Errorf("resetting %s to %s: %w", plan.Database, plan.Commit, err)
+		}
+		return nil
+
+	case RestoreFromSnapshot:
+		return restoreFromSnapshot(townRoot, plan)
+	}
+	return fmt.Errorf("unknown restore method %q", plan.Method)
+}
+
+func restoreFromSnapshot(townRoot string, plan *RestorePlan) error {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — restore requires local server access", config.HostPort())
+	}
+
+	running, _, _ := IsRunning(townRoot)
+	if running {
+		if err := Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+	}
+
+	dbDir := filepath.Join(config.DataDir, plan.Database)
+	restoreErr := func() error {
+		if _, err := os.Stat(dbDir); err == nil {
+			if err := os.MkdirAll(filepath.Dir(plan.SavedDir), 0755); err != nil {
+				return err
+			}
+			if err := os.Rename(dbDir, plan.SavedDir); err != nil {
+				return fmt.Errorf("moving %s aside: %w", dbDir, err)
+			}
+		}
+		if err := restoreBackup(config.DataDir, plan.Snapshot); err != nil {
+			_ = os.RemoveAll(dbDir)
+			if _, statErr := os.Stat(plan.SavedDir); statErr == nil {
+				_ = os.Rename(plan.SavedDir, dbDir)
+			}
+			return err
+		}
+		return nil
+	}()
+
+	if running {
+		if err := Start(townRoot); err != nil {
+			if restoreErr != nil {
+				return fmt.Errorf("%w (and restarting Dolt server: %v)", restoreErr, err)
+			}
+			return fmt.Errorf("restarting Dolt server: %w", err)
+		}
+	}
+	return restoreErr
+}
+
+// commitAt returns the newest commit on main made at or before t.
+func commitAt(townRoot, database string, t time.Time) (string, time.Time, error) {
+	query := fmt.Sprintf("USE `%s`; SELECT commit_hash, date FROM dolt_log WHERE date <= '%s' ORDER BY date DESC LIMIT 1",
+		database, t.UTC().Format("2006-01-02 15:04:05"))
+	output, err := runBackupCmd(DefaultConfig(townRoot), "-r", "csv", "-q", query)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	row := parseSingleValue(output)
+	hash, date, ok := strings.Cut(row, ",")
+	if !ok || hash == "commit_hash" {
+		return "", time.Time{}, fmt.Errorf("no commit at or before %s", t.Format(time.RFC3339))
+	}
+	when, err := time.ParseInLocation("2006-01-02 15:04:05.999999999", strings.TrimSpace(date), time.UTC)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parsing commit date %q: %w", strings.TrimSpace(date), err)
+	}
+	return strings.TrimSpace(hash), when, nil
+}
+
+// queryHead returns the HEAD commit of a database on the running server.
+func queryHead(townRoot, database string) (string, error) {
+	query := fmt.Sprintf("USE `%s`; SELECT HASHOF('HEAD') AS head", database)
+	output, err := runBackupCmd(DefaultConfig(townRoot), "-r", "csv", "-q", query)
+	if err != nil {
+		return "", err
+	}
+	head := parseSingleValue(output)
+	if head == "" {
+		return "", fmt.Errorf("empty HEAD")
+	}
+	return head, nil
+}
+
+// runBackupCmd runs a dolt sql command with the backup timeout.
+func runBackupCmd(config *Config, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+	output, err := buildDoltSQLCmd(ctx, config, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// parseSingleValue returns the last row of CSV query output, which follows
+// any output of the USE statement before it.
+func parseSingleValue(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// fileURL returns a file:// URL for an absolute path.
+func fileURL(path string) string {
+	return "file://" + filepath.ToSlash(path)
+}
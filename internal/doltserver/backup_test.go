@@ -0,0 +1,169 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSnapshot records a snapshot's metadata without a Dolt backup.
+func writeSnapshot(t *testing.T, townRoot, db string, at time.Time, verified bool) Snapshot {
+	t.Helper()
+	s := Snapshot{
+		Database:  db,
+		Path:      filepath.Join(SnapshotsDir(townRoot), db, at.UTC().Format(snapshotTimeFormat)),
+		CreatedAt: at,
+		Head:      "h" + at.Format("150405"),
+		Verified:  verified,
+	}
+	if err := os.MkdirAll(s.Path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.save(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestListSnapshots(t *testing.T) {
+	townRoot := t.TempDir()
+	if got, err := ListSnapshots(townRoot, ""); err != nil || len(got) != 0 {
+		t.Fatalf("no snapshots dir: %v, %v", got, err)
+	}
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeSnapshot(t, townRoot, "gastown", base, true)
+	writeSnapshot(t, townRoot, "hq", base.Add(time.Hour), false)
+	writeSnapshot(t, townRoot, "gastown", base.Add(2*time.Hour), false)
+	// An interrupted backup has a directory but no metadata.
+	if err := os.MkdirAll(filepath.Join(SnapshotsDir(townRoot), "gastown", "20260501-150000"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := ListSnapshots(townRoot, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || !all[0].CreatedAt.Equal(base.Add(2*time.Hour)) || all[1].Database != "hq" {
+		t.Errorf("all snapshots = %+v", all)
+	}
+
+	gastown, _ := ListSnapshots(townRoot, "gastown")
+	if len(gastown) != 2 || !gastown[1].Verified {
+		t.Errorf("gastown snapshots = %+v", gastown)
+	}
+}
+
+func TestExpiredSnapshots(t *testing.T) {
+	base := time.Date(2026, 5, 10, 23, 0, 0, 0, time.UTC)
+	var snapshots []Snapshot
+	// Hourly snapshots over three days, newest first: 10th 23:00 back to 8th 00:00.
+	for i := 0; i < 72; i++ {
+		snapshots = append(snapshots, Snapshot{Database: "gastown", CreatedAt: base.Add(-time.Duration(i) * time.Hour)})
+	}
+	snapshots[70].Verified = true // 8th 01:00, the only verified one
+	snapshots = append(snapshots, Snapshot{Database: "hq", CreatedAt: base.Add(-100 * time.Hour)})
+
+	expired := ExpiredSnapshots(snapshots, RetentionPolicy{Keep: 5, KeepDaily: 2})
+
+	kept := make(map[time.Time]bool)
+	for _, s := range snapshots {
+		kept[s.CreatedAt] = true
+	}
+	for _, s := range expired {
+		delete(kept, s.CreatedAt)
+	}
+	// 5 newest (10th 19:00-23:00), newest of the 9th (23:00), and the
+	// verified snapshot; the 10th's daily is among the newest five.
+	// hq's only snapshot is within its own Keep.
+	if len(kept) != 8 {
+		t.Errorf("kept %d snapshots, want 8", len(kept))
+	}
+	for _, want := range []time.Time{base, base.Add(-4 * time.Hour), base.Add(-24 * time.Hour), snapshots[70].CreatedAt, base.Add(-100 * time.Hour)} {
+		if !kept[want] {
+			t.Errorf("snapshot at %v should be kept", want)
+		}
+	}
+	if len(expired) > 1 && expired[0].CreatedAt.After(expired[1].CreatedAt) {
+		t.Error("expired snapshots should be oldest-first")
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := writeSnapshot(t, townRoot, "gastown", base, false)
+	writeSnapshot(t, townRoot, "gastown", base.Add(time.Hour), false)
+
+	removed, err := PruneSnapshots(townRoot, RetentionPolicy{Keep: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || !removed[0].CreatedAt.Equal(base) {
+		t.Fatalf("removed = %+v", removed)
+	}
+	if _, err := os.Stat(old.Path); !os.IsNotExist(err) {
+		t.Error("pruned backup directory still exists")
+	}
+	if _, err := os.Stat(old.metadataPath()); !os.IsNotExist(err) {
+		t.Error("pruned metadata still exists")
+	}
+}
+
+func TestSnapshotAt(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		{CreatedAt: base.Add(2 * time.Hour)},
+		{CreatedAt: base.Add(time.Hour)},
+		{CreatedAt: base, Verified: true},
+	}
+
+	if s := SnapshotAt(snapshots, base.Add(-time.Minute)); s != nil {
+		t.Errorf("before any snapshot = %+v", s)
+	}
+	if s := SnapshotAt(snapshots, base.Add(90*time.Minute)); s == nil || !s.Verified {
+		t.Errorf("verified snapshot should win over a newer unverified one: %+v", s)
+	}
+
+	snapshots[1].Verified = true
+	if s := SnapshotAt(snapshots, base.Add(3*time.Hour)); s == nil || !s.CreatedAt.Equal(base.Add(time.Hour)) {
+		t.Errorf("newest verified snapshot = %+v", s)
+	}
+}
+
+func TestPlanRestore_InvalidDatabase(t *testing.T) {
+	if _, err := PlanRestore(t.TempDir(), "bad;name", time.Now(), true); err == nil {
+		t.Error("expected error for an unsafe database name")
+	}
+}
+
+func TestPlanRestore_FromSnapshot(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeSnapshot(t, townRoot, "gastown", base, true)
+
+	plan, err := PlanRestore(townRoot, "gastown", base.Add(time.Hour), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Method != RestoreFromSnapshot || plan.Snapshot == nil || !plan.Snapshot.CreatedAt.Equal(base) {
+		t.Errorf("plan = %+v", plan)
+	}
+	if filepath.Dir(plan.SavedDir) != filepath.Join(SnapshotsDir(townRoot), "gastown") {
+		t.Errorf("SavedDir = %s", plan.SavedDir)
+	}
+
+	if _, err := PlanRestore(townRoot, "gastown", base.Add(-time.Hour), true); err == nil {
+		t.Error("expected error when no snapshot is old enough")
+	}
+}
+
+func TestParseSingleValue(t *testing.T) {
+	if got := parseSingleValue("head\nabc123\n"); got != "abc123" {
+		t.Errorf("got %q", got)
+	}
+	if got := parseSingleValue("head\n"); got != "" {
+		t.Errorf("header only: got %q", got)
+	}
+}
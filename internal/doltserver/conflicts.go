@@ -0,0 +1,696 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// mergeTimeout bounds each dolt CLI call made while merging a remote.
+// Fetches from DoltHub can be slow for large databases.
+const mergeTimeout = 5 * time.Minute
+
+// rowColumn marks a conflict on the whole row (deleted on one side,
+// modified on the other) rather than on a single column.
+const rowColumn = "*"
+
+// ConflictRule is how a conflicting bead field is merged when both sides
+// changed it since the merge base.
+type ConflictRule string
+
+const (
+	// RuleStatusMonotonic keeps the more advanced status: beads move
+	// forward (open → in progress → closed), so a close on either side
+	// wins over a concurrent edit that left the bead open.
+	RuleStatusMonotonic ConflictRule = "status-monotonic"
+
+	// RuleUnion merges list-valued fields (JSON arrays or comma-separated
+	// values) as the union of both sides.
+	RuleUnion ConflictRule = "union"
+
+	// RuleLastWriterWins keeps the side whose row has the later updated_at.
+	RuleLastWriterWins ConflictRule = "last-writer-wins"
+
+	// RuleLatest keeps the later of two timestamps, or whichever is set.
+	RuleLatest ConflictRule = "latest"
+)
+
+// beadFieldRules maps bead columns to their merge rule. Columns without a
+// rule are only merged when one side left them unchanged.
+//
+// Labels stored in the separate labels table merge as a set without
+// conflicts (rows added on either side are kept); the union rule covers
+// list-valued label columns.
+var beadFieldRules = map[string]ConflictRule{
+	"status":              RuleStatusMonotonic,
+	"labels":              RuleUnion,
+	"title":               RuleLastWriterWins,
+	"description":         RuleLastWriterWins,
+	"design":              RuleLastWriterWins,
+	"notes":               RuleLastWriterWins,
+	"acceptance_criteria": RuleLastWriterWins,
+	"assignee":            RuleLastWriterWins,
+	"priority":            RuleLastWriterWins,
+	"updated_at":          RuleLatest,
+	"closed_at":           RuleLatest,
+}
+
+// statusRank orders bead statuses for RuleStatusMonotonic. Statuses at the
+// same rank (or unknown ones) fall back to last-writer-wins.
+var statusRank = map[string]int{
+	"open":        0,
+	"blocked":     1,
+	"deferred":    1,
+	"hooked":      2,
+	"in_progress": 2,
+	"pinned":      2,
+	"closed":      3,
+	"tombstone":   4,
+}
+
+// fieldRules returns the column rules for a table. Only bead tables have
+// field-level rules; other tables merge non-overlapping changes only.
+func fieldRules(table string) map[string]ConflictRule {
+	switch table {
+	case "issues", "wisps":
+		return beadFieldRules
+	}
+	return nil
+}
+
+// ConflictRow is one row of a dolt_conflicts_<table> system table.
+// A nil Base, Ours or Theirs means the row is absent on that side.
+type ConflictRow struct {
+	Table  string
+	Key    map[string]any
+	Base   map[string]any
+	Ours   map[string]any
+	Theirs map[string]any
+}
+
+// Resolution is the outcome of merging one conflicting row.
+type Resolution struct {
+	// Values is the merged row. Unresolved columns keep our value.
+	// Nil when the row stays deleted.
+	Values map[string]any
+
+	// Unresolved lists the columns no rule could settle, or "*" when the
+	// row was deleted on one side and modified on the other.
+	Unresolved []string
+}
+
+// ResolveConflict merges a conflicting row column by column. A column
+// changed on only one side takes that side's value; a column changed on
+// both sides is merged by its field rule, if it has one.
+func ResolveConflict(c ConflictRow) Resolution {
+	if c.Ours == nil || c.Theirs == nil {
+		if c.Ours == nil && c.Theirs == nil {
+			return Resolution{}
+		}
+		return Resolution{Values: c.Ours, Unresolved: []string{rowColumn}}
+	}
+
+	rules := fieldRules(c.Table)
+	columns := make(map[string]bool)
+	for col := range c.Ours {
+		columns[col] = true
+	}
+	for col := range c.Theirs {
+		columns[col] = true
+	}
+
+	res := Resolution{Values: make(map[string]any, len(columns))}
+	for col := range columns {
+		ours, theirs := c.Ours[col], c.Theirs[col]
+		var base any
+		if c.Base != nil {
+			base = c.Base[col]
+		}
+
+		switch {
+		case valuesEqual(ours, theirs), c.Base != nil && valuesEqual(base, theirs):
+			res.Values[col] = ours
+			continue
+		case c.Base != nil && valuesEqual(base, ours):
+			res.Values[col] = theirs
+			continue
+		}
+
+		merged, ok := applyRule(rules[col], ours, theirs, c.Ours, c.Theirs)
+		if !ok {
+			res.Unresolved = append(res.Unresolved, col)
+			merged = ours
+		}
+		res.Values[col] = merged
+	}
+	sort.Strings(res.Unresolved)
+	return res
+}
+
+// applyRule merges a column both sides changed. ok is false when the rule
+// (or its last-writer-wins fallback) can't pick a value.
+func applyRule(rule ConflictRule, ours, theirs any, ourRow, theirRow map[string]any) (any, bool) {
+	switch rule {
+	case RuleStatusMonotonic:
+		ourRank, ourKnown := statusRank[fmt.Sprint(ours)]
+		theirRank, theirKnown := statusRank[fmt.Sprint(theirs)]
+		if ourKnown && theirKnown && ourRank != theirRank {
+			if theirRank > ourRank {
+				return theirs, true
+			}
+			return ours, true
+		}
+		return lastWriter(ours, theirs, ourRow, theirRow)
+	case RuleUnion:
+		return unionValues(ours, theirs)
+	case RuleLastWriterWins:
+		return lastWriter(ours, theirs, ourRow, theirRow)
+	case RuleLatest:
+		if ours == nil {
+			return theirs, true
+		}
+		if theirs == nil {
+			return ours, true
+		}
+		ourTime, err1 := parseConflictTime(ours)
+		theirTime, err2 := parseConflictTime(theirs)
+		if err1 != nil || err2 != nil {
+			return nil, false
+		}
+		if theirTime.After(ourTime) {
+			return theirs, true
+		}
+		return ours, true
+	}
+	return nil, false
+}
+
+// lastWriter picks the value from the row with the later updated_at.
+func lastWriter(ours, theirs any, ourRow, theirRow map[string]any) (any, bool) {
+	ourTime, err1 := parseConflictTime(ourRow["updated_at"])
+	theirTime, err2 := parseConflictTime(theirRow["updated_at"])
+	if err1 != nil || err2 != nil || ourTime.Equal(theirTime) {
+		return nil, false
+	}
+	if theirTime.After(ourTime) {
+		return theirs, true
+	}
+	return ours, true
+}
+
+// unionValues merges two list values, keeping our encoding (JSON array or
+// comma-separated string).
+func unionValues(ours, theirs any) (any, bool) {
+	ourItems, ourJSON, ok1 := splitList(ours)
+	theirItems, _, ok2 := splitList(theirs)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	seen := make(map[string]bool)
+	var union []string
+	for _, item := range append(ourItems, theirItems...) {
+		if !seen[item] {
+			seen[item] = true
+			union = append(union, item)
+		}
+	}
+	sort.Strings(union)
+	if ourJSON {
+		if union == nil {
+			union = []string{}
+		}
+		data, err := json.Marshal(union)
+		if err != nil {
+			return nil, false
+		}
+		return string(data), true
+	}
+	return strings.Join(union, ","), true
+}
+
+// splitList parses a list value. isJSON reports whether it was a JSON array.
+func splitList(v any) (items []string, isJSON bool, ok bool) {
+	switch list := v.(type) {
+	case nil:
+		return nil, false, true
+	case []any:
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items, true, true
+	case string:
+		s := strings.TrimSpace(list)
+		if strings.HasPrefix(s, "[") {
+			if err := json.Unmarshal([]byte(s), &items); err != nil {
+				return nil, false, false
+			}
+			return items, true, true
+		}
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, false, true
+	}
+	return nil, false, false
+}
+
+// parseConflictTime parses a Dolt DATETIME/TIMESTAMP value.
+func parseConflictTime(v any) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("not a timestamp: %v", v)
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("not a timestamp: %q", s)
+}
+
+func valuesEqual(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// parseConflictRows splits dolt_conflicts_<table> rows into their base,
+// our and their sides. keyColumns are the table's primary key columns.
+func parseConflictRows(table string, keyColumns []string, rows []map[string]any) []ConflictRow {
+	var conflicts []ConflictRow
+	for _, row := range rows {
+		c := ConflictRow{Table: table}
+		sides := map[string]map[string]any{"base_": {}, "our_": {}, "their_": {}}
+		for col, v := range row {
+			for prefix, side := range sides {
+				if name, ok := strings.CutPrefix(col, prefix); ok && name != "diff_type" {
+					side[name] = v
+				}
+			}
+		}
+		present := func(side map[string]any, diffType any) map[string]any {
+			if diffType == "removed" || len(side) == 0 {
+				return nil
+			}
+			for _, k := range keyColumns {
+				if side[k] == nil {
+					return nil
+				}
+			}
+			return side
+		}
+		c.Base = present(sides["base_"], nil)
+		c.Ours = present(sides["our_"], row["our_diff_type"])
+		c.Theirs = present(sides["their_"], row["their_diff_type"])
+
+		c.Key = make(map[string]any, len(keyColumns))
+		for _, k := range keyColumns {
+			for _, side := range []map[string]any{c.Ours, c.Theirs, c.Base} {
+				if side != nil && side[k] != nil {
+					c.Key[k] = side[k]
+					break
+				}
+			}
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts
+}
+
+// rowID renders a primary key as a stable identifier, e.g. "gt-abc".
+func rowID(key map[string]any) string {
+	cols := make([]string, 0, len(key))
+	for col := range key {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	parts := make([]string, len(cols))
+	for i, col := range cols {
+		parts[i] = fmt.Sprint(key[col])
+	}
+	return strings.Join(parts, ",")
+}
+
+// sqlLiteral renders a value decoded from dolt's JSON output as SQL.
+func sqlLiteral(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case json.Number:
+		return val.String()
+	case bool:
+		if val {
+			return "TRUE"
+		}
+		return "FALSE"
+	case string:
+		return "'" + strings.ReplaceAll(strings.ReplaceAll(val, `\`, `\\`), "'", "''") + "'"
+	default:
+		data, _ := json.Marshal(val)
+		return sqlLiteral(string(data))
+	}
+}
+
+func sqlIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// keyWhere renders a WHERE clause matching a primary key.
+func keyWhere(key map[string]any) string {
+	cols := make([]string, 0, len(key))
+	for col := range key {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	conds := make([]string, len(cols))
+	for i, col := range cols {
+		conds[i] = fmt.Sprintf("%s = %s", sqlIdent(col), sqlLiteral(key[col]))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// rowUpsertSQL writes values to a row, inserting it if it is missing.
+// Uses ON DUPLICATE KEY UPDATE rather than REPLACE so existing rows are
+// updated in place and ON DELETE CASCADE children (labels, dependencies)
+// survive.
+func rowUpsertSQL(table string, values map[string]any) string {
+	cols := make([]string, 0, len(values))
+	for col := range values {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	idents := make([]string, len(cols))
+	literals := make([]string, len(cols))
+	updates := make([]string, len(cols))
+	for i, col := range cols {
+		idents[i] = sqlIdent(col)
+		literals[i] = sqlLiteral(values[col])
+		updates[i] = fmt.Sprintf("%s = VALUES(%s)", idents[i], idents[i])
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s;\n",
+		sqlIdent(table), strings.Join(idents, ", "), strings.Join(literals, ", "), strings.Join(updates, ", "))
+}
+
+// RecordedConflict is a conflicting row a merge could not resolve. The
+// merge kept our version; Theirs holds the remote version so it can still
+// be taken with ResolveRecordedConflict.
+type RecordedConflict struct {
+	Database   string         `json:"database"`
+	Table      string         `json:"table"`
+	Row        string         `json:"row"`
+	Key        map[string]any `json:"key"`
+	Columns    []string       `json:"columns"`
+	Ours       map[string]any `json:"ours"`
+	Theirs     map[string]any `json:"theirs"`
+	RecordedAt time.Time      `json:"recorded_at"`
+}
+
+// ConflictsFile returns the path of the unresolved conflict log.
+func ConflictsFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-conflicts.json")
+}
+
+// LoadConflicts returns the recorded unresolved conflicts, oldest first.
+func LoadConflicts(townRoot string) ([]RecordedConflict, error) {
+	data, err := os.ReadFile(ConflictsFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var conflicts []RecordedConflict
+	if err := json.Unmarshal(data, &conflicts); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ConflictsFile(townRoot), err)
+	}
+	return conflicts, nil
+}
+
+func saveConflicts(townRoot string, conflicts []RecordedConflict) error {
+	path := ConflictsFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if conflicts == nil {
+		conflicts = []RecordedConflict{}
+	}
+	return util.AtomicWriteJSON(path, conflicts)
+}
+
+// recordConflicts adds conflicts to the log, replacing older entries for
+// the same row.
+func recordConflicts(townRoot string, added []RecordedConflict) error {
+	if len(added) == 0 {
+		return nil
+	}
+	existing, err := LoadConflicts(townRoot)
+	if err != nil {
+		return err
+	}
+	replaced := make(map[string]bool)
+	for _, c := range added {
+		replaced[c.Database+"/"+c.Table+"/"+c.Row] = true
+	}
+	var kept []RecordedConflict
+	for _, c := range existing {
+		if !replaced[c.Database+"/"+c.Table+"/"+c.Row] {
+			kept = append(kept, c)
+		}
+	}
+	return saveConflicts(townRoot, append(kept, added...))
+}
+
+// ResolveRecordedConflict settles a recorded conflict. Taking ours just
+// drops it from the log (the merge already kept our version); taking
+// theirs writes the remote version of the row through the running server.
+func ResolveRecordedConflict(townRoot, db, row string, takeTheirs bool) (*RecordedConflict, error) {
+	conflicts, err := LoadConflicts(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, c := range conflicts {
+		if c.Database == db && c.Row == row {
+			if idx >= 0 {
+				return nil, fmt.Errorf("row %s conflicts in several tables of %s", row, db)
+			}
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("no recorded conflict for %s in %s", row, db)
+	}
+	c := conflicts[idx]
+
+	if takeTheirs {
+		if !validDatabaseNameRe.MatchString(db) {
+			return nil, fmt.Errorf("invalid database name %q", db)
+		}
+		script := fmt.Sprintf("USE %s;\n", sqlIdent(db))
+		if c.Theirs == nil {
+			script += fmt.Sprintf("DELETE FROM %s WHERE %s;\n", sqlIdent(c.Table), keyWhere(c.Key))
+		} else {
+			script += rowUpsertSQL(c.Table, c.Theirs)
+		}
+		script += fmt.Sprintf("CALL DOLT_COMMIT('-Am', %s);\n",
+			sqlLiteral(fmt.Sprintf("gt dolt conflicts: take remote %s.%s", c.Table, c.Row)))
+		if err := doltSQLScript(townRoot, script); err != nil {
+			return nil, fmt.Errorf("applying remote version of %s: %w", row, err)
+		}
+	}
+
+	conflicts = append(conflicts[:idx], conflicts[idx+1:]...)
+	if err := saveConflicts(townRoot, conflicts); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// MergeResult records the outcome of merging origin/main into a database.
+type MergeResult struct {
+	// Merged is true if origin/main had changes that were merged.
+	Merged bool
+
+	// Resolved counts conflicting rows settled by the field rules.
+	Resolved int
+
+	// Unresolved are rows recorded for manual resolution (our version kept).
+	Unresolved []RecordedConflict
+}
+
+// MergeRemote fetches origin and merges origin/main into the database's
+// main branch with Dolt's three-way merge. Conflicting rows are resolved
+// with ResolveConflict; rows it can't settle keep our version and are
+// recorded in ConflictsFile. Operates on the database directory, so the
+// Dolt server must be stopped. If the merge can't be completed it is
+// aborted, so a failed sync never leaves the database mid-merge.
+func MergeRemote(townRoot, db string) (_ *MergeResult, err error) {
+	dbDir := RigDatabaseDir(townRoot, db)
+	result := &MergeResult{}
+
+	if _, err := runDoltCLI(dbDir, "fetch", "origin"); err != nil {
+		return nil, err
+	}
+	rows, err := queryDoltCLI(dbDir, "SELECT COUNT(*) AS n FROM dolt_remote_branches WHERE name = 'remotes/origin/main'")
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || fmt.Sprint(rows[0]["n"]) == "0" {
+		return result, nil // nothing pushed to the remote yet
+	}
+
+	ahead, err := queryDoltCLI(dbDir, "SELECT COUNT(*) AS n FROM dolt_log('main..remotes/origin/main')")
+	if err != nil {
+		return nil, err
+	}
+	if len(ahead) == 0 || fmt.Sprint(ahead[0]["n"]) == "0" {
+		return result, nil
+	}
+	result.Merged = true
+
+	committed := false
+	defer func() {
+		if err == nil || committed {
+			return
+		}
+		if _, abortErr := runDoltCLI(dbDir, "merge", "--abort"); abortErr != nil {
+			err = fmt.Errorf("%w (and aborting the merge: %v)", err, abortErr)
+		}
+	}()
+
+	_, mergeErr := runDoltCLI(dbDir, "merge", "-m", "gt dolt sync: merge origin/main", "origin/main")
+	tables, err := queryDoltCLI(dbDir, "SELECT `table` FROM dolt_conflicts")
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return result, mergeErr
+	}
+
+	var script strings.Builder
+	script.WriteString("SET @@dolt_allow_commit_conflicts = 1;\n")
+	now := time.Now().UTC()
+	for _, t := range tables {
+		table := fmt.Sprint(t["table"])
+		keyColumns, err := primaryKeyColumns(dbDir, table)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := queryDoltCLI(dbDir, "SELECT * FROM dolt_conflicts_"+table)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range parseConflictRows(table, keyColumns, rows) {
+			res := ResolveConflict(c)
+			if res.Values != nil {
+				script.WriteString(rowUpsertSQL(table, res.Values))
+			} else if len(c.Key) > 0 {
+				fmt.Fprintf(&script, "DELETE FROM %s WHERE %s;\n", sqlIdent(table), keyWhere(c.Key))
+			}
+			if len(res.Unresolved) == 0 {
+				result.Resolved++
+				continue
+			}
+			result.Unresolved = append(result.Unresolved, RecordedConflict{
+				Database:   db,
+				Table:      table,
+				Row:        rowID(c.Key),
+				Key:        c.Key,
+				Columns:    res.Unresolved,
+				Ours:       c.Ours,
+				Theirs:     c.Theirs,
+				RecordedAt: now,
+			})
+		}
+		fmt.Fprintf(&script, "DELETE FROM %s;\n", sqlIdent("dolt_conflicts_"+table))
+	}
+
+	if _, err := runDoltCLI(dbDir, "sql", "-q", script.String()); err != nil {
+		return nil, fmt.Errorf("resolving merge conflicts: %w", err)
+	}
+	if _, err := runDoltCLI(dbDir, "add", "."); err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("gt dolt sync: merge origin/main (%d conflict(s) resolved, %d kept local)",
+		result.Resolved, len(result.Unresolved))
+	if _, err := runDoltCLI(dbDir, "commit", "-m", msg); err != nil {
+		return nil, err
+	}
+	committed = true
+	if err := recordConflicts(townRoot, result.Unresolved); err != nil {
+		return nil, fmt.Errorf("recording unresolved conflicts: %w", err)
+	}
+	return result, nil
+}
+
+// primaryKeyColumns returns a table's primary key columns.
+func primaryKeyColumns(dbDir, table string) ([]string, error) {
+	rows, err := queryDoltCLI(dbDir, fmt.Sprintf(
+		"SELECT column_name AS name FROM information_schema.key_column_usage "+
+			"WHERE table_schema = DATABASE() AND table_name = %s AND constraint_name = 'PRIMARY' "+
+			"ORDER BY ordinal_position", sqlLiteral(table)))
+	if err != nil {
+		return nil, err
+	}
+	var cols []string
+	for _, row := range rows {
+		cols = append(cols, fmt.Sprint(row["name"]))
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("table %s has no primary key", table)
+	}
+	return cols, nil
+}
+
+// runDoltCLI runs a dolt command in a database directory.
+func runDoltCLI(dbDir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mergeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "dolt", args...)
+	cmd.Dir = dbDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return output, fmt.Errorf("dolt %s: %w (%s)", args[0], err,
+			strings.TrimSpace(stderr.String()+" "+string(output)))
+	}
+	return output, nil
+}
+
+// queryDoltCLI runs a query in a database directory and decodes its rows.
+// Numbers are kept as json.Number so they round-trip exactly.
+func queryDoltCLI(dbDir, query string) ([]map[string]any, error) {
+	output, err := runDoltCLI(dbDir, "sql", "-r", "json", "-q", query)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONRows(output)
+}
+
+// decodeJSONRows decodes dolt's `-r json` output.
+func decodeJSONRows(output []byte) ([]map[string]any, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(extractJSON(output)))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("parsing dolt JSON output: %w", err)
+	}
+	return result.Rows, nil
+}
@@ -0,0 +1,162 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func beadRow(status, description, labels, updatedAt string) map[string]any {
+	return map[string]any{
+		"id":          "gt-abc",
+		"status":      status,
+		"description": description,
+		"labels":      labels,
+		"updated_at":  updatedAt,
+	}
+}
+
+func TestResolveConflict_FieldRules(t *testing.T) {
+	c := ConflictRow{
+		Table:  "issues",
+		Key:    map[string]any{"id": "gt-abc"},
+		Base:   beadRow("open", "base", `["a"]`, "2026-05-01 10:00:00"),
+		Ours:   beadRow("closed", "ours", `["a","b"]`, "2026-05-01 11:00:00"),
+		Theirs: beadRow("in_progress", "theirs", `["a","c"]`, "2026-05-01 12:00:00"),
+	}
+
+	res := ResolveConflict(c)
+	if len(res.Unresolved) != 0 {
+		t.Fatalf("unresolved = %v", res.Unresolved)
+	}
+	want := map[string]any{
+		"id":          "gt-abc",
+		"status":      "closed",              // monotonic: closed beats in_progress
+		"description": "theirs",              // last writer wins
+		"labels":      `["a","b","c"]`,       // union
+		"updated_at":  "2026-05-01 12:00:00", // latest
+	}
+	if !reflect.DeepEqual(res.Values, want) {
+		t.Errorf("merged = %v\nwant %v", res.Values, want)
+	}
+}
+
+func TestResolveConflict_OneSideChanged(t *testing.T) {
+	base := map[string]any{"id": "x", "owner": "a", "notes": "n"}
+	res := ResolveConflict(ConflictRow{
+		Table:  "config",
+		Base:   base,
+		Ours:   map[string]any{"id": "x", "owner": "b", "notes": "n"},
+		Theirs: map[string]any{"id": "x", "owner": "a", "notes": "m"},
+	})
+	if len(res.Unresolved) != 0 || res.Values["owner"] != "b" || res.Values["notes"] != "m" {
+		t.Errorf("resolution = %+v", res)
+	}
+}
+
+func TestResolveConflict_Unresolved(t *testing.T) {
+	// No rule for the column outside bead tables.
+	res := ResolveConflict(ConflictRow{
+		Table:  "config",
+		Base:   map[string]any{"k": "v", "value": "0"},
+		Ours:   map[string]any{"k": "v", "value": "1"},
+		Theirs: map[string]any{"k": "v", "value": "2"},
+	})
+	if !reflect.DeepEqual(res.Unresolved, []string{"value"}) || res.Values["value"] != "1" {
+		t.Errorf("rule-less column: %+v", res)
+	}
+
+	// Last-writer-wins needs distinct timestamps.
+	res = ResolveConflict(ConflictRow{
+		Table:  "issues",
+		Base:   beadRow("open", "base", "", "2026-05-01 10:00:00"),
+		Ours:   beadRow("open", "ours", "", "2026-05-01 11:00:00"),
+		Theirs: beadRow("open", "theirs", "", "2026-05-01 11:00:00"),
+	})
+	if !reflect.DeepEqual(res.Unresolved, []string{"description"}) || res.Values["description"] != "ours" {
+		t.Errorf("timestamp tie: %+v", res)
+	}
+
+	// Deleted on one side, modified on the other.
+	res = ResolveConflict(ConflictRow{
+		Table:  "issues",
+		Base:   beadRow("open", "base", "", "2026-05-01 10:00:00"),
+		Theirs: beadRow("open", "theirs", "", "2026-05-01 11:00:00"),
+	})
+	if res.Values != nil || !reflect.DeepEqual(res.Unresolved, []string{rowColumn}) {
+		t.Errorf("delete/modify: %+v", res)
+	}
+}
+
+func TestUnionValues_CommaSeparated(t *testing.T) {
+	got, ok := unionValues("b,a", "c, a")
+	if !ok || got != "a,b,c" {
+		t.Errorf("union = %v, %v", got, ok)
+	}
+}
+
+func TestParseConflictRows(t *testing.T) {
+	output := []byte(`{"rows":[{"base_id":null,"base_title":null,"our_id":"gt-1","our_title":"a","our_diff_type":"added",` +
+		`"their_id":"gt-1","their_title":"b","their_diff_type":"added","dolt_conflict_id":"x","priority":null}]}`)
+	rows, err := decodeJSONRows(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts := parseConflictRows("issues", []string{"id"}, rows)
+	if len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts", len(conflicts))
+	}
+	c := conflicts[0]
+	if c.Base != nil || c.Ours["title"] != "a" || c.Theirs["title"] != "b" || rowID(c.Key) != "gt-1" {
+		t.Errorf("conflict = %+v", c)
+	}
+	if _, ok := c.Ours["diff_type"]; ok {
+		t.Error("diff_type should not be a row column")
+	}
+}
+
+func TestRowUpsertSQL(t *testing.T) {
+	got := rowUpsertSQL("issues", map[string]any{
+		"id":       "gt-1",
+		"title":    "it's",
+		"priority": json.Number("2"),
+		"closed":   nil,
+	})
+	want := "INSERT INTO `issues` (`closed`, `id`, `priority`, `title`) VALUES (NULL, 'gt-1', 2, 'it''s')"
+	if !strings.HasPrefix(got, want) || !strings.Contains(got, "ON DUPLICATE KEY UPDATE `closed` = VALUES(`closed`)") {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestRecordConflicts(t *testing.T) {
+	townRoot := t.TempDir()
+	first := RecordedConflict{Database: "gastown", Table: "issues", Row: "gt-1", Columns: []string{"notes"}}
+	other := RecordedConflict{Database: "gastown", Table: "issues", Row: "gt-2", Columns: []string{"notes"}}
+	if err := recordConflicts(townRoot, []RecordedConflict{first, other}); err != nil {
+		t.Fatal(err)
+	}
+	again := first
+	again.Columns = []string{"title"}
+	if err := recordConflicts(townRoot, []RecordedConflict{again}); err != nil {
+		t.Fatal(err)
+	}
+
+	conflicts, err := LoadConflicts(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 2 || conflicts[1].Row != "gt-1" || conflicts[1].Columns[0] != "title" {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+
+	if _, err := ResolveRecordedConflict(townRoot, "gastown", "gt-2", false); err != nil {
+		t.Fatal(err)
+	}
+	if conflicts, _ = LoadConflicts(townRoot); len(conflicts) != 1 {
+		t.Errorf("after resolve: %+v", conflicts)
+	}
+	if _, err := ResolveRecordedConflict(townRoot, "gastown", "gt-9", false); err == nil {
+		t.Error("expected error for an unknown row")
+	}
+}
@@ -22,6 +22,11 @@ type SyncOptions struct {
 
 	// Filter restricts sync to a single database name. Empty means all.
 	Filter string
+
+	// Merge pulls origin/main and merges it before pushing, resolving
+	// conflicting bead rows with field-level rules (see MergeRemote).
+	// Lets several towns share one remote without force-pushing.
+	Merge bool
 }
 
 // SyncResult records the outcome of syncing a single database.
@@ -43,6 +48,9 @@ type SyncResult struct {
 
 	// Remote is the origin push URL, or empty if none configured.
 	Remote string
+
+	// Merge is the outcome of merging origin/main (Merge mode only).
+	Merge *MergeResult
 }
 
 // HasRemote checks whether a Dolt database directory has an "origin" remote configured.
@@ -118,7 +126,8 @@ func PushDatabase(dbDir string, force bool) error {
 }
 
 // SyncDatabases iterates all databases (or a filtered subset), checks for remotes,
-// commits working changes, and pushes to origin. Never fails fast — collects all results.
+// commits working changes, optionally merges origin/main, and pushes to origin.
+// Never fails fast — collects all results.
 func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 	databases, err := ListDatabases(townRoot)
 	if err != nil {
@@ -187,6 +196,16 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 			continue
 		}
 
+		if opts.Merge {
+			merge, err := MergeRemote(townRoot, db)
+			if err != nil {
+				result.Error = fmt.Errorf("merging origin/main: %w", err)
+				results = append(results, result)
+				continue
+			}
+			result.Merge = merge
+		}
+
 		// Push
 		if err := PushDatabase(dbDir, opts.Force); err != nil {
 			result.Error = err
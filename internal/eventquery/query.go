@@ -0,0 +1,580 @@
+// Package eventquery implements a small query language over the town's
+// event logs (.events.jsonl and the town log), for incident review without
+// piping JSONL through jq.
+//
+// A query is a list of filters optionally followed by pipeline stages:
+//
+//	type:done actor:*/polecats/* rig:gastown since:2h | count by actor
+//	type:spawn,kill since:1d | count per 1h
+//	-actor:mayor "merge failed" | limit 20
+//
+// Filters are field:value terms ANDed together. Values may list
+// alternatives separated by commas and use * and ? globs; matching is
+// case-insensitive. A leading - negates a filter. Bare words (or quoted
+// phrases) match any field or payload value as a substring.
+package eventquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/krc"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+// Log names usable with the log: filter.
+const (
+	LogEvents  = "events"
+	LogTownlog = "townlog"
+)
+
+// Record is one event from either log, normalized for querying.
+type Record struct {
+	Time       time.Time              `json:"time"`
+	Log        string                 `json:"log"`
+	Type       string                 `json:"type"`
+	Actor      string                 `json:"actor"`
+	Rig        string                 `json:"rig,omitempty"`
+	Source     string                 `json:"source,omitempty"`
+	Visibility string                 `json:"visibility,omitempty"`
+	Context    string                 `json:"context,omitempty"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+}
+
+// Field returns the value of a named field, falling back to payload keys
+// (optionally written as payload.<key>). Returns "" if unset.
+func (r Record) Field(name string) string {
+	switch name {
+	case "type":
+		return r.Type
+	case "actor", "agent":
+		return r.Actor
+	case "rig":
+		return r.Rig
+	case "log":
+		return r.Log
+	case "source":
+		return r.Source
+	case "visibility":
+		return r.Visibility
+	case "context":
+		return r.Context
+	}
+	if v, ok := r.Payload[strings.TrimPrefix(name, "payload.")]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// FromEvent converts an events.Event. Returns false if its timestamp
+// can't be parsed.
+func FromEvent(e events.Event) (Record, bool) {
+	ts, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return Record{}, false
+	}
+	r := Record{
+		Time:       ts,
+		Log:        LogEvents,
+		Type:       e.Type,
+		Actor:      e.Actor,
+		Rig:        rigOf(e.Actor),
+		Source:     e.Source,
+		Visibility: e.Visibility,
+		Payload:    e.Payload,
+	}
+	if rig, ok := e.Payload["rig"].(string); ok && rig != "" {
+		r.Rig = rig
+	}
+	return r, true
+}
+
+// FromTownlog converts a townlog.Event.
+func FromTownlog(e townlog.Event) Record {
+	return Record{
+		Time:    e.Timestamp,
+		Log:     LogTownlog,
+		Type:    string(e.Type),
+		Actor:   e.Agent,
+		Rig:     rigOf(e.Agent),
+		Context: e.Context,
+	}
+}
+
+// rigOf extracts the rig from an actor address like "gastown/polecats/Toast".
+// Town-level actors ("mayor", "deacon") have no rig.
+func rigOf(actor string) string {
+	if i := strings.Index(actor, "/"); i > 0 {
+		return actor[:i]
+	}
+	return ""
+}
+
+// filter is one field:value term.
+type filter struct {
+	field   string // "" for a bare word
+	negate  bool
+	word    string           // bare word, lowercased
+	matches []*regexp.Regexp // field alternatives
+}
+
+func (f filter) match(r Record) bool {
+	return f.matchRaw(r) != f.negate
+}
+
+func (f filter) matchRaw(r Record) bool {
+	if f.field == "" {
+		return containsText(r, f.word)
+	}
+	v := r.Field(f.field)
+	for _, re := range f.matches {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsText reports whether any field or payload value contains word.
+func containsText(r Record, word string) bool {
+	for _, v := range []string{r.Type, r.Actor, r.Rig, r.Source, r.Context} {
+		if strings.Contains(strings.ToLower(v), word) {
+			return true
+		}
+	}
+	for _, v := range r.Payload {
+		if v != nil && strings.Contains(strings.ToLower(fmt.Sprint(v)), word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query is a parsed query.
+type Query struct {
+	Text  string
+	Since time.Time
+	Until time.Time
+	Logs  []string // empty means all logs
+
+	filters []filter
+
+	// Pipeline.
+	Count   bool
+	By      []string
+	Per     time.Duration
+	Limit   int // 0 = unlimited
+	limited bool
+}
+
+// Parse parses query text. now anchors relative since:/until: values.
+func Parse(text string, now time.Time) (*Query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{Text: strings.TrimSpace(text)}
+	stages := splitStages(tokens)
+	for _, tok := range stages[0] {
+		if err := q.addTerm(tok, now); err != nil {
+			return nil, err
+		}
+	}
+	for _, stage := range stages[1:] {
+		if err := q.addStage(stage); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// token is a word from the query; quoted tokens are never operators or
+// field filters.
+type token struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	var cur strings.Builder
+	inQuote, quoted, started := false, false, false
+	flush := func() {
+		if started {
+			tokens = append(tokens, token{text: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+		quoted, started = false, false
+	}
+
+	for _, c := range text {
+		switch {
+		case c == '"':
+			// A token is quoted (a literal phrase) only if it opens with a
+			// quote; field:"two words" stays a field filter.
+			if !started {
+				quoted = true
+			}
+			inQuote = !inQuote
+			started = true
+		case inQuote:
+			cur.WriteRune(c)
+		case c == ' ' || c == '\t' || c == '\n':
+			flush()
+		case c == '|':
+			flush()
+			tokens = append(tokens, token{text: "|"})
+		default:
+			cur.WriteRune(c)
+			started = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+// splitStages splits tokens on unquoted pipes. The first stage holds filters.
+func splitStages(tokens []token) [][]token {
+	stages := [][]token{nil}
+	for _, t := range tokens {
+		if t.text == "|" && !t.quoted {
+			stages = append(stages, nil)
+			continue
+		}
+		stages[len(stages)-1] = append(stages[len(stages)-1], t)
+	}
+	return stages
+}
+
+func (q *Query) addTerm(tok token, now time.Time) error {
+	text := tok.text
+	negate := false
+	if !tok.quoted && strings.HasPrefix(text, "-") && len(text) > 1 {
+		negate = true
+		text = text[1:]
+	}
+
+	field, value, ok := strings.Cut(text, ":")
+	if tok.quoted || !ok || field == "" || strings.ContainsAny(field, "*?") {
+		q.filters = append(q.filters, filter{negate: negate, word: strings.ToLower(text)})
+		return nil
+	}
+	field = strings.ToLower(field)
+	if value == "" {
+		return fmt.Errorf("%s: missing value", field)
+	}
+
+	switch field {
+	case "since", "until":
+		if negate {
+			return fmt.Errorf("%s: cannot be negated", field)
+		}
+		t, err := parseTime(value, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if field == "since" {
+			q.Since = t
+		} else {
+			q.Until = t
+		}
+		return nil
+	case "log":
+		for _, l := range strings.Split(value, ",") {
+			if l != LogEvents && l != LogTownlog {
+				return fmt.Errorf("log: unknown log %q (use %s or %s)", l, LogEvents, LogTownlog)
+			}
+		}
+	}
+
+	f := filter{field: field, negate: negate}
+	for _, alt := range strings.Split(value, ",") {
+		f.matches = append(f.matches, globRegexp(alt))
+	}
+	if field == "log" && !negate {
+		q.Logs = strings.Split(value, ",")
+	}
+	q.filters = append(q.filters, f)
+	return nil
+}
+
+// globRegexp compiles a glob where * matches any run of characters
+// (including /) and ? matches one.
+func globRegexp(glob string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(glob)
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	return regexp.MustCompile("(?is)^" + pattern + "$")
+}
+
+// parseTime accepts an age ("2h", "3d"), RFC3339, or a local date
+// ("2026-05-01") or date-time ("2026-05-01T14:00", "2026-05-01 14:00").
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use an age like 2h or 3d, or a date like 2026-05-01)", s)
+}
+
+// parseDuration extends time.ParseDuration with a d (day) suffix.
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid days: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d < 0 {
+		return 0, fmt.Errorf("negative duration: %s", s)
+	}
+	return d, err
+}
+
+// addStage parses one pipeline stage:
+//
+//	count [by field[,field...]] [per <duration>]
+//	limit <n>
+func (q *Query) addStage(stage []token) error {
+	if len(stage) == 0 {
+		return fmt.Errorf("empty pipeline stage")
+	}
+	words := make([]string, len(stage))
+	for i, t := range stage {
+		words[i] = t.text
+	}
+
+	switch strings.ToLower(words[0]) {
+	case "count":
+		if q.Count {
+			return fmt.Errorf("count: only one count stage allowed")
+		}
+		if q.limited {
+			return fmt.Errorf("count: must come before limit")
+		}
+		q.Count = true
+		rest := words[1:]
+		for len(rest) > 0 {
+			if len(rest) < 2 {
+				return fmt.Errorf("count: %q needs an argument", rest[0])
+			}
+			switch strings.ToLower(rest[0]) {
+			case "by":
+				for _, f := range strings.Split(rest[1], ",") {
+					if f != "" {
+						q.By = append(q.By, strings.ToLower(f))
+					}
+				}
+			case "per":
+				d, err := parseDuration(rest[1])
+				if err != nil || d <= 0 {
+					return fmt.Errorf("count: invalid bucket %q", rest[1])
+				}
+				q.Per = d
+			default:
+				return fmt.Errorf("count: unexpected %q (use by <fields> or per <duration>)", rest[0])
+			}
+			rest = rest[2:]
+		}
+	case "limit", "head":
+		if len(words) != 2 {
+			return fmt.Errorf("limit: expected a number")
+		}
+		n, err := strconv.Atoi(words[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("limit: invalid number %q", words[1])
+		}
+		q.Limit = n
+		q.limited = true
+	default:
+		return fmt.Errorf("unknown stage %q (use count or limit)", words[0])
+	}
+	return nil
+}
+
+// Match reports whether a record passes the query's time window and filters.
+func (q *Query) Match(r Record) bool {
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && r.Time.After(q.Until) {
+		return false
+	}
+	for _, f := range q.filters {
+		if !f.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// wantsLog reports whether the query reads the named log.
+func (q *Query) wantsLog(log string) bool {
+	if len(q.Logs) == 0 {
+		return true
+	}
+	for _, l := range q.Logs {
+		if l == log {
+			return true
+		}
+	}
+	return false
+}
+
+// Group is one row of a count stage.
+type Group struct {
+	Keys   []string   `json:"keys,omitempty"`
+	Bucket *time.Time `json:"bucket,omitempty"`
+	Count  int        `json:"count"`
+}
+
+// Result is the outcome of running a query.
+type Result struct {
+	Query   string   `json:"query"`
+	Matched int      `json:"matched"`
+	Records []Record `json:"records,omitempty"`
+	By      []string `json:"by,omitempty"`
+	Per     string   `json:"per,omitempty"`
+	Groups  []Group  `json:"groups,omitempty"`
+	Counted bool     `json:"counted"`
+}
+
+// Execute filters records (oldest first) and applies the pipeline.
+// Without a count stage, limit keeps the most recent records. With one,
+// groups are ordered by bucket, then by descending count, and limit keeps
+// the first n.
+func (q *Query) Execute(records []Record) *Result {
+	var matched []Record
+	for _, r := range records {
+		if q.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Time.Before(matched[j].Time) })
+
+	res := &Result{Query: q.Text, Matched: len(matched), Counted: q.Count, By: q.By}
+	if !q.Count {
+		if q.Limit > 0 && len(matched) > q.Limit {
+			matched = matched[len(matched)-q.Limit:]
+		}
+		res.Records = matched
+		return res
+	}
+
+	if q.Per > 0 {
+		res.Per = q.Per.String()
+	}
+	res.Groups = q.count(matched)
+	if q.Limit > 0 && len(res.Groups) > q.Limit {
+		res.Groups = res.Groups[:q.Limit]
+	}
+	return res
+}
+
+func (q *Query) count(records []Record) []Group {
+	index := make(map[string]int)
+	var groups []Group
+	for _, r := range records {
+		keys := make([]string, len(q.By))
+		for i, f := range q.By {
+			keys[i] = r.Field(f)
+		}
+		var bucket *time.Time
+		if q.Per > 0 {
+			b := r.Time.Truncate(q.Per)
+			bucket = &b
+		}
+
+		id := strings.Join(keys, "\x00")
+		if bucket != nil {
+			id += "\x00" + bucket.Format(time.RFC3339)
+		}
+		if i, ok := index[id]; ok {
+			groups[i].Count++
+			continue
+		}
+		index[id] = len(groups)
+		groups = append(groups, Group{Keys: keys, Bucket: bucket, Count: 1})
+	}
+	if len(q.By) == 0 && q.Per == 0 {
+		// Plain count: a single total row, even when nothing matched.
+		return []Group{{Count: len(records)}}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Bucket != nil && !groups[i].Bucket.Equal(*groups[j].Bucket) {
+			return groups[i].Bucket.Before(*groups[j].Bucket)
+		}
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return strings.Join(groups[i].Keys, "\x00") < strings.Join(groups[j].Keys, "\x00")
+	})
+	return groups
+}
+
+// Load reads the records a query may match from a town's logs. Archived
+// events are included when the query sets since:.
+func Load(townRoot string, q *Query) ([]Record, error) {
+	var records []Record
+
+	if q.wantsLog(LogEvents) {
+		err := krc.ScanEvents(townRoot, events.EventsFile, q.Since, func(line []byte) error {
+			var e events.Event
+			if json.Unmarshal(line, &e) != nil {
+				return nil // skip malformed lines
+			}
+			if r, ok := FromEvent(e); ok {
+				records = append(records, r)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading events: %w", err)
+		}
+	}
+
+	if q.wantsLog(LogTownlog) {
+		logEvents, err := townlog.ReadEvents(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range logEvents {
+			records = append(records, FromTownlog(e))
+		}
+	}
+
+	return records, nil
+}
+
+// Run parses and executes a query against a town's logs.
+func Run(townRoot, text string, now time.Time) (*Result, error) {
+	q, err := Parse(text, now)
+	if err != nil {
+		return nil, fmt.Errorf("parsing query: %w", err)
+	}
+	records, err := Load(townRoot, q)
+	if err != nil {
+		return nil, err
+	}
+	return q.Execute(records), nil
+}
@@ -0,0 +1,170 @@
+package eventquery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+var testNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func testRecords() []Record {
+	return []Record{
+		{Time: testNow.Add(-3 * time.Hour), Log: LogEvents, Type: "sling", Actor: "mayor", Payload: map[string]interface{}{"bead": "gt-1", "rig": "gastown"}},
+		{Time: testNow.Add(-90 * time.Minute), Log: LogEvents, Type: "done", Actor: "gastown/polecats/Toast", Rig: "gastown", Payload: map[string]interface{}{"bead": "gt-1"}},
+		{Time: testNow.Add(-80 * time.Minute), Log: LogEvents, Type: "done", Actor: "gastown/polecats/Nux", Rig: "gastown", Payload: map[string]interface{}{"bead": "gt-2"}},
+		{Time: testNow.Add(-30 * time.Minute), Log: LogEvents, Type: "done", Actor: "beads/polecats/Toast", Rig: "beads", Payload: map[string]interface{}{"bead": "bd-9"}},
+		{Time: testNow.Add(-20 * time.Minute), Log: LogTownlog, Type: "crash", Actor: "gastown/crew/max", Rig: "gastown", Context: "signal 9"},
+		{Time: testNow.Add(-10 * time.Minute), Log: LogEvents, Type: "done", Actor: "gastown/polecats/Toast", Rig: "gastown", Payload: map[string]interface{}{"bead": "gt-3"}},
+	}
+}
+
+func run(t *testing.T, text string) *Result {
+	t.Helper()
+	q, err := Parse(text, testNow)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", text, err)
+	}
+	return q.Execute(testRecords())
+}
+
+func actors(records []Record) []string {
+	var out []string
+	for _, r := range records {
+		out = append(out, r.Actor)
+	}
+	return out
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"type:done actor:*/polecats/* rig:gastown", []string{"gastown/polecats/Toast", "gastown/polecats/Nux", "gastown/polecats/Toast"}},
+		{"type:done since:1h", []string{"beads/polecats/Toast", "gastown/polecats/Toast"}},
+		{"type:done -rig:gastown", []string{"beads/polecats/Toast"}},
+		{"type:SLING,crash", []string{"mayor", "gastown/crew/max"}},
+		{"bead:gt-?", []string{"mayor", "gastown/polecats/Toast", "gastown/polecats/Nux", "gastown/polecats/Toast"}},
+		{`"signal 9"`, []string{"gastown/crew/max"}},
+		{`context:"signal 9"`, []string{"gastown/crew/max"}},
+		{"log:townlog", []string{"gastown/crew/max"}},
+		{"bd-9", []string{"beads/polecats/Toast"}},
+		{"type:done | limit 2", []string{"beads/polecats/Toast", "gastown/polecats/Toast"}},
+	}
+	for _, tt := range tests {
+		if got := actors(run(t, tt.query).Records); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFromEvent(t *testing.T) {
+	r, ok := FromEvent(events.Event{Timestamp: "2026-05-01T10:00:00Z", Type: "sling", Actor: "mayor", Payload: map[string]interface{}{"rig": "gastown"}})
+	if !ok || r.Rig != "gastown" || r.Log != LogEvents {
+		t.Errorf("payload rig: %+v", r)
+	}
+	r, _ = FromEvent(events.Event{Timestamp: "2026-05-01T10:00:00Z", Actor: "beads/polecats/Nux"})
+	if r.Rig != "beads" {
+		t.Errorf("actor rig: %+v", r)
+	}
+	if _, ok := FromEvent(events.Event{Timestamp: "yesterday"}); ok {
+		t.Error("expected bad timestamp to be rejected")
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	res := run(t, "type:done | count by actor")
+	want := []Group{
+		{Keys: []string{"gastown/polecats/Toast"}, Count: 2},
+		{Keys: []string{"beads/polecats/Toast"}, Count: 1},
+		{Keys: []string{"gastown/polecats/Nux"}, Count: 1},
+	}
+	if !res.Counted || res.Matched != 4 || !reflect.DeepEqual(res.Groups, want) {
+		t.Errorf("groups = %+v", res.Groups)
+	}
+
+	res = run(t, "type:done | count by rig | limit 1")
+	if len(res.Groups) != 1 || res.Groups[0].Keys[0] != "gastown" || res.Groups[0].Count != 3 {
+		t.Errorf("limited groups = %+v", res.Groups)
+	}
+
+	res = run(t, "type:nothing | count")
+	if len(res.Groups) != 1 || res.Groups[0].Count != 0 {
+		t.Errorf("plain count = %+v", res.Groups)
+	}
+}
+
+func TestCountPer(t *testing.T) {
+	res := run(t, "type:done | count by rig per 1h")
+	if res.Per != "1h0m0s" || len(res.Groups) != 3 {
+		t.Fatalf("groups = %+v", res.Groups)
+	}
+	// 10:00 bucket: gastown x2; 11:00 bucket: gastown and beads.
+	first, second := res.Groups[0], res.Groups[1]
+	if !first.Bucket.Equal(testNow.Add(-2*time.Hour)) || first.Count != 2 {
+		t.Errorf("first bucket = %v %+v", first.Bucket, first)
+	}
+	if !second.Bucket.Equal(testNow.Add(-time.Hour)) || second.Keys[0] != "beads" {
+		t.Errorf("second bucket = %v %+v", second.Bucket, second)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, q := range []string{
+		`"unterminated`,
+		"since:yesterday",
+		"-since:2h",
+		"log:mail",
+		"type:",
+		"type:done | frobnicate",
+		"type:done | count per",
+		"type:done | limit 0",
+		"type:done | limit 5 | count",
+		"type:done |",
+	} {
+		if _, err := Parse(q, testNow); err == nil {
+			t.Errorf("Parse(%q): expected error", q)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	q, err := Parse("since:2d until:2026-05-01T10:30", testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Since.Equal(testNow.Add(-48*time.Hour)) || !q.Until.Equal(time.Date(2026, 5, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("since %v, until %v", q.Since, q.Until)
+	}
+}
+
+func TestRun(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	lines := `{"ts":"` + now.Add(-time.Hour).Format(time.RFC3339) + `","source":"gt","type":"done","actor":"gastown/polecats/Toast","visibility":"feed"}` + "\n" +
+		"not json\n"
+	if err := os.WriteFile(filepath.Join(townRoot, ".events.jsonl"), []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logDir := filepath.Join(townRoot, "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logLine := now.Add(-time.Hour).Format("2006-01-02 15:04:05") + " [done] gastown/polecats/Nux completed gt-1\n"
+	if err := os.WriteFile(filepath.Join(logDir, "town.log"), []byte(logLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Run(townRoot, "type:done | count by log", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Matched != 2 || len(res.Groups) != 2 {
+		t.Errorf("result = %+v", res)
+	}
+}
@@ -1,7 +1,8 @@
 // Package events provides event logging for the gt activity feed.
 //
 // Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). Audit-visible
+// events are also appended to the tamper-evident chain (see auditlog).
 package events
 
 import (
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/auditlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -68,6 +70,13 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Guard events (emitted by gt tap guard)
+	TypeGuardBlock = "guard_block"
+
+	// Daemon config reload events
+	TypeConfigReloaded = "config_reloaded"
+	TypeConfigRejected = "config_rejected"
 )
 
 // EventsFile is the name of the raw events log.
@@ -135,6 +144,22 @@ func write(event Event) error {
 		return fmt.Errorf("writing event: %w", err)
 	}
 
+	// .events.jsonl is pruned and archived by KRC, so audit events are
+	// also kept in the hash-chained audit log. The event is already written
+	// at this point, so a chain failure is only reported.
+	if event.Visibility == VisibilityAudit || event.Visibility == VisibilityBoth {
+		ts, _ := time.Parse(time.RFC3339, event.Timestamp)
+		if err := auditlog.Append(townRoot, auditlog.Entry{
+			Time:    ts,
+			Source:  "events",
+			Type:    event.Type,
+			Actor:   event.Actor,
+			Payload: event.Payload,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: event %s written but not appended to audit chain: %v\n", event.Type, err)
+		}
+	}
+
 	return nil
 }
 
@@ -313,6 +338,38 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// GuardBlockPayload creates a payload for guard block events.
+// rule: name of the guard rule that blocked the call
+// tool: Claude Code tool name (e.g., "Bash", "Write")
+// reason: which rule condition matched
+// target: the blocked command or file path
+func GuardBlockPayload(rule, tool, reason, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"rule":   rule,
+		"tool":   tool,
+		"reason": reason,
+		"target": target,
+	}
+}
+
+// ConfigReloadPayload creates a payload for daemon config reload events.
+// trigger: what asked for the reload ("control", "signal", "watch")
+// changed: config files that changed since the last reload
+// detail: the validation error for rejected reloads, or settings that
+// only take effect after a daemon restart
+func ConfigReloadPayload(trigger string, changed []string, detail string) map[string]interface{} {
+	p := map[string]interface{}{
+		"trigger": trigger,
+	}
+	if len(changed) > 0 {
+		p["changed"] = changed
+	}
+	if detail != "" {
+		p["detail"] = detail
+	}
+	return p
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
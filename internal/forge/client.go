@@ -0,0 +1,121 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError is a non-2xx response from a forge API.
+type APIError struct {
+	Provider string
+	Method   string
+	Path     string
+	Status   int
+	Message  string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Status)
+	}
+	return fmt.Sprintf("%s API %s %s: HTTP %d: %s", e.Provider, e.Method, e.Path, e.Status, msg)
+}
+
+// IsStatus reports whether err is an APIError with one of the given statuses.
+func IsStatus(err error, statuses ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, s := range statuses {
+		if apiErr.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// apiClient is the JSON-over-HTTP plumbing shared by the providers.
+type apiClient struct {
+	provider string
+	base     string
+	hc       *http.Client
+	header   http.Header // auth and content negotiation, sent on every request
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response
+// into out (if non-nil).
+func (c *apiClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding %s request: %w", c.provider, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return fmt.Errorf("creating %s request: %w", c.provider, err)
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s API %s %s: %w", c.provider, method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("reading %s response: %w", c.provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{
+			Provider: c.provider,
+			Method:   method,
+			Path:     path,
+			Status:   resp.StatusCode,
+			Message:  errorMessage(data),
+		}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding %s response for %s: %w", c.provider, path, err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message from a forge error body. All three
+// forges use a top-level "message"; GitLab sometimes nests it or uses "error".
+func errorMessage(data []byte) string {
+	var body struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return strings.TrimSpace(string(data))
+	}
+	if len(body.Message) > 0 {
+		var s string
+		if json.Unmarshal(body.Message, &s) == nil {
+			return s
+		}
+		return string(body.Message)
+	}
+	return body.Error
+}
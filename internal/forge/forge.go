@@ -0,0 +1,233 @@
+// Package forge talks to the code forge hosting a rig's repository (GitHub,
+// GitLab, or Gitea). The refinery uses it to mirror MR beads as pull
+// requests, relay review comments to polecats, and hold merges until CI
+// checks pass and reviewers approve.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNoToken is returned by New when the configured token variable is unset.
+var ErrNoToken = errors.New("forge token not set")
+
+// Provider is a code forge API.
+type Provider interface {
+	// Name returns the provider name ("github", "gitlab", "gitea").
+	Name() string
+
+	// OpenPR opens a pull request, or returns the open one for the same
+	// head branch if it already exists.
+	OpenPR(ctx context.Context, opts PROptions) (*PullRequest, error)
+
+	// GetPR returns a pull request by number.
+	GetPR(ctx context.Context, number int) (*PullRequest, error)
+
+	// Comments returns the pull request's conversation and review comments
+	// created after since, oldest first. System notes are excluded.
+	Comments(ctx context.Context, number int, since time.Time) ([]Comment, error)
+
+	// Reviews returns the pull request's reviews, oldest first.
+	Reviews(ctx context.Context, number int) ([]Review, error)
+
+	// Checks returns the CI checks and commit statuses reported for sha.
+	Checks(ctx context.Context, sha string) ([]Check, error)
+}
+
+// PROptions describes a pull request to open.
+type PROptions struct {
+	Title string
+	Body  string
+	Head  string // source branch
+	Base  string // target branch
+}
+
+// Pull request states.
+const (
+	PROpen   = "open"
+	PRClosed = "closed"
+	PRMerged = "merged"
+)
+
+// PullRequest is a pull request (merge request on GitLab).
+type PullRequest struct {
+	Number  int    `json:"number"`
+	URL     string `json:"url"`
+	State   string `json:"state"` // open, closed, merged
+	Head    string `json:"head"`
+	Base    string `json:"base"`
+	HeadSHA string `json:"head_sha"`
+}
+
+// Comment is a conversation or inline review comment.
+type Comment struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Path      string    `json:"path,omitempty"` // set for inline comments
+	Line      int       `json:"line,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Review states.
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewCommented        = "commented"
+)
+
+// Review is a reviewer's verdict on a pull request.
+type Review struct {
+	Author      string    `json:"author"`
+	State       string    `json:"state"`
+	Body        string    `json:"body,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Check states.
+const (
+	CheckPending = "pending"
+	CheckSuccess = "success"
+	CheckFailure = "failure"
+)
+
+// Check is a CI check run or commit status.
+type Check struct {
+	Name  string `json:"name"`
+	State string `json:"state"` // pending, success, failure
+	URL   string `json:"url,omitempty"`
+}
+
+// New returns the provider for cfg, reading the API token from the
+// environment.
+func New(cfg *config.ForgeConfig) (Provider, error) {
+	if cfg == nil {
+		return nil, errors.New("no forge configured")
+	}
+	token := os.Getenv(cfg.DefaultTokenEnv())
+	if token == "" {
+		return nil, fmt.Errorf("%w: $%s", ErrNoToken, cfg.DefaultTokenEnv())
+	}
+	return NewWithClient(cfg, token, &http.Client{Timeout: 30 * time.Second})
+}
+
+// NewWithClient returns the provider for cfg using the given token and
+// HTTP client.
+func NewWithClient(cfg *config.ForgeConfig, token string, hc *http.Client) (Provider, error) {
+	switch cfg.Provider {
+	case config.ForgeGitHub:
+		return newGitHub(cfg.Repo, cfg.APIURL, token, hc), nil
+	case config.ForgeGitLab:
+		return newGitLab(cfg.Repo, cfg.APIURL, token, hc), nil
+	case config.ForgeGitea:
+		return newGitea(cfg.Repo, cfg.APIURL, token, hc), nil
+	default:
+		return nil, fmt.Errorf("unknown forge provider %q", cfg.Provider)
+	}
+}
+
+// Verdict states.
+const (
+	VerdictPass    = "pass"
+	VerdictPending = "pending"
+	VerdictFail    = "fail"
+)
+
+// Verdict is whether a pull request may merge under a rig's forge rules.
+type Verdict struct {
+	State  string   `json:"state"`            // pass, pending, fail
+	Failed []string `json:"failed,omitempty"` // failing checks, or reviewers requesting changes
+	Detail string   `json:"detail"`
+}
+
+// Evaluate applies the rig's required checks and approvals to a pull
+// request's checks and reviews. Failed checks fail; missing or running
+// checks and missing approvals are pending; a reviewer whose latest review
+// requests changes fails the verdict when approvals are required.
+func Evaluate(cfg *config.ForgeConfig, checks []Check, reviews []Review) Verdict {
+	var failed, waiting []string
+
+	if len(cfg.RequiredChecks) > 0 {
+		byName := make(map[string]Check, len(checks))
+		for _, c := range checks {
+			// A rerun reports the same name again; later entries win
+			byName[c.Name] = c
+		}
+		required := cfg.RequiredChecks
+		if len(required) == 1 && required[0] == "*" {
+			required = make([]string, 0, len(byName))
+			for name := range byName {
+				required = append(required, name)
+			}
+			sort.Strings(required)
+			if len(required) == 0 {
+				waiting = append(waiting, "no checks reported yet")
+			}
+		}
+		for _, name := range required {
+			c, ok := byName[name]
+			switch {
+			case !ok:
+				waiting = append(waiting, name+" (not reported)")
+			case c.State == CheckFailure:
+				failed = append(failed, name)
+			case c.State != CheckSuccess:
+				waiting = append(waiting, name)
+			}
+		}
+	}
+
+	if cfg.RequiredApprovals > 0 {
+		latest := make(map[string]Review)
+		for _, r := range reviews {
+			if r.State == ReviewCommented {
+				continue // comments don't change a reviewer's verdict
+			}
+			latest[r.Author] = r
+		}
+		approvals := 0
+		for _, author := range sortedAuthors(latest) {
+			switch latest[author].State {
+			case ReviewApproved:
+				approvals++
+			case ReviewChangesRequested:
+				failed = append(failed, "changes requested by "+author)
+			}
+		}
+		if approvals < cfg.RequiredApprovals {
+			waiting = append(waiting, fmt.Sprintf("%d/%d approvals", approvals, cfg.RequiredApprovals))
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return Verdict{State: VerdictFail, Failed: failed, Detail: "failed: " + strings.Join(failed, ", ")}
+	case len(waiting) > 0:
+		return Verdict{State: VerdictPending, Detail: "waiting on " + strings.Join(waiting, ", ")}
+	default:
+		return Verdict{State: VerdictPass, Detail: "checks and reviews satisfied"}
+	}
+}
+
+func sortedAuthors(m map[string]Review) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// sortComments orders comments oldest first.
+func sortComments(cs []Comment) {
+	sort.SliceStable(cs, func(i, j int) bool { return cs[i].CreatedAt.Before(cs[j].CreatedAt) })
+}
@@ -0,0 +1,318 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// cassette is a recorded exchange with a forge API, replayed by a local
+// stand-in server. Interactions with the same method and path are served
+// in order; the last one repeats.
+type cassette struct {
+	Auth struct {
+		Header string `json:"header"`
+		Value  string `json:"value"`
+	} `json:"auth"`
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`            // escaped path, without query
+	Query    map[string]string `json:"query,omitempty"` // must be present in the request query
+	Request  map[string]string `json:"request,omitempty"`
+	Status   int               `json:"status"`
+	Response json.RawMessage   `json:"response"`
+}
+
+// replay starts a stand-in server for testdata/<name>.json and fails the
+// test if a request is unrecorded or a recording goes unused.
+func replay(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("parsing cassette %s: %v", name, err)
+	}
+
+	var mu sync.Mutex
+	used := make([]bool, len(c.Interactions))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if got := r.Header.Get(c.Auth.Header); got != c.Auth.Value {
+			t.Errorf("%s %s: %s header = %q, want %q", r.Method, r.URL, c.Auth.Header, got, c.Auth.Value)
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		match := -1
+		for i, in := range c.Interactions {
+			if in.Method != r.Method || in.Path != r.URL.EscapedPath() || !queryMatches(in.Query, r) {
+				continue
+			}
+			match = i
+			if !used[i] {
+				break
+			}
+		}
+		if match < 0 {
+			t.Errorf("unrecorded request: %s %s", r.Method, r.URL)
+			http.Error(w, `{"message":"not recorded"}`, http.StatusNotImplemented)
+			return
+		}
+		in := c.Interactions[match]
+		used[match] = true
+		if len(in.Request) > 0 {
+			var got map[string]string
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Errorf("%s %s: request body is not a JSON object: %s", r.Method, r.URL, body)
+			}
+			for k, want := range in.Request {
+				if got[k] != want {
+					t.Errorf("%s %s: request %s = %q, want %q", r.Method, r.URL, k, got[k], want)
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(in.Status)
+		_, _ = w.Write(in.Response)
+	}))
+	t.Cleanup(func() {
+		srv.Close()
+		for i, u := range used {
+			if !u {
+				in := c.Interactions[i]
+				t.Errorf("recorded interaction %d (%s %s) was never requested", i, in.Method, in.Path)
+			}
+		}
+	})
+	return srv
+}
+
+func queryMatches(want map[string]string, r *http.Request) bool {
+	q := r.URL.Query()
+	for k, v := range want {
+		if q.Get(k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestProviders_RecordedFixtures(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		provider    string
+		prURL       string
+		inline      bool     // provider reports inline comment positions
+		wantReviews []string // author:state
+	}{
+		{
+			provider:    config.ForgeGitHub,
+			prURL:       "https://github.com/acme/widgets/pull/7",
+			inline:      true,
+			wantReviews: []string{"alice:changes_requested", "bob:commented", "alice:approved"},
+		},
+		{
+			provider:    config.ForgeGitLab,
+			prURL:       "https://gitlab.com/acme/widgets/-/merge_requests/7",
+			inline:      true,
+			wantReviews: []string{"alice:approved"},
+		},
+		{
+			provider:    config.ForgeGitea,
+			prURL:       "https://git.acme.dev/acme/widgets/pulls/7",
+			wantReviews: []string{"alice:changes_requested", "bob:commented", "alice:approved"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			srv := replay(t, tt.provider)
+			cfg := &config.ForgeConfig{Provider: tt.provider, Repo: "acme/widgets", APIURL: srv.URL}
+			p, err := NewWithClient(cfg, "t0ken", srv.Client())
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			opts := PROptions{Title: "Merge: gt-abc", Body: "MR gt-mr1", Head: "polecat/nux/gt-abc", Base: "main"}
+
+			// Opening twice yields the same pull request
+			for i := 0; i < 2; i++ {
+				pr, err := p.OpenPR(ctx, opts)
+				if err != nil {
+					t.Fatalf("OpenPR #%d: %v", i+1, err)
+				}
+				if pr.Number != 7 || pr.URL != tt.prURL || pr.State != PROpen || pr.Head != opts.Head {
+					t.Errorf("OpenPR #%d = %+v", i+1, pr)
+				}
+			}
+
+			pr, err := p.GetPR(ctx, 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pr.HeadSHA != "abc123" || pr.Base != "main" {
+				t.Errorf("GetPR = %+v", pr)
+			}
+
+			comments, err := p.Comments(ctx, 7, since)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(comments) != 2 || comments[0].Author != "alice" || comments[1].Author != "bob" {
+				t.Fatalf("Comments = %+v, want alice then bob", comments)
+			}
+			if !strings.Contains(comments[1].Body, "parseAll") {
+				t.Errorf("bob's comment body = %q", comments[1].Body)
+			}
+			if tt.inline && (comments[1].Path != "main.go" || comments[1].Line != 12) {
+				t.Errorf("inline comment position = %s:%d, want main.go:12", comments[1].Path, comments[1].Line)
+			}
+
+			reviews, err := p.Reviews(ctx, 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range reviews {
+				got = append(got, r.Author+":"+r.State)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantReviews, ",") {
+				t.Errorf("Reviews = %v, want %v", got, tt.wantReviews)
+			}
+
+			checks, err := p.Checks(ctx, "abc123")
+			if err != nil {
+				t.Fatal(err)
+			}
+			states := make(map[string]string)
+			for _, c := range checks {
+				states[c.Name] = c.State
+			}
+			want := map[string]string{"ci/build": CheckSuccess, "lint": CheckFailure, "e2e": CheckPending}
+			for name, state := range want {
+				if states[name] != state {
+					t.Errorf("check %s = %q, want %q (all: %v)", name, states[name], state, states)
+				}
+			}
+
+			v := Evaluate(&config.ForgeConfig{RequiredChecks: []string{"ci/build", "lint"}, RequiredApprovals: 1}, checks, reviews)
+			if v.State != VerdictFail || len(v.Failed) != 1 || v.Failed[0] != "lint" {
+				t.Errorf("Evaluate = %+v, want fail on lint", v)
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer srv.Close()
+
+	p, _ := NewWithClient(&config.ForgeConfig{Provider: config.ForgeGitHub, Repo: "acme/widgets", APIURL: srv.URL}, "t", srv.Client())
+	_, err := p.GetPR(context.Background(), 1)
+	if !IsStatus(err, http.StatusNotFound) {
+		t.Fatalf("err = %v, want a 404 APIError", err)
+	}
+	if !strings.Contains(err.Error(), "Not Found") {
+		t.Errorf("error should carry the API message: %v", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	ok := func(name string) Check { return Check{Name: name, State: CheckSuccess} }
+	tests := []struct {
+		name    string
+		cfg     config.ForgeConfig
+		checks  []Check
+		reviews []Review
+		want    string
+		detail  string
+	}{
+		{
+			name: "nothing required",
+			want: VerdictPass,
+		},
+		{
+			name:   "required check missing",
+			cfg:    config.ForgeConfig{RequiredChecks: []string{"build"}},
+			checks: []Check{ok("lint")},
+			want:   VerdictPending,
+			detail: "build (not reported)",
+		},
+		{
+			name:   "all checks, none reported yet",
+			cfg:    config.ForgeConfig{RequiredChecks: []string{"*"}},
+			want:   VerdictPending,
+			detail: "no checks reported",
+		},
+		{
+			name:   "all checks pass",
+			cfg:    config.ForgeConfig{RequiredChecks: []string{"*"}},
+			checks: []Check{ok("build"), ok("lint")},
+			want:   VerdictPass,
+		},
+		{
+			name:    "approval missing",
+			cfg:     config.ForgeConfig{RequiredApprovals: 2},
+			reviews: []Review{{Author: "alice", State: ReviewApproved}, {Author: "alice", State: ReviewApproved}},
+			want:    VerdictPending,
+			detail:  "1/2 approvals",
+		},
+		{
+			name: "later approval clears change request",
+			cfg:  config.ForgeConfig{RequiredApprovals: 1},
+			reviews: []Review{
+				{Author: "bob", State: ReviewChangesRequested},
+				{Author: "bob", State: ReviewCommented},
+				{Author: "bob", State: ReviewApproved},
+			},
+			want: VerdictPass,
+		},
+		{
+			name: "outstanding change request fails",
+			cfg:  config.ForgeConfig{RequiredApprovals: 1},
+			reviews: []Review{
+				{Author: "alice", State: ReviewApproved},
+				{Author: "bob", State: ReviewChangesRequested},
+			},
+			want:   VerdictFail,
+			detail: "changes requested by bob",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Evaluate(&tt.cfg, tt.checks, tt.reviews)
+			if v.State != tt.want {
+				t.Errorf("State = %s, want %s (%s)", v.State, tt.want, v.Detail)
+			}
+			if tt.detail != "" && !strings.Contains(v.Detail, tt.detail) {
+				t.Errorf("Detail = %q, want containing %q", v.Detail, tt.detail)
+			}
+		})
+	}
+}
+
+func TestNew_RequiresToken(t *testing.T) {
+	t.Setenv("GT_TEST_FORGE_TOKEN", "")
+	_, err := New(&config.ForgeConfig{Provider: config.ForgeGitHub, Repo: "a/b", TokenEnv: "GT_TEST_FORGE_TOKEN"})
+	if err == nil || !strings.Contains(err.Error(), "GT_TEST_FORGE_TOKEN") {
+		t.Errorf("err = %v, want missing token error naming the variable", err)
+	}
+}
@@ -0,0 +1,213 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// gitea implements Provider with the Gitea (and Forgejo) v1 REST API.
+type gitea struct {
+	api   apiClient
+	owner string
+	repo  string
+}
+
+func newGitea(repo, apiURL, token string, hc *http.Client) *gitea {
+	owner, name, _ := strings.Cut(repo, "/")
+	return &gitea{
+		api: apiClient{
+			provider: config.ForgeGitea,
+			base:     strings.TrimSuffix(apiURL, "/"),
+			hc:       hc,
+			header: http.Header{
+				"Authorization": {"token " + token},
+				"Accept":        {"application/json"},
+			},
+		},
+		owner: owner,
+		repo:  name,
+	}
+}
+
+func (g *gitea) Name() string { return config.ForgeGitea }
+
+func (g *gitea) path(format string, args ...any) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(g.owner), url.PathEscape(g.repo)) + fmt.Sprintf(format, args...)
+}
+
+type giteaPR struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *giteaPR) toPR() *PullRequest {
+	state := PROpen
+	switch {
+	case p.Merged:
+		state = PRMerged
+	case p.State == "closed":
+		state = PRClosed
+	}
+	return &PullRequest{Number: p.Number, URL: p.HTMLURL, State: state, Head: p.Head.Ref, Base: p.Base.Ref, HeadSHA: p.Head.SHA}
+}
+
+func (g *gitea) OpenPR(ctx context.Context, opts PROptions) (*PullRequest, error) {
+	var pr giteaPR
+	err := g.api.do(ctx, http.MethodPost, g.path("/pulls"), map[string]string{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+	}, &pr)
+	if err == nil {
+		return pr.toPR(), nil
+	}
+	// 409 (422 on older versions): a pull request already exists for this head
+	if !IsStatus(err, http.StatusConflict, http.StatusUnprocessableEntity) {
+		return nil, err
+	}
+	var open []giteaPR
+	if listErr := g.api.do(ctx, http.MethodGet, g.path("/pulls?state=open&limit=50"), nil, &open); listErr != nil {
+		return nil, listErr
+	}
+	for i := range open {
+		if open[i].Head.Ref == opts.Head && open[i].Base.Ref == opts.Base {
+			return open[i].toPR(), nil
+		}
+	}
+	return nil, err
+}
+
+func (g *gitea) GetPR(ctx context.Context, number int) (*PullRequest, error) {
+	var pr giteaPR
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toPR(), nil
+}
+
+// Comments returns conversation comments and the bodies of reviews. Gitea
+// serves inline review comments per review; they are folded into the
+// review body rather than fetched one review at a time.
+func (g *gitea) Comments(ctx context.Context, number int, since time.Time) ([]Comment, error) {
+	var raw []struct {
+		ID   int64 `json:"id"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Body      string    `json:"body"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	path := g.path("/issues/%d/comments", number)
+	if !since.IsZero() {
+		path += "?since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+	if err := g.api.do(ctx, http.MethodGet, path, nil, &raw); err != nil {
+		return nil, err
+	}
+	var out []Comment
+	for _, c := range raw {
+		if c.CreatedAt.After(since) {
+			out = append(out, Comment{ID: fmt.Sprintf("%d", c.ID), Author: c.User.Login, Body: c.Body, CreatedAt: c.CreatedAt})
+		}
+	}
+
+	reviews, err := g.rawReviews(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reviews {
+		if r.Body == "" || !r.SubmittedAt.After(since) {
+			continue
+		}
+		out = append(out, Comment{ID: fmt.Sprintf("review-%d", r.ID), Author: r.User.Login, Body: r.Body, CreatedAt: r.SubmittedAt})
+	}
+	sortComments(out)
+	return out, nil
+}
+
+type giteaReview struct {
+	ID   int64 `json:"id"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	Dismissed   bool      `json:"dismissed"`
+	Stale       bool      `json:"stale"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+func (g *gitea) rawReviews(ctx context.Context, number int) ([]giteaReview, error) {
+	var raw []giteaReview
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews", number), nil, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (g *gitea) Reviews(ctx context.Context, number int) ([]Review, error) {
+	raw, err := g.rawReviews(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	var out []Review
+	for _, r := range raw {
+		if r.Dismissed {
+			continue
+		}
+		var state string
+		switch r.State {
+		case "APPROVED":
+			state = ReviewApproved
+		case "REQUEST_CHANGES":
+			state = ReviewChangesRequested
+		case "COMMENT":
+			state = ReviewCommented
+		default:
+			continue // PENDING, REQUEST_REVIEW
+		}
+		out = append(out, Review{Author: r.User.Login, State: state, Body: r.Body, SubmittedAt: r.SubmittedAt})
+	}
+	return out, nil
+}
+
+func (g *gitea) Checks(ctx context.Context, sha string) ([]Check, error) {
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			Status    string `json:"status"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/commits/%s/status", url.PathEscape(sha)), nil, &combined); err != nil {
+		return nil, err
+	}
+	var out []Check
+	for _, s := range combined.Statuses {
+		state := CheckPending
+		switch s.Status {
+		case "success", "warning":
+			state = CheckSuccess
+		case "failure", "error":
+			state = CheckFailure
+		}
+		out = append(out, Check{Name: s.Context, State: state, URL: s.TargetURL})
+	}
+	return out, nil
+}
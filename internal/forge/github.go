@@ -0,0 +1,235 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const githubAPI = "https://api.github.com"
+
+// github implements Provider with the GitHub REST API.
+type github struct {
+	api   apiClient
+	owner string
+	repo  string
+}
+
+func newGitHub(repo, apiURL, token string, hc *http.Client) *github {
+	if apiURL == "" {
+		apiURL = githubAPI
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	return &github{
+		api: apiClient{
+			provider: config.ForgeGitHub,
+			base:     strings.TrimSuffix(apiURL, "/"),
+			hc:       hc,
+			header: http.Header{
+				"Authorization":        {"Bearer " + token},
+				"Accept":               {"application/vnd.github+json"},
+				"X-Github-Api-Version": {"2022-11-28"},
+			},
+		},
+		owner: owner,
+		repo:  name,
+	}
+}
+
+func (g *github) Name() string { return config.ForgeGitHub }
+
+func (g *github) path(format string, args ...any) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(g.owner), url.PathEscape(g.repo)) + fmt.Sprintf(format, args...)
+}
+
+type githubPR struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	// MergedAt is set on list responses, which omit "merged"
+	MergedAt *time.Time `json:"merged_at"`
+	Head     struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *githubPR) toPR() *PullRequest {
+	state := PROpen
+	switch {
+	case p.Merged || p.MergedAt != nil:
+		state = PRMerged
+	case p.State == "closed":
+		state = PRClosed
+	}
+	return &PullRequest{Number: p.Number, URL: p.HTMLURL, State: state, Head: p.Head.Ref, Base: p.Base.Ref, HeadSHA: p.Head.SHA}
+}
+
+func (g *github) OpenPR(ctx context.Context, opts PROptions) (*PullRequest, error) {
+	var pr githubPR
+	err := g.api.do(ctx, http.MethodPost, g.path("/pulls"), map[string]string{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+	}, &pr)
+	if err == nil {
+		return pr.toPR(), nil
+	}
+	// 422: a pull request already exists for this head
+	if !IsStatus(err, http.StatusUnprocessableEntity) {
+		return nil, err
+	}
+	var existing []githubPR
+	q := url.Values{"state": {"open"}, "head": {g.owner + ":" + opts.Head}}
+	if listErr := g.api.do(ctx, http.MethodGet, g.path("/pulls?%s", q.Encode()), nil, &existing); listErr != nil {
+		return nil, listErr
+	}
+	if len(existing) == 0 {
+		return nil, err
+	}
+	return existing[0].toPR(), nil
+}
+
+func (g *github) GetPR(ctx context.Context, number int) (*PullRequest, error) {
+	var pr githubPR
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toPR(), nil
+}
+
+type githubComment struct {
+	ID   int64 `json:"id"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Body      string    `json:"body"`
+	Path      string    `json:"path"`
+	Line      int       `json:"line"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (g *github) Comments(ctx context.Context, number int, since time.Time) ([]Comment, error) {
+	q := url.Values{"per_page": {"100"}}
+	if !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	var conversation, inline []githubComment
+	if err := g.api.do(ctx, http.MethodGet, g.path("/issues/%d/comments?%s", number, q.Encode()), nil, &conversation); err != nil {
+		return nil, err
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/comments?%s", number, q.Encode()), nil, &inline); err != nil {
+		return nil, err
+	}
+	var out []Comment
+	for _, c := range append(conversation, inline...) {
+		if !c.CreatedAt.After(since) {
+			continue // since filters on update time; keep only new comments
+		}
+		out = append(out, Comment{
+			ID:        fmt.Sprintf("%d", c.ID),
+			Author:    c.User.Login,
+			Body:      c.Body,
+			Path:      c.Path,
+			Line:      c.Line,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	sortComments(out)
+	return out, nil
+}
+
+func (g *github) Reviews(ctx context.Context, number int) ([]Review, error) {
+	var raw []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State       string    `json:"state"`
+		Body        string    `json:"body"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews?per_page=100", number), nil, &raw); err != nil {
+		return nil, err
+	}
+	var out []Review
+	for _, r := range raw {
+		var state string
+		switch r.State {
+		case "APPROVED":
+			state = ReviewApproved
+		case "CHANGES_REQUESTED":
+			state = ReviewChangesRequested
+		case "COMMENTED":
+			state = ReviewCommented
+		default:
+			continue // PENDING (unsubmitted) and DISMISSED don't count
+		}
+		out = append(out, Review{Author: r.User.Login, State: state, Body: r.Body, SubmittedAt: r.SubmittedAt})
+	}
+	return out, nil
+}
+
+func (g *github) Checks(ctx context.Context, sha string) ([]Check, error) {
+	var status struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"state"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/commits/%s/status", url.PathEscape(sha)), nil, &status); err != nil {
+		return nil, err
+	}
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/commits/%s/check-runs?per_page=100", url.PathEscape(sha)), nil, &runs); err != nil {
+		return nil, err
+	}
+
+	var out []Check
+	for _, s := range status.Statuses {
+		state := CheckPending
+		switch s.State {
+		case "success":
+			state = CheckSuccess
+		case "failure", "error":
+			state = CheckFailure
+		}
+		out = append(out, Check{Name: s.Context, State: state, URL: s.TargetURL})
+	}
+	seen := make(map[string]bool, len(runs.CheckRuns))
+	for _, r := range runs.CheckRuns {
+		// Check runs are listed newest first; a rerun supersedes older runs
+		if seen[r.Name] {
+			continue
+		}
+		seen[r.Name] = true
+		state := CheckPending
+		if r.Status == "completed" {
+			switch r.Conclusion {
+			case "success", "neutral", "skipped":
+				state = CheckSuccess
+			default:
+				state = CheckFailure
+			}
+		}
+		out = append(out, Check{Name: r.Name, State: state, URL: r.HTMLURL})
+	}
+	return out, nil
+}
@@ -0,0 +1,176 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const gitlabAPI = "https://gitlab.com/api/v4"
+
+// gitlab implements Provider with the GitLab v4 REST API. Pull request
+// numbers are merge request IIDs.
+type gitlab struct {
+	api     apiClient
+	project string // URL-escaped project path, usable as :id
+}
+
+func newGitLab(repo, apiURL, token string, hc *http.Client) *gitlab {
+	if apiURL == "" {
+		apiURL = gitlabAPI
+	}
+	return &gitlab{
+		api: apiClient{
+			provider: config.ForgeGitLab,
+			base:     strings.TrimSuffix(apiURL, "/"),
+			hc:       hc,
+			header: http.Header{
+				"Private-Token": {token},
+				"Accept":        {"application/json"},
+			},
+		},
+		project: url.PathEscape(repo),
+	}
+}
+
+func (g *gitlab) Name() string { return config.ForgeGitLab }
+
+func (g *gitlab) path(format string, args ...any) string {
+	return "/projects/" + g.project + fmt.Sprintf(format, args...)
+}
+
+type gitlabMR struct {
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	State        string `json:"state"` // opened, closed, locked, merged
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	SHA          string `json:"sha"`
+}
+
+func (m *gitlabMR) toPR() *PullRequest {
+	state := PROpen
+	switch m.State {
+	case "merged":
+		state = PRMerged
+	case "closed":
+		state = PRClosed
+	}
+	return &PullRequest{Number: m.IID, URL: m.WebURL, State: state, Head: m.SourceBranch, Base: m.TargetBranch, HeadSHA: m.SHA}
+}
+
+func (g *gitlab) OpenPR(ctx context.Context, opts PROptions) (*PullRequest, error) {
+	var mr gitlabMR
+	err := g.api.do(ctx, http.MethodPost, g.path("/merge_requests"), map[string]string{
+		"title":         opts.Title,
+		"description":   opts.Body,
+		"source_branch": opts.Head,
+		"target_branch": opts.Base,
+	}, &mr)
+	if err == nil {
+		return mr.toPR(), nil
+	}
+	// 409: another open merge request exists for this source branch
+	if !IsStatus(err, http.StatusConflict) {
+		return nil, err
+	}
+	var existing []gitlabMR
+	q := url.Values{"state": {"opened"}, "source_branch": {opts.Head}}
+	if listErr := g.api.do(ctx, http.MethodGet, g.path("/merge_requests?%s", q.Encode()), nil, &existing); listErr != nil {
+		return nil, listErr
+	}
+	if len(existing) == 0 {
+		return nil, err
+	}
+	return existing[0].toPR(), nil
+}
+
+func (g *gitlab) GetPR(ctx context.Context, number int) (*PullRequest, error) {
+	var mr gitlabMR
+	if err := g.api.do(ctx, http.MethodGet, g.path("/merge_requests/%d", number), nil, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPR(), nil
+}
+
+func (g *gitlab) Comments(ctx context.Context, number int, since time.Time) ([]Comment, error) {
+	var notes []struct {
+		ID     int64  `json:"id"`
+		Body   string `json:"body"`
+		System bool   `json:"system"`
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		CreatedAt time.Time `json:"created_at"`
+		Position  *struct {
+			NewPath string `json:"new_path"`
+			NewLine int    `json:"new_line"`
+		} `json:"position"`
+	}
+	q := url.Values{"sort": {"asc"}, "order_by": {"created_at"}, "per_page": {"100"}}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/merge_requests/%d/notes?%s", number, q.Encode()), nil, &notes); err != nil {
+		return nil, err
+	}
+	var out []Comment
+	for _, n := range notes {
+		if n.System || !n.CreatedAt.After(since) {
+			continue
+		}
+		c := Comment{ID: fmt.Sprintf("%d", n.ID), Author: n.Author.Username, Body: n.Body, CreatedAt: n.CreatedAt}
+		if n.Position != nil {
+			c.Path, c.Line = n.Position.NewPath, n.Position.NewLine
+		}
+		out = append(out, c)
+	}
+	sortComments(out)
+	return out, nil
+}
+
+// Reviews maps GitLab approvals to approved reviews. GitLab has no
+// "request changes" state in the approvals API; blocking discussions are
+// outside what Reviews reports.
+func (g *gitlab) Reviews(ctx context.Context, number int) ([]Review, error) {
+	var approvals struct {
+		ApprovedBy []struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+		} `json:"approved_by"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/merge_requests/%d/approvals", number), nil, &approvals); err != nil {
+		return nil, err
+	}
+	var out []Review
+	for _, a := range approvals.ApprovedBy {
+		out = append(out, Review{Author: a.User.Username, State: ReviewApproved})
+	}
+	return out, nil
+}
+
+func (g *gitlab) Checks(ctx context.Context, sha string) ([]Check, error) {
+	var statuses []struct {
+		Name      string `json:"name"`
+		Status    string `json:"status"`
+		TargetURL string `json:"target_url"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/repository/commits/%s/statuses?per_page=100", url.PathEscape(sha)), nil, &statuses); err != nil {
+		return nil, err
+	}
+	var out []Check
+	for _, s := range statuses {
+		state := CheckPending
+		switch s.Status {
+		case "success", "skipped":
+			state = CheckSuccess
+		case "failed", "canceled":
+			state = CheckFailure
+		}
+		out = append(out, Check{Name: s.Name, State: state, URL: s.TargetURL})
+	}
+	return out, nil
+}
@@ -369,6 +369,20 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
+// CheckoutNewBranchFrom creates (or resets) branch at startPoint and checks it out.
+// Uses `git checkout -B`, so an existing branch of the same name is overwritten.
+func (g *Git) CheckoutNewBranchFrom(branch, startPoint string) error {
+	_, err := g.run("checkout", "-B", branch, startPoint)
+	return err
+}
+
+// CleanUntracked removes untracked files and directories, keeping ignored
+// files (build caches, installed dependencies) in place.
+func (g *Git) CleanUntracked() error {
+	_, err := g.run("clean", "-fd")
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -443,6 +457,14 @@ func (g *Git) Push(remote, branch string, force bool) error {
 	return err
 }
 
+// PushForceWithLease force-pushes ref to the remote branch, but only if the
+// remote branch is still at expect. Fails instead of clobbering commits
+// someone else pushed in the meantime.
+func (g *Git) PushForceWithLease(remote, ref, branch, expect string) error {
+	_, err := g.run("push", "--force-with-lease="+branch+":"+expect, remote, ref+":refs/heads/"+branch)
+	return err
+}
+
 // PushWithEnv pushes with additional environment variables.
 // Used by gt mq integration land to set GT_INTEGRATION_LAND=1, which the
 // pre-push hook checks to allow integration branch content landing on main.
@@ -684,6 +706,14 @@ func (g *Git) Rebase(onto string) error {
 	return err
 }
 
+// RebaseOnto replays the commits after upstream onto newBase
+// (git rebase --onto newBase upstream). Used to move a stacked branch
+// from its old parent tip to a new one without replaying the parent's commits.
+func (g *Git) RebaseOnto(newBase, upstream string) error {
+	_, err := g.run("rebase", "--onto", newBase, upstream)
+	return err
+}
+
 // AbortMerge aborts a merge in progress.
 func (g *Git) AbortMerge() error {
 	_, err := g.run("merge", "--abort")
@@ -898,6 +928,23 @@ func (g *Git) ListBranches(pattern string) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ListRemoteTrackingBranches returns remote-tracking branches of remote whose
+// names start with prefix, without the remote name (e.g. "polecat/nux/gt-abc").
+func (g *Git) ListRemoteTrackingBranches(remote, prefix string) ([]string, error) {
+	out, err := g.run("for-each-ref", "--format=%(refname)", "refs/remotes/"+remote+"/"+prefix)
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimPrefix(strings.TrimSpace(line), "refs/remotes/"+remote+"/")
+		if name != "" && name != "HEAD" {
+			branches = append(branches, name)
+		}
+	}
+	return branches, nil
+}
+
 // ResetBranch force-updates a branch to point to a ref.
 // This is useful for resetting stale polecat branches to main.
 // NOTE: This uses `git branch -f` which fails on the currently checked-out branch.
@@ -932,6 +979,11 @@ func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	return true, nil
 }
 
+// MergeBase returns the best common ancestor of two refs.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	return g.run("merge-base", a, b)
+}
+
 // WorktreeAdd creates a new worktree at the given path with a new branch.
 // The new branch is created from the current HEAD.
 func (g *Git) WorktreeAdd(path, branch string) error {
@@ -1006,6 +1058,13 @@ func (g *Git) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
+// WorktreeMove moves an existing worktree to a new path, keeping its
+// checkout and untracked/ignored files intact.
+func (g *Git) WorktreeMove(from, to string) error {
+	_, err := g.run("worktree", "move", from, to)
+	return err
+}
+
 // WorktreePrune removes worktree entries for deleted paths.
 func (g *Git) WorktreePrune() error {
 	_, err := g.run("worktree", "prune")
@@ -1112,6 +1171,124 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// FirstParentCommits returns the commits on head's first-parent chain that
+// are not reachable from base, oldest first. An empty base walks back from
+// head without a lower bound. A limit > 0 keeps only the newest limit commits.
+func (g *Git) FirstParentCommits(base, head string, limit int) ([]string, error) {
+	args := []string{"rev-list", "--first-parent", "--reverse"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	if base != "" {
+		args = append(args, base+".."+head)
+	} else {
+		args = append(args, head)
+	}
+	out, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// Revert creates a commit that undoes commit. Merge commits are reverted
+// relative to their first parent.
+func (g *Git) Revert(commit string) error {
+	args := []string{"revert", "--no-edit"}
+	if _, err := g.run("rev-parse", "--verify", "--quiet", commit+"^2"); err == nil {
+		args = append(args, "-m", "1")
+	}
+	_, err := g.run(append(args, commit)...)
+	return err
+}
+
+// DiffLineCount returns the number of lines added plus removed on branch
+// since it diverged from base (i.e. `git diff --shortstat base...branch`).
+func (g *Git) DiffLineCount(base, branch string) (int, error) {
+	out, err := g.run("diff", "--shortstat", base+"..."+branch)
+	if err != nil {
+		return 0, err
+	}
+
+	// e.g. " 3 files changed, 120 insertions(+), 8 deletions(-)"
+	lines := 0
+	for _, part := range strings.Split(out, ",") {
+		var n int
+		var kind string
+		if _, err := fmt.Sscanf(strings.TrimSpace(part), "%d %s", &n, &kind); err != nil {
+			continue
+		}
+		if strings.HasPrefix(kind, "insertion") || strings.HasPrefix(kind, "deletion") {
+			lines += n
+		}
+	}
+	return lines, nil
+}
+
+// LineRange is an inclusive range of line numbers in a file.
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Touches reports whether r and o overlap or are adjacent. Git's merge
+// treats adjacent changes as conflicting, so both count.
+func (r LineRange) Touches(o LineRange) bool {
+	return r.Start <= o.End+1 && o.Start <= r.End+1
+}
+
+// DiffHunks returns the lines each file's changes on branch replace, in base
+// line numbers, since branch diverged from base (`git diff -U0 base...branch`).
+// A pure insertion is recorded as the single line it follows. Renames are
+// reported as a delete plus an add.
+func (g *Git) DiffHunks(base, branch string) (map[string][]LineRange, error) {
+	out, err := g.run("diff", "-U0", "--no-color", "--no-ext-diff", "--no-renames", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiffHunks(out), nil
+}
+
+// parseDiffHunks extracts per-file old-side hunk ranges from unified diff output.
+func parseDiffHunks(out string) map[string][]LineRange {
+	hunks := make(map[string][]LineRange)
+	path := ""
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			// "diff --git a/<path> b/<path>" (same path on both sides with --no-renames)
+			path = ""
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				path = line[i+len(" b/"):]
+				hunks[path] = hunks[path]
+			}
+		case strings.HasPrefix(line, "@@ ") && path != "":
+			// "@@ -start[,count] +start[,count] @@"
+			fields := strings.Fields(line)
+			if len(fields) < 2 || !strings.HasPrefix(fields[1], "-") {
+				continue
+			}
+			var start, count int
+			count = 1
+			old := strings.TrimPrefix(fields[1], "-")
+			if strings.Contains(old, ",") {
+				_, _ = fmt.Sscanf(old, "%d,%d", &start, &count)
+			} else {
+				_, _ = fmt.Sscanf(old, "%d", &start)
+			}
+			end := start + count - 1
+			if count == 0 {
+				end = start
+			}
+			hunks[path] = append(hunks[path], LineRange{Start: start, End: end})
+		}
+	}
+	return hunks
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
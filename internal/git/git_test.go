@@ -1554,3 +1554,132 @@ func TestClearPushURL(t *testing.T) {
 		t.Errorf("ClearPushURL (idempotent) should not error, got: %v", err)
 	}
 }
+
+func TestDiffLineCount(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	// One new line plus a rewritten README line (1 insertion, 1 deletion)
+	commitAs(t, dir, "toast", "a.go", "package a")
+	commitAs(t, dir, "toast", "README.md", "# Changed")
+
+	lines, err := g.DiffLineCount(base, "feature")
+	if err != nil {
+		t.Fatalf("DiffLineCount: %v", err)
+	}
+	if lines != 3 {
+		t.Errorf("DiffLineCount = %d, want 3", lines)
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	commitAs(t, dir, "toast", "README.md", "# Changed")
+	commitAs(t, dir, "toast", "pkg/new.go", "package pkg")
+
+	hunks, err := g.DiffHunks(base, "feature")
+	if err != nil {
+		t.Fatalf("DiffHunks: %v", err)
+	}
+	if got := hunks["README.md"]; len(got) != 1 || got[0] != (LineRange{Start: 1, End: 1}) {
+		t.Errorf("README.md hunks = %v, want [{1 1}]", got)
+	}
+	// New file: insertion at line 0 of the (empty) base
+	if got := hunks["pkg/new.go"]; len(got) != 1 || got[0] != (LineRange{Start: 0, End: 0}) {
+		t.Errorf("pkg/new.go hunks = %v, want [{0 0}]", got)
+	}
+}
+
+func TestFirstParentCommitsAndRevert(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+	commitAs(t, dir, "toast", "a.go", "package a")
+	commitAs(t, dir, "toast", "b.go", "package b")
+	commitAs(t, dir, "toast", "c.go", "package c")
+
+	all, err := g.FirstParentCommits(base, "HEAD", 0)
+	if err != nil {
+		t.Fatalf("FirstParentCommits: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("FirstParentCommits = %v, want 3 commits", all)
+	}
+	head, _ := g.Rev("HEAD")
+	if all[2] != head {
+		t.Errorf("commits should be oldest first, last = %s, want HEAD %s", all[2], head)
+	}
+	newest, err := g.FirstParentCommits("", "HEAD", 2)
+	if err != nil {
+		t.Fatalf("FirstParentCommits with limit: %v", err)
+	}
+	if len(newest) != 2 || newest[0] != all[1] || newest[1] != all[2] {
+		t.Errorf("limit 2 = %v, want %v", newest, all[1:])
+	}
+	if none, err := g.FirstParentCommits("HEAD", "HEAD", 0); err != nil || len(none) != 0 {
+		t.Errorf("empty range = %v, %v; want none", none, err)
+	}
+
+	if err := g.Revert(all[1]); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.go")); !os.IsNotExist(err) {
+		t.Error("reverted commit's file should be gone")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.go")); err != nil {
+		t.Error("later commit's file should remain")
+	}
+}
+
+func TestParseDiffHunks(t *testing.T) {
+	out := `diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -3 +3 @@ func a() {
+-old
++new
+@@ -10,4 +10,0 @@
+-x
+@@ -20,0 +17,2 @@
++y
+diff --git a/img.png b/img.png
+Binary files a/img.png and b/img.png differ`
+	hunks := parseDiffHunks(out)
+	want := []LineRange{{Start: 3, End: 3}, {Start: 10, End: 13}, {Start: 20, End: 20}}
+	if got := hunks["a.go"]; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("a.go hunks = %v, want %v", got, want)
+	}
+	if got, ok := hunks["img.png"]; !ok || len(got) != 0 {
+		t.Errorf("img.png should be listed with no hunks, got %v (present=%v)", got, ok)
+	}
+	if !(LineRange{Start: 1, End: 5}).Touches(LineRange{Start: 6, End: 8}) {
+		t.Error("adjacent ranges should touch")
+	}
+	if (LineRange{Start: 1, End: 5}).Touches(LineRange{Start: 7, End: 8}) {
+		t.Error("separated ranges should not touch")
+	}
+}
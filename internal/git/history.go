@@ -0,0 +1,63 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FilesByAuthor returns the files touched by each commit author in the last
+// maxCommits commits reachable from ref, keyed by author name. Files touched
+// repeatedly are listed once per author.
+func (g *Git) FilesByAuthor(ref string, maxCommits int) (map[string][]string, error) {
+	// %x00 marks the start of each commit so author lines can't be
+	// mistaken for file names.
+	out, err := g.run("log", ref, "-n", strconv.Itoa(maxCommits), "--no-merges", "--format=%x00%an", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	author := ""
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "\x00") {
+			author = strings.TrimPrefix(line, "\x00")
+			if seen[author] == nil {
+				seen[author] = make(map[string]bool)
+			}
+			continue
+		}
+		if author == "" || seen[author][line] {
+			continue
+		}
+		seen[author][line] = true
+		files[author] = append(files[author], line)
+	}
+	return files, nil
+}
+
+// FilesForCommitsMatching returns the files touched by commits reachable from
+// ref whose message contains pattern (a fixed string, e.g. a bead ID).
+func (g *Git) FilesForCommitsMatching(ref, pattern string, maxCommits int) ([]string, error) {
+	out, err := g.run("log", ref, "-n", strconv.Itoa(maxCommits), "--no-merges", "--fixed-strings",
+		"--grep="+pattern, "--format=", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		files = append(files, line)
+	}
+	return files, nil
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func commitAs(t *testing.T, dir, author, file, message string) {
+	t.Helper()
+	path := filepath.Join(dir, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(message+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"add", file},
+		{"commit", "-q", "-m", message},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		// Env, not -c user.name: GIT_AUTHOR_* (set inside polecats) wins over config.
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@test",
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@test",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestFilesByAuthor(t *testing.T) {
+	dir := initTestRepo(t)
+	commitAs(t, dir, "toast", "internal/git/a.go", "feat: a (gt-abc)")
+	commitAs(t, dir, "toast", "internal/git/a.go", "fix: a again")
+	commitAs(t, dir, "nux", "docs/b.md", "docs: b (gt-def)")
+
+	g := NewGit(dir)
+	byAuthor, err := g.FilesByAuthor("HEAD", 100)
+	if err != nil {
+		t.Fatalf("FilesByAuthor: %v", err)
+	}
+	if !slices.Equal(byAuthor["toast"], []string{"internal/git/a.go"}) {
+		t.Errorf("toast files = %v", byAuthor["toast"])
+	}
+	if !slices.Equal(byAuthor["nux"], []string{"docs/b.md"}) {
+		t.Errorf("nux files = %v", byAuthor["nux"])
+	}
+
+	files, err := g.FilesForCommitsMatching("HEAD", "gt-abc", 100)
+	if err != nil {
+		t.Fatalf("FilesForCommitsMatching: %v", err)
+	}
+	if !slices.Equal(files, []string{"internal/git/a.go"}) {
+		t.Errorf("files for gt-abc = %v", files)
+	}
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CloneOptions configures CloneWithOptions.
+type CloneOptions struct {
+	// Reference is a local repo to borrow objects from (--reference-if-able).
+	Reference string
+
+	// Filter is a partial-clone filter (e.g., "blob:none"). Empty clones everything.
+	Filter string
+
+	// Sparse lists cone-mode sparse-checkout directories. Empty checks out everything.
+	Sparse []string
+}
+
+// CloneWithOptions clones a repository with optional object reference,
+// partial-clone filter and cone-mode sparse checkout. With Sparse set, only
+// top-level files and the listed directories are checked out, so blobs
+// outside them are never fetched when combined with a blob filter.
+func (g *Git) CloneWithOptions(url, dest string, opts CloneOptions) error {
+	if opts.Filter == "" && len(opts.Sparse) == 0 {
+		if opts.Reference != "" {
+			return g.CloneWithReference(url, dest, opts.Reference)
+		}
+		return g.Clone(url, dest)
+	}
+
+	destParent := filepath.Dir(dest)
+	if err := os.MkdirAll(destParent, 0755); err != nil {
+		return fmt.Errorf("creating destination parent: %w", err)
+	}
+	// Run clone from a temporary directory to completely isolate from any
+	// git repo at the process cwd. Then move the result to the destination.
+	tmpDir, err := os.MkdirTemp("", "gt-clone-*")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
+	args := []string{"clone"}
+	if runtime.GOOS == "windows" {
+		args = append([]string{"-c", "core.symlinks=true"}, args...)
+	}
+	if opts.Reference != "" {
+		args = append(args, "--reference-if-able", opts.Reference)
+	}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	}
+	if len(opts.Sparse) > 0 {
+		// --sparse starts with only top-level files checked out
+		args = append(args, "--sparse")
+	}
+	args = append(args, url, tmpDest)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return g.wrapError(err, stdout.String(), stderr.String(), args)
+	}
+
+	if len(opts.Sparse) > 0 {
+		if err := NewGit(tmpDest).SparseCheckoutSet(opts.Sparse); err != nil {
+			return err
+		}
+	}
+
+	// Move to final destination (handles cross-filesystem moves)
+	if err := moveDir(tmpDest, dest); err != nil {
+		return fmt.Errorf("moving clone to destination: %w", err)
+	}
+
+	// Configure hooks path for Gas Town clones
+	if err := configureHooksPath(dest); err != nil {
+		return err
+	}
+	// Initialize submodules if present
+	return InitSubmodules(dest)
+}
+
+// WorktreeAddSparse creates a worktree with a new branch from startPoint,
+// checking out only top-level files and the given cone-mode directories.
+// The sparse-checkout config is per-worktree, so other worktrees sharing
+// the repo keep their own checkout shape.
+func (g *Git) WorktreeAddSparse(path, branch, startPoint string, dirs []string) error {
+	if len(dirs) == 0 {
+		return g.WorktreeAddFromRef(path, branch, startPoint)
+	}
+	if _, err := g.run("worktree", "add", "--no-checkout", "-b", branch, path, startPoint); err != nil {
+		return err
+	}
+	wt := NewGit(path)
+	if err := wt.SparseCheckoutSet(dirs); err != nil {
+		return err
+	}
+	// --no-checkout leaves an empty index; populate it within the cone
+	if _, err := wt.run("checkout"); err != nil {
+		return err
+	}
+	return InitSubmodules(path)
+}
+
+// SparseCheckoutSet enables cone-mode sparse checkout restricted to dirs
+// and updates the working tree to match.
+func (g *Git) SparseCheckoutSet(dirs []string) error {
+	args := append([]string{"sparse-checkout", "set", "--cone"}, dirs...)
+	_, err := g.run(args...)
+	return err
+}
+
+// SparseCheckoutDisable restores a full checkout.
+func (g *Git) SparseCheckoutDisable() error {
+	_, err := g.run("sparse-checkout", "disable")
+	return err
+}
+
+// SparseCheckoutList returns the cone-mode directories of a sparse checkout,
+// or nil if the checkout is full.
+func (g *Git) SparseCheckoutList() ([]string, error) {
+	enabled, _ := g.run("config", "--bool", "core.sparseCheckout")
+	if enabled != "true" {
+		return nil, nil
+	}
+	out, err := g.run("sparse-checkout", "list")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// PartialCloneFilter returns the partial-clone filter configured for remote,
+// or "" if the repo is a full clone.
+func (g *Git) PartialCloneFilter(remote string) string {
+	out, _ := g.ConfigGet("remote." + remote + ".partialclonefilter")
+	return out
+}
+
+// EnablePartialClone marks remote as a promisor with the given filter, so
+// later fetches skip objects outside the filter and fetch them on demand.
+// Existing objects are kept. No-op if the filter is already configured.
+func (g *Git) EnablePartialClone(remote, filter string) error {
+	if filter == "" || g.PartialCloneFilter(remote) == filter {
+		return nil
+	}
+	for _, kv := range [][2]string{
+		{"remote." + remote + ".promisor", "true"},
+		{"remote." + remote + ".partialclonefilter", filter},
+	} {
+		if _, err := g.run("config", kv[0], kv[1]); err != nil {
+			return fmt.Errorf("configuring partial clone: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// initMonorepo creates a repo with two service directories and a shared lib.
+func initMonorepo(t *testing.T) string {
+	t.Helper()
+	dir := initTestRepo(t)
+	for _, f := range []string{"services/billing/main.go", "services/web/main.go", "libs/common/util.go"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "services"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestWorktreeAddSparse(t *testing.T) {
+	repo := initMonorepo(t)
+	g := NewGit(repo)
+	wt := filepath.Join(t.TempDir(), "wt")
+
+	if err := g.WorktreeAddSparse(wt, "feature", "HEAD", []string{"services/billing"}); err != nil {
+		t.Fatalf("WorktreeAddSparse: %v", err)
+	}
+
+	if !exists(filepath.Join(wt, "README.md")) || !exists(filepath.Join(wt, "services/billing/main.go")) {
+		t.Error("expected top-level files and cone directory to be checked out")
+	}
+	if exists(filepath.Join(wt, "services/web")) || exists(filepath.Join(wt, "libs")) {
+		t.Error("directories outside the cone should not be checked out")
+	}
+
+	wtGit := NewGit(wt)
+	dirs, err := wtGit.SparseCheckoutList()
+	if err != nil {
+		t.Fatalf("SparseCheckoutList: %v", err)
+	}
+	if !slices.Equal(dirs, []string{"services/billing"}) {
+		t.Errorf("SparseCheckoutList = %v", dirs)
+	}
+	if status, err := wtGit.Status(); err != nil || !status.Clean {
+		t.Errorf("sparse worktree should be clean: %+v err=%v", status, err)
+	}
+
+	// The main checkout is unaffected (sparse config is per-worktree)
+	if dirs, _ := g.SparseCheckoutList(); dirs != nil {
+		t.Errorf("main checkout became sparse: %v", dirs)
+	}
+
+	if err := wtGit.SparseCheckoutDisable(); err != nil {
+		t.Fatalf("SparseCheckoutDisable: %v", err)
+	}
+	if !exists(filepath.Join(wt, "services/web/main.go")) {
+		t.Error("disable should restore a full checkout")
+	}
+}
+
+func TestCloneWithOptionsSparseFiltered(t *testing.T) {
+	repo := initMonorepo(t)
+	// Filters need a transport that supports them
+	cmd := exec.Command("git", "config", "uploadpack.allowFilter", "true")
+	cmd.Dir = repo
+	_ = cmd.Run()
+
+	dest := filepath.Join(t.TempDir(), "crew", "max")
+	err := NewGit("").CloneWithOptions("file://"+repo, dest, CloneOptions{
+		Filter: "blob:none",
+		Sparse: []string{"services/web", "libs/common"},
+	})
+	if err != nil {
+		t.Fatalf("CloneWithOptions: %v", err)
+	}
+
+	if !exists(filepath.Join(dest, "services/web/main.go")) || !exists(filepath.Join(dest, "libs/common/util.go")) {
+		t.Error("cone directories missing from sparse clone")
+	}
+	if exists(filepath.Join(dest, "services/billing")) {
+		t.Error("services/billing should not be checked out")
+	}
+	if got := NewGit(dest).PartialCloneFilter("origin"); got != "blob:none" {
+		t.Errorf("PartialCloneFilter = %q, want blob:none", got)
+	}
+}
+
+func TestEnablePartialClone(t *testing.T) {
+	repo := initTestRepo(t)
+	g := NewGit(repo)
+
+	if got := g.PartialCloneFilter("origin"); got != "" {
+		t.Fatalf("fresh repo has filter %q", got)
+	}
+	if err := g.EnablePartialClone("origin", "blob:none"); err != nil {
+		t.Fatalf("EnablePartialClone: %v", err)
+	}
+	if got := g.PartialCloneFilter("origin"); got != "blob:none" {
+		t.Errorf("PartialCloneFilter = %q, want blob:none", got)
+	}
+	if promisor, _ := g.ConfigGet("remote.origin.promisor"); promisor != "true" {
+		t.Errorf("remote.origin.promisor = %q, want true", promisor)
+	}
+	// Empty filter is a no-op
+	if err := g.EnablePartialClone("origin", ""); err != nil {
+		t.Errorf("EnablePartialClone(\"\"): %v", err)
+	}
+}
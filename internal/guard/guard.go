@@ -0,0 +1,280 @@
+// Package guard provides the policy engine behind `gt tap guard`.
+//
+// Policies are declarative rules loaded per role and rig. Each rule names
+// the tools it applies to and the conditions that block a call: command
+// regexes for Bash, path globs for file-writing tools, or a worktree
+// boundary check. The PreToolUse hook evaluates the applicable rules and
+// exits with code 2 when one of them matches.
+package guard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultTools is the tool set a rule applies to when it declares command
+// patterns but no explicit tools.
+var DefaultTools = []string{"Bash"}
+
+// FileTools are the Claude Code tools that write to a file path.
+var FileTools = []string{"Write", "Edit", "MultiEdit", "NotebookEdit"}
+
+// Rule is a single declarative guard rule.
+type Rule struct {
+	// Name uniquely identifies the rule within the merged policy.
+	Name string `toml:"name"`
+
+	// Description explains what the rule protects against.
+	Description string `toml:"description,omitempty"`
+
+	// Message is shown to the agent when the rule blocks a tool call.
+	Message string `toml:"message,omitempty"`
+
+	// Tools lists the tool names the rule applies to (e.g., "Bash", "Write").
+	// Empty means Bash for command rules and FileTools for path rules.
+	Tools []string `toml:"tools,omitempty"`
+
+	// Roles restricts the rule to specific roles (polecat, crew, witness, ...).
+	// Empty means all roles.
+	Roles []string `toml:"roles,omitempty"`
+
+	// Rigs restricts the rule to specific rigs. Empty means all rigs.
+	Rigs []string `toml:"rigs,omitempty"`
+
+	// Commands are regular expressions matched against the Bash command.
+	Commands []string `toml:"commands,omitempty"`
+
+	// Paths are glob patterns matched against the target file path.
+	// A trailing "/**" matches everything under the directory, and a
+	// leading "~/" expands to the user's home directory.
+	Paths []string `toml:"paths,omitempty"`
+
+	// OutsideWorktree blocks file writes that resolve outside the
+	// agent's git worktree.
+	OutsideWorktree bool `toml:"outside_worktree,omitempty"`
+
+	// Disabled turns off a rule inherited from an earlier policy layer.
+	Disabled bool `toml:"disabled,omitempty"`
+
+	// commandRes caches the compiled Commands patterns.
+	commandRes []*regexp.Regexp
+}
+
+// Input is the PreToolUse hook payload Claude Code writes to stdin.
+type Input struct {
+	SessionID string                 `json:"session_id,omitempty"`
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input"`
+	Cwd       string                 `json:"cwd,omitempty"`
+}
+
+// Command returns the Bash command of the tool call, if any.
+func (in *Input) Command() string {
+	s, _ := in.ToolInput["command"].(string)
+	return s
+}
+
+// FilePath returns the file path the tool call targets, if any.
+func (in *Input) FilePath() string {
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if s, ok := in.ToolInput[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// Context describes the agent a tool call is evaluated for.
+type Context struct {
+	Role     string // polecat, crew, witness, refinery, mayor, deacon
+	Rig      string // rig name, empty for town-level agents
+	Worktree string // absolute path of the agent's git worktree
+}
+
+// Decision is the outcome of evaluating a tool call against a policy.
+type Decision struct {
+	Blocked bool
+	Rule    *Rule
+	Reason  string // which condition matched, e.g. the command pattern
+}
+
+// Validate checks that the rule is well-formed.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule missing name")
+	}
+	if len(r.Commands) == 0 && len(r.Paths) == 0 && !r.OutsideWorktree {
+		return fmt.Errorf("rule %q has no conditions (commands, paths, or outside_worktree)", r.Name)
+	}
+	if _, err := r.commandRegexps(); err != nil {
+		return err
+	}
+	for _, pattern := range r.Paths {
+		if _, err := filepath.Match(strings.TrimSuffix(expandHome(pattern), "/**"), ""); err != nil {
+			return fmt.Errorf("rule %q: invalid path pattern %q: %w", r.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+// AppliesTo reports whether the rule is in scope for the given agent context.
+func (r *Rule) AppliesTo(ctx Context) bool {
+	if r.Disabled {
+		return false
+	}
+	if len(r.Roles) > 0 && !contains(r.Roles, ctx.Role) {
+		return false
+	}
+	if len(r.Rigs) > 0 && !contains(r.Rigs, ctx.Rig) {
+		return false
+	}
+	return true
+}
+
+// tools returns the effective tool list for the rule.
+func (r *Rule) tools() []string {
+	if len(r.Tools) > 0 {
+		return r.Tools
+	}
+	var tools []string
+	if len(r.Commands) > 0 {
+		tools = append(tools, DefaultTools...)
+	}
+	if len(r.Paths) > 0 || r.OutsideWorktree {
+		tools = append(tools, FileTools...)
+	}
+	return tools
+}
+
+// Match evaluates a single tool call against the rule, ignoring role and
+// rig scoping. It returns a non-empty reason when the call is blocked.
+func (r *Rule) Match(in *Input, ctx Context) (string, error) {
+	if !contains(r.tools(), in.ToolName) {
+		return "", nil
+	}
+
+	if cmd := in.Command(); cmd != "" {
+		res, err := r.commandRegexps()
+		if err != nil {
+			return "", err
+		}
+		for i, re := range res {
+			if re.MatchString(cmd) {
+				return fmt.Sprintf("command matches %q", r.Commands[i]), nil
+			}
+		}
+	}
+
+	path := in.FilePath()
+	if path == "" {
+		return "", nil
+	}
+	abs := resolvePath(path, in.Cwd)
+
+	for _, pattern := range r.Paths {
+		if matchPath(pattern, abs) {
+			return fmt.Sprintf("path %s matches %q", abs, pattern), nil
+		}
+	}
+
+	if r.OutsideWorktree && ctx.Worktree != "" && !within(abs, ctx.Worktree) {
+		return fmt.Sprintf("path %s is outside worktree %s", abs, ctx.Worktree), nil
+	}
+
+	return "", nil
+}
+
+// commandRegexps compiles the rule's command patterns on first use; rules
+// loaded through LoadPolicy are compiled once by Validate.
+func (r *Rule) commandRegexps() ([]*regexp.Regexp, error) {
+	if len(r.commandRes) == len(r.Commands) {
+		return r.commandRes, nil
+	}
+	res := make([]*regexp.Regexp, 0, len(r.Commands))
+	for _, pattern := range r.Commands {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid command pattern %q: %w", r.Name, pattern, err)
+		}
+		res = append(res, re)
+	}
+	r.commandRes = res
+	return res, nil
+}
+
+// Evaluate checks a tool call against every rule in scope and returns the
+// first blocking decision. Rules are evaluated in policy order.
+func Evaluate(rules []Rule, in *Input, ctx Context) (Decision, error) {
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.AppliesTo(ctx) {
+			continue
+		}
+		reason, err := rule.Match(in, ctx)
+		if err != nil {
+			return Decision{}, err
+		}
+		if reason != "" {
+			return Decision{Blocked: true, Rule: rule, Reason: reason}, nil
+		}
+	}
+	return Decision{}, nil
+}
+
+// resolvePath makes path absolute relative to cwd and cleans it.
+func resolvePath(path, cwd string) string {
+	path = expandHome(path)
+	if !filepath.IsAbs(path) && cwd != "" {
+		path = filepath.Join(cwd, path)
+	}
+	return filepath.Clean(path)
+}
+
+// matchPath reports whether abs matches a guard path pattern.
+func matchPath(pattern, abs string) bool {
+	pattern = expandHome(pattern)
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return within(abs, filepath.Clean(dir))
+	}
+	if ok, _ := filepath.Match(pattern, abs); ok {
+		return true
+	}
+	// Patterns without a directory component match the base name,
+	// so "*.pem" blocks key files anywhere.
+	if !strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, filepath.Base(abs))
+		return ok
+	}
+	return false
+}
+
+// within reports whether path is dir or lies underneath it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// expandHome expands a leading "~/" to the user's home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
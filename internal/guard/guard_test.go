@@ -0,0 +1,254 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func bashInput(command string) *Input {
+	return &Input{ToolName: "Bash", ToolInput: map[string]interface{}{"command": command}}
+}
+
+func writeInput(path, cwd string) *Input {
+	return &Input{ToolName: "Write", ToolInput: map[string]interface{}{"file_path": path}, Cwd: cwd}
+}
+
+func TestBuiltinPolicy(t *testing.T) {
+	policy, err := LoadPolicy("", "")
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+
+	tests := []struct {
+		rule    string
+		command string
+		blocked bool
+	}{
+		{"no-force-push", "git push --force origin main", true},
+		{"no-force-push", "git push -f origin master", true},
+		{"no-force-push", "git push origin main --force-with-lease", true},
+		{"no-force-push", "git push origin +main", true},
+		{"no-force-push", "git push origin main", false},
+		{"no-force-push", "git push --force origin polecat/toast", false},
+		{"no-rm-rf-root", "rm -rf /", true},
+		{"no-rm-rf-root", "rm -rf ~", true},
+		{"no-rm-rf-root", "rm -fr $HOME/", true},
+		{"no-rm-rf-root", "rm -rf ./build", false},
+		{"no-rm-rf-root", "rm -rf /tmp/scratch", false},
+		{"no-network-tools", "curl https://example.com", true},
+		{"no-network-tools", "go build ./... && wget -q https://example.com/x", true},
+		{"no-network-tools", "echo $(ssh host cat /etc/passwd)", true},
+		{"no-network-tools", "sudo nc -l 8080", true},
+		{"no-network-tools", "git push origin polecat/toast", false},
+		{"no-network-tools", "grep -rn curl internal/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			rule := policy.Find(tt.rule)
+			if rule == nil {
+				t.Fatalf("built-in rule %q not found", tt.rule)
+			}
+			reason, err := rule.Match(bashInput(tt.command), Context{})
+			if err != nil {
+				t.Fatalf("Match: %v", err)
+			}
+			if got := reason != ""; got != tt.blocked {
+				t.Errorf("%s on %q: blocked=%v, want %v (reason %q)", tt.rule, tt.command, got, tt.blocked, reason)
+			}
+		})
+	}
+}
+
+func TestBuiltinPolicyScopedToPolecats(t *testing.T) {
+	policy, err := LoadPolicy("", "")
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	polecat := Context{Role: "polecat", Rig: "gastown", Worktree: "/town/gastown/polecats/toast/gastown"}
+	crew := Context{Role: "crew", Rig: "gastown", Worktree: "/town/gastown/crew/max/gastown"}
+
+	for _, tt := range []struct {
+		in      *Input
+		ctx     Context
+		blocked string
+	}{
+		{writeInput("/tmp/notes.md", polecat.Worktree), polecat, "worktree-only"},
+		{writeInput("main.go", polecat.Worktree), polecat, ""},
+		{writeInput("/tmp/notes.md", crew.Worktree), crew, ""},
+		{bashInput("curl https://example.com"), polecat, "no-network-tools"},
+		{bashInput("curl https://example.com"), crew, ""},
+	} {
+		d, err := Evaluate(policy.Rules, tt.in, tt.ctx)
+		if err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+		got := ""
+		if d.Blocked {
+			got = d.Rule.Name
+		}
+		if got != tt.blocked {
+			t.Errorf("%s %s%s as %s: blocked by %q, want %q", tt.in.ToolName, tt.in.Command(), tt.in.FilePath(), tt.ctx.Role, got, tt.blocked)
+		}
+	}
+}
+
+func TestRuleScope(t *testing.T) {
+	rule := Rule{Name: "r", Roles: []string{"polecat"}, Rigs: []string{"gastown"}, Commands: []string{"curl"}}
+
+	if !rule.AppliesTo(Context{Role: "polecat", Rig: "gastown"}) {
+		t.Error("expected rule to apply to gastown polecat")
+	}
+	if rule.AppliesTo(Context{Role: "crew", Rig: "gastown"}) {
+		t.Error("expected rule not to apply to crew")
+	}
+	if rule.AppliesTo(Context{Role: "polecat", Rig: "beads"}) {
+		t.Error("expected rule not to apply to other rig")
+	}
+
+	rule.Disabled = true
+	if rule.AppliesTo(Context{Role: "polecat", Rig: "gastown"}) {
+		t.Error("disabled rule should never apply")
+	}
+}
+
+func TestOutsideWorktree(t *testing.T) {
+	worktree := t.TempDir()
+	rule := Rule{Name: "worktree-only", OutsideWorktree: true}
+	ctx := Context{Worktree: worktree}
+
+	tests := []struct {
+		path    string
+		blocked bool
+	}{
+		{filepath.Join(worktree, "main.go"), false},
+		{"internal/cmd/x.go", false},
+		{"../escape.go", true},
+		{"/etc/passwd", true},
+	}
+	for _, tt := range tests {
+		reason, err := rule.Match(writeInput(tt.path, worktree), ctx)
+		if err != nil {
+			t.Fatalf("Match: %v", err)
+		}
+		if got := reason != ""; got != tt.blocked {
+			t.Errorf("write %q: blocked=%v, want %v", tt.path, got, tt.blocked)
+		}
+	}
+
+	// Bash calls are not file writes and are out of scope for path rules.
+	if reason, _ := rule.Match(bashInput("echo hi > /etc/motd"), ctx); reason != "" {
+		t.Errorf("path rule should not match Bash, got %q", reason)
+	}
+}
+
+func TestPathPatterns(t *testing.T) {
+	rule := Rule{Name: "secrets", Paths: []string{"*.pem", "/etc/**"}}
+
+	tests := []struct {
+		path    string
+		blocked bool
+	}{
+		{"/work/keys/server.pem", true},
+		{"/etc/hosts", true},
+		{"/etc", true},
+		{"/etcetera/file", false},
+		{"/work/main.go", false},
+	}
+	for _, tt := range tests {
+		reason, err := rule.Match(writeInput(tt.path, "/"), Context{})
+		if err != nil {
+			t.Fatalf("Match: %v", err)
+		}
+		if got := reason != ""; got != tt.blocked {
+			t.Errorf("write %q: blocked=%v, want %v", tt.path, got, tt.blocked)
+		}
+	}
+}
+
+func TestLoadPolicyLayers(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	writePolicy(t, TownPolicyPath(townRoot), `
+[[rule]]
+name = "no-network-tools"
+roles = ["polecat"]
+commands = ['\bcurl\s']
+
+[[rule]]
+name = "no-rm-rf-root"
+disabled = true
+`)
+	writePolicy(t, RigPolicyPath(rigPath), `
+[[rule]]
+name = "no-network-tools"
+disabled = true
+`)
+
+	town, err := LoadPolicy(townRoot, "")
+	if err != nil {
+		t.Fatalf("LoadPolicy(town): %v", err)
+	}
+	if r := town.Find("no-network-tools"); r == nil || r.Disabled {
+		t.Fatalf("expected town rule no-network-tools enabled, got %+v", r)
+	}
+	if r := town.Find("no-rm-rf-root"); r == nil || !r.Disabled {
+		t.Errorf("expected town layer to disable no-rm-rf-root, got %+v", r)
+	}
+	if r := town.Find("no-force-push"); r == nil || r.Disabled {
+		t.Errorf("expected built-in no-force-push to survive, got %+v", r)
+	}
+
+	d, err := Evaluate(town.Rules, bashInput("curl https://example.com"), Context{Role: "polecat", Rig: "gastown"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if !d.Blocked || d.Rule.Name != "no-network-tools" {
+		t.Errorf("expected no-network-tools block, got %+v", d)
+	}
+
+	rig, err := LoadPolicy(townRoot, rigPath)
+	if err != nil {
+		t.Fatalf("LoadPolicy(rig): %v", err)
+	}
+	d, err = Evaluate(rig.Rules, bashInput("curl https://example.com"), Context{Role: "polecat", Rig: "gastown"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if d.Blocked {
+		t.Errorf("rig layer should disable no-network-tools, got block by %s", d.Rule.Name)
+	}
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	townRoot := t.TempDir()
+
+	writePolicy(t, TownPolicyPath(townRoot), `
+[[rule]]
+name = "empty"
+`)
+	if _, err := LoadPolicy(townRoot, ""); err == nil {
+		t.Error("expected error for rule without conditions")
+	}
+
+	writePolicy(t, TownPolicyPath(townRoot), `
+[[rule]]
+name = "bad-regex"
+commands = ['(unclosed']
+`)
+	if _, err := LoadPolicy(townRoot, ""); err == nil {
+		t.Error("expected error for invalid command regex")
+	}
+}
+
+func writePolicy(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,125 @@
+package guard
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed policies/*.toml
+var defaultPoliciesFS embed.FS
+
+// PolicyFile is the file name of a town- or rig-level guard policy.
+const PolicyFile = "policy.toml"
+
+// Policy is an ordered set of guard rules.
+type Policy struct {
+	Rules []Rule `toml:"rule"`
+}
+
+// Find returns the rule with the given name, or nil.
+func (p *Policy) Find(name string) *Rule {
+	for i := range p.Rules {
+		if p.Rules[i].Name == name {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// TownPolicyPath returns the town-level guard policy path.
+func TownPolicyPath(townRoot string) string {
+	return filepath.Join(townRoot, "guards", PolicyFile)
+}
+
+// RigPolicyPath returns the rig-level guard policy path.
+func RigPolicyPath(rigPath string) string {
+	return filepath.Join(rigPath, "guards", PolicyFile)
+}
+
+// LoadPolicy loads the effective guard policy with override resolution.
+// Resolution order (later overrides earlier):
+//  1. Built-in defaults (embedded in binary)
+//  2. Town-level policy (<town>/guards/policy.toml)
+//  3. Rig-level policy (<rig>/guards/policy.toml)
+//
+// A rule in a later layer replaces the rule with the same name from an
+// earlier layer; new names are appended. Set disabled = true to turn off
+// an inherited rule.
+func LoadPolicy(townRoot, rigPath string) (*Policy, error) {
+	policy, err := loadBuiltinPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("loading built-in guard policy: %w", err)
+	}
+
+	var paths []string
+	if townRoot != "" {
+		paths = append(paths, TownPolicyPath(townRoot))
+	}
+	if rigPath != "" {
+		paths = append(paths, RigPolicyPath(rigPath))
+	}
+
+	for _, path := range paths {
+		layer, err := LoadPolicyFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		mergePolicy(policy, layer)
+	}
+
+	return policy, nil
+}
+
+// LoadPolicyFile loads and validates a single policy file.
+// Returns an os.IsNotExist error if the file doesn't exist.
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	return parsePolicy(path, data)
+}
+
+// loadBuiltinPolicy loads the embedded default guard policy.
+func loadBuiltinPolicy() (*Policy, error) {
+	data, err := defaultPoliciesFS.ReadFile("policies/default.toml")
+	if err != nil {
+		return nil, err
+	}
+	return parsePolicy("policies/default.toml", data)
+}
+
+func parsePolicy(name string, data []byte) (*Policy, error) {
+	var p Policy
+	if err := toml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	for i := range p.Rules {
+		// Disabled entries only need a name to identify the rule they turn off.
+		if p.Rules[i].Disabled && p.Rules[i].Name != "" {
+			continue
+		}
+		if err := p.Rules[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return &p, nil
+}
+
+// mergePolicy layers override on top of base, replacing rules by name.
+func mergePolicy(base, override *Policy) {
+	for _, rule := range override.Rules {
+		if existing := base.Find(rule.Name); existing != nil {
+			*existing = rule
+			continue
+		}
+		base.Rules = append(base.Rules, rule)
+	}
+}
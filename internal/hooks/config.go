@@ -525,6 +525,20 @@ func DefaultBase() *HooksConfig {
 					Command: fmt.Sprintf("%s && gt tap guard pr-workflow", pathSetup),
 				}},
 			},
+			{
+				Matcher: "Bash",
+				Hooks: []Hook{{
+					Type:    "command",
+					Command: fmt.Sprintf("%s && gt tap guard policy", pathSetup),
+				}},
+			},
+			{
+				Matcher: "Write|Edit|MultiEdit|NotebookEdit",
+				Hooks: []Hook{{
+					Type:    "command",
+					Command: fmt.Sprintf("%s && gt tap guard policy", pathSetup),
+				}},
+			},
 		},
 		SessionStart: []HookEntry{
 			{
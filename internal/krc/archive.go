@@ -0,0 +1,337 @@
+// Package krc provides the Key Record Chronicle - configurable TTL management
+// and auto-pruning for Level 0 ephemeral operational data.
+//
+// This file implements the cold tier. Events past their (hot) TTL are moved
+// out of .events.jsonl/.feed.jsonl into gzip-compressed segments partitioned
+// by source and UTC day, and only deleted once past the cold TTL:
+//
+//	.krc-archive/
+//	  index.json                 # segment list: counts, first/last event times
+//	  events/2026-05-01.jsonl.gz
+//	  feed/2026-05-01.jsonl.gz
+//
+// Each prune appends a new gzip member to the day's segment; readers see the
+// members as one stream.
+package krc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveDir is the directory, relative to the town root, holding archived events.
+const ArchiveDir = ".krc-archive"
+
+const (
+	archiveIndexFile  = "index.json"
+	segmentDateFormat = "2006-01-02"
+)
+
+// Segment describes one compressed day of archived events from one source.
+type Segment struct {
+	Source string    `json:"source"` // "events" or "feed"
+	Date   string    `json:"date"`   // UTC day, YYYY-MM-DD
+	File   string    `json:"file"`   // path relative to ArchiveDir
+	Events int       `json:"events"`
+	First  time.Time `json:"first"`
+	Last   time.Time `json:"last"`
+	Bytes  int64     `json:"bytes"`
+}
+
+// ArchiveIndex lists the archive's segments so readers can skip days
+// outside the window they need without decompressing them.
+type ArchiveIndex struct {
+	Segments []Segment `json:"segments"`
+}
+
+// ArchiveStats summarizes the cold tier.
+type ArchiveStats struct {
+	Segments    int       `json:"segments"`
+	Events      int       `json:"events"`
+	Bytes       int64     `json:"bytes"`
+	OldestEvent time.Time `json:"oldest_event,omitempty"`
+}
+
+// archivedLine is an event line on its way to the archive.
+type archivedLine struct {
+	ts   time.Time
+	line string
+}
+
+// ArchivePath returns the archive directory for a town.
+func ArchivePath(townRoot string) string {
+	return filepath.Join(townRoot, ArchiveDir)
+}
+
+// ArchiveSource names the archive partition for a hot file,
+// e.g. ".events.jsonl" -> "events".
+func ArchiveSource(fileName string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(filepath.Base(fileName), "."), ".jsonl")
+}
+
+// LoadArchiveIndex reads the archive index. Returns an empty index if the
+// town has no archive yet.
+func LoadArchiveIndex(townRoot string) (*ArchiveIndex, error) {
+	data, err := os.ReadFile(filepath.Join(ArchivePath(townRoot), archiveIndexFile))
+	if os.IsNotExist(err) {
+		return &ArchiveIndex{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading archive index: %w", err)
+	}
+
+	var index ArchiveIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing archive index: %w", err)
+	}
+	return &index, nil
+}
+
+// saveArchiveIndex writes the index atomically (temp file and rename).
+func saveArchiveIndex(townRoot string, index *ArchiveIndex) error {
+	sort.Slice(index.Segments, func(i, j int) bool {
+		if index.Segments[i].Date != index.Segments[j].Date {
+			return index.Segments[i].Date < index.Segments[j].Date
+		}
+		return index.Segments[i].Source < index.Segments[j].Source
+	})
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling archive index: %w", err)
+	}
+
+	path := filepath.Join(ArchivePath(townRoot), archiveIndexFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: index is non-sensitive
+		return fmt.Errorf("writing archive index: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("renaming archive index: %w", err)
+	}
+	return nil
+}
+
+// appendToArchive appends event lines to their day's segment and updates
+// the index.
+func appendToArchive(townRoot, source string, lines []archivedLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	byDate := make(map[string][]archivedLine)
+	for _, l := range lines {
+		date := l.ts.UTC().Format(segmentDateFormat)
+		byDate[date] = append(byDate[date], l)
+	}
+
+	if err := os.MkdirAll(filepath.Join(ArchivePath(townRoot), source), 0755); err != nil {
+		return fmt.Errorf("creating archive directory: %w", err)
+	}
+	index, err := LoadArchiveIndex(townRoot)
+	if err != nil {
+		return err
+	}
+
+	for date, dayLines := range byDate {
+		rel := filepath.Join(source, date+".jsonl.gz")
+		size, err := appendSegment(filepath.Join(ArchivePath(townRoot), rel), dayLines)
+		if err != nil {
+			return fmt.Errorf("archiving %s: %w", rel, err)
+		}
+
+		seg := findSegment(index, source, date)
+		if seg == nil {
+			index.Segments = append(index.Segments, Segment{Source: source, Date: date, File: rel})
+			seg = &index.Segments[len(index.Segments)-1]
+		}
+		seg.Bytes = size
+		for _, l := range dayLines {
+			seg.Events++
+			if seg.First.IsZero() || l.ts.Before(seg.First) {
+				seg.First = l.ts
+			}
+			if l.ts.After(seg.Last) {
+				seg.Last = l.ts
+			}
+		}
+	}
+
+	return saveArchiveIndex(townRoot, index)
+}
+
+// appendSegment writes lines as a new gzip member at the end of a segment
+// file and returns the file's new size.
+func appendSegment(path string, lines []archivedLine) (size int64, err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: archive is non-sensitive
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	for _, l := range lines {
+		if _, err := gz.Write([]byte(l.line + "\n")); err != nil {
+			return 0, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func findSegment(index *ArchiveIndex, source, date string) *Segment {
+	for i := range index.Segments {
+		if index.Segments[i].Source == source && index.Segments[i].Date == date {
+			return &index.Segments[i]
+		}
+	}
+	return nil
+}
+
+// expireArchive deletes segments whose newest event is older than the cold
+// TTL. Returns the number of segments and events removed.
+func expireArchive(townRoot string, coldTTL time.Duration, now time.Time) (segments, events int, err error) {
+	index, err := LoadArchiveIndex(townRoot)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cutoff := now.Add(-coldTTL)
+	var kept []Segment
+	for _, seg := range index.Segments {
+		if !seg.Last.Before(cutoff) {
+			kept = append(kept, seg)
+			continue
+		}
+		if err := os.Remove(filepath.Join(ArchivePath(townRoot), seg.File)); err != nil && !os.IsNotExist(err) {
+			return segments, events, fmt.Errorf("removing segment %s: %w", seg.File, err)
+		}
+		segments++
+		events += seg.Events
+	}
+	if segments == 0 {
+		return 0, 0, nil
+	}
+
+	index.Segments = kept
+	return segments, events, saveArchiveIndex(townRoot, index)
+}
+
+// GetArchiveStats summarizes the archive from its index.
+func GetArchiveStats(townRoot string) (ArchiveStats, error) {
+	var stats ArchiveStats
+	index, err := LoadArchiveIndex(townRoot)
+	if err != nil {
+		return stats, err
+	}
+	for _, seg := range index.Segments {
+		stats.Segments++
+		stats.Events += seg.Events
+		stats.Bytes += seg.Bytes
+		if stats.OldestEvent.IsZero() || seg.First.Before(stats.OldestEvent) {
+			stats.OldestEvent = seg.First
+		}
+	}
+	return stats, nil
+}
+
+// ScanArchive calls fn for each archived line of a source (e.g. "events")
+// in segments that may hold events at or after since, oldest day first.
+// A zero since scans the whole archive.
+func ScanArchive(townRoot, source string, since time.Time, fn func(line []byte) error) error {
+	index, err := LoadArchiveIndex(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var segments []Segment
+	for _, seg := range index.Segments {
+		if seg.Source == source && (since.IsZero() || !seg.Last.Before(since)) {
+			segments = append(segments, seg)
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].Date < segments[j].Date })
+
+	for _, seg := range segments {
+		if err := scanSegment(filepath.Join(ArchivePath(townRoot), seg.File), fn); err != nil {
+			return fmt.Errorf("reading archive segment %s: %w", seg.File, err)
+		}
+	}
+	return nil
+}
+
+func scanSegment(path string, fn func(line []byte) error) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil // pruned concurrently
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	return scanLines(gz, fn)
+}
+
+// ScanEvents calls fn for each line of a hot event file (e.g.
+// events.EventsFile), preceded by its archived lines when since is set and
+// the archive may hold events that recent. Lines come oldest first; callers
+// still filter individual events by time.
+func ScanEvents(townRoot, fileName string, since time.Time, fn func(line []byte) error) error {
+	if !since.IsZero() {
+		if err := ScanArchive(townRoot, ArchiveSource(fileName), since, fn); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(filepath.Join(townRoot, fileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return scanLines(f, fn)
+}
+
+func scanLines(r io.Reader, fn func(line []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
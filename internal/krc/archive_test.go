@@ -0,0 +1,150 @@
+package krc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeEventLines(t *testing.T, path string, now time.Time, ages map[string]time.Duration) {
+	t.Helper()
+	var b strings.Builder
+	for typ, age := range ages {
+		fmt.Fprintf(&b, `{"ts":%q,"type":%q}`+"\n", now.Add(-age).Format(time.RFC3339), typ)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrune_ArchivesExpiredEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	writeEventLines(t, filepath.Join(townRoot, ".events.jsonl"), now, map[string]time.Duration{
+		"fresh":   time.Hour,
+		"aged":    10 * 24 * time.Hour,
+		"ancient": 200 * 24 * time.Hour,
+	})
+
+	config := &Config{DefaultTTL: 7 * 24 * time.Hour, ColdTTL: 90 * 24 * time.Hour, PruneInterval: time.Hour}
+	result, err := NewPruner(townRoot, config).Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.EventsPruned != 2 || result.EventsArchived != 1 {
+		t.Errorf("pruned %d, archived %d; want 2, 1", result.EventsPruned, result.EventsArchived)
+	}
+
+	var archived []string
+	err = ScanArchive(townRoot, "events", time.Time{}, func(line []byte) error {
+		archived = append(archived, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 1 || !strings.Contains(archived[0], `"aged"`) {
+		t.Errorf("archived = %v", archived)
+	}
+
+	stats, err := GetArchiveStats(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Segments != 1 || stats.Events != 1 || stats.Bytes == 0 {
+		t.Errorf("archive stats = %+v", stats)
+	}
+
+	// A second prune appends to the same day's segment.
+	writeEventLines(t, filepath.Join(townRoot, ".events.jsonl"), now, map[string]time.Duration{"aged": 10 * 24 * time.Hour})
+	if _, err := NewPruner(townRoot, config).Prune(); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	_ = ScanArchive(townRoot, "events", time.Time{}, func([]byte) error { count++; return nil })
+	if stats, _ := GetArchiveStats(townRoot); count != 2 || stats.Segments != 1 || stats.Events != 2 {
+		t.Errorf("after second prune: %d lines, stats %+v", count, stats)
+	}
+}
+
+func TestPrune_ColdTTLZeroDeletes(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventLines(t, filepath.Join(townRoot, ".events.jsonl"), time.Now(), map[string]time.Duration{"aged": 10 * 24 * time.Hour})
+
+	config := &Config{DefaultTTL: 7 * 24 * time.Hour, PruneInterval: time.Hour}
+	result, err := NewPruner(townRoot, config).Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.EventsPruned != 1 || result.EventsArchived != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := os.Stat(ArchivePath(townRoot)); !os.IsNotExist(err) {
+		t.Error("archive created with archiving disabled")
+	}
+}
+
+func TestExpireArchive(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	old := now.Add(-100 * 24 * time.Hour)
+	recent := now.Add(-20 * 24 * time.Hour)
+	if err := appendToArchive(townRoot, "events", []archivedLine{
+		{ts: old, line: `{"type":"old"}`},
+		{ts: recent, line: `{"type":"recent"}`},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, events, err := expireArchive(townRoot, 90*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segments != 1 || events != 1 {
+		t.Errorf("expired %d segments, %d events", segments, events)
+	}
+	oldFile := filepath.Join(ArchivePath(townRoot), "events", old.UTC().Format(segmentDateFormat)+".jsonl.gz")
+	if _, err := os.Stat(oldFile); !os.IsNotExist(err) {
+		t.Error("expired segment still on disk")
+	}
+	if stats, _ := GetArchiveStats(townRoot); stats.Segments != 1 {
+		t.Errorf("stats after expiry = %+v", stats)
+	}
+}
+
+func TestScanEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	if err := appendToArchive(townRoot, "events", []archivedLine{
+		{ts: now.Add(-30 * 24 * time.Hour), line: `{"type":"month"}`},
+		{ts: now.Add(-10 * 24 * time.Hour), line: `{"type":"week"}`},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, ".events.jsonl"), []byte(`{"type":"live"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scan := func(since time.Time) []string {
+		var lines []string
+		if err := ScanEvents(townRoot, ".events.jsonl", since, func(line []byte) error {
+			lines = append(lines, string(line))
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return lines
+	}
+
+	if got := scan(time.Time{}); len(got) != 1 {
+		t.Errorf("without since, only the hot file is read: %v", got)
+	}
+	// The index skips the month-old segment; the week-old one comes before live events.
+	got := scan(now.Add(-14 * 24 * time.Hour))
+	if len(got) != 2 || !strings.Contains(got[0], "week") || !strings.Contains(got[1], "live") {
+		t.Errorf("since two weeks = %v", got)
+	}
+}
@@ -9,6 +9,7 @@
 //
 // KRC provides:
 // - Configurable TTLs per event type (default: 7 days)
+// - A compressed cold tier for events past their TTL (default: 90 days)
 // - Auto-pruning on daemon startup and periodic intervals
 // - Stats and visibility into ephemeral data lifecycle
 package krc
@@ -25,6 +26,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/recording"
 )
 
 // Config defines TTL settings for ephemeral records.
@@ -45,6 +47,18 @@ type Config struct {
 	// MinRetainCount keeps at least N events even if expired (for debugging).
 	// Default: 100
 	MinRetainCount int `json:"min_retain_count"`
+
+	// ColdTTL is how long events are kept in the compressed archive
+	// (.krc-archive/) after their TTL moves them out of the hot files,
+	// measured from the event's timestamp. Zero disables archiving:
+	// expired events are deleted.
+	// Default: 90 days
+	ColdTTL time.Duration `json:"cold_ttl"`
+
+	// RecordingTTL is how long pane recordings (.recordings/) are kept
+	// after their last write. Zero keeps them forever.
+	// Default: 7 days
+	RecordingTTL time.Duration `json:"recording_ttl"`
 }
 
 // DefaultConfig returns the default KRC configuration.
@@ -53,6 +67,8 @@ func DefaultConfig() *Config {
 		DefaultTTL:    7 * 24 * time.Hour, // 7 days
 		PruneInterval: 1 * time.Hour,
 		MinRetainCount: 100,
+		ColdTTL:       90 * 24 * time.Hour, // 90 days
+		RecordingTTL:  7 * 24 * time.Hour,  // 7 days
 		TTLs: map[string]time.Duration{
 			// Patrol events decay fastest - low forensic value after hours
 			"patrol_*":       24 * time.Hour,  // 1 day
@@ -109,6 +125,32 @@ func LoadConfig(townRoot string) (*Config, error) {
 	return config, nil
 }
 
+// Validate checks that the configuration can drive the daemon's pruner:
+// a positive prune interval and default TTL, and no negative values.
+func (c *Config) Validate() error {
+	if c.PruneInterval <= 0 {
+		return fmt.Errorf("prune_interval must be positive, got %v", c.PruneInterval)
+	}
+	if c.DefaultTTL <= 0 {
+		return fmt.Errorf("default_ttl must be positive, got %v", c.DefaultTTL)
+	}
+	if c.MinRetainCount < 0 {
+		return fmt.Errorf("min_retain_count must be non-negative, got %d", c.MinRetainCount)
+	}
+	if c.ColdTTL < 0 {
+		return fmt.Errorf("cold_ttl must be non-negative, got %v", c.ColdTTL)
+	}
+	if c.RecordingTTL < 0 {
+		return fmt.Errorf("recording_ttl must be non-negative, got %v", c.RecordingTTL)
+	}
+	for pattern, ttl := range c.TTLs {
+		if ttl < 0 {
+			return fmt.Errorf("ttl for %q must be non-negative, got %v", pattern, ttl)
+		}
+	}
+	return nil
+}
+
 // SaveConfig writes the KRC configuration to the town root.
 func SaveConfig(townRoot string, config *Config) error {
 	configPath := ConfigFile(townRoot)
@@ -171,6 +213,20 @@ type PruneResult struct {
 	BytesAfter      int64          `json:"bytes_after"`
 	PrunedByType    map[string]int `json:"pruned_by_type"`
 	Duration        time.Duration  `json:"duration"`
+
+	// EventsArchived counts pruned events moved to the archive rather
+	// than deleted (included in EventsPruned).
+	EventsArchived int `json:"events_archived"`
+
+	// SegmentsExpired and ArchiveEventsExpired count archive segments
+	// deleted for passing the cold TTL.
+	SegmentsExpired      int `json:"segments_expired"`
+	ArchiveEventsExpired int `json:"archive_events_expired"`
+
+	// RecordingsExpired and RecordingBytesFreed count pane recording
+	// segments deleted for passing the recording TTL.
+	RecordingsExpired   int   `json:"recordings_expired"`
+	RecordingBytesFreed int64 `json:"recording_bytes_freed"`
 }
 
 // Pruner handles the pruning of expired events.
@@ -187,8 +243,12 @@ func NewPruner(townRoot string, config *Config) *Pruner {
 	}
 }
 
-// Prune removes expired events from the events and feed files.
-// It operates atomically by writing to temp files then renaming.
+// Prune removes expired events from the events and feed files, moving them
+// to the archive while they're within the cold TTL, then deletes archive
+// segments past the cold TTL and pane recordings past the recording TTL.
+// It operates atomically by writing to temp files then renaming. Archived
+// events are written before the hot file is replaced, so an interrupted
+// prune can archive an event twice but never loses one.
 func (p *Pruner) Prune() (*PruneResult, error) {
 	start := time.Now()
 	result := &PruneResult{
@@ -222,6 +282,25 @@ func (p *Pruner) Prune() (*PruneResult, error) {
 	for k, v := range feedResult.PrunedByType {
 		result.PrunedByType[k] += v
 	}
+	result.EventsArchived = eventsResult.EventsArchived + feedResult.EventsArchived
+
+	if p.config.ColdTTL > 0 {
+		segments, expired, err := expireArchive(p.townRoot, p.config.ColdTTL, start)
+		if err != nil {
+			return nil, fmt.Errorf("expiring archive: %w", err)
+		}
+		result.SegmentsExpired = segments
+		result.ArchiveEventsExpired = expired
+	}
+
+	if p.config.RecordingTTL > 0 {
+		segments, bytes, err := recording.Prune(p.townRoot, p.config.RecordingTTL, start)
+		if err != nil {
+			return nil, fmt.Errorf("pruning recordings: %w", err)
+		}
+		result.RecordingsExpired = segments
+		result.RecordingBytesFreed = bytes
+	}
 
 	result.Duration = time.Since(start)
 	return result, nil
@@ -285,6 +364,7 @@ func (p *Pruner) pruneFile(filePath string) (result *PruneResult, err error) {
 	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
 
 	var retained []string
+	var archived []archivedLine
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -312,11 +392,14 @@ func (p *Pruner) pruneFile(filePath string) (result *PruneResult, err error) {
 			continue
 		}
 
-		// Check if event has expired
+		// Check if event has expired; archive it unless it's also past the cold TTL
 		ttl := p.config.GetTTL(event.Type)
-		if now.Sub(ts) > ttl {
+		if age := now.Sub(ts); age > ttl {
 			result.EventsPruned++
 			result.PrunedByType[event.Type]++
+			if age <= p.config.ColdTTL {
+				archived = append(archived, archivedLine{ts: ts, line: line})
+			}
 		} else {
 			retained = append(retained, line)
 		}
@@ -341,6 +424,12 @@ func (p *Pruner) pruneFile(filePath string) (result *PruneResult, err error) {
 		}
 	}
 
+	// Archive before replacing the hot file so no event is lost
+	if err := appendToArchive(p.townRoot, ArchiveSource(filePath), archived); err != nil {
+		return nil, err
+	}
+	result.EventsArchived = len(archived)
+
 	// Get final size
 	tmpInfo, err := tmpFile.Stat()
 	if err != nil {
@@ -377,6 +466,7 @@ type Stats struct {
 	OldestEvent  time.Time          `json:"oldest_event"`
 	NewestEvent  time.Time          `json:"newest_event"`
 	TTLBreakdown map[string]TTLInfo `json:"ttl_breakdown"`
+	Archive      ArchiveStats       `json:"archive"`
 }
 
 // FileStats contains statistics for a single file.
@@ -432,6 +522,12 @@ func GetStats(townRoot string, config *Config) (*Stats, error) {
 		stats.NewestEvent = newest2
 	}
 
+	archive, err := GetArchiveStats(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	stats.Archive = archive
+
 	return stats, nil
 }
 
@@ -134,6 +134,60 @@ func TestSaveAndLoadConfig(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("default config: %v", err)
+	}
+
+	tests := map[string]func(*Config){
+		"zero prune interval": func(c *Config) { c.PruneInterval = 0 },
+		"zero default ttl":    func(c *Config) { c.DefaultTTL = 0 },
+		"negative retain":     func(c *Config) { c.MinRetainCount = -1 },
+		"negative ttl":        func(c *Config) { c.TTLs["mail"] = -time.Hour },
+		"negative cold ttl":   func(c *Config) { c.ColdTTL = -time.Hour },
+		"negative recording":  func(c *Config) { c.RecordingTTL = -time.Hour },
+	}
+	for name, mutate := range tests {
+		config := DefaultConfig()
+		mutate(config)
+		if err := config.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPruner_PruneRecordings(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := filepath.Join(townRoot, ".recordings", "gastown", "nux")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(dir, "20260101T000000Z.cast")
+	if err := os.WriteFile(old, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-8 * 24 * time.Hour)
+	_ = os.Chtimes(old, stale, stale)
+
+	config := DefaultConfig()
+	config.RecordingTTL = 0
+	result, err := NewPruner(townRoot, config).Prune()
+	if err != nil || result.RecordingsExpired != 0 {
+		t.Fatalf("TTL 0: result = %+v, %v", result, err)
+	}
+
+	result, err = NewPruner(townRoot, DefaultConfig()).Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RecordingsExpired != 1 || result.RecordingBytesFreed != 3 {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expired recording still present: %v", err)
+	}
+}
+
 func TestPruner_Prune(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "krc-test-*")
 	if err != nil {
@@ -0,0 +1,252 @@
+package polecat
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+const (
+	// affinityLabelWeight is the score for each label shared with past work.
+	affinityLabelWeight = 1
+
+	// affinityDirWeight is the score for each directory of the work that the
+	// identity has committed to before. Code context counts more than labels.
+	affinityDirWeight = 2
+
+	// affinityHistoryCommits bounds how far back commit history is scanned.
+	affinityHistoryCommits = 1000
+
+	// affinityHistoryBeads bounds how many closed beads are scanned.
+	affinityHistoryBeads = 500
+)
+
+// WorkProfile describes work by the labels on its beads and the repo paths
+// it touches. It is used both for a bead about to be slung and for the
+// accumulated history of a polecat identity.
+type WorkProfile struct {
+	Labels []string `json:"labels,omitempty"`
+	Paths  []string `json:"paths,omitempty"`
+}
+
+// AffinityMatch is how well a polecat identity's history matches some work.
+type AffinityMatch struct {
+	Name   string   `json:"name"`
+	Score  int      `json:"score"`
+	Labels []string `json:"labels,omitempty"` // labels shared with past beads
+	Dirs   []string `json:"dirs,omitempty"`   // directories the identity has committed to
+}
+
+// String formats the match for recording on the bead,
+// e.g. "toast score=5 dirs=internal/git,internal/polecat labels=area:git".
+func (a *AffinityMatch) String() string {
+	s := fmt.Sprintf("%s score=%d", a.Name, a.Score)
+	if len(a.Dirs) > 0 {
+		s += " dirs=" + strings.Join(a.Dirs, ",")
+	}
+	if len(a.Labels) > 0 {
+		s += " labels=" + strings.Join(a.Labels, ",")
+	}
+	return s
+}
+
+// ScoreAffinity scores the overlap between work and an identity's history.
+// Each shared label scores affinityLabelWeight; each directory of the work
+// that history touched (at or below it) scores affinityDirWeight.
+func ScoreAffinity(name string, work, history WorkProfile) AffinityMatch {
+	match := AffinityMatch{Name: name}
+
+	historyLabels := make(map[string]bool, len(history.Labels))
+	for _, l := range history.Labels {
+		historyLabels[l] = true
+	}
+	for _, l := range dedupe(work.Labels) {
+		if historyLabels[l] {
+			match.Labels = append(match.Labels, l)
+		}
+	}
+
+	historyDirs := profileDirs(history.Paths)
+	for _, d := range sortedKeys(profileDirs(work.Paths)) {
+		for h := range historyDirs {
+			if h == d || strings.HasPrefix(h, d+"/") {
+				match.Dirs = append(match.Dirs, d)
+				break
+			}
+		}
+	}
+
+	match.Score = len(match.Labels)*affinityLabelWeight + len(match.Dirs)*affinityDirWeight
+	return match
+}
+
+// RankAffinity scores every identity in histories against work and returns
+// those with a positive score, best first (ties broken by name).
+func RankAffinity(work WorkProfile, histories map[string]WorkProfile) []AffinityMatch {
+	var ranked []AffinityMatch
+	for name, history := range histories {
+		if m := ScoreAffinity(name, work, history); m.Score > 0 {
+			ranked = append(ranked, m)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	return ranked
+}
+
+// AllocateNameWithAffinity allocates a polecat name, preferring the free
+// identity whose past beads and commits best overlap work. Returns the
+// winning match, or nil if no free identity had relevant history (the name
+// then comes from the pool as usual).
+func (m *Manager) AllocateNameWithAffinity(work WorkProfile) (string, *AffinityMatch, error) {
+	// History is gathered outside the pool lock: it shells out to bd and git.
+	ranked := RankAffinity(work, m.identityHistories())
+	preferred := make([]string, len(ranked))
+	for i, r := range ranked {
+		preferred[i] = r.Name
+	}
+
+	name, err := m.allocateName(preferred)
+	if err != nil {
+		return "", nil, err
+	}
+	for i := range ranked {
+		if ranked[i].Name == name {
+			return name, &ranked[i], nil
+		}
+	}
+	return name, nil, nil
+}
+
+// identityHistories builds a work profile for each polecat identity in the
+// rig from closed beads assigned to it (labels) and its commits on the
+// default branch (paths; polecats commit with their name as author).
+func (m *Manager) identityHistories() map[string]WorkProfile {
+	histories := make(map[string]WorkProfile)
+	assigneePrefix := m.rig.Name + "/polecats/"
+
+	if closed, err := m.beads.List(beads.ListOptions{Status: "closed", Priority: -1, Limit: affinityHistoryBeads}); err == nil {
+		for _, issue := range closed {
+			name, ok := strings.CutPrefix(issue.Assignee, assigneePrefix)
+			if !ok || name == "" {
+				continue
+			}
+			h := histories[name]
+			h.Labels = append(h.Labels, issue.Labels...)
+			histories[name] = h
+		}
+	}
+
+	if repoGit, err := m.repoBase(); err == nil {
+		if byAuthor, err := repoGit.FilesByAuthor("origin/"+m.defaultBranch(), affinityHistoryCommits); err == nil {
+			for author, files := range byAuthor {
+				if !m.namePool.IsPoolName(author) {
+					continue
+				}
+				h := histories[author]
+				h.Paths = append(h.Paths, files...)
+				histories[author] = h
+			}
+		}
+	}
+	return histories
+}
+
+// BeadWorkProfile describes the work a bead is likely to touch: its labels,
+// path-like tokens in its title and description, and the files touched by
+// commits that reference its parent or the beads it depends on (so
+// follow-up work lands with the agent that did the original).
+func (m *Manager) BeadWorkProfile(issue *beads.Issue) WorkProfile {
+	profile := WorkProfile{Labels: issue.Labels}
+	profile.Paths = append(profile.Paths, PathTokens(issue.Title+"\n"+issue.Description)...)
+
+	related := append([]string{}, issue.DependsOn...)
+	if issue.Parent != "" {
+		related = append(related, issue.Parent)
+	}
+	if len(related) == 0 {
+		return profile
+	}
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return profile
+	}
+	ref := "origin/" + m.defaultBranch()
+	for _, id := range related {
+		if files, err := repoGit.FilesForCommitsMatching(ref, id, affinityHistoryCommits); err == nil {
+			profile.Paths = append(profile.Paths, files...)
+		}
+	}
+	return profile
+}
+
+// defaultBranch returns the rig's configured default branch.
+func (m *Manager) defaultBranch() string {
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		return rigCfg.DefaultBranch
+	}
+	return "main"
+}
+
+// PathTokens extracts repo-relative path-like tokens (containing a slash,
+// not URLs) from free text, e.g. "internal/polecat/manager.go" or "docs/".
+func PathTokens(text string) []string {
+	var paths []string
+	for _, field := range strings.Fields(text) {
+		tok := strings.TrimLeft(field, "`'\"([{<")
+		tok = strings.TrimRight(tok, "`'\")]}>,;:!?.")
+		if !strings.Contains(tok, "/") || strings.Contains(tok, "://") ||
+			strings.HasPrefix(tok, "/") || strings.HasPrefix(tok, "~") {
+			continue
+		}
+		paths = append(paths, tok)
+	}
+	return paths
+}
+
+// profileDirs maps paths to the set of directories they live in. Tokens
+// ending in "/" are directories themselves.
+func profileDirs(paths []string) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, p := range paths {
+		var d string
+		if strings.HasSuffix(p, "/") {
+			d = path.Clean(p)
+		} else {
+			d = path.Dir(path.Clean(p))
+		}
+		if d != "." && d != "/" && !strings.HasPrefix(d, "..") {
+			dirs[d] = true
+		}
+	}
+	return dirs
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var out []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package polecat
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScoreAffinity(t *testing.T) {
+	work := WorkProfile{
+		Labels: []string{"area:git", "p1"},
+		Paths:  []string{"internal/git/sparse.go", "internal/polecat/"},
+	}
+	history := WorkProfile{
+		Labels: []string{"area:git", "area:git", "area:docs"},
+		Paths:  []string{"internal/git/git.go", "internal/polecat/manager.go", "docs/README.md"},
+	}
+
+	m := ScoreAffinity("toast", work, history)
+	if !slices.Equal(m.Labels, []string{"area:git"}) {
+		t.Errorf("Labels = %v", m.Labels)
+	}
+	if !slices.Equal(m.Dirs, []string{"internal/git", "internal/polecat"}) {
+		t.Errorf("Dirs = %v", m.Dirs)
+	}
+	if m.Score != 1*affinityLabelWeight+2*affinityDirWeight {
+		t.Errorf("Score = %d", m.Score)
+	}
+	if got := m.String(); got != "toast score=5 dirs=internal/git,internal/polecat labels=area:git" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// A sibling directory is not context for this work
+	if m := ScoreAffinity("nux", work, WorkProfile{Paths: []string{"internal/gitx/x.go"}}); m.Score != 0 {
+		t.Errorf("sibling dir scored %d", m.Score)
+	}
+}
+
+func TestRankAffinity(t *testing.T) {
+	work := WorkProfile{Labels: []string{"area:git"}, Paths: []string{"internal/git/git.go"}}
+	ranked := RankAffinity(work, map[string]WorkProfile{
+		"slit":    {Labels: []string{"area:git"}},
+		"toast":   {Labels: []string{"area:git"}, Paths: []string{"internal/git/sparse.go"}},
+		"nux":     {Paths: []string{"docs/a.md"}},
+		"furiosa": {Labels: []string{"area:git"}},
+	})
+
+	var names []string
+	for _, r := range ranked {
+		names = append(names, r.Name)
+	}
+	if !slices.Equal(names, []string{"toast", "furiosa", "slit"}) {
+		t.Errorf("ranking = %v", names)
+	}
+}
+
+func TestPathTokens(t *testing.T) {
+	text := "Fix `internal/polecat/manager.go` and docs/ (see https://example.com/x, /etc/hosts, gastown/polecats/Toast)."
+	got := PathTokens(text)
+	want := []string{"internal/polecat/manager.go", "docs/", "gastown/polecats/Toast"}
+	if !slices.Equal(got, want) {
+		t.Errorf("PathTokens = %v, want %v", got, want)
+	}
+}
+
+func TestNamePoolAllocatePreferred(t *testing.T) {
+	pool := NewNamePool(t.TempDir(), "gastown")
+	pool.InUse = map[string]bool{"toast": true}
+
+	if name, ok := pool.AllocatePreferred([]string{"toast", "not-a-pool-name", "nux"}); !ok || name != "nux" {
+		t.Errorf("AllocatePreferred = %q, %v; want nux", name, ok)
+	}
+	if _, ok := pool.AllocatePreferred([]string{"toast", "nux"}); ok {
+		t.Error("AllocatePreferred should fail when all preferred names are taken")
+	}
+}
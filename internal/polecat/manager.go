@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -479,6 +480,7 @@ func (m *Manager) exists(name string) bool {
 type AddOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	BaseBranch string // Override base branch for worktree (e.g., "origin/integration/gt-epic")
+	Formula    string // Formula the work is slung with (selects checkout overrides)
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -667,6 +669,13 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		return nil, fmt.Errorf("finding repo base: %w", err)
 	}
 
+	// Resolve sparse checkout / partial clone settings for this work.
+	// The filter must be in place before fetching so new objects are skipped.
+	spec := m.checkoutSpec(opts)
+	if err := repoGit.EnablePartialClone("origin", spec.Filter); err != nil {
+		style.PrintWarning("could not enable partial clone: %v", err)
+	}
+
 	// Fetch latest from origin to ensure worktree starts from up-to-date code
 	if err := repoGit.Fetch("origin"); err != nil {
 		// Non-fatal - proceed with potentially stale code
@@ -700,12 +709,21 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 			startPoint, m.rig.Path, filepath.Join(m.rig.Path, ".repo.git"))
 	}
 
-	// Always create fresh branch - unique name guarantees no collision
-	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
-	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
-		cleanupOnError()
-		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+	// Prefer a pre-warmed worktree from the rig's pool (already checked out,
+	// dependencies primed, shared beads set up). Falls back to creating one.
+	warm := m.claimWarmWorktree(repoGit, clonePath, branchName, startPoint)
+	if warm {
+		if err := applySparseCheckout(clonePath, spec); err != nil {
+			style.PrintWarning("could not apply sparse checkout: %v", err)
+		}
+	} else {
+		// Always create fresh branch - unique name guarantees no collision
+		// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
+		// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
+		if err := repoGit.WorktreeAddSparse(clonePath, branchName, startPoint, spec.Sparse); err != nil {
+			cleanupOnError()
+			return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+		}
 	}
 	worktreeCreated = true
 
@@ -715,10 +733,13 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 
 	// Set up shared beads: polecat uses rig's .beads via redirect file.
 	// This eliminates git sync overhead - all polecats share one database.
-	if err := m.setupSharedBeads(clonePath); err != nil {
-		// Non-fatal - polecat can still work with local beads
-		// Log warning but don't fail the spawn
-		style.PrintWarning("could not set up shared beads: %v", err)
+	// Warm worktrees already have the redirect (the pool mirrors this depth).
+	if !warm {
+		if err := m.setupSharedBeads(clonePath); err != nil {
+			// Non-fatal - polecat can still work with local beads
+			// Log warning but don't fail the spawn
+			style.PrintWarning("could not set up shared beads: %v", err)
+		}
 	}
 
 	// Provision PRIME.md with Gas Town context for this worker.
@@ -1028,6 +1049,12 @@ func forceRemoveDir(dir string) error {
 // After allocation, kills any lingering tmux session for the name (gt-pqf9x)
 // to prevent "session already running" errors when reusing names from dead polecats.
 func (m *Manager) AllocateName() (string, error) {
+	return m.allocateName(nil)
+}
+
+// allocateName allocates the first free name in preferred, falling back to
+// the next name from the pool.
+func (m *Manager) allocateName(preferred []string) (string, error) {
 	// Acquire pool lock to prevent concurrent allocations from racing
 	fl, err := m.lockPool()
 	if err != nil {
@@ -1038,9 +1065,12 @@ func (m *Manager) AllocateName() (string, error) {
 	// Reconcile without re-acquiring the pool lock
 	m.reconcilePoolInternal()
 
-	name, err := m.namePool.Allocate()
-	if err != nil {
-		return "", err
+	name, ok := m.namePool.AllocatePreferred(preferred)
+	if !ok {
+		name, err = m.namePool.Allocate()
+		if err != nil {
+			return "", err
+		}
 	}
 
 	if err := m.namePool.Save(); err != nil {
@@ -1138,6 +1168,8 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	}
 
 	// Fetch latest from origin to ensure we have fresh commits (non-fatal: may be offline)
+	spec := m.checkoutSpec(opts)
+	_ = repoGit.EnablePartialClone("origin", spec.Filter)
 	_ = repoGit.Fetch("origin")
 
 	// Ensure polecat directory exists for new structure
@@ -1175,7 +1207,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	branchName := m.buildBranchName(name, opts.HookBead)
 	tmpClonePath := newClonePath + ".repair-tmp"
 	_ = os.RemoveAll(tmpClonePath) // clean up any leftover temp dir
-	if err := repoGit.WorktreeAddFromRef(tmpClonePath, branchName, startPoint); err != nil {
+	if err := repoGit.WorktreeAddSparse(tmpClonePath, branchName, startPoint, spec.Sparse); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
 
@@ -1696,6 +1728,47 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	}, nil
 }
 
+// checkoutSpec resolves the rig's sparse checkout / partial clone settings
+// for a spawn. Bead labels are only looked up when an override needs them.
+func (m *Manager) checkoutSpec(opts AddOptions) config.CheckoutSpec {
+	cfg := config.LoadRigCheckout(m.rig.Path)
+	if cfg == nil {
+		return config.CheckoutSpec{}
+	}
+	var labels []string
+	if opts.HookBead != "" {
+		for _, o := range cfg.Overrides {
+			if o.Label == "" {
+				continue
+			}
+			if issue, err := m.beads.Show(opts.HookBead); err == nil {
+				labels = issue.Labels
+			}
+			break
+		}
+	}
+	return cfg.Resolve(opts.Formula, labels)
+}
+
+// applySparseCheckout reshapes an existing worktree to match spec.
+func applySparseCheckout(worktreePath string, spec config.CheckoutSpec) error {
+	wt := git.NewGit(worktreePath)
+	current, err := wt.SparseCheckoutList()
+	if err != nil {
+		return err
+	}
+	if spec.IsSparse() {
+		if slices.Equal(current, spec.Sparse) {
+			return nil
+		}
+		return wt.SparseCheckoutSet(spec.Sparse)
+	}
+	if current != nil {
+		return wt.SparseCheckoutDisable()
+	}
+	return nil
+}
+
 // setupSharedBeads creates a redirect file so the polecat uses the rig's shared .beads database.
 // This eliminates the need for git sync between polecat clones - all polecats share one database.
 func (m *Manager) setupSharedBeads(clonePath string) error {
@@ -265,6 +265,22 @@ func (p *NamePool) Allocate() (string, error) {
 	return name, nil
 }
 
+// AllocatePreferred allocates the first of preferred that is a free themed
+// name. Returns false if none is available; callers then fall back to
+// Allocate. Overflow names are never reused, so they can't be preferred.
+func (p *NamePool) AllocatePreferred(preferred []string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, name := range preferred {
+		if !p.InUse[name] && p.isThemedName(name) {
+			p.InUse[name] = true
+			return name, true
+		}
+	}
+	return "", false
+}
+
 // Release returns a name slot to the available pool.
 // Called when a polecat is nuked - the name becomes available for new polecats.
 // NOTE: This releases the NAME, not the polecat. The polecat is gone (nuked).
@@ -21,6 +21,7 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tracing"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -73,6 +74,10 @@ type SessionStartOptions struct {
 	// DoltBranch is the polecat-specific Dolt branch for write isolation.
 	// If set, BD_BRANCH env var is injected into the polecat session.
 	DoltBranch string
+
+	// TraceParent is the sling span this start is traced under.
+	// If set, the start is recorded as a span and GT_TRACEPARENT is injected.
+	TraceParent string
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -182,7 +187,7 @@ func (m *SessionManager) hasPolecat(polecat string) bool {
 }
 
 // Start creates and starts a new session for a polecat.
-func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
+func (m *SessionManager) Start(polecat string, opts SessionStartOptions) (retErr error) {
 	if !m.hasPolecat(polecat) {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
@@ -226,6 +231,17 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	townRoot := filepath.Dir(m.rig.Path)
 	runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, m.rig.Path)
 
+	// Record the start under the sling's trace. The agent's own spans
+	// (gt done) are parented to this one via GT_TRACEPARENT.
+	var traceParent string
+	if parent := tracing.ParseTraceParent(opts.TraceParent); parent.IsValid() {
+		span := tracing.Start(townRoot, "polecat.start", parent)
+		span.SetAttr("agent", fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat))
+		span.SetAttr("bead", opts.Issue)
+		defer func() { span.End(retErr) }()
+		traceParent = span.Context().TraceParent()
+	}
+
 	// Ensure runtime settings exist in the shared polecats parent directory.
 	// Settings are passed to Claude Code via --settings flag.
 	polecatSettingsDir := config.RoleSettingsDir("polecat", m.rig.Path)
@@ -291,6 +307,9 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if polecatGitBranch != "" {
 		envVarsToInject["GT_BRANCH"] = polecatGitBranch
 	}
+	if traceParent != "" {
+		envVarsToInject["GT_TRACEPARENT"] = traceParent
+	}
 	command = config.PrependEnv(command, envVarsToInject)
 
 	// Create session with command directly to avoid send-keys race condition.
@@ -299,6 +318,12 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Record the pane if enabled in town settings (non-fatal)
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && ts.Recording.IsEnabled() {
+		debugSession("StartPaneRecording", m.tmux.StartPaneRecording(sessionID, townRoot,
+			fmt.Sprintf("%s/%s", m.rig.Name, polecat), ts.Recording.SegmentBytes(), ts.Recording.SegmentAge()))
+	}
+
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	// Note: townRoot already defined above for ResolveRoleAgentConfig
@@ -308,6 +333,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		AgentName:        polecat,
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.RuntimeConfigDir,
+		TraceParent:      traceParent,
 	})
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
@@ -0,0 +1,417 @@
+package polecat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// WorktreePoolDir is the rig-level directory holding pre-warmed worktrees.
+//
+// Layout mirrors polecats/<name>/<rigname>/ so the relative beads redirect
+// written at warm time stays valid after the worktree is moved into place:
+//
+//	<rig>/.worktree-pool/<slot>/<rigname>/   detached worktree on the default branch
+//	<rig>/.worktree-pool/<slot>/.ready       written once warmup succeeded
+//	<rig>/.worktree-pool/<slot>/.claimed     .ready renamed by a spawn claiming the slot
+const WorktreePoolDir = ".worktree-pool"
+
+// readyMarker is the file marking a pool slot as warm and claimable.
+const readyMarker = ".ready"
+
+// claimedMarker marks a slot a spawn is moving into place. The claim
+// removes the slot once the move finishes, so a marker older than
+// claimStaleAfter belongs to a spawn that died mid-claim.
+const claimedMarker = ".claimed"
+
+const claimStaleAfter = 10 * time.Minute
+
+// ErrWorktreePoolBusy is returned when another process is already filling the pool.
+var ErrWorktreePoolBusy = errors.New("worktree pool fill already in progress")
+
+// PoolSlot describes one pre-warmed worktree in the pool.
+type PoolSlot struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Ready   bool      `json:"ready"`
+	Claimed bool      `json:"claimed,omitempty"`
+	Commit  string    `json:"commit,omitempty"`
+	ReadyAt time.Time `json:"ready_at,omitempty"`
+
+	claimedAt time.Time
+}
+
+// WorktreePoolStatus reports the state of a rig's worktree pool.
+type WorktreePoolStatus struct {
+	Rig    string     `json:"rig"`
+	Size   int        `json:"size"` // configured target size (0 = disabled)
+	Warmup string     `json:"warmup,omitempty"`
+	Slots  []PoolSlot `json:"slots"`
+}
+
+// ReadyCount returns the number of claimable slots.
+func (s *WorktreePoolStatus) ReadyCount() int {
+	n := 0
+	for _, slot := range s.Slots {
+		if slot.Ready {
+			n++
+		}
+	}
+	return n
+}
+
+// worktreePoolConfig returns the rig's pool config, or nil when the pool is disabled.
+func (m *Manager) worktreePoolConfig() *config.WorktreePoolConfig {
+	settings, err := config.LoadRigSettings(filepath.Join(m.rig.Path, "settings", "config.json"))
+	if err != nil || settings.WorktreePool == nil || settings.WorktreePool.Size <= 0 {
+		return nil
+	}
+	return settings.WorktreePool
+}
+
+// WorktreePoolEnabled reports whether the rig keeps a worktree pool.
+func (m *Manager) WorktreePoolEnabled() bool {
+	return m.worktreePoolConfig() != nil
+}
+
+func (m *Manager) worktreePoolDir() string {
+	return filepath.Join(m.rig.Path, WorktreePoolDir)
+}
+
+// lockWorktreePool acquires the lock guarding slot claims.
+// Caller must defer fl.Unlock().
+func (m *Manager) lockWorktreePool() (*flock.Flock, error) {
+	lockDir := filepath.Join(m.rig.Path, ".runtime", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating lock dir: %w", err)
+	}
+	fl := flock.New(filepath.Join(lockDir, "worktree-pool.lock"))
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring worktree pool lock: %w", err)
+	}
+	return fl, nil
+}
+
+// listPoolSlots returns the pool's slots, oldest first.
+func (m *Manager) listPoolSlots() ([]PoolSlot, error) {
+	entries, err := os.ReadDir(m.worktreePoolDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading worktree pool: %w", err)
+	}
+
+	var slots []PoolSlot
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		slot := PoolSlot{
+			Name: e.Name(),
+			Path: filepath.Join(m.worktreePoolDir(), e.Name(), m.rig.Name),
+		}
+		if info, err := os.Stat(filepath.Join(m.worktreePoolDir(), e.Name(), readyMarker)); err == nil {
+			slot.Ready = true
+			slot.ReadyAt = info.ModTime()
+		}
+		if info, err := os.Stat(filepath.Join(m.worktreePoolDir(), e.Name(), claimedMarker)); err == nil {
+			slot.Claimed = true
+			slot.claimedAt = info.ModTime()
+		}
+		slots = append(slots, slot)
+	}
+	// Slot names embed their creation time, so name order is age order
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+	return slots, nil
+}
+
+// WorktreePoolStatus returns the configured size and current slots of the pool.
+func (m *Manager) WorktreePoolStatus() (*WorktreePoolStatus, error) {
+	status := &WorktreePoolStatus{Rig: m.rig.Name}
+	if cfg := m.worktreePoolConfig(); cfg != nil {
+		status.Size = cfg.Size
+		status.Warmup = cfg.Warmup
+	}
+
+	slots, err := m.listPoolSlots()
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		if slots[i].Ready {
+			if commit, err := git.NewGit(slots[i].Path).Rev("HEAD"); err == nil {
+				slots[i].Commit = commit
+			}
+		}
+	}
+	status.Slots = slots
+	if status.Slots == nil {
+		status.Slots = []PoolSlot{}
+	}
+	return status, nil
+}
+
+// FillWorktreePool tops the pool up to its configured size. Slots left
+// behind by a failed or interrupted warmup are discarded and rebuilt.
+// Returns the number of slots created, or ErrWorktreePoolBusy if another
+// process is already filling the pool.
+func (m *Manager) FillWorktreePool() (int, error) {
+	cfg := m.worktreePoolConfig()
+	if cfg == nil {
+		return 0, nil
+	}
+
+	// Only one filler at a time; claims use a separate lock so spawns never
+	// wait behind a slow warmup.
+	lockDir := filepath.Join(m.rig.Path, ".runtime", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return 0, fmt.Errorf("creating lock dir: %w", err)
+	}
+	fillLock := flock.New(filepath.Join(lockDir, "worktree-pool-fill.lock"))
+	locked, err := fillLock.TryLock()
+	if err != nil {
+		return 0, fmt.Errorf("acquiring worktree pool fill lock: %w", err)
+	}
+	if !locked {
+		return 0, ErrWorktreePoolBusy
+	}
+	defer func() { _ = fillLock.Unlock() }()
+
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return 0, fmt.Errorf("finding repo base: %w", err)
+	}
+
+	ready, err := m.discardStalePoolSlots(repoGit, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if ready >= cfg.Size {
+		return 0, nil
+	}
+
+	if err := repoGit.EnablePartialClone("origin", m.checkoutSpec(AddOptions{}).Filter); err != nil {
+		style.PrintWarning("could not enable partial clone: %v", err)
+	}
+	if err := repoGit.Fetch("origin"); err != nil {
+		// Non-fatal - warm from what we have
+		style.PrintWarning("could not fetch origin: %v", err)
+	}
+	startPoint := "origin/" + m.rig.DefaultBranch()
+
+	created := 0
+	for ready+created < cfg.Size {
+		if err := m.warmPoolSlot(repoGit, cfg, startPoint); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// discardStalePoolSlots removes slots left by a failed or interrupted warmup
+// or claim and returns the number of ready slots. It holds the claim lock so
+// a slot a spawn is moving into place is never mistaken for a stale one;
+// the caller must hold the fill lock, so no slot is mid-warmup.
+func (m *Manager) discardStalePoolSlots(repoGit *git.Git, now time.Time) (int, error) {
+	fl, err := m.lockWorktreePool()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	slots, err := m.listPoolSlots()
+	if err != nil {
+		return 0, err
+	}
+	ready := 0
+	for _, slot := range slots {
+		switch {
+		case slot.Ready:
+			ready++
+		case slot.Claimed && now.Sub(slot.claimedAt) < claimStaleAfter:
+			// Being moved into place by a spawn
+		default:
+			m.discardPoolSlot(repoGit, slot.Name)
+		}
+	}
+	return ready, nil
+}
+
+// warmPoolSlot creates one pool worktree, primes it and marks it ready.
+func (m *Manager) warmPoolSlot(repoGit *git.Git, cfg *config.WorktreePoolConfig, startPoint string) error {
+	name := "wt-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	slotDir := filepath.Join(m.worktreePoolDir(), name)
+	wtPath := filepath.Join(slotDir, m.rig.Name)
+
+	if err := os.MkdirAll(slotDir, 0755); err != nil {
+		return fmt.Errorf("creating pool slot: %w", err)
+	}
+	// Rigs created before the pool existed don't ignore it yet
+	if err := rig.EnsureGitignoreEntry(filepath.Join(m.rig.Path, ".gitignore"), WorktreePoolDir+"/"); err != nil {
+		_ = os.RemoveAll(slotDir)
+		return fmt.Errorf("ignoring pool dir: %w", err)
+	}
+	if err := repoGit.WorktreeAddDetached(wtPath, startPoint); err != nil {
+		_ = os.RemoveAll(slotDir)
+		return fmt.Errorf("creating pool worktree from %s: %w", startPoint, err)
+	}
+
+	// Warm with the rig's default checkout shape; claims reshape as needed
+	if err := applySparseCheckout(wtPath, m.checkoutSpec(AddOptions{})); err != nil {
+		m.discardPoolSlot(repoGit, name)
+		return fmt.Errorf("applying sparse checkout for %s: %w", name, err)
+	}
+
+	if err := m.setupSharedBeads(wtPath); err != nil {
+		style.PrintWarning("could not set up shared beads for %s: %v", name, err)
+	}
+
+	if cfg.Warmup != "" {
+		if err := runWarmup(wtPath, m.rig.Name, cfg.Warmup, cfg.GetWarmupTimeout()); err != nil {
+			m.discardPoolSlot(repoGit, name)
+			return fmt.Errorf("warmup for %s: %w", name, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(slotDir, readyMarker), []byte(startPoint+"\n"), 0644); err != nil {
+		m.discardPoolSlot(repoGit, name)
+		return fmt.Errorf("marking pool slot ready: %w", err)
+	}
+	return nil
+}
+
+// runWarmup runs the rig's warmup command in a pool worktree.
+func runWarmup(dir, rigName, command string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GT_RIG="+rigName, "GT_WORKTREE_POOL=1")
+	util.SetProcessGroup(cmd)
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, lastLines(string(out), 5))
+	}
+	return nil
+}
+
+// lastLines returns the last n non-empty lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// discardPoolSlot removes a slot's worktree registration and directory.
+func (m *Manager) discardPoolSlot(repoGit *git.Git, name string) {
+	slotDir := filepath.Join(m.worktreePoolDir(), name)
+	_ = repoGit.WorktreeRemove(filepath.Join(slotDir, m.rig.Name), true)
+	_ = os.RemoveAll(slotDir)
+	_ = repoGit.WorktreePrune()
+}
+
+// DrainWorktreePool removes every slot in the pool. Returns the number removed.
+func (m *Manager) DrainWorktreePool() (int, error) {
+	fl, err := m.lockWorktreePool()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return 0, fmt.Errorf("finding repo base: %w", err)
+	}
+	slots, err := m.listPoolSlots()
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		m.discardPoolSlot(repoGit, slot.Name)
+	}
+	return len(slots), nil
+}
+
+// claimWarmWorktree moves a ready pool worktree to clonePath and switches it
+// to a fresh branch at startPoint. Returns false (with the pool untouched
+// where possible) if no warm worktree could be used, so the caller falls
+// back to creating a worktree from scratch.
+func (m *Manager) claimWarmWorktree(repoGit *git.Git, clonePath, branchName, startPoint string) bool {
+	if !m.WorktreePoolEnabled() {
+		return false
+	}
+
+	fl, err := m.lockWorktreePool()
+	if err != nil {
+		return false
+	}
+	slots, err := m.listPoolSlots()
+	var claimed *PoolSlot
+	if err == nil {
+		for i := range slots {
+			if !slots[i].Ready {
+				continue
+			}
+			// Renaming the marker under the lock is the claim
+			slotDir := filepath.Join(m.worktreePoolDir(), slots[i].Name)
+			marker := filepath.Join(slotDir, claimedMarker)
+			if os.Rename(filepath.Join(slotDir, readyMarker), marker) == nil {
+				now := time.Now()
+				_ = os.Chtimes(marker, now, now)
+				claimed = &slots[i]
+				break
+			}
+		}
+	}
+	_ = fl.Unlock()
+	if claimed == nil {
+		return false
+	}
+
+	if err := repoGit.WorktreeMove(claimed.Path, clonePath); err != nil {
+		style.PrintWarning("could not claim warm worktree %s: %v", claimed.Name, err)
+		m.discardPoolSlot(repoGit, claimed.Name)
+		return false
+	}
+	_ = os.RemoveAll(filepath.Join(m.worktreePoolDir(), claimed.Name))
+
+	// Reset onto the spawn's branch. Ignored files (installed dependencies,
+	// build caches from warmup) survive; anything else is discarded.
+	wtGit := git.NewGit(clonePath)
+	if err := wtGit.ResetHard("HEAD"); err == nil {
+		err = wtGit.CleanUntracked()
+		if err == nil {
+			err = wtGit.CheckoutNewBranchFrom(branchName, startPoint)
+		}
+		if err == nil {
+			return true
+		}
+		style.PrintWarning("could not reset warm worktree to %s: %v", startPoint, err)
+	}
+	_ = repoGit.WorktreeRemove(clonePath, true)
+	_ = os.RemoveAll(clonePath)
+	return false
+}
@@ -0,0 +1,211 @@
+package polecat
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupPoolRig creates a rig whose mayor/rig repo has origin/main and a
+// worktree pool configured with the given size and warmup command.
+func setupPoolRig(t *testing.T, size int, warmup string) *Manager {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("warmup commands use sh")
+	}
+	installMockBd(t)
+
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	runPoolGit(t, mayorRig, "init", "-q", "-b", "main")
+	runPoolGit(t, mayorRig, "config", "user.name", "Test")
+	runPoolGit(t, mayorRig, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(mayorRig, ".gitignore"), []byte("deps/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mayorRig, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runPoolGit(t, mayorRig, "add", ".")
+	runPoolGit(t, mayorRig, "commit", "-q", "-m", "initial")
+	runPoolGit(t, mayorRig, "remote", "add", "origin", mayorRig)
+	runPoolGit(t, mayorRig, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	settingsDir := filepath.Join(root, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := fmt.Sprintf(`{"type":"rig-settings","version":1,"worktree_pool":{"size":%d,"warmup":%q}}`, size, warmup)
+	if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &rig.Rig{Name: "rig", Path: root}
+	return NewManager(r, git.NewGit(root), nil)
+}
+
+func runPoolGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestFillWorktreePool(t *testing.T) {
+	m := setupPoolRig(t, 2, "mkdir -p deps && touch deps/primed")
+
+	created, err := m.FillWorktreePool()
+	if err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+	if created != 2 {
+		t.Fatalf("created = %d, want 2", created)
+	}
+
+	status, err := m.WorktreePoolStatus()
+	if err != nil {
+		t.Fatalf("WorktreePoolStatus: %v", err)
+	}
+	if status.Size != 2 || status.ReadyCount() != 2 {
+		t.Fatalf("status = %+v, want 2/2 ready", status)
+	}
+	for _, slot := range status.Slots {
+		if _, err := os.Stat(filepath.Join(slot.Path, "deps", "primed")); err != nil {
+			t.Errorf("slot %s not warmed: %v", slot.Name, err)
+		}
+		if slot.Commit == "" {
+			t.Errorf("slot %s missing commit", slot.Name)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(m.rig.Path, ".gitignore")); !strings.Contains(string(data), ".worktree-pool/") {
+		t.Errorf("rig .gitignore should ignore the pool, got %q", data)
+	}
+
+	// A full pool is left alone
+	if created, err := m.FillWorktreePool(); err != nil || created != 0 {
+		t.Errorf("refill of full pool: created=%d err=%v", created, err)
+	}
+}
+
+func TestAddWithOptions_ClaimsWarmWorktree(t *testing.T) {
+	m := setupPoolRig(t, 1, "mkdir -p deps && touch deps/primed && touch stray.txt")
+	if _, err := m.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+
+	p, err := m.AddWithOptions("Toast", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+
+	// Ignored warmup output survives the claim; untracked junk does not
+	if _, err := os.Stat(filepath.Join(p.ClonePath, "deps", "primed")); err != nil {
+		t.Errorf("claimed worktree lost warmed deps: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClonePath, "stray.txt")); !os.IsNotExist(err) {
+		t.Errorf("untracked file should be cleaned on claim")
+	}
+	if branch := runPoolGit(t, p.ClonePath, "rev-parse", "--abbrev-ref", "HEAD"); branch != p.Branch {
+		t.Errorf("branch = %q, want %q", branch, p.Branch)
+	}
+
+	status, err := m.WorktreePoolStatus()
+	if err != nil {
+		t.Fatalf("WorktreePoolStatus: %v", err)
+	}
+	if len(status.Slots) != 0 {
+		t.Errorf("pool should be empty after claim, got %+v", status.Slots)
+	}
+
+	// Empty pool falls back to creating a worktree from scratch
+	p2, err := m.AddWithOptions("Nux", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions (cold): %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p2.ClonePath, "deps")); !os.IsNotExist(err) {
+		t.Errorf("cold worktree should not have warmed deps")
+	}
+}
+
+func TestFillWorktreePool_WarmupFailure(t *testing.T) {
+	m := setupPoolRig(t, 1, "exit 3")
+
+	if _, err := m.FillWorktreePool(); err == nil {
+		t.Fatal("expected warmup failure")
+	}
+	status, err := m.WorktreePoolStatus()
+	if err != nil {
+		t.Fatalf("WorktreePoolStatus: %v", err)
+	}
+	if len(status.Slots) != 0 {
+		t.Errorf("failed slot should be discarded, got %+v", status.Slots)
+	}
+}
+
+func TestFillWorktreePool_SkipsClaimedSlot(t *testing.T) {
+	m := setupPoolRig(t, 1, "")
+	if _, err := m.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+	status, _ := m.WorktreePoolStatus()
+	claimed := status.Slots[0]
+
+	// Simulate a spawn that has claimed the slot but not yet moved it
+	slotDir := filepath.Join(m.worktreePoolDir(), claimed.Name)
+	if err := os.Rename(filepath.Join(slotDir, readyMarker), filepath.Join(slotDir, claimedMarker)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+	if _, err := os.Stat(claimed.Path); err != nil {
+		t.Fatalf("fill discarded a slot being claimed: %v", err)
+	}
+
+	// A claim that never finished is eventually cleaned up
+	old := time.Now().Add(-2 * claimStaleAfter)
+	_ = os.Chtimes(filepath.Join(slotDir, claimedMarker), old, old)
+	if _, err := m.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+	if _, err := os.Stat(slotDir); !os.IsNotExist(err) {
+		t.Errorf("stale claimed slot should be discarded")
+	}
+	if status, _ := m.WorktreePoolStatus(); status.ReadyCount() != 1 {
+		t.Errorf("pool should be refilled, got %+v", status.Slots)
+	}
+}
+
+func TestDrainWorktreePool(t *testing.T) {
+	m := setupPoolRig(t, 2, "")
+	if _, err := m.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool: %v", err)
+	}
+
+	removed, err := m.DrainWorktreePool()
+	if err != nil {
+		t.Fatalf("DrainWorktreePool: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	out := runPoolGit(t, filepath.Join(m.rig.Path, "mayor", "rig"), "worktree", "list")
+	if strings.Contains(out, WorktreePoolDir) {
+		t.Errorf("pool worktrees still registered:\n%s", out)
+	}
+}
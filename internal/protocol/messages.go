@@ -91,7 +91,7 @@ func formatMergedBody(p MergedPayload) string {
 
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
-func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
+func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string, failingTests []string) *mail.Message {
 	payload := MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
@@ -101,6 +101,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+		FailingTests: failingTests,
 	}
 
 	body := formatMergeFailedBody(payload)
@@ -128,6 +129,9 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if len(p.FailingTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Failing-Tests: %s\n", strings.Join(p.FailingTests, "; ")))
+	}
 	return sb.String()
 }
 
@@ -268,6 +272,9 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
 	}
+	if tests := parseField(body, "Failing-Tests"); tests != "" {
+		payload.FailingTests = strings.Split(tests, "; ")
+	}
 
 	// Parse timestamp
 	if ts := parseField(body, "Failed-At"); ts != "" {
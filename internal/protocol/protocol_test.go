@@ -128,7 +128,7 @@ func TestNewMergedMessage(t *testing.T) {
 }
 
 func TestNewMergeFailedMessage(t *testing.T) {
-	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed")
+	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed", nil)
 
 	if msg.Subject != "MERGE_FAILED nux" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGE_FAILED nux")
@@ -139,6 +139,22 @@ func TestNewMergeFailedMessage(t *testing.T) {
 	if !strings.Contains(msg.Body, "Error: Test failed") {
 		t.Errorf("Body missing error: %s", msg.Body)
 	}
+	if strings.Contains(msg.Body, "Failing-Tests:") {
+		t.Errorf("Body should omit failing tests when there are none: %s", msg.Body)
+	}
+}
+
+func TestNewMergeFailedMessage_FailingTests(t *testing.T) {
+	tests := []string{"unit: pkg.TestFoo", "unit: pkg.TestBar"}
+	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "quality gates failed", tests)
+
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailedPayload: %v", err)
+	}
+	if strings.Join(payload.FailingTests, ",") != strings.Join(tests, ",") {
+		t.Errorf("FailingTests = %v, want %v", payload.FailingTests, tests)
+	}
 }
 
 func TestNewReworkRequestMessage(t *testing.T) {
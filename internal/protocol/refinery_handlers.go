@@ -79,8 +79,8 @@ func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch
 
 // SendMergeFailed sends a MERGE_FAILED message to the Witness.
 // Called by the Refinery when a merge fails.
-func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string) error {
-	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg)
+func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string, failingTests []string) error {
+	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg, failingTests)
 	return h.Router.Send(msg)
 }
 
@@ -111,6 +111,9 @@ type MergeOutcome struct {
 
 	// ConflictFiles lists files with conflicts (if Conflict is true).
 	ConflictFiles []string
+
+	// FailingTests lists failing tests from gate reports (if any).
+	FailingTests []string
 }
 
 // NotifyMergeOutcome sends the appropriate protocol message based on the outcome.
@@ -123,7 +126,7 @@ func (h *DefaultRefineryHandler) NotifyMergeOutcome(polecat, branch, issue, targ
 		return h.SendReworkRequest(polecat, branch, issue, targetBranch, outcome.ConflictFiles)
 	}
 
-	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error)
+	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error, outcome.FailingTests)
 }
 
 // Ensure DefaultRefineryHandler implements RefineryHandler.
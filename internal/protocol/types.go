@@ -136,6 +136,10 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// FailingTests lists failing tests parsed from gate reports
+	// ("<gate>: <test>"), if any.
+	FailingTests []string `json:"failing_tests,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
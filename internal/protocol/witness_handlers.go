@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -203,13 +204,12 @@ func (h *DefaultWitnessHandler) notifyPolecatFailed(payload *MergeFailedPayload)
 Branch: %s
 Issue: %s
 Failure: %s
-Error: %s
-
+%s
 Please fix the issue and resubmit your work with 'gt done'.`,
 			payload.Branch,
 			payload.Issue,
 			payload.FailureType,
-			payload.Error,
+			formatFailureDetail(payload),
 		),
 	)
 	msg.Priority = mail.PriorityHigh
@@ -218,6 +218,20 @@ Please fix the issue and resubmit your work with 'gt done'.`,
 	return h.Router.Send(msg)
 }
 
+// formatFailureDetail lists the failing tests when the refinery parsed them
+// from gate reports, and falls back to the raw error otherwise.
+func formatFailureDetail(payload *MergeFailedPayload) string {
+	if len(payload.FailingTests) == 0 {
+		return fmt.Sprintf("Error: %s\n", payload.Error)
+	}
+	var sb strings.Builder
+	sb.WriteString("Failing tests:\n")
+	for _, t := range payload.FailingTests {
+		sb.WriteString(fmt.Sprintf("  - %s\n", t))
+	}
+	return sb.String()
+}
+
 // notifyPolecatRebase sends a rebase request notification to a polecat.
 func (h *DefaultWitnessHandler) notifyPolecatRebase(payload *ReworkRequestPayload) error {
 	conflictInfo := ""
@@ -0,0 +1,44 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Prune deletes segments last written more than ttl before now, and the
+// agent directories they leave empty. Segments still being recorded are
+// written continuously, so they're never old enough to prune. Returns the
+// number of segments and bytes removed.
+func Prune(townRoot string, ttl time.Duration, now time.Time) (segments int, bytes int64, err error) {
+	agents, err := ListAgents(townRoot)
+	if err != nil {
+		return 0, 0, err
+	}
+	cutoff := now.Add(-ttl)
+	for _, agent := range agents {
+		segs, err := ListSegments(townRoot, agent)
+		if err != nil {
+			return segments, bytes, err
+		}
+		kept := len(segs)
+		for _, seg := range segs {
+			if !seg.End.Before(cutoff) {
+				continue
+			}
+			if err := os.Remove(seg.Path); err != nil && !os.IsNotExist(err) {
+				return segments, bytes, fmt.Errorf("removing recording %s: %w", seg.Name, err)
+			}
+			segments++
+			bytes += seg.Bytes
+			kept--
+		}
+		if kept == 0 {
+			// Fails harmlessly if the directory holds anything else.
+			_ = os.Remove(AgentDir(townRoot, agent))
+			_ = os.Remove(filepath.Dir(AgentDir(townRoot, agent)))
+		}
+	}
+	return segments, bytes, nil
+}
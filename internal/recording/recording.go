@@ -0,0 +1,347 @@
+// Package recording captures agent tmux panes as asciicast v2 files so a
+// session's terminal output survives the session.
+//
+// tmux pipe-pane feeds the pane's output to 'gt session record-pipe', which
+// writes it with a Recorder. Recordings are rotated into segments and kept
+// per agent:
+//
+//	.recordings/
+//	  gastown/nux/20260501T093000Z.cast
+//	  gastown/nux/20260501T103000Z.cast
+//
+// Segments are pruned by 'gt krc prune' once past the KRC recording TTL.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Dir is the directory, relative to the town root, holding recordings.
+const Dir = ".recordings"
+
+// Default rotation limits for a segment.
+const (
+	DefaultSegmentBytes    = 8 * 1024 * 1024
+	DefaultSegmentDuration = time.Hour
+)
+
+const segmentTimeFormat = "20060102T150405Z"
+
+// agentPartRe matches one component of an agent name ("gastown", "nux").
+var agentPartRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// segmentNameRe matches segment file names: start time plus an optional
+// counter for segments started within the same second.
+var segmentNameRe = regexp.MustCompile(`^(\d{8}T\d{6}Z)(?:-(\d+))?\.cast$`)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Segment is one recording file.
+type Segment struct {
+	Agent string    `json:"agent"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"` // last write
+	Bytes int64     `json:"bytes"`
+	Path  string    `json:"-"`
+	seq   int
+}
+
+// ValidAgent reports whether agent is a "<rig>/<name>" pair that is safe to
+// use as a path under Dir.
+func ValidAgent(agent string) bool {
+	rig, name, ok := strings.Cut(agent, "/")
+	return ok && agentPartRe.MatchString(rig) && agentPartRe.MatchString(name)
+}
+
+// AgentDir returns the recordings directory for an agent ("<rig>/<name>").
+func AgentDir(townRoot, agent string) string {
+	return filepath.Join(townRoot, Dir, filepath.FromSlash(agent))
+}
+
+// ListAgents returns the agents that have recordings, sorted.
+func ListAgents(townRoot string) ([]string, error) {
+	rigs, err := os.ReadDir(filepath.Join(townRoot, Dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading recordings: %w", err)
+	}
+	var agents []string
+	for _, rig := range rigs {
+		if !rig.IsDir() {
+			continue
+		}
+		names, err := os.ReadDir(filepath.Join(townRoot, Dir, rig.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading recordings: %w", err)
+		}
+		for _, name := range names {
+			agent := rig.Name() + "/" + name.Name()
+			if name.IsDir() && ValidAgent(agent) {
+				agents = append(agents, agent)
+			}
+		}
+	}
+	sort.Strings(agents)
+	return agents, nil
+}
+
+// ListSegments returns an agent's segments, oldest first.
+func ListSegments(townRoot, agent string) ([]Segment, error) {
+	if !ValidAgent(agent) {
+		return nil, fmt.Errorf("invalid agent %q: expected <rig>/<name>", agent)
+	}
+	dir := AgentDir(townRoot, agent)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading recordings: %w", err)
+	}
+	var segs []Segment
+	for _, e := range entries {
+		start, seq, ok := parseSegmentName(e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		segs = append(segs, Segment{
+			Agent: agent,
+			Name:  e.Name(),
+			Start: start,
+			End:   info.ModTime(),
+			Bytes: info.Size(),
+			Path:  filepath.Join(dir, e.Name()),
+			seq:   seq,
+		})
+	}
+	sort.Slice(segs, func(i, j int) bool {
+		if !segs[i].Start.Equal(segs[j].Start) {
+			return segs[i].Start.Before(segs[j].Start)
+		}
+		return segs[i].seq < segs[j].seq
+	})
+	return segs, nil
+}
+
+// FindSegment returns the named segment of an agent.
+func FindSegment(townRoot, agent, name string) (Segment, error) {
+	segs, err := ListSegments(townRoot, agent)
+	if err != nil {
+		return Segment{}, err
+	}
+	for _, s := range segs {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Segment{}, fmt.Errorf("no recording %q for %s", name, agent)
+}
+
+// SegmentAt returns the segment that was recording at t.
+func SegmentAt(segs []Segment, t time.Time) (Segment, bool) {
+	for i := len(segs) - 1; i >= 0; i-- {
+		if !segs[i].Start.After(t) {
+			if t.After(segs[i].End) {
+				return Segment{}, false
+			}
+			return segs[i], true
+		}
+	}
+	return Segment{}, false
+}
+
+func parseSegmentName(name string) (time.Time, int, bool) {
+	m := segmentNameRe.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, 0, false
+	}
+	start, err := time.Parse(segmentTimeFormat, m[1])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	seq := 0
+	if m[2] != "" {
+		seq, _ = strconv.Atoi(m[2])
+	}
+	return start, seq, true
+}
+
+// Options configures a Recorder.
+type Options struct {
+	Width, Height   int
+	SegmentBytes    int64         // rotate after this many bytes (0 = default)
+	SegmentDuration time.Duration // rotate after this long (0 = default)
+	Now             func() time.Time
+}
+
+// Recorder writes pane output as asciicast v2 output events, starting a new
+// segment when the current one reaches the size or age limit. Events are
+// written straight through so a segment is playable while it's recorded and
+// nothing is lost if the recorder is killed.
+type Recorder struct {
+	dir, agent string
+	opts       Options
+
+	f       *os.File
+	start   time.Time
+	written int64
+	pending []byte // trailing bytes of an incomplete UTF-8 sequence
+}
+
+// NewRecorder returns a Recorder for an agent. The first segment is created
+// on the first write.
+func NewRecorder(townRoot, agent string, opts Options) (*Recorder, error) {
+	if !ValidAgent(agent) {
+		return nil, fmt.Errorf("invalid agent %q: expected <rig>/<name>", agent)
+	}
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+	if opts.Height <= 0 {
+		opts.Height = 24
+	}
+	if opts.SegmentBytes <= 0 {
+		opts.SegmentBytes = DefaultSegmentBytes
+	}
+	if opts.SegmentDuration <= 0 {
+		opts.SegmentDuration = DefaultSegmentDuration
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Recorder{dir: AgentDir(townRoot, agent), agent: agent, opts: opts}, nil
+}
+
+// Write records p as one output event.
+func (r *Recorder) Write(p []byte) (int, error) {
+	data := append(r.pending, p...)
+	cut := completeUTF8(data)
+	r.pending = append([]byte(nil), data[cut:]...)
+	if cut == 0 {
+		return len(p), nil
+	}
+
+	now := r.opts.Now()
+	if r.f == nil || r.written >= r.opts.SegmentBytes || now.Sub(r.start) >= r.opts.SegmentDuration {
+		if err := r.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+	elapsed := math.Round(now.Sub(r.start).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, "o", string(data[:cut])})
+	if err != nil {
+		return 0, fmt.Errorf("encoding event: %w", err)
+	}
+	if err := r.writeLine(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close flushes any incomplete trailing bytes and closes the segment.
+func (r *Recorder) Close() error {
+	if len(r.pending) > 0 && r.f != nil {
+		line, _ := json.Marshal([]interface{}{
+			math.Round(r.opts.Now().Sub(r.start).Seconds()*1e6) / 1e6, "o", string(r.pending)})
+		_ = r.writeLine(line)
+		r.pending = nil
+	}
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *Recorder) rotate(now time.Time) error {
+	if r.f != nil {
+		if err := r.f.Close(); err != nil {
+			return fmt.Errorf("closing segment: %w", err)
+		}
+		r.f = nil
+	}
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("creating recordings directory: %w", err)
+	}
+
+	base := now.UTC().Format(segmentTimeFormat)
+	for n := 0; ; n++ {
+		name := base + ".cast"
+		if n > 0 {
+			name = fmt.Sprintf("%s-%d.cast", base, n)
+		}
+		f, err := os.OpenFile(filepath.Join(r.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) //nolint:gosec // G304: path is constructed internally
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("creating segment: %w", err)
+		}
+		r.f = f
+		break
+	}
+	r.start, r.written = now, 0
+
+	header, err := json.Marshal(Header{
+		Version:   2,
+		Width:     r.opts.Width,
+		Height:    r.opts.Height,
+		Timestamp: now.Unix(),
+		Title:     r.agent,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+	if err != nil {
+		return fmt.Errorf("encoding header: %w", err)
+	}
+	return r.writeLine(header)
+}
+
+func (r *Recorder) writeLine(line []byte) error {
+	n, err := r.f.Write(append(line, '\n'))
+	r.written += int64(n)
+	if err != nil {
+		return fmt.Errorf("writing recording: %w", err)
+	}
+	return nil
+}
+
+// completeUTF8 returns the length of the longest prefix of p that doesn't
+// end in the middle of a UTF-8 sequence. Bytes that can never start a valid
+// sequence are left in the prefix.
+func completeUTF8(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}
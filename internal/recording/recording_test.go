@@ -0,0 +1,165 @@
+package recording
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestValidAgent(t *testing.T) {
+	for agent, want := range map[string]bool{
+		"gastown/nux":    true,
+		"my-rig/Toast_2": true,
+		"gastown":        false,
+		"gastown/":       false,
+		"../etc/passwd":  false,
+		"gastown/../x":   false,
+		"gastown/a/b":    false,
+		"gastown/.hid":   false,
+	} {
+		if got := ValidAgent(agent); got != want {
+			t.Errorf("ValidAgent(%q) = %v, want %v", agent, got, want)
+		}
+	}
+}
+
+func TestRecorderWritesAsciicastAndRotates(t *testing.T) {
+	townRoot := t.TempDir()
+	clock := &fakeClock{t: time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)}
+	rec, err := NewRecorder(townRoot, "gastown/nux", Options{Width: 120, Height: 40, SegmentDuration: time.Minute, Now: clock.now})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = rec.Write([]byte("hello "))
+	clock.t = clock.t.Add(1500 * time.Millisecond)
+	// "é" split across two writes is held back until complete.
+	_, _ = rec.Write([]byte{'c', 'a', 'f', 0xc3})
+	_, _ = rec.Write([]byte{0xa9, '\n'})
+	clock.t = clock.t.Add(time.Minute)
+	_, _ = rec.Write([]byte("next segment"))
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	segs, err := ListSegments(townRoot, "gastown/nux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segs) != 2 || segs[0].Name != "20260501T093000Z.cast" || segs[1].Name != "20260501T093101Z.cast" {
+		t.Fatalf("segments = %+v", segs)
+	}
+
+	h, err := ReadHeader(segs[0].Path)
+	if err != nil || h.Width != 120 || h.Height != 40 || h.Title != "gastown/nux" {
+		t.Fatalf("header = %+v, %v", h, err)
+	}
+	data, _ := os.ReadFile(segs[0].Path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{`[0,"o","hello "]`, `[1.5,"o","caf"]`, `[1.5,"o","é\n"]`}
+	if len(lines) != 4 || strings.Join(lines[1:], "|") != strings.Join(want, "|") {
+		t.Errorf("events = %q", lines[1:])
+	}
+
+	var out bytes.Buffer
+	if err := Replay(&out, bytes.NewReader(data), ReplayOptions{Sleep: func(time.Duration) {}}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "hello café\n" {
+		t.Errorf("replay = %q", out.String())
+	}
+}
+
+func TestRecorderNeverOverwritesSegment(t *testing.T) {
+	townRoot := t.TempDir()
+	clock := &fakeClock{t: time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)}
+	for i := 0; i < 2; i++ {
+		rec, _ := NewRecorder(townRoot, "gastown/nux", Options{Now: clock.now})
+		_, _ = rec.Write([]byte("x"))
+		_ = rec.Close()
+	}
+	segs, _ := ListSegments(townRoot, "gastown/nux")
+	if len(segs) != 2 || segs[1].Name != "20260501T093000Z-1.cast" {
+		t.Errorf("segments = %+v", segs)
+	}
+}
+
+func TestReplayOffsetAndIdleLimit(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24,"timestamp":0}
+[0.5,"o","a"]
+[1,"i","ignored"]
+[2,"o","b"]
+[10,"o","c"]
+[12,"o","d`
+	var slept []time.Duration
+	var out bytes.Buffer
+	err := Replay(&out, strings.NewReader(cast), ReplayOptions{
+		Speed:     2,
+		IdleLimit: 3 * time.Second,
+		Offset:    time.Second,
+		Sleep:     func(d time.Duration) { slept = append(slept, d) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "abc" {
+		t.Errorf("replay = %q", out.String())
+	}
+	// "a" is before the offset; "b" waits 1s after it, "c" is capped at 3s.
+	want := []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond}
+	if len(slept) != 2 || slept[0] != want[0] || slept[1] != want[1] {
+		t.Errorf("slept = %v, want %v", slept, want)
+	}
+}
+
+func TestSegmentAt(t *testing.T) {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	segs := []Segment{
+		{Name: "a", Start: base, End: base.Add(time.Hour)},
+		{Name: "b", Start: base.Add(2 * time.Hour), End: base.Add(3 * time.Hour)},
+	}
+	for _, tt := range []struct {
+		at   time.Duration
+		want string
+	}{
+		{30 * time.Minute, "a"},
+		{150 * time.Minute, "b"},
+		{90 * time.Minute, ""},
+		{-time.Minute, ""},
+	} {
+		seg, ok := SegmentAt(segs, base.Add(tt.at))
+		if (tt.want == "") == ok || seg.Name != tt.want {
+			t.Errorf("SegmentAt(+%v) = %q, %v", tt.at, seg.Name, ok)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	old := filepath.Join(AgentDir(townRoot, "gastown/nux"), "20260101T000000Z.cast")
+	fresh := filepath.Join(AgentDir(townRoot, "gastown/toast"), "20260102T000000Z.cast")
+	for _, p := range []string{old, fresh} {
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte("{}\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = os.Chtimes(old, now.Add(-8*24*time.Hour), now.Add(-8*24*time.Hour))
+
+	n, bytes, err := Prune(townRoot, 7*24*time.Hour, now)
+	if err != nil || n != 1 || bytes != 3 {
+		t.Fatalf("Prune = %d, %d, %v", n, bytes, err)
+	}
+	agents, _ := ListAgents(townRoot)
+	if len(agents) != 1 || agents[0] != "gastown/toast" {
+		t.Errorf("agents = %v", agents)
+	}
+}
@@ -0,0 +1,115 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ReplayOptions controls playback speed.
+type ReplayOptions struct {
+	// Speed multiplies playback speed (0 = 1x).
+	Speed float64
+
+	// IdleLimit caps the pause between events (0 = no cap).
+	IdleLimit time.Duration
+
+	// Offset skips ahead: events before it are written without pausing so
+	// the screen is rebuilt as it was at that point.
+	Offset time.Duration
+
+	// Sleep pauses between events (default time.Sleep).
+	Sleep func(time.Duration)
+}
+
+// ReadHeader reads the header of a recording file.
+func ReadHeader(path string) (Header, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from ListSegments
+	if err != nil {
+		return Header{}, fmt.Errorf("opening recording: %w", err)
+	}
+	defer f.Close()
+	return readHeader(bufio.NewReader(f))
+}
+
+func readHeader(r *bufio.Reader) (Header, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Header{}, fmt.Errorf("reading recording: %w", err)
+	}
+	var h Header
+	if err := json.Unmarshal(line, &h); err != nil || h.Version != 2 {
+		return Header{}, fmt.Errorf("not an asciicast v2 recording")
+	}
+	return h, nil
+}
+
+// Replay plays an asciicast v2 recording from r to w in real time, scaled by
+// opts. Input and resize events are skipped; a truncated last line (a segment
+// still being written) ends playback.
+func Replay(w io.Writer, r io.Reader, opts ReplayOptions) error {
+	if opts.Speed <= 0 {
+		opts.Speed = 1
+	}
+	if opts.Sleep == nil {
+		opts.Sleep = time.Sleep
+	}
+
+	reader := bufio.NewReader(r)
+	if _, err := readHeader(reader); err != nil {
+		return err
+	}
+
+	var last time.Duration
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var ev []interface{}
+			if jsonErr := json.Unmarshal(line, &ev); jsonErr != nil {
+				if err != nil {
+					return nil
+				}
+				return fmt.Errorf("malformed event: %w", jsonErr)
+			}
+			at, kind, data, ok := parseEvent(ev)
+			if ok && kind == "o" {
+				if at > opts.Offset {
+					delay := at - max(last, opts.Offset)
+					if opts.IdleLimit > 0 && delay > opts.IdleLimit {
+						delay = opts.IdleLimit
+					}
+					if delay > 0 {
+						opts.Sleep(time.Duration(float64(delay) / opts.Speed))
+					}
+				}
+				if _, werr := io.WriteString(w, data); werr != nil {
+					return werr
+				}
+				last = at
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading recording: %w", err)
+		}
+	}
+}
+
+func parseEvent(ev []interface{}) (time.Duration, string, string, bool) {
+	if len(ev) != 3 {
+		return 0, "", "", false
+	}
+	secs, ok1 := ev[0].(float64)
+	kind, ok2 := ev[1].(string)
+	data, ok3 := ev[2].(string)
+	if !ok1 || !ok2 || !ok3 {
+		return 0, "", "", false
+	}
+	return time.Duration(secs * float64(time.Second)), kind, data, true
+}
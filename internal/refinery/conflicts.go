@@ -0,0 +1,279 @@
+// This file contains conflict prediction: comparing the changes of all
+// in-flight polecat branches and open MRs in a rig to flag likely merge
+// conflicts before they reach the merge queue.
+
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FileConflictForecastJSON is the cached forecast in the rig's .runtime dir.
+const FileConflictForecastJSON = "conflict-forecast.json"
+
+// ConflictForecastMaxAge is how long a cached forecast is trusted by
+// callers that don't recompute it (sling and submit warnings).
+const ConflictForecastMaxAge = 30 * time.Minute
+
+// BranchChanges is what one in-flight branch changes relative to its target.
+type BranchChanges struct {
+	Branch string `json:"branch"`
+	Target string `json:"target"`
+	MRID   string `json:"mr_id,omitempty"` // empty until submitted to the queue
+	Worker string `json:"worker,omitempty"`
+
+	// Files maps each changed path to the target-side line ranges it replaces.
+	Files map[string][]git.LineRange `json:"files"`
+}
+
+// FileOverlap is one file changed on both branches of an Overlap.
+type FileOverlap struct {
+	Path string `json:"path"`
+
+	// Hunks is true when changed line ranges overlap or touch, which git
+	// usually can't merge cleanly. Otherwise only the same file is touched.
+	Hunks bool `json:"hunks"`
+}
+
+// Overlap is a pair of branches with the same target that change the same files.
+type Overlap struct {
+	A     string        `json:"a"`
+	B     string        `json:"b"`
+	Files []FileOverlap `json:"files"`
+}
+
+// Likely reports whether any shared file has overlapping hunks.
+func (o Overlap) Likely() bool {
+	for _, f := range o.Files {
+		if f.Hunks {
+			return true
+		}
+	}
+	return false
+}
+
+// Other returns the branch on the other side of the overlap from branch.
+func (o Overlap) Other(branch string) string {
+	if o.A == branch {
+		return o.B
+	}
+	return o.A
+}
+
+// ConflictForecast is a snapshot of the overlap graph for a rig.
+type ConflictForecast struct {
+	Rig         string           `json:"rig"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Branches    []*BranchChanges `json:"branches"`
+	Overlaps    []Overlap        `json:"overlaps"`
+}
+
+// PredictConflicts compares every pair of branches with the same target and
+// returns those that change a common file, in branch order.
+func PredictConflicts(branches []*BranchChanges) []Overlap {
+	sorted := append([]*BranchChanges(nil), branches...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Branch < sorted[j].Branch })
+
+	var overlaps []Overlap
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if a.Target != b.Target {
+				continue
+			}
+			if files := overlappingFiles(a.Files, b.Files); len(files) > 0 {
+				overlaps = append(overlaps, Overlap{A: a.Branch, B: b.Branch, Files: files})
+			}
+		}
+	}
+	return overlaps
+}
+
+func overlappingFiles(a, b map[string][]git.LineRange) []FileOverlap {
+	var files []FileOverlap
+	for path, ra := range a {
+		rb, ok := b[path]
+		if !ok {
+			continue
+		}
+		files = append(files, FileOverlap{Path: path, Hunks: rangesTouch(ra, rb)})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// rangesTouch reports whether any range in a touches any range in b. A file
+// with no hunks (binary, mode change) counts as touching everything.
+func rangesTouch(a, b []git.LineRange) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, x := range a {
+		for _, y := range b {
+			if x.Touches(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OverlapsFor returns the overlaps involving branch.
+func (f *ConflictForecast) OverlapsFor(branch string) []Overlap {
+	var out []Overlap
+	for _, o := range f.Overlaps {
+		if o.A == branch || o.B == branch {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Compare checks changes (typically a branch about to be submitted) against
+// every other branch in the forecast with the same target.
+func (f *ConflictForecast) Compare(changes *BranchChanges) []Overlap {
+	others := []*BranchChanges{changes}
+	for _, b := range f.Branches {
+		if b.Branch != changes.Branch {
+			others = append(others, b)
+		}
+	}
+	var out []Overlap
+	for _, o := range PredictConflicts(others) {
+		if o.A == changes.Branch || o.B == changes.Branch {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// BranchesTouching maps each branch to the changed files under paths. A path
+// ending in "/" (or naming a directory) matches everything below it.
+func (f *ConflictForecast) BranchesTouching(paths []string) map[string][]string {
+	touching := make(map[string][]string)
+	for _, b := range f.Branches {
+		for file := range b.Files {
+			for _, p := range paths {
+				dir := strings.TrimSuffix(p, "/")
+				if file == p || strings.HasPrefix(file, dir+"/") {
+					touching[b.Branch] = append(touching[b.Branch], file)
+					break
+				}
+			}
+		}
+		sort.Strings(touching[b.Branch])
+	}
+	return touching
+}
+
+// ConflictForecastPath returns the cached forecast path for a rig.
+func ConflictForecastPath(rigPath string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), FileConflictForecastJSON)
+}
+
+// SaveConflictForecast writes f to the rig's forecast cache.
+func SaveConflictForecast(rigPath string, f *ConflictForecast) error {
+	return util.EnsureDirAndWriteJSON(ConflictForecastPath(rigPath), f)
+}
+
+// LoadConflictForecast reads the rig's cached forecast. Returns nil (and no
+// error) if none has been recorded yet.
+func LoadConflictForecast(rigPath string) (*ConflictForecast, error) {
+	data, err := os.ReadFile(ConflictForecastPath(rigPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading conflict forecast: %w", err)
+	}
+	var f ConflictForecast
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing conflict forecast: %w", err)
+	}
+	return &f, nil
+}
+
+// ForecastConflicts fetches origin and builds the overlap graph of all
+// in-flight polecat branches (pushed to origin) and open MRs in the rig.
+func (e *Engineer) ForecastConflicts() (*ConflictForecast, error) {
+	if err := e.git.FetchPrune("origin"); err != nil {
+		return nil, fmt.Errorf("fetching origin: %w", err)
+	}
+
+	defaultBranch := e.rig.DefaultBranch()
+	byBranch := make(map[string]*BranchChanges)
+
+	polecatBranches, err := e.git.ListRemoteTrackingBranches("origin", "polecat/")
+	if err != nil {
+		return nil, fmt.Errorf("listing polecat branches: %w", err)
+	}
+	for _, branch := range polecatBranches {
+		byBranch[branch] = &BranchChanges{Branch: branch, Target: defaultBranch, Worker: polecatFromBranch(branch)}
+	}
+
+	// Open MRs supply the real target and worker, and cover non-polecat branches
+	issues, err := e.beads.List(beads.ListOptions{
+		Status:   "open",
+		Label:    "gt:merge-request",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil || fields.Branch == "" || issue.Status != "open" {
+			continue
+		}
+		bc := byBranch[fields.Branch]
+		if bc == nil {
+			bc = &BranchChanges{Branch: fields.Branch, Target: defaultBranch}
+			byBranch[fields.Branch] = bc
+		}
+		bc.MRID = issue.ID
+		if fields.Target != "" {
+			bc.Target = fields.Target
+		}
+		if fields.Worker != "" {
+			bc.Worker = fields.Worker
+		}
+	}
+
+	forecast := &ConflictForecast{Rig: e.rig.Name, GeneratedAt: time.Now().UTC()}
+	for _, bc := range byBranch {
+		files, err := e.git.DiffHunks("origin/"+bc.Target, "origin/"+bc.Branch)
+		if err != nil {
+			// Branch deleted after merge, or target missing: nothing in flight
+			continue
+		}
+		if len(files) == 0 {
+			continue
+		}
+		bc.Files = files
+		forecast.Branches = append(forecast.Branches, bc)
+	}
+	sort.Slice(forecast.Branches, func(i, j int) bool {
+		return forecast.Branches[i].Branch < forecast.Branches[j].Branch
+	})
+	forecast.Overlaps = PredictConflicts(forecast.Branches)
+	return forecast, nil
+}
+
+// polecatFromBranch extracts the polecat name from "polecat/<name>/...".
+func polecatFromBranch(branch string) string {
+	parts := strings.Split(branch, "/")
+	if len(parts) >= 2 && parts[0] == "polecat" {
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package refinery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestPredictConflicts(t *testing.T) {
+	branches := []*BranchChanges{
+		{Branch: "polecat/nux/gt-1", Target: "main", Files: map[string][]git.LineRange{
+			"internal/git/git.go": {{Start: 10, End: 20}},
+			"docs/a.md":           {{Start: 1, End: 1}},
+		}},
+		{Branch: "polecat/toast/gt-2", Target: "main", Files: map[string][]git.LineRange{
+			"internal/git/git.go": {{Start: 21, End: 25}}, // adjacent to nux's hunk
+		}},
+		{Branch: "polecat/ace/gt-3", Target: "main", Files: map[string][]git.LineRange{
+			"docs/a.md": {{Start: 40, End: 42}}, // same file, distant lines
+		}},
+		{Branch: "polecat/max/gt-4", Target: "integration/gt-epic", Files: map[string][]git.LineRange{
+			"internal/git/git.go": {{Start: 10, End: 20}}, // different target: ignored
+		}},
+	}
+
+	overlaps := PredictConflicts(branches)
+	if len(overlaps) != 2 {
+		t.Fatalf("got %d overlaps, want 2: %+v", len(overlaps), overlaps)
+	}
+
+	// Branch-sorted: ace < nux < toast
+	if o := overlaps[0]; o.A != "polecat/ace/gt-3" || o.B != "polecat/nux/gt-1" || o.Likely() {
+		t.Errorf("overlap[0] = %+v, want ace/nux same-file only", o)
+	}
+	if o := overlaps[1]; o.A != "polecat/nux/gt-1" || o.B != "polecat/toast/gt-2" || !o.Likely() {
+		t.Errorf("overlap[1] = %+v, want nux/toast likely", o)
+	}
+
+	f := &ConflictForecast{Branches: branches, Overlaps: overlaps}
+	if got := len(f.OverlapsFor("polecat/nux/gt-1")); got != 2 {
+		t.Errorf("OverlapsFor(nux) = %d, want 2", got)
+	}
+
+	// A new branch being submitted is compared against everything else
+	incoming := &BranchChanges{Branch: "polecat/fury/gt-5", Target: "main", Files: map[string][]git.LineRange{
+		"internal/git/git.go": {{Start: 15, End: 15}},
+	}}
+	var likely []string
+	for _, o := range f.Compare(incoming) {
+		if o.Likely() {
+			likely = append(likely, o.Other(incoming.Branch))
+		}
+	}
+	if !reflect.DeepEqual(likely, []string{"polecat/nux/gt-1"}) {
+		t.Errorf("Compare likely = %v, want [polecat/nux/gt-1]", likely)
+	}
+
+	touching := f.BranchesTouching([]string{"docs/"})
+	if len(touching) != 2 || touching["polecat/ace/gt-3"][0] != "docs/a.md" {
+		t.Errorf("BranchesTouching(docs/) = %v", touching)
+	}
+}
+
+func TestConflictForecastRoundTrip(t *testing.T) {
+	rigPath := t.TempDir()
+	if f, err := LoadConflictForecast(rigPath); err != nil || f != nil {
+		t.Fatalf("Load before save = %v, %v; want nil, nil", f, err)
+	}
+
+	want := &ConflictForecast{
+		Rig:         "gastown",
+		GeneratedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Branches: []*BranchChanges{{Branch: "polecat/nux/gt-1", Target: "main", MRID: "gt-mr-1",
+			Files: map[string][]git.LineRange{"a.go": {{Start: 1, End: 2}}}}},
+	}
+	if err := SaveConflictForecast(rigPath, want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := LoadConflictForecast(rigPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestPolecatFromBranch(t *testing.T) {
+	for branch, want := range map[string]string{
+		"polecat/nux/gt-abc":  "nux",
+		"polecat/nux-mk123":   "nux-mk123",
+		"feature/polecat/nux": "",
+	} {
+		if got := polecatFromBranch(branch); got != want {
+			t.Errorf("polecatFromBranch(%q) = %q, want %q", branch, got, want)
+		}
+	}
+}
@@ -11,18 +11,19 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
-	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tracing"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -54,6 +55,22 @@ type GateConfig struct {
 	// Timeout is the maximum time the gate command may run.
 	// Zero means no timeout (inherits context deadline).
 	Timeout time.Duration `json:"timeout"`
+
+	// DependsOn names gates that must pass before this one starts.
+	// If any of them fails, this gate is skipped.
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Format is the test report format the gate produces ("junit" or "tap"),
+	// used to extract failing test names. Empty means pass/fail only.
+	Format string `json:"format,omitempty"`
+
+	// Report is the JUnit XML file the gate writes, relative to the
+	// refinery worktree. Empty means the report is read from stdout.
+	Report string `json:"report,omitempty"`
+
+	// NoCache disables reusing a previous pass on the same tree, for gates
+	// that depend on more than the checked-out code.
+	NoCache bool `json:"no_cache,omitempty"`
 }
 
 // GateResult holds the outcome of a single gate execution.
@@ -62,6 +79,30 @@ type GateResult struct {
 	Success bool
 	Error   string
 	Elapsed time.Duration
+
+	// Cached is true when the gate was not run because it already passed
+	// on the same tree.
+	Cached bool
+
+	// Skipped is true when the gate did not run because a dependency failed.
+	Skipped bool
+
+	// FailingTests lists failing test names parsed from the gate's report.
+	FailingTests []string
+
+	// Explained is true when a failed gate exited normally with a non-zero
+	// status and this run's report lists failing tests, so the failure is
+	// accounted for by those tests. Only explained failures may be excused.
+	Explained bool
+
+	// PassedTests lists passing test names parsed from the gate's report.
+	PassedTests []string
+
+	// Quarantined and FailedOnBase list failing tests that were excused:
+	// quarantined as flaky, or failing on the target branch too. A gate whose
+	// failures were all excused counts as passed.
+	Quarantined  []string
+	FailedOnBase []string
 }
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -109,8 +150,40 @@ type MergeQueueConfig struct {
 	Gates map[string]*GateConfig `json:"gates"`
 
 	// GatesParallel controls whether gates run concurrently.
-	// When true, all gates start simultaneously; any failure = overall failure.
+	// When true, each gate starts as soon as its dependencies have passed;
+	// any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// GateCache enables skipping gates that already passed on the same tree.
+	GateCache bool `json:"gate_cache"`
+
+	// FlakyRetryOnBase re-runs a gate on the target branch when it fails
+	// with parsed test failures; tests that fail there too don't block the MR.
+	FlakyRetryOnBase bool `json:"flaky_retry_on_base"`
+
+	// QuarantineThreshold is how many target-branch failures quarantine a
+	// test automatically (see flaky.go). Zero disables automatic quarantine.
+	QuarantineThreshold int `json:"quarantine_threshold"`
+
+	// ScorePolicy names the policy that orders ready MRs (see policy.go).
+	// Empty means PolicyDefault.
+	ScorePolicy string `json:"score_policy"`
+
+	// LeadTimeSLA is the target time from submission to merge, reported
+	// by gt mq stats. Zero means no target.
+	LeadTimeSLA time.Duration `json:"lead_time_sla"`
+
+	// VerifyMain is a check run on the tip of the default branch after merges
+	// land (see verify.go). When it fails, recent commits are bisected to find
+	// the culprit. Nil disables post-merge verification.
+	VerifyMain *GateConfig `json:"verify_main"`
+
+	// BisectDepth caps how many recent commits are searched for the culprit.
+	BisectDepth int `json:"bisect_depth"`
+
+	// AutoRevert opens a revert MR for the culprit and re-slings its bead to
+	// the author. When false, a broken default branch is only escalated.
+	AutoRevert bool `json:"auto_revert"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -124,7 +197,12 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		RetryFlakyTests:      1,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		GateCache:            true,
+		FlakyRetryOnBase:     true,
+		QuarantineThreshold:  DefaultQuarantineThreshold,
 		StaleClaimTimeout:    DefaultStaleClaimTimeout,
+		BisectDepth:          DefaultBisectDepth,
+		AutoRevert:           true,
 	}
 }
 
@@ -145,12 +223,17 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	ParentMR        string     // MR this one is stacked on (held until it closes)
+	TraceID         string     // Trace started when the work was slung
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
 	Assignee           string    // Who claimed this MR (empty = unclaimed)
 	BranchExistsLocal  bool      // Whether the MR branch exists locally
 	BranchExistsRemote bool      // Whether the MR branch exists in remote tracking refs
+
+	// ScoreBreakdown is the MR's score under the rig's policy (set by ListReadyMRs).
+	ScoreBreakdown *ScoreBreakdown `json:",omitempty"`
 }
 
 // MRAnomaly represents an MR queue health problem that can stall processing.
@@ -195,6 +278,7 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	newForge              func(*config.ForgeConfig) (forge.Provider, error)
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -229,6 +313,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		newForge:              forge.New,
 	}
 }
 
@@ -277,6 +362,14 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		GateCache            *bool                      `json:"gate_cache"`
+		FlakyRetryOnBase     *bool                      `json:"flaky_retry_on_base"`
+		QuarantineThreshold  *int                       `json:"quarantine_threshold"`
+		ScorePolicy          *string                    `json:"score_policy"`
+		LeadTimeSLA          *string                    `json:"lead_time_sla"`
+		VerifyMain           *gateConfigRaw             `json:"verify_main"`
+		BisectDepth          *int                       `json:"bisect_depth"`
+		AutoRevert           *bool                      `json:"auto_revert"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -327,23 +420,66 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.Gates != nil {
 		e.config.Gates = make(map[string]*GateConfig, len(mqRaw.Gates))
 		for name, raw := range mqRaw.Gates {
-			gc := &GateConfig{Cmd: raw.Cmd}
-			if raw.Timeout != "" {
-				dur, err := time.ParseDuration(raw.Timeout)
-				if err != nil {
-					return fmt.Errorf("invalid timeout for gate %q: %w", name, err)
-				}
-				if dur <= 0 {
-					return fmt.Errorf("gate %q timeout must be positive, got %v", name, dur)
-				}
-				gc.Timeout = dur
+			gc, err := raw.gateConfig(name)
+			if err != nil {
+				return err
 			}
 			e.config.Gates[name] = gc
 		}
+		if err := ValidateGates(e.config.Gates); err != nil {
+			return fmt.Errorf("invalid gates: %w", err)
+		}
 	}
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.GateCache != nil {
+		e.config.GateCache = *mqRaw.GateCache
+	}
+	if mqRaw.FlakyRetryOnBase != nil {
+		e.config.FlakyRetryOnBase = *mqRaw.FlakyRetryOnBase
+	}
+	if mqRaw.QuarantineThreshold != nil {
+		if *mqRaw.QuarantineThreshold < 0 {
+			return fmt.Errorf("quarantine_threshold must be non-negative, got %d", *mqRaw.QuarantineThreshold)
+		}
+		e.config.QuarantineThreshold = *mqRaw.QuarantineThreshold
+	}
+	if mqRaw.ScorePolicy != nil {
+		if _, err := LookupScorePolicy(*mqRaw.ScorePolicy); err != nil {
+			return fmt.Errorf("invalid score_policy: %w", err)
+		}
+		e.config.ScorePolicy = *mqRaw.ScorePolicy
+	}
+	if mqRaw.LeadTimeSLA != nil {
+		dur, err := time.ParseDuration(*mqRaw.LeadTimeSLA)
+		if err != nil {
+			return fmt.Errorf("invalid lead_time_sla %q: %w", *mqRaw.LeadTimeSLA, err)
+		}
+		if dur < 0 {
+			return fmt.Errorf("lead_time_sla must be non-negative, got %v", dur)
+		}
+		e.config.LeadTimeSLA = dur
+	}
+	if mqRaw.VerifyMain != nil {
+		if strings.TrimSpace(mqRaw.VerifyMain.Cmd) == "" {
+			return fmt.Errorf("verify_main: cmd is required")
+		}
+		gc, err := mqRaw.VerifyMain.gateConfig("verify_main")
+		if err != nil {
+			return err
+		}
+		e.config.VerifyMain = gc
+	}
+	if mqRaw.BisectDepth != nil {
+		if *mqRaw.BisectDepth < 1 {
+			return fmt.Errorf("bisect_depth must be at least 1, got %d", *mqRaw.BisectDepth)
+		}
+		e.config.BisectDepth = *mqRaw.BisectDepth
+	}
+	if mqRaw.AutoRevert != nil {
+		e.config.AutoRevert = *mqRaw.AutoRevert
+	}
 
 	return nil
 }
@@ -351,8 +487,34 @@ func (e *Engineer) LoadConfig() error {
 // gateConfigRaw is the JSON-friendly representation of a gate config
 // with timeout as a string duration.
 type gateConfigRaw struct {
-	Cmd     string `json:"cmd"`
-	Timeout string `json:"timeout"`
+	Cmd       string   `json:"cmd"`
+	Timeout   string   `json:"timeout"`
+	DependsOn []string `json:"depends_on"`
+	Format    string   `json:"format"`
+	Report    string   `json:"report"`
+	NoCache   bool     `json:"no_cache"`
+}
+
+// gateConfig converts raw into a GateConfig, parsing its timeout.
+func (raw *gateConfigRaw) gateConfig(name string) (*GateConfig, error) {
+	gc := &GateConfig{
+		Cmd:       raw.Cmd,
+		DependsOn: raw.DependsOn,
+		Format:    raw.Format,
+		Report:    raw.Report,
+		NoCache:   raw.NoCache,
+	}
+	if raw.Timeout != "" {
+		dur, err := time.ParseDuration(raw.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for gate %q: %w", name, err)
+		}
+		if dur <= 0 {
+			return nil, fmt.Errorf("gate %q timeout must be positive, got %v", name, dur)
+		}
+		gc.Timeout = dur
+	}
+	return gc, nil
 }
 
 // Config returns the current merge queue configuration.
@@ -368,6 +530,15 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	Waiting     bool // Forge checks or reviews still pending; retry later
+
+	// Gates holds per-gate results when quality gates ran.
+	Gates []GateResult
+
+	// GatesStarted and GatesFinished bound the quality gate phase (zero
+	// if gates didn't run).
+	GatesStarted  time.Time
+	GatesFinished time.Time
 }
 
 // doMerge performs the actual git merge operation.
@@ -451,27 +622,6 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
 	}
 
-	// Step 4: Run quality gates (or legacy tests) if configured
-	if len(e.config.Gates) > 0 {
-		// New gates system: run configured quality gates
-		gateResult := e.runGates(ctx)
-		if !gateResult.Success {
-			return gateResult
-		}
-	} else if e.config.RunTests && e.config.TestCommand != "" {
-		// Legacy test command path (backward compatible)
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
-			}
-		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
-	}
-
 	// Step 5: Perform the actual merge using squash merge
 	// Get the original commit message from the polecat branch to preserve the
 	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
@@ -503,6 +653,20 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 5.5: Run quality gates (or legacy tests) if configured.
+	// They run against the squash-merged tree, which is exactly what will be
+	// pushed, so a cached gate pass can be keyed by that tree's hash. On
+	// failure the local squash commit is discarded.
+	gatesStarted := time.Now()
+	gates := e.runQualityChecks(ctx, target)
+	gates.GatesStarted, gates.GatesFinished = gatesStarted, time.Now()
+	if !gates.Success {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after failed gates: %v\n", target, resetErr)
+		}
+		return gates
+	}
+
 	// Step 6: Get the merge commit SHA
 	mergeCommit, err := e.git.Rev("HEAD")
 	if err != nil {
@@ -561,8 +725,11 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
 	return ProcessResult{
-		Success:     true,
-		MergeCommit: mergeCommit,
+		Success:       true,
+		MergeCommit:   mergeCommit,
+		Gates:         gates.Gates,
+		GatesStarted:  gates.GatesStarted,
+		GatesFinished: gates.GatesFinished,
 	}
 }
 
@@ -681,8 +848,37 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 }
 
-// runGate executes a single quality gate command and returns the result.
+// runQualityChecks runs the configured quality gates, or the legacy test
+// command when no gates are configured. Gate failures are checked against
+// target for flaky tests.
+func (e *Engineer) runQualityChecks(ctx context.Context, target string) ProcessResult {
+	if len(e.config.Gates) > 0 {
+		return e.runGatesAgainst(ctx, "origin/"+target)
+	}
+	if e.config.RunTests && e.config.TestCommand != "" {
+		// Legacy test command path (backward compatible)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
+		result := e.runTests(ctx)
+		if !result.Success {
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Error:       result.Error,
+			}
+		}
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+	}
+	return ProcessResult{Success: true}
+}
+
+// runGate executes a single quality gate command in the refinery worktree
+// and returns the result.
 func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+	return e.runGateIn(ctx, e.workDir, name, gate)
+}
+
+// runGateIn executes a single quality gate command in dir.
+func (e *Engineer) runGateIn(ctx context.Context, dir, name string, gate *GateConfig) GateResult {
 	start := time.Now()
 
 	if strings.TrimSpace(gate.Cmd) == "" {
@@ -702,20 +898,27 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		defer cancel()
 	}
 
+	// A report left by an earlier MR must not be read as this run's results
+	if err := clearGateReport(gate, dir); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: gate %q: %v\n", name, err)
+	}
+
 	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
-	cmd.Dir = e.workDir
+	cmd.Dir = dir
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
 	elapsed := time.Since(start)
+	passed, failed := gateTestResults(gate, dir, stdout.Bytes(), start)
 
 	if err == nil {
 		return GateResult{
-			Name:    name,
-			Success: true,
-			Elapsed: elapsed,
+			Name:        name,
+			Success:     true,
+			Elapsed:     elapsed,
+			PassedTests: passed,
 		}
 	}
 
@@ -731,66 +934,117 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		errMsg = fmt.Sprintf("%s: %s", errMsg, stderrStr)
 	}
 
+	// Crashes, kills and timeouts aren't explained by the report, however
+	// many failing tests it lists.
+	var exitErr *exec.ExitError
+	explained := len(failed) > 0 && gateCtx.Err() == nil &&
+		errors.As(err, &exitErr) && exitErr.Exited()
+
 	return GateResult{
-		Name:    name,
-		Success: false,
-		Error:   errMsg,
-		Elapsed: elapsed,
+		Name:         name,
+		Success:      false,
+		Error:        errMsg,
+		Elapsed:      elapsed,
+		FailingTests: failed,
+		PassedTests:  passed,
+		Explained:    explained,
 	}
 }
 
-// runGates executes all configured quality gates and returns a ProcessResult.
-// Gates run in parallel if GatesParallel is true; otherwise sequentially.
-// Any single gate failure means overall failure.
+// runGates executes all configured quality gates without retrying failures
+// on a base branch. See runGatesAgainst.
 func (e *Engineer) runGates(ctx context.Context) ProcessResult {
+	return e.runGatesAgainst(ctx, "")
+}
+
+// runGatesAgainst executes all configured quality gates and returns a
+// ProcessResult. Gates run in dependency order: in parallel if GatesParallel
+// is true (each gate starts once its dependencies pass), otherwise
+// sequentially. Gates that already passed on the current tree are skipped
+// when GateCache is enabled. Failing tests that are quarantined, or that
+// also fail on baseRef (if set), are excused. Any other gate failure means
+// overall failure.
+func (e *Engineer) runGatesAgainst(ctx context.Context, baseRef string) ProcessResult {
 	gates := e.config.Gates
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
 	}
 
-	// Sort gate names for deterministic ordering
-	names := make([]string, 0, len(gates))
-	for name := range gates {
-		names = append(names, name)
+	order, err := gateOrder(gates)
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("invalid gate configuration: %v", err),
+		}
+	}
+
+	var cache *gateCache
+	var tree string
+	if e.config.GateCache {
+		if tree = worktreeTree(e.workDir); tree != "" {
+			cache = loadGateCache(e.rig.Path)
+		}
 	}
-	sort.Strings(names)
 
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(names), e.config.GatesParallel)
+	triage := e.newFlakyTriage(baseRef)
 
-	var results []GateResult
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(order), e.config.GatesParallel)
 
-	if e.config.GatesParallel {
-		results = make([]GateResult, len(names))
-		var wg sync.WaitGroup
-		for i, name := range names {
-			wg.Add(1)
-			go func(idx int, gateName string) {
-				defer wg.Done()
-				_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", gateName, gates[gateName].Cmd)
-				results[idx] = e.runGate(ctx, gateName, gates[gateName])
-			}(i, name)
+	results := scheduleGates(ctx, gates, order, e.config.GatesParallel, func(ctx context.Context, name string) GateResult {
+		gate := gates[name]
+		if cache != nil && !gate.NoCache && cache.passed(name, tree, gate) {
+			return GateResult{Name: name, Success: true, Cached: true}
 		}
-		wg.Wait()
-	} else {
-		for _, name := range names {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gates[name].Cmd)
-			result := e.runGate(ctx, name, gates[name])
-			results = append(results, result)
-			if !result.Success {
-				// Sequential mode: stop on first failure
-				break
-			}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gate.Cmd)
+		result := e.runGate(ctx, name, gate)
+		if result.Success && cache != nil && !gate.NoCache {
+			cache.record(name, tree, gate)
+		}
+		if triage != nil {
+			// Excused passes aren't cached: a released quarantine must re-run
+			result = triage.check(ctx, name, gate, result)
+		}
+		return result
+	})
+
+	if triage != nil {
+		triage.save(results)
+	}
+
+	if cache != nil {
+		if err := cache.save(); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to save gate cache: %v\n", err)
 		}
 	}
 
 	// Report results
 	var failures []string
 	for _, r := range results {
-		if r.Success {
+		switch {
+		case r.Cached:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (cached for tree %s)\n", r.Name, shortSHA(tree))
+		case r.Success && len(r.Quarantined)+len(r.FailedOnBase) > 0:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed with excused failures (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
+			for _, t := range r.Quarantined {
+				_, _ = fmt.Fprintf(e.output, "[Engineer]   quarantined: %s\n", t)
+			}
+			for _, t := range r.FailedOnBase {
+				_, _ = fmt.Fprintf(e.output, "[Engineer]   also fails on %s: %s\n", baseRef, t)
+			}
+		case r.Success:
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
-		} else {
+		case r.Skipped:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: %s\n", r.Name, r.Error)
+		default:
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
-			failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
+			for _, t := range r.FailingTests {
+				_, _ = fmt.Fprintf(e.output, "[Engineer]   failing: %s\n", t)
+			}
+			if len(r.FailingTests) > 0 {
+				failures = append(failures, fmt.Sprintf("%s: %d failing test(s)", r.Name, len(r.FailingTests)))
+			} else {
+				failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
+			}
 		}
 	}
 
@@ -799,11 +1053,20 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			Success:     false,
 			TestsFailed: true,
 			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
+			Gates:       results,
 		}
 	}
 
 	_, _ = fmt.Fprintln(e.output, "[Engineer] All quality gates passed")
-	return ProcessResult{Success: true}
+	return ProcessResult{Success: true, Gates: results}
+}
+
+// shortSHA abbreviates a hash for log output.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
 }
 
 // syncCrewWorkspaces pulls latest changes to all crew workspaces.
@@ -838,8 +1101,28 @@ func (e *Engineer) syncCrewWorkspaces() {
 	}
 }
 
-// ProcessMRInfo processes a merge request from MRInfo.
+// ProcessMRInfo processes a merge request from MRInfo, recording the
+// attempt in the rig's merge queue metrics.
 func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
+	claimed := time.Now()
+	span := tracing.Start(filepath.Dir(e.rig.Path), "refinery.merge", tracing.Root(mr.TraceID))
+	span.SetAttr("bead", mr.SourceIssue)
+	span.SetAttr("mr", mr.ID)
+	result := e.processMRInfo(ctx, mr)
+	e.recordAttempt(mr, claimed, result)
+	span.SetAttr("commit", result.MergeCommit)
+	var spanErr error
+	if !result.Success && !result.Waiting {
+		spanErr = errors.New("merge failed")
+		if result.Error != "" {
+			spanErr = errors.New(result.Error)
+		}
+	}
+	span.End(spanErr)
+	return result
+}
+
+func (e *Engineer) processMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
 	// MR fields are directly on the struct
 	_, _ = fmt.Fprintln(e.output, "[Engineer] Processing MR:")
 	_, _ = fmt.Fprintf(e.output, "  Branch: %s\n", mr.Branch)
@@ -847,6 +1130,12 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	// Rigs that gate on the forge hold the MR until its pull request's
+	// CI checks pass and reviewers approve
+	if result, held := e.forgeGate(ctx, mr); held {
+		return result
+	}
+
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
@@ -878,6 +1167,10 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			}
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
+			if len(result.Gates) > 0 {
+				mrFields.GateResults = FormatGateResults(result.Gates)
+			}
+			mrFields.FailingTests = ""
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -923,6 +1216,13 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// 2.5. Move MRs stacked on this one onto the target so they can merge next
+	if results, err := e.SyncStacks(context.Background()); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to restack MRs on %s: %v\n", mr.ID, err)
+	} else if len(results) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Checked %d stacked MR(s)\n", len(results))
+	}
+
 	// 3. Check and auto-close completed convoys
 	// After closing a source issue, its parent convoy may now be complete.
 	// Run convoy check to auto-close and notify subscribers.
@@ -934,7 +1234,8 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
-// For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
+// For slot timeouts and pending forge checks, the MR stays in queue for automatic
+// retry without notifying polecats.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	// Slot timeout is transient infrastructure contention — not a build/test/conflict failure.
@@ -946,6 +1247,13 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// Pending forge checks or reviews are not the worker's to fix either.
+	if result.Waiting {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Waiting on forge: %s - %s\n", mr.ID, result.Error)
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue until checks and reviews complete")
+		return
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -954,13 +1262,20 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
+	failingTests := FailingTests(result.Gates)
+	if len(result.Gates) > 0 {
+		e.recordGateResults(mr, result.Gates, failingTests)
+	}
+	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error, failingTests)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
 	} else {
 		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
 	}
 
+	// MRs stacked on this one will have to follow its rework
+	e.notifyStackRework(mr, result.Error)
+
 	// If this was a conflict, create a conflict-resolution task for dispatch
 	// and block the MR until the task is resolved (non-blocking delegation)
 	if result.Conflict {
@@ -987,6 +1302,29 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// recordGateResults stores the per-gate outcome and failing tests on the MR
+// bead, so they survive the refinery session and show up in bd show.
+func (e *Engineer) recordGateResults(mr *MRInfo, results []GateResult, failingTests []string) {
+	if mr.ID == "" {
+		return
+	}
+	mrBead, err := e.beads.Show(mr.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	mrFields.GateResults = FormatGateResults(results)
+	mrFields.FailingTests = strings.Join(failingTests, "; ")
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record gate results on MR %s: %v\n", mr.ID, err)
+	}
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1127,6 +1465,11 @@ func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
 	return issue.Status != "closed", nil
 }
 
+// MRInfoFromIssue converts a merge-request bead and its parsed fields to MRInfo.
+func MRInfoFromIssue(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
+	return issueToMRInfo(issue, fields)
+}
+
 // issueToMRInfo converts a beads issue (with parsed MR fields) into an MRInfo.
 // Shared by ListReadyMRs, ListBlockedMRs, and ListAllOpenMRs.
 func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
@@ -1167,6 +1510,8 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
+		ParentMR:        fields.ParentMR,
+		TraceID:         fields.TraceID,
 	}
 }
 
@@ -1185,7 +1530,7 @@ func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
 // - Not blocked by an open task (checked via firstOpenBlocker)
-// Sorted by priority (highest first).
+// Sorted by the rig's score policy (highest score first).
 //
 // Uses bd list instead of bd ready because MRs are ephemeral beads and
 // bd ready filters out ephemeral issues (see gt-t5t6y). This matches the
@@ -1229,6 +1574,12 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue // Skip issues without MR fields
 		}
 
+		// Skip stacked MRs: they wait for their parent to close and for
+		// SyncStacks to rebase them onto the target.
+		if fields.ParentMR != "" {
+			continue
+		}
+
 		// Skip if already assigned, unless claim is stale (allows re-claim after crash).
 		// NOTE: Only one refinery runs per rig (enforced by ErrAlreadyRunning in
 		// manager.go), so concurrent re-claim race conditions are not a concern.
@@ -1248,7 +1599,24 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		mrs = append(mrs, issueToMRInfo(issue, fields))
 	}
 
-	return mrs, nil
+	return e.sortByScorePolicy(mrs), nil
+}
+
+// sortByScorePolicy orders mrs by the rig's score policy, highest first, and
+// records each MR's score breakdown. An unknown policy name falls back to
+// the default policy.
+func (e *Engineer) sortByScorePolicy(mrs []*MRInfo) []*MRInfo {
+	ranked, err := e.RankMRs(mrs, "")
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v; using %s policy\n", err, PolicyDefault)
+		ranked, _ = e.RankMRs(mrs, PolicyDefault)
+	}
+	sorted := make([]*MRInfo, len(ranked))
+	for i := range ranked {
+		ranked[i].MR.ScoreBreakdown = &ranked[i].Score
+		sorted[i] = ranked[i].MR
+	}
+	return sorted
 }
 
 // ListBlockedMRs returns MRs that are blocked by open tasks.
@@ -1266,22 +1634,20 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
-	// Filter for blocked issues (those with open blockers)
+	// Filter for blocked issues (those with open blockers or a stack parent)
 	var mrs []*MRInfo
 	for _, issue := range issues {
-		// Skip if not blocked
-		if len(issue.BlockedBy) == 0 {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
 			continue
 		}
 
-		// Check if any blocker is still open
+		// Check if any blocker is still open; stacked MRs are held by their parent
 		blockedBy := e.firstOpenBlocker(issue)
 		if blockedBy == "" {
-			continue // All blockers are closed, not blocked
+			blockedBy = fields.ParentMR
 		}
-
-		fields := beads.ParseMRFields(issue)
-		if fields == nil {
+		if blockedBy == "" {
 			continue
 		}
 
@@ -1325,6 +1691,9 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 		mr.BranchExistsLocal, _ = e.git.BranchExists(fields.Branch)
 		mr.BranchExistsRemote, _ = e.git.RemoteTrackingBranchExists("origin", fields.Branch)
 		mr.BlockedBy = e.firstOpenBlocker(issue)
+		if mr.BlockedBy == "" {
+			mr.BlockedBy = fields.ParentMR
+		}
 
 		mrs = append(mrs, mr)
 	}
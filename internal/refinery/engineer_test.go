@@ -236,6 +236,84 @@ func TestEngineer_LoadConfig_InvalidStaleClaimTimeout(t *testing.T) {
 	}
 }
 
+func TestEngineer_LoadConfig_LeadTimeSLA(t *testing.T) {
+	tests := []struct {
+		sla     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"4h", 4 * time.Hour, false},
+		{"not-a-duration", 0, true},
+		{"-1h", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sla, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			config := map[string]interface{}{
+				"merge_queue": map[string]interface{}{
+					"lead_time_sla": tt.sla,
+				},
+			}
+			data, _ := json.MarshalIndent(config, "", "  ")
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+			err := e.LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && e.config.LeadTimeSLA != tt.want {
+				t.Errorf("LeadTimeSLA = %v, want %v", e.config.LeadTimeSLA, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineer_LoadConfig_VerifyMain(t *testing.T) {
+	tests := []struct {
+		name    string
+		mq      map[string]interface{}
+		wantErr bool
+	}{
+		{"valid", map[string]interface{}{
+			"verify_main":  map[string]interface{}{"cmd": "make test", "timeout": "20m"},
+			"bisect_depth": 5,
+			"auto_revert":  false,
+		}, false},
+		{"missing cmd", map[string]interface{}{"verify_main": map[string]interface{}{"timeout": "20m"}}, true},
+		{"bad timeout", map[string]interface{}{"verify_main": map[string]interface{}{"cmd": "make test", "timeout": "soon"}}, true},
+		{"zero bisect depth", map[string]interface{}{"bisect_depth": 0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			data, _ := json.MarshalIndent(map[string]interface{}{"merge_queue": tt.mq}, "", "  ")
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+			err := e.LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if e.config.VerifyMain == nil || e.config.VerifyMain.Cmd != "make test" || e.config.VerifyMain.Timeout != 20*time.Minute {
+				t.Errorf("VerifyMain = %+v", e.config.VerifyMain)
+			}
+			if e.config.BisectDepth != 5 || e.config.AutoRevert {
+				t.Errorf("BisectDepth = %d, AutoRevert = %v", e.config.BisectDepth, e.config.AutoRevert)
+			}
+		})
+	}
+}
+
 func TestNewEngineer(t *testing.T) {
 	r := &rig.Rig{
 		Name: "test-rig",
@@ -0,0 +1,382 @@
+// This file contains flaky test tracking: per-test pass/fail history across
+// MRs, quarantine of tests that fail on the target branch too, and the
+// weekly flaky-test report.
+
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FileFlakyHistoryJSON is the per-test history in the rig's .runtime dir.
+const FileFlakyHistoryJSON = "test-history.json"
+
+// DefaultQuarantineThreshold is how many times a test must fail on the
+// target branch (during base retries) before it is quarantined automatically.
+const DefaultQuarantineThreshold = 2
+
+// FlakyReportInterval is how often the flaky-test report bead is filed.
+const FlakyReportInterval = 7 * 24 * time.Hour
+
+// TestRecord is the history of one test, keyed "<gate>: <test>".
+// Only tests that have failed at least once are tracked.
+type TestRecord struct {
+	Name         string    `json:"name"`
+	Passes       int       `json:"passes"`
+	Failures     int       `json:"failures"`
+	BaseFailures int       `json:"base_failures"` // failures on the target branch during base retries
+	LastFailedAt time.Time `json:"last_failed_at,omitempty"`
+
+	Quarantined      bool      `json:"quarantined,omitempty"`
+	QuarantinedAt    time.Time `json:"quarantined_at,omitempty"`
+	QuarantineReason string    `json:"quarantine_reason,omitempty"`
+}
+
+// FailureRate is the fraction of recorded runs that failed.
+func (r *TestRecord) FailureRate() float64 {
+	runs := r.Passes + r.Failures
+	if runs == 0 {
+		return 0
+	}
+	return float64(r.Failures) / float64(runs)
+}
+
+// FlakyHistory is the test history of a rig's merge queue.
+type FlakyHistory struct {
+	Tests        map[string]*TestRecord `json:"tests"`
+	LastReportAt time.Time              `json:"last_report_at,omitempty"`
+}
+
+// FlakyHistoryPath returns the test history path for a rig.
+func FlakyHistoryPath(rigPath string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), FileFlakyHistoryJSON)
+}
+
+// LoadFlakyHistory reads the rig's test history. Returns an empty history
+// if none has been recorded yet.
+func LoadFlakyHistory(rigPath string) (*FlakyHistory, error) {
+	h := &FlakyHistory{Tests: make(map[string]*TestRecord)}
+	data, err := os.ReadFile(FlakyHistoryPath(rigPath))
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading test history: %w", err)
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing test history: %w", err)
+	}
+	if h.Tests == nil {
+		h.Tests = make(map[string]*TestRecord)
+	}
+	return h, nil
+}
+
+// UpdateFlakyHistory loads the rig's test history under a file lock,
+// applies fn, and saves the result if fn returns nil. The refinery and
+// `gt mq flaky` both write the history.
+func UpdateFlakyHistory(rigPath string, fn func(h *FlakyHistory) error) error {
+	path := FlakyHistoryPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring test history lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	h, err := LoadFlakyHistory(rigPath)
+	if err != nil {
+		return err
+	}
+	if err := fn(h); err != nil {
+		return err
+	}
+	return util.EnsureDirAndWriteJSON(path, h)
+}
+
+// IsQuarantined reports whether name is quarantined.
+func (h *FlakyHistory) IsQuarantined(name string) bool {
+	r := h.Tests[name]
+	return r != nil && r.Quarantined
+}
+
+// record returns the record for name, creating it if needed.
+func (h *FlakyHistory) record(name string) *TestRecord {
+	r := h.Tests[name]
+	if r == nil {
+		r = &TestRecord{Name: name}
+		h.Tests[name] = r
+	}
+	return r
+}
+
+// Quarantine marks name as quarantined so its failures no longer block merges.
+func (h *FlakyHistory) Quarantine(name, reason string, now time.Time) {
+	r := h.record(name)
+	if r.Quarantined {
+		return
+	}
+	r.Quarantined = true
+	r.QuarantinedAt = now
+	r.QuarantineReason = reason
+}
+
+// Release lifts the quarantine on name. Returns false if it wasn't quarantined.
+func (h *FlakyHistory) Release(name string) bool {
+	r := h.Tests[name]
+	if r == nil || !r.Quarantined {
+		return false
+	}
+	r.Quarantined = false
+	r.QuarantinedAt = time.Time{}
+	r.QuarantineReason = ""
+	// Start over so the test must fail on base again to be re-quarantined
+	r.BaseFailures = 0
+	return true
+}
+
+// RecordGate adds a gate run's test outcomes to the history. Failures are
+// always recorded; passes only for tests that are already tracked.
+func (h *FlakyHistory) RecordGate(r GateResult, now time.Time) {
+	if r.Cached || r.Skipped {
+		return
+	}
+	failed := append(append(append([]string(nil), r.FailingTests...), r.Quarantined...), r.FailedOnBase...)
+	for _, t := range failed {
+		if strings.HasPrefix(t, "(+") {
+			continue // truncation marker, not a test
+		}
+		rec := h.record(r.Name + ": " + t)
+		rec.Failures++
+		rec.LastFailedAt = now
+	}
+	for _, t := range r.PassedTests {
+		if rec := h.Tests[r.Name+": "+t]; rec != nil {
+			rec.Passes++
+		}
+	}
+}
+
+// RecordBaseFailure notes that name also failed on the target branch, and
+// quarantines it once it has done so threshold times (0 disables automatic
+// quarantine). Returns true if the test was quarantined by this call.
+func (h *FlakyHistory) RecordBaseFailure(name string, threshold int, now time.Time) bool {
+	r := h.record(name)
+	r.BaseFailures++
+	if threshold <= 0 || r.Quarantined || r.BaseFailures < threshold {
+		return false
+	}
+	h.Quarantine(name, fmt.Sprintf("failed on the target branch %d times", r.BaseFailures), now)
+	return true
+}
+
+// Flaky returns quarantined tests and tests that have failed on the target
+// branch: quarantined first, then by base failures and failure rate.
+func (h *FlakyHistory) Flaky() []*TestRecord {
+	var out []*TestRecord
+	for _, r := range h.Tests {
+		if r.Quarantined || r.BaseFailures > 0 {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Quarantined != b.Quarantined {
+			return a.Quarantined
+		}
+		if a.BaseFailures != b.BaseFailures {
+			return a.BaseFailures > b.BaseFailures
+		}
+		if a.FailureRate() != b.FailureRate() {
+			return a.FailureRate() > b.FailureRate()
+		}
+		return a.Name < b.Name
+	})
+	return out
+}
+
+// ReportDue reports whether the weekly report should be filed.
+func (h *FlakyHistory) ReportDue(now time.Time) bool {
+	return len(h.Flaky()) > 0 && now.Sub(h.LastReportAt) >= FlakyReportInterval
+}
+
+// FormatFlakyReport renders the flaky-test report bead description.
+func FormatFlakyReport(rigName string, h *FlakyHistory) string {
+	var sb strings.Builder
+	flaky := h.Flaky()
+	quarantined := 0
+	for _, r := range flaky {
+		if r.Quarantined {
+			quarantined++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Flaky tests in the %s merge queue: %d tracked, %d quarantined.\n", rigName, len(flaky), quarantined))
+	sb.WriteString("Quarantined tests do not block merges; fix them and release with\n")
+	sb.WriteString(fmt.Sprintf("'gt mq flaky release %s <test>'.\n\n", rigName))
+	for _, r := range flaky {
+		status := "suspect"
+		if r.Quarantined {
+			status = "quarantined " + r.QuarantinedAt.Format("2006-01-02")
+		}
+		sb.WriteString(fmt.Sprintf("- %s [%s]: %d/%d runs failed (%.0f%%), %d on the target branch\n",
+			r.Name, status, r.Failures, r.Passes+r.Failures, 100*r.FailureRate(), r.BaseFailures))
+	}
+	return sb.String()
+}
+
+// flakyTriage excuses gate failures caused by quarantined tests or by tests
+// that fail on the target branch too, and collects the history updates to
+// apply once all gates have run.
+type flakyTriage struct {
+	e       *Engineer
+	history *FlakyHistory // snapshot, read-only during the gate run
+	baseRef string        // e.g. "origin/main"; empty disables base retries
+	baseMu  sync.Mutex    // serializes base retries (worktree add/remove)
+	mu      sync.Mutex
+	onBase  []string // "<gate>: <test>" that failed on base
+}
+
+// newFlakyTriage loads the rig's test history. Returns nil if it can't be
+// read, in which case failures are not triaged.
+func (e *Engineer) newFlakyTriage(baseRef string) *flakyTriage {
+	history, err := LoadFlakyHistory(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: flaky test tracking disabled: %v\n", err)
+		return nil
+	}
+	if !e.config.FlakyRetryOnBase {
+		baseRef = ""
+	}
+	return &flakyTriage{e: e, history: history, baseRef: baseRef}
+}
+
+// check excuses the failing tests of a failed gate that are quarantined or
+// also fail when the gate is re-run on the target branch. If every failure
+// is excused the gate counts as passed, so dependent gates still run. A
+// failure the gate's report doesn't fully explain (a build error, a crashed
+// runner, a timeout) is never excused.
+func (t *flakyTriage) check(ctx context.Context, name string, gate *GateConfig, r GateResult) GateResult {
+	if r.Success || !r.Explained || len(r.FailingTests) == 0 {
+		return r
+	}
+	for _, test := range r.FailingTests {
+		if strings.HasPrefix(test, "(+") {
+			return r // truncated list: unknown failures can't be excused
+		}
+	}
+
+	var remaining []string
+	for _, test := range r.FailingTests {
+		if t.history.IsQuarantined(name + ": " + test) {
+			r.Quarantined = append(r.Quarantined, test)
+		} else {
+			remaining = append(remaining, test)
+		}
+	}
+
+	if len(remaining) > 0 && t.baseRef != "" {
+		baseFailing := t.failingOnBase(ctx, name, gate)
+		var stillFailing []string
+		for _, test := range remaining {
+			if baseFailing[test] {
+				r.FailedOnBase = append(r.FailedOnBase, test)
+			} else {
+				stillFailing = append(stillFailing, test)
+			}
+		}
+		remaining = stillFailing
+	}
+
+	if len(r.FailedOnBase) > 0 {
+		t.mu.Lock()
+		for _, test := range r.FailedOnBase {
+			t.onBase = append(t.onBase, name+": "+test)
+		}
+		t.mu.Unlock()
+	}
+
+	r.FailingTests = remaining
+	if len(remaining) == 0 {
+		r.Success = true
+		r.Error = ""
+	}
+	return r
+}
+
+// failingOnBase re-runs gate on the target branch in a temporary detached
+// worktree and returns the tests that fail there. Returns nil unless the
+// base run failed too and its report explains why.
+func (t *flakyTriage) failingOnBase(ctx context.Context, name string, gate *GateConfig) map[string]bool {
+	t.baseMu.Lock()
+	defer t.baseMu.Unlock()
+
+	e := t.e
+	dir, err := os.MkdirTemp("", "gt-flaky-base-")
+	if err != nil {
+		return nil
+	}
+	_ = os.Remove(dir) // git worktree add wants to create it
+	g := git.NewGit(e.workDir)
+	if err := g.WorktreeAddDetached(dir, t.baseRef); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check out %s to retry gate %q: %v\n", t.baseRef, name, err)
+		return nil
+	}
+	defer func() {
+		if err := g.WorktreeRemove(dir, true); err != nil {
+			_ = os.RemoveAll(dir)
+			_ = g.WorktreePrune()
+		}
+	}()
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: retrying on %s to check for flaky tests\n", name, t.baseRef)
+	base := e.runGateIn(ctx, dir, name, gate)
+	if base.Success || !base.Explained {
+		return nil
+	}
+	failing := make(map[string]bool, len(base.FailingTests))
+	for _, test := range base.FailingTests {
+		failing[test] = true
+	}
+	return failing
+}
+
+// save records the gate results and base failures in the rig's history,
+// quarantining tests that crossed the threshold.
+func (t *flakyTriage) save(results []GateResult) {
+	e := t.e
+	now := time.Now().UTC()
+	var quarantined []string
+	err := UpdateFlakyHistory(e.rig.Path, func(h *FlakyHistory) error {
+		for _, r := range results {
+			h.RecordGate(r, now)
+		}
+		for _, name := range t.onBase {
+			if h.RecordBaseFailure(name, e.config.QuarantineThreshold, now) {
+				quarantined = append(quarantined, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to save test history: %v\n", err)
+		return
+	}
+	for _, name := range quarantined {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Quarantined flaky test %s\n", name)
+	}
+}
@@ -0,0 +1,224 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestFlakyHistory_RecordAndQuarantine(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	h := &FlakyHistory{Tests: make(map[string]*TestRecord)}
+
+	h.RecordGate(GateResult{Name: "unit", FailingTests: []string{"TestNet", "(+3 more)"}, PassedTests: []string{"TestAdd"}}, now)
+	h.RecordGate(GateResult{Name: "unit", Success: true, PassedTests: []string{"TestNet", "TestAdd"}}, now)
+	h.RecordGate(GateResult{Name: "unit", Cached: true, Success: true, PassedTests: []string{"TestNet"}}, now)
+
+	net := h.Tests["unit: TestNet"]
+	if net == nil || net.Failures != 1 || net.Passes != 1 {
+		t.Fatalf("unit: TestNet = %+v, want 1 failure and 1 pass", net)
+	}
+	if _, ok := h.Tests["unit: TestAdd"]; ok {
+		t.Error("tests that never failed should not be tracked")
+	}
+	if _, ok := h.Tests["unit: (+3 more)"]; ok {
+		t.Error("truncation marker should not be tracked")
+	}
+
+	if h.RecordBaseFailure("unit: TestNet", 2, now) {
+		t.Error("first base failure should not quarantine at threshold 2")
+	}
+	if !h.RecordBaseFailure("unit: TestNet", 2, now) {
+		t.Error("second base failure should quarantine at threshold 2")
+	}
+	if !h.IsQuarantined("unit: TestNet") {
+		t.Error("expected unit: TestNet quarantined")
+	}
+	if !h.ReportDue(now) {
+		t.Error("report should be due with a quarantined test and no prior report")
+	}
+	h.LastReportAt = now
+	if h.ReportDue(now.Add(24 * time.Hour)) {
+		t.Error("report should not be due a day after the last one")
+	}
+
+	if !h.Release("unit: TestNet") || h.IsQuarantined("unit: TestNet") {
+		t.Error("Release should lift the quarantine")
+	}
+	if h.Release("unit: TestNet") {
+		t.Error("releasing twice should report false")
+	}
+	if h.RecordBaseFailure("unit: TestNet", 0, now) {
+		t.Error("threshold 0 should disable automatic quarantine")
+	}
+}
+
+func TestFlakyHistory_FlakyOrder(t *testing.T) {
+	h := &FlakyHistory{Tests: map[string]*TestRecord{
+		"unit: a": {Name: "unit: a", Failures: 1, BaseFailures: 1},
+		"unit: b": {Name: "unit: b", Failures: 5, BaseFailures: 3},
+		"unit: c": {Name: "unit: c", Failures: 1, Quarantined: true},
+		"unit: d": {Name: "unit: d", Failures: 9},
+	}}
+	var names []string
+	for _, r := range h.Flaky() {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "unit: c,unit: b,unit: a" {
+		t.Errorf("Flaky() = %s", got)
+	}
+	if report := FormatFlakyReport("gastown", h); !strings.Contains(report, "3 tracked, 1 quarantined") {
+		t.Errorf("report missing summary:\n%s", report)
+	}
+}
+
+func TestUpdateFlakyHistory(t *testing.T) {
+	rigPath := t.TempDir()
+	err := UpdateFlakyHistory(rigPath, func(h *FlakyHistory) error {
+		h.Quarantine("unit: TestNet", "by hand", time.Now())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadFlakyHistory(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsQuarantined("unit: TestNet") {
+		t.Error("quarantine not persisted")
+	}
+}
+
+func TestRunGates_QuarantinedFailuresDontBlock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	e.config.Gates = map[string]*GateConfig{
+		"unit": {Cmd: "echo 'not ok 1 - net'; exit 1", Format: GateFormatTAP},
+		"e2e":  {Cmd: "true", DependsOn: []string{"unit"}},
+	}
+	e.config.GatesParallel = true
+
+	if result := e.runGates(context.Background()); result.Success {
+		t.Fatal("unquarantined failure should block")
+	}
+
+	if err := UpdateFlakyHistory(r.Path, func(h *FlakyHistory) error {
+		h.Quarantine("unit: net", "by hand", time.Now())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	result := e.runGates(context.Background())
+	if !result.Success {
+		t.Fatalf("quarantined failure should not block: %s", result.Error)
+	}
+	if got := FormatGateResults(result.Gates); got != "unit=excused(1) e2e=pass" {
+		t.Errorf("gate results = %q", got)
+	}
+}
+
+func TestRunGates_UnexplainedFailuresAreNeverExcused(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	if err := UpdateFlakyHistory(r.Path, func(h *FlakyHistory) error {
+		h.Quarantine("unit: net", "by hand", time.Now())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cmd := range []string{
+		"exit 2",                            // no report: a build error
+		"echo 'not ok 1 - net'; kill -9 $$", // runner crashed after the report
+	} {
+		e.config.Gates = map[string]*GateConfig{"unit": {Cmd: cmd, Format: GateFormatTAP}}
+		if result := e.runGates(context.Background()); result.Success {
+			t.Errorf("%q: unexplained failure must block, got %s", cmd, FormatGateResults(result.Gates))
+		}
+	}
+}
+
+func TestRunGatesAgainst_BaseRetryExcusesPreexistingFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	workDir := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitRun("init", "-q", "-b", "main")
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("config", "user.name", "Test")
+	gitRun("commit", "-q", "--allow-empty", "-m", "base")
+	gitRun("checkout", "-q", "-b", "feature")
+
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = workDir
+	e.output = io.Discard
+	e.config.GateCache = false
+	// "net" fails everywhere; "parse" fails only when the tree has a "broken" file
+	e.config.Gates = map[string]*GateConfig{
+		"unit": {
+			Cmd:    "echo 'not ok 1 - net'; if [ -f broken ]; then echo 'not ok 2 - parse'; else echo 'ok 2 - parse'; fi; exit 1",
+			Format: GateFormatTAP,
+		},
+	}
+
+	result := e.runGatesAgainst(context.Background(), "main")
+	if !result.Success {
+		t.Fatalf("failure that also happens on main should not block: %s", result.Error)
+	}
+	if fob := result.Gates[0].FailedOnBase; len(fob) != 1 || fob[0] != "net" {
+		t.Errorf("FailedOnBase = %v, want [net]", fob)
+	}
+
+	if err := os.WriteFile(filepath.Join(workDir, "broken"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", "broken")
+	gitRun("commit", "-q", "-m", "break parse")
+
+	result = e.runGatesAgainst(context.Background(), "main")
+	if result.Success {
+		t.Fatal("failure introduced by the branch should block")
+	}
+	if got := FailingTests(result.Gates); len(got) != 1 || got[0] != "unit: parse" {
+		t.Errorf("FailingTests = %v, want [unit: parse]", got)
+	}
+
+	h, err := LoadFlakyHistory(r.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsQuarantined("unit: net") {
+		t.Errorf("unit: net should be quarantined after failing on main twice: %+v", h.Tests["unit: net"])
+	}
+	if h.IsQuarantined("unit: parse") {
+		t.Error("unit: parse should not be quarantined")
+	}
+}
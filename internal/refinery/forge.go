@@ -0,0 +1,366 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Forge mirroring.
+//
+// A rig whose settings configure a forge can mirror each MR as a pull
+// request (forge.mirror_mrs). The PR number is kept on the MR bead
+// (forge_pr), and SyncForge relays new review comments to the MR's polecat
+// by mail, remembering how far it got (forge_synced_at). When the rig
+// requires checks or approvals, ProcessMRInfo holds the MR until the PR
+// passes them: pending checks and reviews keep it waiting in the queue,
+// failed checks and change requests fail it like a failing gate.
+
+// ForgeSyncResult describes what SyncForge did with one MR.
+type ForgeSyncResult struct {
+	MR      *MRInfo `json:"mr"`
+	PR      int     `json:"pr,omitempty"`
+	URL     string  `json:"url,omitempty"`
+	Opened  bool    `json:"opened,omitempty"`  // PR was opened by this sync
+	Relayed int     `json:"relayed,omitempty"` // Comments and reviews mailed to the worker
+	Error   string  `json:"error,omitempty"`
+}
+
+// ForgeStatus is the forge's view of an MR: its pull request, the checks
+// on the PR head, its reviews, and the verdict under the rig's rules.
+type ForgeStatus struct {
+	MR      *MRInfo            `json:"mr"`
+	PR      *forge.PullRequest `json:"pr"`
+	Checks  []forge.Check      `json:"checks"`
+	Reviews []forge.Review     `json:"reviews"`
+	Verdict forge.Verdict      `json:"verdict"`
+}
+
+// ForgeConfig returns the rig's forge settings, or nil if none are set.
+func (e *Engineer) ForgeConfig() *config.ForgeConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err != nil || settings.Forge == nil || settings.Forge.Provider == "" {
+		return nil
+	}
+	return settings.Forge
+}
+
+// forgeProvider returns the rig's forge settings and a provider for them.
+// Both are nil when the rig has no forge.
+func (e *Engineer) forgeProvider() (*config.ForgeConfig, forge.Provider, error) {
+	cfg := e.ForgeConfig()
+	if cfg == nil {
+		return nil, nil, nil
+	}
+	p, err := e.newForge(cfg)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("connecting to %s: %w", cfg.Provider, err)
+	}
+	return cfg, p, nil
+}
+
+// OpenForgePR opens (or finds) the pull request for an MR and records it
+// on the MR bead.
+func (e *Engineer) OpenForgePR(ctx context.Context, mrID string) (*forge.PullRequest, error) {
+	_, p, err := e.forgeProvider()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("rig %s has no forge configured", e.rig.Name)
+	}
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching MR %s: %w", mrID, err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Branch == "" {
+		return nil, fmt.Errorf("%s is not a merge request", mrID)
+	}
+	return e.openForgePR(ctx, p, issue, fields)
+}
+
+func (e *Engineer) openForgePR(ctx context.Context, p forge.Provider, issue *beads.Issue, fields *beads.MRFields) (*forge.PullRequest, error) {
+	body := fmt.Sprintf("Merge request %s", issue.ID)
+	if fields.SourceIssue != "" {
+		body += fmt.Sprintf(" for %s", fields.SourceIssue)
+	}
+	if fields.Worker != "" {
+		body += fmt.Sprintf(" by %s/%s", e.rig.Name, fields.Worker)
+	}
+	body += ".\n\nThe Gas Town refinery merges this branch; please review here rather than merging."
+	pr, err := p.OpenPR(ctx, forge.PROptions{
+		Title: issue.Title,
+		Body:  body,
+		Head:  fields.Branch,
+		Base:  fields.Target,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening pull request for %s: %w", issue.ID, err)
+	}
+	if fields.ForgePR != pr.Number {
+		fields.ForgePR = pr.Number
+		fields.ForgeURL = pr.URL
+		if fields.ForgeSyncedAt == "" {
+			fields.ForgeSyncedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+		desc := beads.SetMRFields(issue, fields)
+		if err := e.beads.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+			return pr, fmt.Errorf("recording pull request on %s: %w", issue.ID, err)
+		}
+	}
+	return pr, nil
+}
+
+// SyncForge opens pull requests for open MRs that lack one (when the rig
+// mirrors MRs) and mails new review comments on existing ones to the MR's
+// worker. It returns nil when the rig has no forge.
+func (e *Engineer) SyncForge(ctx context.Context) ([]ForgeSyncResult, error) {
+	cfg, p, err := e.forgeProvider()
+	if err != nil || p == nil {
+		return nil, err
+	}
+	issues, fields, err := e.openMRFields()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ForgeSyncResult
+	for _, id := range sortedKeys(issues) {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		f := fields[id]
+		mr := issueToMRInfo(issues[id], f)
+		r := ForgeSyncResult{MR: mr, PR: f.ForgePR, URL: f.ForgeURL}
+		if f.ForgePR == 0 {
+			if !cfg.MirrorMRs || f.Branch == "" {
+				continue
+			}
+			pr, err := e.openForgePR(ctx, p, issues[id], f)
+			if err != nil {
+				r.Error = err.Error()
+			} else {
+				r.PR, r.URL, r.Opened = pr.Number, pr.URL, true
+			}
+			results = append(results, r)
+			continue
+		}
+		n, err := e.relayForgeReviews(ctx, p, issues[id], f)
+		r.Relayed = n
+		if err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// relayForgeReviews mails the worker the comments and reviews posted on
+// the MR's pull request since forge_synced_at, then advances it.
+func (e *Engineer) relayForgeReviews(ctx context.Context, p forge.Provider, issue *beads.Issue, f *beads.MRFields) (int, error) {
+	var since time.Time
+	if f.ForgeSyncedAt != "" {
+		t, err := time.Parse(time.RFC3339, f.ForgeSyncedAt)
+		if err != nil {
+			return 0, fmt.Errorf("parsing forge_synced_at on %s: %w", issue.ID, err)
+		}
+		since = t
+	}
+	comments, err := p.Comments(ctx, f.ForgePR, since)
+	if err != nil {
+		return 0, fmt.Errorf("fetching comments on PR #%d: %w", f.ForgePR, err)
+	}
+	reviews, err := p.Reviews(ctx, f.ForgePR)
+	if err != nil {
+		return 0, fmt.Errorf("fetching reviews on PR #%d: %w", f.ForgePR, err)
+	}
+	// Some forges (Gitea) also list review bodies as comments
+	seen := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		seen[c.Author+"\x00"+c.Body] = true
+	}
+	var newReviews []forge.Review
+	for _, r := range reviews {
+		if r.State != forge.ReviewCommented && r.SubmittedAt.After(since) {
+			if seen[r.Author+"\x00"+r.Body] {
+				r.Body = ""
+			}
+			newReviews = append(newReviews, r)
+		}
+	}
+	if len(comments) == 0 && len(newReviews) == 0 {
+		return 0, nil
+	}
+
+	latest := since
+	for _, c := range comments {
+		if c.CreatedAt.After(latest) {
+			latest = c.CreatedAt
+		}
+	}
+	for _, r := range newReviews {
+		if r.SubmittedAt.After(latest) {
+			latest = r.SubmittedAt
+		}
+	}
+
+	if f.Worker != "" {
+		subject := fmt.Sprintf("FORGE_REVIEW %s", f.Branch)
+		body := formatForgeReviewMail(issue.ID, f, comments, newReviews)
+		msg := mail.NewMessage(e.rig.Name+"/refinery", fmt.Sprintf("%s/%s", e.rig.Name, f.Worker), subject, body)
+		if err := e.router.Send(msg); err != nil {
+			return 0, fmt.Errorf("mailing %s: %w", f.Worker, err)
+		}
+	}
+
+	f.ForgeSyncedAt = latest.UTC().Format(time.RFC3339Nano)
+	desc := beads.SetMRFields(issue, f)
+	if err := e.beads.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return len(comments) + len(newReviews), fmt.Errorf("recording forge sync on %s: %w", issue.ID, err)
+	}
+	return len(comments) + len(newReviews), nil
+}
+
+// formatForgeReviewMail renders review activity on a PR for its worker.
+func formatForgeReviewMail(mrID string, f *beads.MRFields, comments []forge.Comment, reviews []forge.Review) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "New review activity on PR #%d for %s (branch %s)", f.ForgePR, mrID, f.Branch)
+	if f.ForgeURL != "" {
+		fmt.Fprintf(&sb, "\n%s", f.ForgeURL)
+	}
+	sb.WriteString("\n")
+	for _, r := range reviews {
+		verdict := "approved"
+		if r.State == forge.ReviewChangesRequested {
+			verdict = "requested changes"
+		}
+		fmt.Fprintf(&sb, "\n%s %s", r.Author, verdict)
+		if r.Body != "" {
+			fmt.Fprintf(&sb, ":\n%s", indent(r.Body))
+		}
+		sb.WriteString("\n")
+	}
+	for _, c := range comments {
+		where := ""
+		if c.Path != "" {
+			where = fmt.Sprintf(" on %s:%d", c.Path, c.Line)
+		}
+		fmt.Fprintf(&sb, "\n%s commented%s:\n%s\n", c.Author, where, indent(c.Body))
+	}
+	sb.WriteString("\nPush fixes to your branch; the PR updates with it. Reply on the forge, not by mail.")
+	return sb.String()
+}
+
+func indent(s string) string {
+	return "  " + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n  ")
+}
+
+// ForgeCheck reports the forge's view of an MR without acting on it.
+func (e *Engineer) ForgeCheck(ctx context.Context, mr *MRInfo) (*ForgeStatus, error) {
+	cfg, p, err := e.forgeProvider()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("rig %s has no forge configured", e.rig.Name)
+	}
+	return e.forgeStatus(ctx, cfg, p, mr)
+}
+
+func (e *Engineer) forgeStatus(ctx context.Context, cfg *config.ForgeConfig, p forge.Provider, mr *MRInfo) (*ForgeStatus, error) {
+	issue, err := e.beads.Show(mr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching MR %s: %w", mr.ID, err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{Branch: mr.Branch, Target: mr.Target}
+	}
+
+	status := &ForgeStatus{MR: mr}
+	if fields.ForgePR == 0 {
+		if !cfg.MirrorMRs {
+			status.Verdict = forge.Verdict{State: forge.VerdictPending, Detail: "no pull request for this MR"}
+			return status, nil
+		}
+		if _, err := e.openForgePR(ctx, p, issue, fields); err != nil {
+			return nil, err
+		}
+	}
+	pr, err := p.GetPR(ctx, fields.ForgePR)
+	if err != nil {
+		return nil, fmt.Errorf("fetching PR #%d: %w", fields.ForgePR, err)
+	}
+	status.PR = pr
+	if pr.State != forge.PROpen {
+		status.Verdict = forge.Verdict{State: forge.VerdictFail, Failed: []string{"pull request " + pr.State}, Detail: fmt.Sprintf("PR #%d is %s", pr.Number, pr.State)}
+		return status, nil
+	}
+	// Checks on an older head say nothing about the branch being merged
+	if head, err := e.remoteBranchHead(mr.Branch); err == nil && pr.HeadSHA != "" && head != pr.HeadSHA {
+		status.Verdict = forge.Verdict{State: forge.VerdictPending, Detail: fmt.Sprintf("PR head is %s, origin/%s is at %s", shortSHA(pr.HeadSHA), mr.Branch, shortSHA(head))}
+		return status, nil
+	}
+	if status.Checks, err = p.Checks(ctx, pr.HeadSHA); err != nil {
+		return nil, fmt.Errorf("fetching checks for %s: %w", shortSHA(pr.HeadSHA), err)
+	}
+	if status.Reviews, err = p.Reviews(ctx, pr.Number); err != nil {
+		return nil, fmt.Errorf("fetching reviews on PR #%d: %w", pr.Number, err)
+	}
+	status.Verdict = forge.Evaluate(cfg, status.Checks, status.Reviews)
+	return status, nil
+}
+
+// remoteBranchHead fetches branch and returns the commit origin has for it.
+// That is the head the forge sees; the local branch may be stale.
+func (e *Engineer) remoteBranchHead(branch string) (string, error) {
+	if err := e.git.FetchBranch("origin", branch); err != nil {
+		return "", err
+	}
+	return e.git.Rev("origin/" + branch)
+}
+
+// forgeGate applies the rig's forge requirements to mr. It reports held
+// when the MR must not merge yet, with the result to hand to
+// HandleMRInfoFailure: Waiting while checks or reviews are pending (or the
+// forge can't be reached), a "forge" gate failure when they failed.
+func (e *Engineer) forgeGate(ctx context.Context, mr *MRInfo) (ProcessResult, bool) {
+	cfg := e.ForgeConfig()
+	if cfg == nil || !cfg.Gated() || mr.ID == "" {
+		return ProcessResult{}, false
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking %s pull request for %s...\n", cfg.Provider, mr.ID)
+	p, err := e.newForge(cfg)
+	if err != nil {
+		return ProcessResult{Waiting: true, Error: fmt.Sprintf("forge: %v", err)}, true
+	}
+	status, err := e.forgeStatus(ctx, cfg, p, mr)
+	if err != nil {
+		// A forge outage must not land unreviewed code, nor blame the worker
+		return ProcessResult{Waiting: true, Error: fmt.Sprintf("forge: %v", err)}, true
+	}
+	switch status.Verdict.State {
+	case forge.VerdictPass:
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Forge: %s\n", status.Verdict.Detail)
+		return ProcessResult{}, false
+	case forge.VerdictPending:
+		return ProcessResult{Waiting: true, Error: status.Verdict.Detail}, true
+	default:
+		return ProcessResult{
+			TestsFailed: true,
+			Error:       "forge " + status.Verdict.Detail,
+			Gates: []GateResult{{
+				Name:         "forge",
+				Error:        status.Verdict.Detail,
+				FailingTests: status.Verdict.Failed,
+			}},
+		}, true
+	}
+}
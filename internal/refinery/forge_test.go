@@ -0,0 +1,151 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func writeRigSettings(t *testing.T, rigPath, settings string) {
+	t.Helper()
+	path := config.RigSettingsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestForgeGate_NotGated(t *testing.T) {
+	rigPath := t.TempDir()
+	e := &Engineer{
+		rig:    &rig.Rig{Name: "testrig", Path: rigPath},
+		output: io.Discard,
+		newForge: func(*config.ForgeConfig) (forge.Provider, error) {
+			t.Fatal("provider should not be created for an ungated rig")
+			return nil, nil
+		},
+	}
+	mr := &MRInfo{ID: "gt-mr1", Branch: "polecat/nux/gt-abc", Target: "main"}
+
+	// No settings at all
+	if _, held := e.forgeGate(context.Background(), mr); held {
+		t.Error("MR held with no forge configured")
+	}
+
+	// A mirror-only forge does not gate merges
+	writeRigSettings(t, rigPath, `{"type":"rig-settings","version":1,
+		"forge":{"provider":"github","repo":"acme/widgets","mirror_mrs":true}}`)
+	if _, held := e.forgeGate(context.Background(), mr); held {
+		t.Error("MR held by a forge with no required checks or approvals")
+	}
+}
+
+func TestForgeGate_UnreachableForgeWaits(t *testing.T) {
+	rigPath := t.TempDir()
+	writeRigSettings(t, rigPath, `{"type":"rig-settings","version":1,
+		"forge":{"provider":"github","repo":"acme/widgets","required_checks":["*"]}}`)
+	e := &Engineer{
+		rig:    &rig.Rig{Name: "testrig", Path: rigPath},
+		output: io.Discard,
+		newForge: func(*config.ForgeConfig) (forge.Provider, error) {
+			return nil, forge.ErrNoToken
+		},
+	}
+
+	result, held := e.forgeGate(context.Background(), &MRInfo{ID: "gt-mr1", Branch: "polecat/nux/gt-abc"})
+	if !held {
+		t.Fatal("gated MR merged without reaching the forge")
+	}
+	if !result.Waiting || result.TestsFailed || result.Success {
+		t.Errorf("result = %+v, want Waiting only", result)
+	}
+	if !strings.Contains(result.Error, "token") {
+		t.Errorf("Error = %q, want the provider error", result.Error)
+	}
+}
+
+func TestHandleMRInfoFailure_WaitingIsNotNotified(t *testing.T) {
+	var out bytes.Buffer
+	// router is nil: sending MERGE_FAILED would panic
+	e := &Engineer{rig: &rig.Rig{Name: "testrig"}, output: &out}
+
+	e.HandleMRInfoFailure(&MRInfo{ID: "gt-mr1", Worker: "nux"}, ProcessResult{Waiting: true, Error: "waiting on e2e"})
+	if !strings.Contains(out.String(), "waiting on e2e") {
+		t.Errorf("output = %q, want the pending detail", out.String())
+	}
+}
+
+func TestFormatForgeReviewMail(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	f := &beads.MRFields{Branch: "polecat/nux/gt-abc", ForgePR: 7, ForgeURL: "https://github.com/acme/widgets/pull/7"}
+	body := formatForgeReviewMail("gt-mr1", f,
+		[]forge.Comment{
+			{Author: "bob", Body: "nit: rename\nto parseAll", Path: "main.go", Line: 12, CreatedAt: at},
+			{Author: "carol", Body: "LGTM otherwise", CreatedAt: at},
+		},
+		[]forge.Review{{Author: "alice", State: forge.ReviewChangesRequested, Body: "Needs a test.", SubmittedAt: at}},
+	)
+
+	for _, want := range []string{
+		"PR #7 for gt-mr1",
+		"https://github.com/acme/widgets/pull/7",
+		"alice requested changes:\n  Needs a test.",
+		"bob commented on main.go:12:\n  nit: rename\n  to parseAll",
+		"carol commented:\n  LGTM otherwise",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("mail body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestForgeSync_NoForge(t *testing.T) {
+	e := &Engineer{
+		rig:    &rig.Rig{Name: "testrig", Path: t.TempDir()},
+		output: io.Discard,
+		newForge: func(*config.ForgeConfig) (forge.Provider, error) {
+			return nil, errors.New("unexpected")
+		},
+	}
+	results, err := e.SyncForge(context.Background())
+	if err != nil || results != nil {
+		t.Errorf("SyncForge = %v, %v; want nothing for a rig without a forge", results, err)
+	}
+}
+
+func TestRemoteBranchHead_SeesNewPushes(t *testing.T) {
+	r := newStackRepo(t)
+	r.git(r.dir, "checkout", "-q", "-b", "polecat/nux")
+	r.commit("nux.txt", "v1\n")
+	r.git(r.dir, "push", "-q", "origin", "polecat/nux")
+
+	// The polecat pushes again from its own clone; ours is now stale.
+	origin := r.git(r.dir, "remote", "get-url", "origin")
+	other := &stackRepo{t: t, dir: filepath.Join(t.TempDir(), "other")}
+	other.git(filepath.Dir(other.dir), "clone", "-q", "-b", "polecat/nux", origin, other.dir)
+	other.git(other.dir, "config", "user.email", "test@example.com")
+	other.git(other.dir, "config", "user.name", "Test")
+	other.commit("nux.txt", "v2\n")
+	other.git(other.dir, "push", "-q", "origin", "polecat/nux")
+
+	head, err := r.engineer().remoteBranchHead("polecat/nux")
+	if err != nil {
+		t.Fatalf("remoteBranchHead: %v", err)
+	}
+	if want := other.rev("HEAD"); head != want {
+		t.Errorf("head = %s, want the pushed %s (local branch is %s)", head, want, r.rev("polecat/nux"))
+	}
+}
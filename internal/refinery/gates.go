@@ -0,0 +1,539 @@
+// This file contains quality gate scheduling (dependency ordering and
+// parallel execution), the tree-hash result cache, and parsing of JUnit and
+// TAP test reports into failing test names.
+
+package refinery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Gate report formats (GateConfig.Format).
+const (
+	GateFormatJUnit = "junit"
+	GateFormatTAP   = "tap"
+)
+
+// FileGateCacheJSON is the gate result cache in the rig's .runtime dir.
+const FileGateCacheJSON = "gate-cache.json"
+
+// gateCacheMaxAge bounds how long a cached pass is reused, and
+// gateCacheMaxEntries how many are kept, so the cache can't grow without bound.
+const (
+	gateCacheMaxAge     = 7 * 24 * time.Hour
+	gateCacheMaxEntries = 500
+)
+
+// maxFailingTests caps how many failing test names are kept per gate; a
+// broken build can fail thousands of tests and the names go into bead
+// descriptions and mail.
+const maxFailingTests = 50
+
+// ValidateGates checks gate formats and dependencies: every dependency must
+// name a configured gate, and dependencies must not form a cycle.
+func ValidateGates(gates map[string]*GateConfig) error {
+	for _, name := range sortedGateNames(gates) {
+		gate := gates[name]
+		switch gate.Format {
+		case "", GateFormatJUnit, GateFormatTAP:
+		default:
+			return fmt.Errorf("gate %q: unknown format %q (want %s or %s)", name, gate.Format, GateFormatJUnit, GateFormatTAP)
+		}
+		if gate.Report != "" && gate.Format != GateFormatJUnit {
+			return fmt.Errorf("gate %q: report is only supported with format %q", name, GateFormatJUnit)
+		}
+		for _, dep := range gate.DependsOn {
+			if dep == name {
+				return fmt.Errorf("gate %q depends on itself", name)
+			}
+			if _, ok := gates[dep]; !ok {
+				return fmt.Errorf("gate %q depends on unknown gate %q", name, dep)
+			}
+		}
+	}
+	_, err := gateOrder(gates)
+	return err
+}
+
+// gateOrder returns gate names in dependency order, breaking ties by name so
+// sequential runs are deterministic.
+func gateOrder(gates map[string]*GateConfig) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(gates))
+	order := make([]string, 0, len(gates))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("gate dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		deps := append([]string(nil), gates[name].DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := gates[dep]; !ok {
+				return fmt.Errorf("gate %q depends on unknown gate %q", name, dep)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range sortedGateNames(gates) {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func sortedGateNames(gates map[string]*GateConfig) []string {
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scheduleGates runs gates in dependency order and returns their results in
+// that order. In parallel mode each gate starts as soon as its dependencies
+// pass; in sequential mode gates run one at a time and stop at the first
+// failure. Gates whose dependencies failed are reported as skipped.
+func scheduleGates(ctx context.Context, gates map[string]*GateConfig, order []string, parallel bool, run func(context.Context, string) GateResult) []GateResult {
+	results := make(map[string]GateResult, len(order))
+
+	if !parallel {
+		var out []GateResult
+		for _, name := range order {
+			r := run(ctx, name)
+			out = append(out, r)
+			if !r.Success {
+				break
+			}
+		}
+		return out
+	}
+
+	var mu sync.Mutex
+	finished := make(map[string]chan struct{}, len(order))
+	for _, name := range order {
+		finished[name] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range order {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer close(finished[name])
+
+			var blocked []string
+			for _, dep := range gates[name].DependsOn {
+				<-finished[dep]
+				mu.Lock()
+				ok := results[dep].Success
+				mu.Unlock()
+				if !ok {
+					blocked = append(blocked, dep)
+				}
+			}
+
+			var r GateResult
+			if len(blocked) > 0 {
+				r = GateResult{
+					Name:    name,
+					Skipped: true,
+					Error:   fmt.Sprintf("skipped: depends on failed gate(s) %s", strings.Join(blocked, ", ")),
+				}
+			} else {
+				r = run(ctx, name)
+			}
+			mu.Lock()
+			results[name] = r
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+
+	out := make([]GateResult, 0, len(order))
+	for _, name := range order {
+		out = append(out, results[name])
+	}
+	return out
+}
+
+// FailingTests returns the failing test names across results, prefixed with
+// the gate name ("test: TestFoo").
+func FailingTests(results []GateResult) []string {
+	var tests []string
+	for _, r := range results {
+		for _, t := range r.FailingTests {
+			tests = append(tests, r.Name+": "+t)
+		}
+	}
+	return tests
+}
+
+// FormatGateResults summarizes results for the MR bead, e.g.
+// "build=pass lint=cached test=fail(3)". A gate that passed only because its
+// failing tests were quarantined or also fail on the target branch is
+// "excused(n)".
+func FormatGateResults(results []GateResult) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		status := "pass"
+		switch {
+		case r.Skipped:
+			status = "skipped"
+		case !r.Success && len(r.FailingTests) > 0:
+			status = fmt.Sprintf("fail(%d)", len(r.FailingTests))
+		case !r.Success:
+			status = "fail"
+		case len(r.Quarantined)+len(r.FailedOnBase) > 0:
+			status = fmt.Sprintf("excused(%d)", len(r.Quarantined)+len(r.FailedOnBase))
+		case r.Cached:
+			status = "cached"
+		}
+		parts = append(parts, r.Name+"="+status)
+	}
+	return strings.Join(parts, " ")
+}
+
+// gateCacheEntry records a gate that passed on a tree.
+type gateCacheEntry struct {
+	Gate     string    `json:"gate"`
+	Tree     string    `json:"tree"`
+	PassedAt time.Time `json:"passed_at"`
+}
+
+// gateCache remembers gates that passed on a given tree so an unchanged tree
+// (e.g. an MR retried after merge slot contention) skips re-running them.
+// Only passes are cached: a failure may be flaky and is always re-run.
+type gateCache struct {
+	path    string
+	mu      sync.Mutex
+	Entries map[string]gateCacheEntry `json:"entries"`
+}
+
+// gateCacheKey identifies a gate run by tree and command, so editing a
+// gate's command invalidates its cached passes.
+func gateCacheKey(tree string, gate *GateConfig) string {
+	sum := sha256.Sum256([]byte(gate.Cmd))
+	return tree + ":" + hex.EncodeToString(sum[:8])
+}
+
+// loadGateCache reads the rig's gate cache. A missing or unreadable cache
+// is treated as empty.
+func loadGateCache(rigPath string) *gateCache {
+	c := &gateCache{
+		path:    filepath.Join(constants.RigRuntimePath(rigPath), FileGateCacheJSON),
+		Entries: make(map[string]gateCacheEntry),
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(data, c); err != nil || c.Entries == nil {
+		c.Entries = make(map[string]gateCacheEntry)
+	}
+	return c
+}
+
+// passed reports whether gate has a fresh cached pass on tree.
+func (c *gateCache) passed(name, tree string, gate *GateConfig) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.Entries[gateCacheKey(tree, gate)]
+	return ok && entry.Gate == name && time.Since(entry.PassedAt) < gateCacheMaxAge
+}
+
+// record notes that gate passed on tree.
+func (c *gateCache) record(name, tree string, gate *GateConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Entries[gateCacheKey(tree, gate)] = gateCacheEntry{Gate: name, Tree: tree, PassedAt: time.Now().UTC()}
+}
+
+// save prunes expired and excess entries and writes the cache.
+func (c *gateCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.Entries))
+	for key, entry := range c.Entries {
+		if time.Since(entry.PassedAt) >= gateCacheMaxAge {
+			delete(c.Entries, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) > gateCacheMaxEntries {
+		sort.Slice(keys, func(i, j int) bool {
+			return c.Entries[keys[i]].PassedAt.After(c.Entries[keys[j]].PassedAt)
+		})
+		for _, key := range keys[gateCacheMaxEntries:] {
+			delete(c.Entries, key)
+		}
+	}
+	return util.EnsureDirAndWriteJSON(c.path, c)
+}
+
+// worktreeTree returns the tree hash of HEAD in dir, or "" if dir isn't a
+// git worktree or has changes to tracked files (which would make the hash
+// meaningless). Untracked files such as earlier gate reports are ignored.
+func worktreeTree(dir string) string {
+	g := git.NewGit(dir)
+	tree, err := g.Rev("HEAD^{tree}")
+	if err != nil {
+		return ""
+	}
+	status, err := g.Status()
+	if err != nil || len(status.Modified)+len(status.Added)+len(status.Deleted) > 0 {
+		return ""
+	}
+	return tree
+}
+
+// testOutcome is one test case parsed from a gate's report.
+type testOutcome struct {
+	name   string
+	failed bool
+}
+
+// gateReportPath returns where gate writes its JUnit report when run in
+// dir, or "" if the gate reports on stdout.
+func gateReportPath(gate *GateConfig, dir string) string {
+	if gate.Format != GateFormatJUnit || gate.Report == "" {
+		return ""
+	}
+	if filepath.IsAbs(gate.Report) {
+		return gate.Report
+	}
+	return filepath.Join(dir, gate.Report)
+}
+
+// clearGateReport removes a report left in dir by an earlier run, so the
+// results read after this run are this run's.
+func clearGateReport(gate *GateConfig, dir string) error {
+	path := gateReportPath(gate, dir)
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale report %s: %w", gate.Report, err)
+	}
+	return nil
+}
+
+// gateTestResults extracts passing and failing test names from a gate's
+// output according to its format. JUnit reports are read from gate.Report
+// (relative to dir) when set, otherwise from stdout; a report last written
+// before since, the start of the gate run, belongs to an earlier run and is
+// ignored. Failing tests are capped at maxFailingTests, with a "(+N more)"
+// marker.
+func gateTestResults(gate *GateConfig, dir string, stdout []byte, since time.Time) (passed, failed []string) {
+	var outcomes []testOutcome
+	switch gate.Format {
+	case GateFormatJUnit:
+		data := stdout
+		if path := gateReportPath(gate, dir); path != "" {
+			// Truncated: some filesystems keep whole-second mtimes
+			if info, err := os.Stat(path); err != nil || info.ModTime().Before(since.Truncate(time.Second)) {
+				return nil, nil
+			}
+			report, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil
+			}
+			data = report
+		}
+		outcomes = parseJUnit(data)
+	case GateFormatTAP:
+		outcomes = parseTAP(string(stdout))
+	}
+	for _, o := range outcomes {
+		if o.failed {
+			failed = append(failed, o.name)
+		} else {
+			passed = append(passed, o.name)
+		}
+	}
+	if len(failed) > maxFailingTests {
+		failed = append(failed[:maxFailingTests], fmt.Sprintf("(+%d more)", len(failed)-maxFailingTests))
+	}
+	return passed, failed
+}
+
+// junitSuite is the subset of the JUnit XML schema needed to find failures.
+// Suites may nest, and the document root may be <testsuites> or <testsuite>.
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []struct {
+		Name      string    `xml:"name,attr"`
+		Classname string    `xml:"classname,attr"`
+		Failure   *struct{} `xml:"failure"`
+		Error     *struct{} `xml:"error"`
+		Skipped   *struct{} `xml:"skipped"`
+	} `xml:"testcase"`
+}
+
+// ParseJUnitFailures returns "<classname>.<name>" for every failed or
+// errored testcase in a JUnit XML report. Returns nil if data isn't JUnit XML.
+func ParseJUnitFailures(data []byte) []string {
+	return failedNames(parseJUnit(data))
+}
+
+// parseJUnit returns the outcome of every testcase that ran (skipped
+// testcases are omitted).
+func parseJUnit(data []byte) []testOutcome {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	var outcomes []testOutcome
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, tc := range s.Cases {
+			failed := tc.Failure != nil || tc.Error != nil
+			if !failed && tc.Skipped != nil {
+				continue
+			}
+			name := tc.Name
+			if tc.Classname != "" {
+				name = tc.Classname + "." + tc.Name
+			}
+			outcomes = append(outcomes, testOutcome{name: name, failed: failed})
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return outcomes
+}
+
+// ParseTAPFailures returns the descriptions of "not ok" test points in TAP
+// output, skipping TODO tests (expected failures). Failed subtests (indented
+// four spaces per level) are reported as "<parent> > <child>".
+func ParseTAPFailures(output string) []string {
+	return failedNames(parseTAP(output))
+}
+
+// parseTAP returns the outcome of every TAP test point except TODO and SKIP
+// directives. A point with subtests is replaced by its subtests, named
+// "<parent> > <child>".
+func parseTAP(output string) []testOutcome {
+	// pending[d] holds outcomes at nesting depth d not yet claimed by a
+	// parent; TAP prints subtest points before their parent's point.
+	var pending [][]testOutcome
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		depth := (len(line) - len(trimmed)) / 4
+
+		var rest string
+		failed := false
+		switch {
+		case strings.HasPrefix(trimmed, "not ok"):
+			failed = true
+			rest = strings.TrimPrefix(trimmed, "not ok")
+		case strings.HasPrefix(trimmed, "ok"):
+			rest = strings.TrimPrefix(trimmed, "ok")
+		default:
+			continue
+		}
+		for len(pending) <= depth+1 {
+			pending = append(pending, nil)
+		}
+		children := pending[depth+1]
+		pending[depth+1] = nil
+
+		desc, directive, _ := strings.Cut(rest, "#")
+		directive = strings.ToUpper(strings.TrimSpace(directive))
+		if strings.HasPrefix(directive, "TODO") || strings.HasPrefix(directive, "SKIP") {
+			continue
+		}
+		desc = tapDescription(desc)
+		if len(children) == 0 {
+			pending[depth] = append(pending[depth], testOutcome{name: desc, failed: failed})
+			continue
+		}
+		for _, child := range children {
+			pending[depth] = append(pending[depth], testOutcome{name: desc + " > " + child.name, failed: child.failed})
+		}
+	}
+
+	var outcomes []testOutcome
+	for _, level := range pending {
+		outcomes = append(outcomes, level...)
+	}
+	return outcomes
+}
+
+func failedNames(outcomes []testOutcome) []string {
+	var names []string
+	for _, o := range outcomes {
+		if o.failed {
+			names = append(names, o.name)
+		}
+	}
+	return names
+}
+
+// tapDescription strips the test number and "- " separator from the text
+// after "ok"/"not ok".
+func tapDescription(s string) string {
+	s = strings.TrimSpace(s)
+	if num, desc, found := strings.Cut(s, " "); isDigits(num) {
+		s = ""
+		if found {
+			s = desc
+		}
+	} else if isDigits(s) {
+		s = ""
+	}
+	s = strings.TrimSpace(strings.TrimPrefix(s, "-"))
+	if s == "" {
+		return "(unnamed test)"
+	}
+	return s
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
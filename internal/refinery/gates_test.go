@@ -0,0 +1,328 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestGateOrder(t *testing.T) {
+	gates := map[string]*GateConfig{
+		"test":  {Cmd: "true", DependsOn: []string{"build"}},
+		"build": {Cmd: "true"},
+		"lint":  {Cmd: "true"},
+		"e2e":   {Cmd: "true", DependsOn: []string{"test", "build"}},
+	}
+	order, err := gateOrder(gates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "build,test,e2e,lint" {
+		t.Errorf("order = %s, want build,test,e2e,lint", got)
+	}
+}
+
+func TestValidateGates(t *testing.T) {
+	tests := []struct {
+		name    string
+		gates   map[string]*GateConfig
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			gates: map[string]*GateConfig{"a": {Cmd: "true"}, "b": {Cmd: "true", DependsOn: []string{"a"}, Format: "junit", Report: "out.xml"}},
+		},
+		{
+			name:    "unknown dependency",
+			gates:   map[string]*GateConfig{"a": {Cmd: "true", DependsOn: []string{"nope"}}},
+			wantErr: "unknown gate",
+		},
+		{
+			name:    "self dependency",
+			gates:   map[string]*GateConfig{"a": {Cmd: "true", DependsOn: []string{"a"}}},
+			wantErr: "depends on itself",
+		},
+		{
+			name: "cycle",
+			gates: map[string]*GateConfig{
+				"a": {Cmd: "true", DependsOn: []string{"c"}},
+				"b": {Cmd: "true", DependsOn: []string{"a"}},
+				"c": {Cmd: "true", DependsOn: []string{"b"}},
+			},
+			wantErr: "cycle",
+		},
+		{
+			name:    "unknown format",
+			gates:   map[string]*GateConfig{"a": {Cmd: "true", Format: "xunit"}},
+			wantErr: "unknown format",
+		},
+		{
+			name:    "report without junit",
+			gates:   map[string]*GateConfig{"a": {Cmd: "true", Format: "tap", Report: "out.tap"}},
+			wantErr: "report",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGates(tt.gates)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEngineer_LoadConfig_GateDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := `{
+		"merge_queue": {
+			"gates": {
+				"build": {"cmd": "go build ./..."},
+				"test": {"cmd": "go test ./...", "depends_on": ["build"], "format": "junit", "report": "report.xml"}
+			},
+			"gate_cache": false
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	test := e.config.Gates["test"]
+	if len(test.DependsOn) != 1 || test.DependsOn[0] != "build" {
+		t.Errorf("DependsOn = %v, want [build]", test.DependsOn)
+	}
+	if test.Format != GateFormatJUnit || test.Report != "report.xml" {
+		t.Errorf("Format/Report = %q/%q", test.Format, test.Report)
+	}
+	if e.config.GateCache {
+		t.Error("expected gate_cache false")
+	}
+
+	cyclic := `{"merge_queue": {"gates": {"a": {"cmd": "true", "depends_on": ["b"]}, "b": {"cmd": "true", "depends_on": ["a"]}}}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(cyclic), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for cyclic gate dependencies")
+	}
+}
+
+func TestRunGates_Parallel_SkipsDependentsOfFailedGate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	markerDir := t.TempDir()
+	e.config.Gates = map[string]*GateConfig{
+		"build": {Cmd: "exit 1"},
+		"test":  {Cmd: fmt.Sprintf("touch %s/test", markerDir), DependsOn: []string{"build"}},
+		"lint":  {Cmd: fmt.Sprintf("touch %s/lint", markerDir)},
+	}
+	e.config.GatesParallel = true
+
+	result := e.runGates(context.Background())
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if _, err := os.Stat(filepath.Join(markerDir, "test")); !os.IsNotExist(err) {
+		t.Error("gate 'test' should not run after its dependency failed")
+	}
+	if _, err := os.Stat(filepath.Join(markerDir, "lint")); err != nil {
+		t.Error("independent gate 'lint' should still run")
+	}
+	if got := FormatGateResults(result.Gates); got != "build=fail lint=pass test=skipped" {
+		t.Errorf("gate results = %q", got)
+	}
+}
+
+func TestRunGates_Parallel_WaitsForDependencies(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	markerDir := t.TempDir()
+	e.config.Gates = map[string]*GateConfig{
+		"build": {Cmd: fmt.Sprintf("sleep 0.2 && touch %s/built", markerDir)},
+		"test":  {Cmd: fmt.Sprintf("test -f %s/built", markerDir), DependsOn: []string{"build"}},
+	}
+	e.config.GatesParallel = true
+
+	if result := e.runGates(context.Background()); !result.Success {
+		t.Errorf("expected success, got error: %s", result.Error)
+	}
+}
+
+func TestRunGates_FailingTestsFromReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	e.config.Gates = map[string]*GateConfig{
+		"unit": {Cmd: "printf 'ok 1 - adds\\nnot ok 2 - subtracts\\n'; exit 1", Format: GateFormatTAP},
+	}
+
+	result := e.runGates(context.Background())
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if got := FailingTests(result.Gates); len(got) != 1 || got[0] != "unit: subtracts" {
+		t.Errorf("FailingTests = %v, want [unit: subtracts]", got)
+	}
+	if !strings.Contains(result.Error, "1 failing test") {
+		t.Errorf("error should summarize failing tests, got: %s", result.Error)
+	}
+}
+
+func TestRunGates_IgnoresStaleReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	e.config.Gates = map[string]*GateConfig{
+		"unit": {Cmd: "exit 1", Format: GateFormatJUnit, Report: "report.xml"},
+	}
+	// Left behind by the previous MR's run; this run writes no report.
+	stale := `<testsuite><testcase classname="pkg" name="TestOld"><failure/></testcase></testsuite>`
+	if err := os.WriteFile(filepath.Join(e.workDir, "report.xml"), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := e.runGates(context.Background())
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if got := FailingTests(result.Gates); len(got) != 0 {
+		t.Errorf("FailingTests = %v, want none from a stale report", got)
+	}
+}
+
+func TestRunGates_CachesPassesByTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	workDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = workDir
+	e.output = io.Discard
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	e.config.Gates = map[string]*GateConfig{
+		"check":   {Cmd: fmt.Sprintf("echo x >> %s", counter)},
+		"nocache": {Cmd: fmt.Sprintf("echo y >> %s", counter), NoCache: true},
+	}
+
+	for i := 0; i < 2; i++ {
+		if result := e.runGates(context.Background()); !result.Success {
+			t.Fatalf("run %d failed: %s", i, result.Error)
+		}
+	}
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(data)); strings.Join(got, "") != "xyy" {
+		t.Errorf("gate runs = %v, want check once and nocache twice", got)
+	}
+
+	e.config.GateCache = false
+	_ = e.runGates(context.Background())
+	data, _ = os.ReadFile(counter)
+	if got := len(strings.Fields(string(data))); got != 5 {
+		t.Errorf("with cache disabled both gates should rerun, got %d runs total", got)
+	}
+}
+
+func TestParseJUnitFailures(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="pkg">
+    <testcase classname="pkg" name="TestPass"></testcase>
+    <testcase classname="pkg" name="TestFail"><failure message="boom">trace</failure></testcase>
+    <testsuite name="nested">
+      <testcase name="TestPanics"><error message="panic"/></testcase>
+      <testcase name="TestSkipped"><skipped/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+	got := ParseJUnitFailures([]byte(report))
+	if want := "pkg.TestFail,TestPanics"; strings.Join(got, ",") != want {
+		t.Errorf("failures = %v, want %s", got, want)
+	}
+
+	single := `<testsuite name="s"><testcase classname="a.B" name="c"><failure/></testcase></testsuite>`
+	if got := ParseJUnitFailures([]byte(single)); len(got) != 1 || got[0] != "a.B.c" {
+		t.Errorf("single suite failures = %v", got)
+	}
+
+	if got := ParseJUnitFailures([]byte("PASS\nok pkg 0.1s")); got != nil {
+		t.Errorf("non-XML input should yield nil, got %v", got)
+	}
+}
+
+func TestParseTAPFailures(t *testing.T) {
+	output := `TAP version 14
+1..5
+ok 1 - setup
+not ok 2 - parses input
+  ---
+  message: expected 3
+  ...
+not ok 3 - flaky network # TODO fix later
+# Subtest: math
+    ok 1 - adds
+    not ok 2 - divides
+    1..2
+not ok 4 - math
+not ok 5
+`
+	got := ParseTAPFailures(output)
+	want := []string{"parses input", "math > divides", "(unnamed test)"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("failures = %q, want %q", got, want)
+	}
+}
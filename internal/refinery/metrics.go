@@ -0,0 +1,389 @@
+// This file contains merge queue metrics: a per-rig time series of MR
+// processing attempts (queued → claimed → gates → finished), and the
+// lead time, throughput and failure rate statistics computed from it.
+
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// FileMQMetricsJSONL is the merge queue time series in the rig's .runtime dir.
+const FileMQMetricsJSONL = "mq-metrics.jsonl"
+
+// MetricsRetention is how long merge queue attempts are kept. Older
+// records are dropped when the file is compacted.
+const MetricsRetention = 90 * 24 * time.Hour
+
+// metricsCompactSize is the file size above which an append also drops
+// records older than MetricsRetention.
+const metricsCompactSize = 4 << 20
+
+// Attempt outcomes.
+const (
+	OutcomeMerged      = "merged"
+	OutcomeConflict    = "conflict"
+	OutcomeGateFailed  = "gate-failed"  // Quality gates, tests, or forge checks failed
+	OutcomeError       = "error"        // Branch missing, push failed, etc.
+	OutcomeWaiting     = "waiting"      // Held for pending forge checks or reviews
+	OutcomeSlotTimeout = "slot-timeout" // Merge slot contention; retried
+)
+
+// GateTiming is one gate's run within an attempt.
+type GateTiming struct {
+	Name      string `json:"name"`
+	Outcome   string `json:"outcome"` // pass, fail, cached, skipped
+	ElapsedMS int64  `json:"elapsed_ms"`
+}
+
+// MRAttempt is one pass of an MR through the refinery.
+type MRAttempt struct {
+	MR      string `json:"mr"`
+	Branch  string `json:"branch,omitempty"`
+	Worker  string `json:"worker,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"` // failure detail
+	Retry   int    `json:"retry,omitempty"`  // conflict-resolution cycles so far
+
+	QueuedAt      time.Time `json:"queued_at"`  // MR bead created
+	ClaimedAt     time.Time `json:"claimed_at"` // refinery picked it up
+	GatesStarted  time.Time `json:"gates_started,omitempty"`
+	GatesFinished time.Time `json:"gates_finished,omitempty"`
+	FinishedAt    time.Time `json:"finished_at"`
+
+	Gates []GateTiming `json:"gates,omitempty"`
+}
+
+// LeadTime is the time from submission to the end of the attempt.
+func (a *MRAttempt) LeadTime() time.Duration { return span(a.QueuedAt, a.FinishedAt) }
+
+// QueueWait is the time from submission until the refinery claimed the MR.
+func (a *MRAttempt) QueueWait() time.Duration { return span(a.QueuedAt, a.ClaimedAt) }
+
+// GateTime is the wall time spent running quality gates.
+func (a *MRAttempt) GateTime() time.Duration { return span(a.GatesStarted, a.GatesFinished) }
+
+// Failed reports whether the attempt failed in a way the worker must fix
+// (as opposed to merging, or waiting on the forge or the merge slot).
+func (a *MRAttempt) Failed() bool {
+	switch a.Outcome {
+	case OutcomeConflict, OutcomeGateFailed, OutcomeError:
+		return true
+	}
+	return false
+}
+
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// outcomeOf classifies a ProcessResult.
+func outcomeOf(result ProcessResult) string {
+	switch {
+	case result.Success:
+		return OutcomeMerged
+	case result.Waiting:
+		return OutcomeWaiting
+	case result.SlotTimeout:
+		return OutcomeSlotTimeout
+	case result.Conflict:
+		return OutcomeConflict
+	case result.TestsFailed || len(result.Gates) > 0:
+		return OutcomeGateFailed
+	default:
+		return OutcomeError
+	}
+}
+
+// NewMRAttempt builds the metrics record for an attempt on mr that was
+// claimed at claimed and ended with result.
+func NewMRAttempt(mr *MRInfo, claimed time.Time, result ProcessResult, now time.Time) MRAttempt {
+	a := MRAttempt{
+		MR:            mr.ID,
+		Branch:        mr.Branch,
+		Worker:        mr.Worker,
+		Target:        mr.Target,
+		Outcome:       outcomeOf(result),
+		Retry:         mr.RetryCount,
+		QueuedAt:      mr.CreatedAt,
+		ClaimedAt:     claimed,
+		GatesStarted:  result.GatesStarted,
+		GatesFinished: result.GatesFinished,
+		FinishedAt:    now,
+	}
+	if !result.Success {
+		a.Reason = truncateReason(result.Error)
+	}
+	for _, g := range result.Gates {
+		outcome := "pass"
+		switch {
+		case g.Skipped:
+			outcome = "skipped"
+		case !g.Success:
+			outcome = "fail"
+		case g.Cached:
+			outcome = "cached"
+		}
+		a.Gates = append(a.Gates, GateTiming{Name: g.Name, Outcome: outcome, ElapsedMS: g.Elapsed.Milliseconds()})
+	}
+	return a
+}
+
+func truncateReason(s string) string {
+	const max = 200
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "…"
+}
+
+// MQMetricsPath returns the merge queue metrics path for a rig.
+func MQMetricsPath(rigPath string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), FileMQMetricsJSONL)
+}
+
+// AppendMQAttempt adds an attempt to the rig's metrics, compacting the file
+// when it has grown large.
+func AppendMQAttempt(rigPath string, a MRAttempt) error {
+	path := MQMetricsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring metrics lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	line, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("encoding attempt: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: runtime data, not secret
+	if err != nil {
+		return fmt.Errorf("opening metrics: %w", err)
+	}
+	_, werr := f.Write(append(line, '\n'))
+	info, serr := f.Stat()
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		return fmt.Errorf("writing metrics: %w", werr)
+	}
+	if serr == nil && info.Size() > metricsCompactSize {
+		return compactMQMetrics(path, a.FinishedAt.Add(-MetricsRetention))
+	}
+	return nil
+}
+
+// compactMQMetrics rewrites the metrics file without records finished
+// before cutoff. The caller holds the lock.
+func compactMQMetrics(path string, cutoff time.Time) error {
+	attempts, err := readMQMetrics(path, cutoff)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, a := range attempts {
+		if err := enc.Encode(a); err != nil {
+			return fmt.Errorf("encoding attempt: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil { //nolint:gosec // G306: runtime data, not secret
+		return fmt.Errorf("compacting metrics: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("compacting metrics: %w", err)
+	}
+	return nil
+}
+
+// LoadMQAttempts returns the rig's attempts that finished at or after
+// since, oldest first. Returns nil if nothing has been recorded.
+func LoadMQAttempts(rigPath string, since time.Time) ([]MRAttempt, error) {
+	return readMQMetrics(MQMetricsPath(rigPath), since)
+}
+
+func readMQMetrics(path string, since time.Time) ([]MRAttempt, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+	defer f.Close()
+
+	var out []MRAttempt
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var a MRAttempt
+		if err := json.Unmarshal(sc.Bytes(), &a); err != nil {
+			continue // skip a line torn by a crash mid-write
+		}
+		if a.FinishedAt.Before(since) {
+			continue
+		}
+		out = append(out, a)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].FinishedAt.Before(out[j].FinishedAt) })
+	return out, nil
+}
+
+// GateStats summarizes one gate over a window.
+type GateStats struct {
+	Name     string        `json:"name"`
+	Runs     int           `json:"runs"` // excludes cached and skipped
+	Failures int           `json:"failures"`
+	P50      time.Duration `json:"p50_ns"`
+	P95      time.Duration `json:"p95_ns"`
+}
+
+// MQStats summarizes merge queue performance over a window.
+type MQStats struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	Attempts int `json:"attempts"`
+	Merged   int `json:"merged"`
+	Failed   int `json:"failed"`  // conflicts, gate failures, errors
+	Waiting  int `json:"waiting"` // forge holds and slot timeouts
+	Retried  int `json:"retried"` // merged MRs that failed at least once first
+
+	// FailureRate is failed / (merged + failed).
+	FailureRate float64 `json:"failure_rate"`
+
+	// ThroughputPerDay is merges per day over the window.
+	ThroughputPerDay float64 `json:"throughput_per_day"`
+
+	LeadTimeP50  time.Duration `json:"lead_time_p50_ns"`
+	LeadTimeP95  time.Duration `json:"lead_time_p95_ns"`
+	QueueWaitP50 time.Duration `json:"queue_wait_p50_ns"`
+	QueueWaitP95 time.Duration `json:"queue_wait_p95_ns"`
+
+	// SLA is the lead time target; WithinSLA is the fraction of merges that
+	// met it. Both are zero when no target is set.
+	SLA       time.Duration `json:"sla_ns,omitempty"`
+	WithinSLA float64       `json:"within_sla,omitempty"`
+
+	FailureReasons map[string]int `json:"failure_reasons,omitempty"` // by outcome
+	Gates          []GateStats    `json:"gates,omitempty"`
+}
+
+// ComputeMQStats summarizes attempts finished in [since, until]. Lead time
+// and queue wait are measured on merges only; sla, if positive, is the
+// lead time target.
+func ComputeMQStats(attempts []MRAttempt, since, until time.Time, sla time.Duration) *MQStats {
+	s := &MQStats{Since: since, Until: until, SLA: sla, FailureReasons: make(map[string]int)}
+	var lead, wait []time.Duration
+	gateDur := make(map[string][]time.Duration)
+	gateFail := make(map[string]int)
+	failedMRs := make(map[string]bool)
+	withinSLA := 0
+
+	for i := range attempts {
+		a := &attempts[i]
+		if a.FinishedAt.Before(since) || a.FinishedAt.After(until) {
+			continue
+		}
+		s.Attempts++
+		switch {
+		case a.Outcome == OutcomeMerged:
+			s.Merged++
+			lead = append(lead, a.LeadTime())
+			wait = append(wait, a.QueueWait())
+			if sla > 0 && a.LeadTime() <= sla {
+				withinSLA++
+			}
+			if failedMRs[a.MR] || a.Retry > 0 {
+				s.Retried++
+			}
+		case a.Failed():
+			s.Failed++
+			s.FailureReasons[a.Outcome]++
+			failedMRs[a.MR] = true
+		default:
+			s.Waiting++
+		}
+		for _, g := range a.Gates {
+			if g.Outcome != "pass" && g.Outcome != "fail" {
+				continue
+			}
+			gateDur[g.Name] = append(gateDur[g.Name], time.Duration(g.ElapsedMS)*time.Millisecond)
+			if g.Outcome == "fail" {
+				gateFail[g.Name]++
+			}
+		}
+	}
+
+	if done := s.Merged + s.Failed; done > 0 {
+		s.FailureRate = float64(s.Failed) / float64(done)
+	}
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		s.ThroughputPerDay = float64(s.Merged) / days
+	}
+	s.LeadTimeP50, s.LeadTimeP95 = percentile(lead, 50), percentile(lead, 95)
+	s.QueueWaitP50, s.QueueWaitP95 = percentile(wait, 50), percentile(wait, 95)
+	if sla > 0 && s.Merged > 0 {
+		s.WithinSLA = float64(withinSLA) / float64(s.Merged)
+	}
+	for _, name := range sortedKeys(gateDur) {
+		d := gateDur[name]
+		s.Gates = append(s.Gates, GateStats{
+			Name:     name,
+			Runs:     len(d),
+			Failures: gateFail[name],
+			P50:      percentile(d, 50),
+			P95:      percentile(d, 95),
+		})
+	}
+	return s
+}
+
+// percentile returns the nearest-rank pth percentile of ds (0 if empty).
+func percentile(ds []time.Duration, p float64) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// recordAttempt appends the attempt to the rig's metrics. Metrics are
+// best-effort: a write failure is reported but never fails the merge.
+func (e *Engineer) recordAttempt(mr *MRInfo, claimed time.Time, result ProcessResult) {
+	if mr.ID == "" {
+		return
+	}
+	a := NewMRAttempt(mr, claimed, result, time.Now())
+	if err := AppendMQAttempt(e.rig.Path, a); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record merge queue metrics: %v\n", err)
+	}
+}
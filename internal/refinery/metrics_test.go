@@ -0,0 +1,198 @@
+package refinery
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMQAttempts_AppendAndLoad(t *testing.T) {
+	rigPath := t.TempDir()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, outcome := range []string{OutcomeGateFailed, OutcomeMerged, OutcomeMerged} {
+		a := MRAttempt{MR: "gt-mr" + string(rune('a'+i)), Outcome: outcome, QueuedAt: base, FinishedAt: base.Add(time.Duration(i+1) * time.Hour)}
+		if err := AppendMQAttempt(rigPath, a); err != nil {
+			t.Fatalf("AppendMQAttempt: %v", err)
+		}
+	}
+	// A torn trailing line (crash mid-write) is skipped
+	f, err := os.OpenFile(MQMetricsPath(rigPath), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"mr":"gt-mrx","outc`)
+	_ = f.Close()
+
+	all, err := LoadMQAttempts(rigPath, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("loaded %d attempts, want 3", len(all))
+	}
+	recent, err := LoadMQAttempts(rigPath, base.Add(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].MR != "gt-mrb" {
+		t.Errorf("since filter returned %+v", recent)
+	}
+
+	if got, err := LoadMQAttempts(t.TempDir(), time.Time{}); err != nil || got != nil {
+		t.Errorf("LoadMQAttempts with no history = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestCompactMQMetrics(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, age := range []time.Duration{120 * 24 * time.Hour, 10 * 24 * time.Hour} {
+		if err := AppendMQAttempt(rigPath, MRAttempt{MR: "gt-mr", Outcome: OutcomeMerged, FinishedAt: now.Add(-age)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := compactMQMetrics(MQMetricsPath(rigPath), now.Add(-MetricsRetention)); err != nil {
+		t.Fatal(err)
+	}
+	all, err := LoadMQAttempts(rigPath, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || !all[0].FinishedAt.Equal(now.Add(-10*24*time.Hour)) {
+		t.Errorf("after compaction: %+v, want only the recent attempt", all)
+	}
+}
+
+func TestNewMRAttempt(t *testing.T) {
+	queued := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	claimed := queued.Add(10 * time.Minute)
+	mr := &MRInfo{ID: "gt-mr1", Branch: "polecat/nux/gt-abc", Worker: "nux", Target: "main", RetryCount: 1, CreatedAt: queued}
+
+	tests := []struct {
+		name   string
+		result ProcessResult
+		want   string
+	}{
+		{"merged", ProcessResult{Success: true}, OutcomeMerged},
+		{"waiting", ProcessResult{Waiting: true, Error: "waiting on e2e"}, OutcomeWaiting},
+		{"slot timeout", ProcessResult{SlotTimeout: true}, OutcomeSlotTimeout},
+		{"conflict", ProcessResult{Conflict: true}, OutcomeConflict},
+		{"tests", ProcessResult{TestsFailed: true}, OutcomeGateFailed},
+		{"gate failed", ProcessResult{Gates: []GateResult{{Name: "build"}}}, OutcomeGateFailed},
+		{"other", ProcessResult{Error: "branch not found"}, OutcomeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewMRAttempt(mr, claimed, tt.result, claimed.Add(time.Minute))
+			if a.Outcome != tt.want {
+				t.Errorf("Outcome = %q, want %q", a.Outcome, tt.want)
+			}
+		})
+	}
+
+	result := ProcessResult{
+		TestsFailed:   true,
+		Error:         strings.Repeat("x", 500),
+		GatesStarted:  claimed.Add(time.Minute),
+		GatesFinished: claimed.Add(4 * time.Minute),
+		Gates: []GateResult{
+			{Name: "build", Success: true, Elapsed: 90 * time.Second},
+			{Name: "lint", Success: true, Cached: true},
+			{Name: "test", Elapsed: 2 * time.Minute, FailingTests: []string{"TestFoo"}},
+			{Name: "e2e", Skipped: true},
+		},
+	}
+	a := NewMRAttempt(mr, claimed, result, claimed.Add(5*time.Minute))
+	if a.QueueWait() != 10*time.Minute || a.LeadTime() != 15*time.Minute || a.GateTime() != 3*time.Minute {
+		t.Errorf("timings: wait %v lead %v gates %v", a.QueueWait(), a.LeadTime(), a.GateTime())
+	}
+	if a.Retry != 1 || len(a.Reason) > 210 {
+		t.Errorf("Retry = %d, len(Reason) = %d", a.Retry, len(a.Reason))
+	}
+	var outcomes []string
+	for _, g := range a.Gates {
+		outcomes = append(outcomes, g.Name+"="+g.Outcome)
+	}
+	if got := strings.Join(outcomes, " "); got != "build=pass lint=cached test=fail e2e=skipped" {
+		t.Errorf("gate outcomes = %s", got)
+	}
+}
+
+func TestComputeMQStats(t *testing.T) {
+	until := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	since := until.Add(-7 * 24 * time.Hour)
+	at := func(daysAgo int) time.Time { return until.Add(-time.Duration(daysAgo) * 24 * time.Hour) }
+	merged := func(mr string, daysAgo int, lead time.Duration) MRAttempt {
+		end := at(daysAgo)
+		return MRAttempt{
+			MR: mr, Outcome: OutcomeMerged,
+			QueuedAt: end.Add(-lead), ClaimedAt: end.Add(-lead / 2), FinishedAt: end,
+			Gates: []GateTiming{{Name: "test", Outcome: "pass", ElapsedMS: lead.Milliseconds() / 10}},
+		}
+	}
+
+	attempts := []MRAttempt{
+		{MR: "gt-old", Outcome: OutcomeMerged, FinishedAt: at(30)}, // outside the window
+		{MR: "gt-1", Outcome: OutcomeGateFailed, FinishedAt: at(6), Gates: []GateTiming{{Name: "test", Outcome: "fail", ElapsedMS: 1000}}},
+		merged("gt-1", 5, 4*time.Hour),
+		merged("gt-2", 4, 1*time.Hour),
+		merged("gt-3", 3, 2*time.Hour),
+		{MR: "gt-4", Outcome: OutcomeConflict, FinishedAt: at(2)},
+		{MR: "gt-5", Outcome: OutcomeWaiting, FinishedAt: at(2)},
+		{MR: "gt-5", Outcome: OutcomeSlotTimeout, FinishedAt: at(1)},
+		merged("gt-6", 1, 30*time.Minute),
+	}
+
+	s := ComputeMQStats(attempts, since, until, 3*time.Hour)
+	if s.Attempts != 8 || s.Merged != 4 || s.Failed != 2 || s.Waiting != 2 {
+		t.Fatalf("counts: attempts %d merged %d failed %d waiting %d", s.Attempts, s.Merged, s.Failed, s.Waiting)
+	}
+	if s.Retried != 1 {
+		t.Errorf("Retried = %d, want 1 (gt-1 failed before merging)", s.Retried)
+	}
+	if want := 2.0 / 6.0; s.FailureRate != want {
+		t.Errorf("FailureRate = %v, want %v", s.FailureRate, want)
+	}
+	if s.ThroughputPerDay != 4.0/7.0 {
+		t.Errorf("ThroughputPerDay = %v", s.ThroughputPerDay)
+	}
+	// Lead times sorted: 30m, 1h, 2h, 4h
+	if s.LeadTimeP50 != time.Hour || s.LeadTimeP95 != 4*time.Hour {
+		t.Errorf("lead time p50 %v p95 %v, want 1h and 4h", s.LeadTimeP50, s.LeadTimeP95)
+	}
+	if s.QueueWaitP50 != 30*time.Minute {
+		t.Errorf("QueueWaitP50 = %v, want 30m", s.QueueWaitP50)
+	}
+	if s.WithinSLA != 0.75 {
+		t.Errorf("WithinSLA = %v, want 0.75", s.WithinSLA)
+	}
+	if s.FailureReasons[OutcomeGateFailed] != 1 || s.FailureReasons[OutcomeConflict] != 1 {
+		t.Errorf("FailureReasons = %v", s.FailureReasons)
+	}
+	if len(s.Gates) != 1 || s.Gates[0].Runs != 5 || s.Gates[0].Failures != 1 {
+		t.Errorf("Gates = %+v, want test with 5 runs and 1 failure", s.Gates)
+	}
+
+	empty := ComputeMQStats(nil, since, until, 0)
+	if empty.Attempts != 0 || empty.FailureRate != 0 || empty.LeadTimeP95 != 0 || empty.WithinSLA != 0 {
+		t.Errorf("empty stats = %+v", empty)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	ds := []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	if got := percentile(ds, 50); got != 5 {
+		t.Errorf("p50 = %v, want 5", got)
+	}
+	if got := percentile(ds, 95); got != 10 {
+		t.Errorf("p95 = %v, want 10", got)
+	}
+	if got := percentile([]time.Duration{7}, 95); got != 7 {
+		t.Errorf("single-value p95 = %v, want 7", got)
+	}
+	if ds[0] != 5 {
+		t.Error("percentile must not reorder its input")
+	}
+}
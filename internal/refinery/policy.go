@@ -0,0 +1,397 @@
+// This file contains named MR prioritization policies layered on top of the
+// base score formula in score.go.
+
+package refinery
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Built-in score policy names. Selected per rig with merge_queue.score_policy.
+const (
+	PolicyDefault          = "default"
+	PolicySmallestDiff     = "smallest-diff"
+	PolicyConvoyCompletion = "convoy-completion"
+	PolicyCriticalPath     = "critical-path"
+	PolicyFairShare        = "fair-share"
+)
+
+const (
+	// smallDiffBonus is the most points smallest-diff adds (for an empty diff).
+	// The bonus decays with log2 of the diff size and reaches zero at
+	// smallDiffCapLines, so a 10-line fix beats a 1000-line refactor by about
+	// one priority level.
+	smallDiffBonus    = 400.0
+	smallDiffCapLines = 4096
+
+	// convoyCloseBonus is added when merging the MR completes its convoy.
+	// Otherwise convoy-completion adds convoyCloseBonus/remaining.
+	convoyCloseBonus = 500.0
+
+	// criticalPathDepthWeight is added per level of the longest chain of open
+	// convoy issues waiting on the MR's source issue; criticalPathFanoutWeight
+	// is added per issue (direct or transitive) waiting on it.
+	criticalPathDepthWeight  = 150.0
+	criticalPathFanoutWeight = 25.0
+
+	// fairSharePenalty is subtracted per MR from the same worker ranked ahead,
+	// interleaving workers instead of draining one worker's MRs first.
+	fairSharePenalty = 200.0
+)
+
+// ScoreFactor is one term of an MR's score.
+type ScoreFactor struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// ScoreBreakdown is an MR's total score under a policy and the factors it
+// was built from.
+type ScoreBreakdown struct {
+	Policy  string        `json:"policy"`
+	Total   float64       `json:"total"`
+	Factors []ScoreFactor `json:"factors"`
+}
+
+// String formats the factors for display, e.g.
+// "base +1000.0, priority +300.0 (P1), retries -50.0 (1 retries)".
+func (b *ScoreBreakdown) String() string {
+	parts := make([]string, 0, len(b.Factors))
+	for _, f := range b.Factors {
+		s := fmt.Sprintf("%s %+.1f", f.Name, f.Points)
+		if f.Detail != "" {
+			s += " (" + f.Detail + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PolicyNeeds declares which queue-wide data a policy reads from PolicyData,
+// so callers only pay for the git and bd lookups the policy uses.
+type PolicyNeeds struct {
+	DiffSize    bool // PolicyData.DiffLines
+	ConvoyGraph bool // PolicyData.Convoys
+}
+
+// ConvoyState is the open work tracked by a convoy, as seen by the scorer.
+type ConvoyState struct {
+	// Open is the set of tracked issues that are not yet closed.
+	Open map[string]bool
+
+	// BlockedBy maps each open tracked issue to the open tracked issues it
+	// depends on (the convoy DAG, restricted to open work).
+	BlockedBy map[string][]string
+}
+
+// PolicyData holds queue-wide facts a policy may need beyond a single MR.
+// Missing entries mean "unknown" and score as neutral.
+type PolicyData struct {
+	// DiffLines maps MR ID to lines added plus removed relative to its target.
+	DiffLines map[string]int
+
+	// Convoys maps convoy ID to its open tracked work.
+	Convoys map[string]*ConvoyState
+}
+
+// ScorePolicy decides the order in which MRs are merged.
+type ScorePolicy interface {
+	// Name is the policy's config name (e.g. "smallest-diff").
+	Name() string
+
+	// Description is a one-line summary for help output.
+	Description() string
+
+	// Needs reports which PolicyData fields the policy reads.
+	Needs() PolicyNeeds
+
+	// Factors returns the policy-specific score terms for mr, added on top
+	// of the base score. queue is the full set being ranked, already
+	// ordered by base score (highest first).
+	Factors(mr *MRInfo, queue []*MRInfo, data *PolicyData) []ScoreFactor
+}
+
+var scorePolicies = map[string]ScorePolicy{
+	PolicyDefault:          defaultPolicy{},
+	PolicySmallestDiff:     smallestDiffPolicy{},
+	PolicyConvoyCompletion: convoyCompletionPolicy{},
+	PolicyCriticalPath:     criticalPathPolicy{},
+	PolicyFairShare:        fairSharePolicy{},
+}
+
+// LookupScorePolicy returns the named policy. An empty name selects the
+// default policy.
+func LookupScorePolicy(name string) (ScorePolicy, error) {
+	if name == "" {
+		name = PolicyDefault
+	}
+	p, ok := scorePolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown score policy %q (available: %s)", name, strings.Join(ScorePolicyNames(), ", "))
+	}
+	return p, nil
+}
+
+// ScorePolicyNames returns the names of all built-in policies, sorted.
+func ScorePolicyNames() []string {
+	names := make([]string, 0, len(scorePolicies))
+	for name := range scorePolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RankedMR is an MR with its score under a policy.
+type RankedMR struct {
+	MR    *MRInfo
+	Score ScoreBreakdown
+}
+
+// RankMRs scores mrs under policy and returns them highest score first.
+// Ties go to the older MR, then to the lower ID, so the order is stable.
+func RankMRs(mrs []*MRInfo, policy ScorePolicy, data *PolicyData, now time.Time) []RankedMR {
+	if data == nil {
+		data = &PolicyData{}
+	}
+	cfg := DefaultScoreConfig()
+
+	base := make(map[*MRInfo][]ScoreFactor, len(mrs))
+	for _, mr := range mrs {
+		base[mr] = ScoreFactors(mrScoreInput(mr, now), cfg)
+	}
+
+	// Policies see the queue in base-score order
+	queue := append([]*MRInfo(nil), mrs...)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return sumFactors(base[queue[i]]) > sumFactors(base[queue[j]])
+	})
+
+	ranked := make([]RankedMR, 0, len(queue))
+	for _, mr := range queue {
+		factors := append(append([]ScoreFactor(nil), base[mr]...), policy.Factors(mr, queue, data)...)
+		ranked = append(ranked, RankedMR{
+			MR: mr,
+			Score: ScoreBreakdown{
+				Policy:  policy.Name(),
+				Total:   sumFactors(factors),
+				Factors: factors,
+			},
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score.Total != b.Score.Total {
+			return a.Score.Total > b.Score.Total
+		}
+		if !a.MR.CreatedAt.Equal(b.MR.CreatedAt) {
+			return a.MR.CreatedAt.Before(b.MR.CreatedAt)
+		}
+		return a.MR.ID < b.MR.ID
+	})
+	return ranked
+}
+
+func mrScoreInput(mr *MRInfo, now time.Time) ScoreInput {
+	created := mr.CreatedAt
+	if created.IsZero() {
+		created = now
+	}
+	return ScoreInput{
+		Priority:        mr.Priority,
+		MRCreatedAt:     created,
+		ConvoyCreatedAt: mr.ConvoyCreatedAt,
+		RetryCount:      mr.RetryCount,
+		Now:             now,
+	}
+}
+
+func sumFactors(factors []ScoreFactor) float64 {
+	total := 0.0
+	for _, f := range factors {
+		total += f.Points
+	}
+	return total
+}
+
+// defaultPolicy is the plain base formula: convoy age, priority, retries, MR age.
+type defaultPolicy struct{}
+
+func (defaultPolicy) Name() string { return PolicyDefault }
+func (defaultPolicy) Description() string {
+	return "convoy age, priority, retry count and MR age"
+}
+func (defaultPolicy) Needs() PolicyNeeds { return PolicyNeeds{} }
+func (defaultPolicy) Factors(*MRInfo, []*MRInfo, *PolicyData) []ScoreFactor {
+	return nil
+}
+
+// smallestDiffPolicy favors small diffs: they merge fast and rarely conflict.
+type smallestDiffPolicy struct{}
+
+func (smallestDiffPolicy) Name() string        { return PolicySmallestDiff }
+func (smallestDiffPolicy) Description() string { return "prefer MRs with the smallest diff" }
+func (smallestDiffPolicy) Needs() PolicyNeeds  { return PolicyNeeds{DiffSize: true} }
+func (smallestDiffPolicy) Factors(mr *MRInfo, _ []*MRInfo, data *PolicyData) []ScoreFactor {
+	lines, ok := data.DiffLines[mr.ID]
+	if !ok {
+		return []ScoreFactor{{Name: "diff-size", Detail: "diff size unknown"}}
+	}
+	return []ScoreFactor{{
+		Name:   "diff-size",
+		Points: smallDiffPoints(lines),
+		Detail: fmt.Sprintf("%d lines", lines),
+	}}
+}
+
+func smallDiffPoints(lines int) float64 {
+	if lines < 0 {
+		lines = 0
+	}
+	frac := math.Log2(float64(lines)+1) / math.Log2(smallDiffCapLines+1)
+	if frac > 1 {
+		frac = 1
+	}
+	return smallDiffBonus * (1 - frac)
+}
+
+// convoyCompletionPolicy favors MRs that finish (or nearly finish) a convoy,
+// so convoys land instead of every convoy being partly merged.
+type convoyCompletionPolicy struct{}
+
+func (convoyCompletionPolicy) Name() string { return PolicyConvoyCompletion }
+func (convoyCompletionPolicy) Description() string {
+	return "prefer MRs that close out a convoy"
+}
+func (convoyCompletionPolicy) Needs() PolicyNeeds { return PolicyNeeds{ConvoyGraph: true} }
+func (convoyCompletionPolicy) Factors(mr *MRInfo, _ []*MRInfo, data *PolicyData) []ScoreFactor {
+	if mr.ConvoyID == "" {
+		return nil
+	}
+	convoy := data.Convoys[mr.ConvoyID]
+	if convoy == nil {
+		return []ScoreFactor{{Name: "convoy-completion", Detail: "convoy state unknown"}}
+	}
+	remaining := len(convoy.Open)
+	if mr.SourceIssue != "" && !convoy.Open[mr.SourceIssue] {
+		// Source issue already closed (or untracked): count this MR as the
+		// outstanding work it represents.
+		remaining++
+	}
+	if remaining <= 1 {
+		return []ScoreFactor{{
+			Name:   "convoy-completion",
+			Points: convoyCloseBonus,
+			Detail: "closes convoy " + mr.ConvoyID,
+		}}
+	}
+	return []ScoreFactor{{
+		Name:   "convoy-completion",
+		Points: convoyCloseBonus / float64(remaining),
+		Detail: fmt.Sprintf("%d issues left in convoy %s", remaining, mr.ConvoyID),
+	}}
+}
+
+// criticalPathPolicy favors MRs whose source issue blocks the longest chain
+// of remaining work in its convoy's dependency graph.
+type criticalPathPolicy struct{}
+
+func (criticalPathPolicy) Name() string { return PolicyCriticalPath }
+func (criticalPathPolicy) Description() string {
+	return "prefer MRs that unblock the longest chain of convoy work"
+}
+func (criticalPathPolicy) Needs() PolicyNeeds { return PolicyNeeds{ConvoyGraph: true} }
+func (criticalPathPolicy) Factors(mr *MRInfo, _ []*MRInfo, data *PolicyData) []ScoreFactor {
+	if mr.ConvoyID == "" || mr.SourceIssue == "" {
+		return nil
+	}
+	convoy := data.Convoys[mr.ConvoyID]
+	if convoy == nil {
+		return []ScoreFactor{{Name: "critical-path", Detail: "convoy state unknown"}}
+	}
+	depth, fanout := convoy.downstream(mr.SourceIssue)
+	if depth == 0 {
+		return []ScoreFactor{{Name: "critical-path", Detail: "blocks nothing"}}
+	}
+	return []ScoreFactor{{
+		Name:   "critical-path",
+		Points: criticalPathDepthWeight*float64(depth) + criticalPathFanoutWeight*float64(fanout),
+		Detail: fmt.Sprintf("unblocks %d issues, chain depth %d", fanout, depth),
+	}}
+}
+
+// downstream returns the length of the longest chain of open issues waiting
+// on id, and how many distinct open issues wait on it directly or transitively.
+func (c *ConvoyState) downstream(id string) (depth, fanout int) {
+	dependents := make(map[string][]string)
+	for issue, blockers := range c.BlockedBy {
+		for _, b := range blockers {
+			dependents[b] = append(dependents[b], issue)
+		}
+	}
+
+	seen := make(map[string]bool)
+	memo := make(map[string]int)
+	onPath := make(map[string]bool)
+	var longest func(string) int
+	longest = func(n string) int {
+		if d, ok := memo[n]; ok {
+			return d
+		}
+		if onPath[n] {
+			return 0 // dependency cycle: don't recurse forever
+		}
+		onPath[n] = true
+		best := 0
+		for _, dep := range dependents[n] {
+			seen[dep] = true
+			if d := 1 + longest(dep); d > best {
+				best = d
+			}
+		}
+		onPath[n] = false
+		memo[n] = best
+		return best
+	}
+	depth = longest(id)
+	delete(seen, id)
+	return depth, len(seen)
+}
+
+// fairSharePolicy interleaves workers so one prolific worker can't starve
+// the rest of the queue.
+type fairSharePolicy struct{}
+
+func (fairSharePolicy) Name() string { return PolicyFairShare }
+func (fairSharePolicy) Description() string {
+	return "round-robin between workers"
+}
+func (fairSharePolicy) Needs() PolicyNeeds { return PolicyNeeds{} }
+func (fairSharePolicy) Factors(mr *MRInfo, queue []*MRInfo, _ *PolicyData) []ScoreFactor {
+	if mr.Worker == "" {
+		return nil
+	}
+	ahead := 0
+	for _, other := range queue {
+		if other == mr {
+			break
+		}
+		if other.Worker == mr.Worker {
+			ahead++
+		}
+	}
+	if ahead == 0 {
+		return nil
+	}
+	return []ScoreFactor{{
+		Name:   "fair-share",
+		Points: -fairSharePenalty * float64(ahead),
+		Detail: fmt.Sprintf("%d MRs from %s ahead", ahead, mr.Worker),
+	}}
+}
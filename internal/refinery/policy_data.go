@@ -0,0 +1,182 @@
+package refinery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ScorePolicy returns the rig's configured MR score policy.
+func (e *Engineer) ScorePolicy() (ScorePolicy, error) {
+	return LookupScorePolicy(e.scorePolicyName())
+}
+
+// scorePolicyName resolves the rig's policy name: merge_queue.score_policy
+// from the rig config.json (see LoadConfig) wins over the same key in the
+// rig settings file.
+func (e *Engineer) scorePolicyName() string {
+	if e.config.ScorePolicy != "" {
+		return e.config.ScorePolicy
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err == nil && settings.MergeQueue != nil {
+		return settings.MergeQueue.ScorePolicy
+	}
+	return ""
+}
+
+// RankMRs orders mrs by the rig's score policy (or by override, if set),
+// gathering only the git and beads data the policy needs.
+func (e *Engineer) RankMRs(mrs []*MRInfo, override string) ([]RankedMR, error) {
+	name := e.scorePolicyName()
+	if override != "" {
+		name = override
+	}
+	policy, err := LookupScorePolicy(name)
+	if err != nil {
+		return nil, err
+	}
+	return RankMRs(mrs, policy, e.PolicyData(policy, mrs), time.Now()), nil
+}
+
+// PolicyData gathers the queue-wide facts policy declares it needs.
+// Lookups that fail leave entries missing, which score as neutral.
+func (e *Engineer) PolicyData(policy ScorePolicy, mrs []*MRInfo) *PolicyData {
+	needs := policy.Needs()
+	data := &PolicyData{}
+
+	if needs.DiffSize {
+		data.DiffLines = make(map[string]int)
+		for _, mr := range mrs {
+			if lines, ok := e.diffLines(mr); ok {
+				data.DiffLines[mr.ID] = lines
+			}
+		}
+	}
+
+	if needs.ConvoyGraph {
+		data.Convoys = make(map[string]*ConvoyState)
+		for _, mr := range mrs {
+			if mr.ConvoyID == "" {
+				continue
+			}
+			if _, done := data.Convoys[mr.ConvoyID]; done {
+				continue
+			}
+			if state, err := e.convoyState(mr.ConvoyID); err == nil {
+				data.Convoys[mr.ConvoyID] = state
+			} else {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not load convoy %s for scoring: %v\n", mr.ConvoyID, err)
+			}
+		}
+	}
+
+	return data
+}
+
+// diffLines returns the size of mr's diff against its target, preferring
+// remote-tracking refs (polecat branches usually only exist on origin).
+func (e *Engineer) diffLines(mr *MRInfo) (int, bool) {
+	if mr.Branch == "" {
+		return 0, false
+	}
+	target := mr.Target
+	if target == "" {
+		target = e.rig.DefaultBranch()
+	}
+	if lines, err := e.git.DiffLineCount("origin/"+target, "origin/"+mr.Branch); err == nil {
+		return lines, true
+	}
+	if lines, err := e.git.DiffLineCount(target, mr.Branch); err == nil {
+		return lines, true
+	}
+	return 0, false
+}
+
+// convoyState loads a convoy's open tracked issues and the dependencies
+// between them. Tracked issues usually live in rig beads, so statuses are
+// re-read with a routed bd show rather than trusted from the town's
+// dependency records.
+func (e *Engineer) convoyState(convoyID string) (*ConvoyState, error) {
+	townRoot := filepath.Dir(e.rig.Path)
+
+	depCmd := exec.Command("bd", "dep", "list", convoyID, "--direction=down", "--type=tracks", "--json")
+	depCmd.Dir = townRoot
+	var depOut bytes.Buffer
+	depCmd.Stdout = &depOut
+	if err := depCmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing tracked issues: %w", err)
+	}
+
+	var deps []struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(depOut.Bytes(), &deps); err != nil {
+		return nil, fmt.Errorf("parsing tracked issues: %w", err)
+	}
+
+	ids := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		ids = append(ids, beads.ExtractIssueID(dep.ID))
+	}
+	issues, err := beads.New(townRoot).ShowMultiple(ids)
+	if err != nil {
+		issues = nil // fall back to the dependency records' statuses, no edges
+	}
+
+	state := &ConvoyState{Open: make(map[string]bool), BlockedBy: make(map[string][]string)}
+	for i, dep := range deps {
+		status := dep.Status
+		if issue := issues[ids[i]]; issue != nil {
+			status = issue.Status
+		}
+		if status != "closed" && status != "tombstone" {
+			state.Open[ids[i]] = true
+		}
+	}
+
+	for id := range state.Open {
+		issue := issues[id]
+		if issue == nil {
+			continue
+		}
+		for _, blocker := range issueBlockers(issue) {
+			if state.Open[blocker] {
+				state.BlockedBy[id] = append(state.BlockedBy[id], blocker)
+			}
+		}
+	}
+	return state, nil
+}
+
+// issueBlockers returns the IDs issue depends on via blocking dependencies.
+func issueBlockers(issue *beads.Issue) []string {
+	seen := make(map[string]bool)
+	var blockers []string
+	add := func(id string) {
+		id = beads.ExtractIssueID(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			blockers = append(blockers, id)
+		}
+	}
+	for _, id := range issue.DependsOn {
+		add(id)
+	}
+	for _, id := range issue.BlockedBy {
+		add(id)
+	}
+	for _, dep := range issue.Dependencies {
+		if dep.DependencyType == "" || dep.DependencyType == "blocks" {
+			add(dep.ID)
+		}
+	}
+	return blockers
+}
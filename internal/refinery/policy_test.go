@@ -0,0 +1,156 @@
+package refinery
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func rankedIDs(ranked []RankedMR) []string {
+	ids := make([]string, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.MR.ID
+	}
+	return ids
+}
+
+func TestScoreFactorsSumToScoreMR(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	convoy := now.Add(-10 * time.Hour)
+	input := ScoreInput{
+		Priority:        1,
+		MRCreatedAt:     now.Add(-3 * time.Hour),
+		ConvoyCreatedAt: &convoy,
+		RetryCount:      2,
+		Now:             now,
+	}
+	cfg := DefaultScoreConfig()
+
+	// 1000 + 10*10 + 100*3 - 50*2 + 1*3
+	if got := ScoreMR(input, cfg); got != 1303 {
+		t.Errorf("ScoreMR = %v, want 1303", got)
+	}
+	var names []string
+	for _, f := range ScoreFactors(input, cfg) {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "base,convoy-age,priority,retries,mr-age" {
+		t.Errorf("factors = %s", got)
+	}
+}
+
+func TestLookupScorePolicy(t *testing.T) {
+	for _, name := range append(ScorePolicyNames(), "") {
+		if _, err := LookupScorePolicy(name); err != nil {
+			t.Errorf("LookupScorePolicy(%q): %v", name, err)
+		}
+	}
+	if _, err := LookupScorePolicy("lottery"); err == nil {
+		t.Error("unknown policy should error")
+	}
+}
+
+func TestRankMRsPolicies(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	mr := func(id, worker, convoy, source string, priority int) *MRInfo {
+		return &MRInfo{ID: id, Worker: worker, ConvoyID: convoy, SourceIssue: source,
+			Priority: priority, CreatedAt: now.Add(-time.Hour)}
+	}
+
+	tests := []struct {
+		name   string
+		policy string
+		mrs    []*MRInfo
+		data   *PolicyData
+		want   []string
+	}{
+		{
+			name:   "default orders by priority",
+			policy: PolicyDefault,
+			mrs:    []*MRInfo{mr("a", "nux", "", "", 2), mr("b", "nux", "", "", 1)},
+			want:   []string{"b", "a"},
+		},
+		{
+			name:   "smallest diff first",
+			policy: PolicySmallestDiff,
+			mrs:    []*MRInfo{mr("big", "nux", "", "", 2), mr("small", "nux", "", "", 2)},
+			data:   &PolicyData{DiffLines: map[string]int{"big": 3000, "small": 12}},
+			want:   []string{"small", "big"},
+		},
+		{
+			name:   "convoy completion beats a larger convoy",
+			policy: PolicyConvoyCompletion,
+			mrs:    []*MRInfo{mr("wide", "nux", "hq-c1", "gt-1", 2), mr("last", "toast", "hq-c2", "gt-9", 2)},
+			data: &PolicyData{Convoys: map[string]*ConvoyState{
+				"hq-c1": {Open: map[string]bool{"gt-1": true, "gt-2": true, "gt-3": true}},
+				"hq-c2": {Open: map[string]bool{"gt-9": true}},
+			}},
+			want: []string{"last", "wide"},
+		},
+		{
+			name:   "critical path favors the blocker of a chain",
+			policy: PolicyCriticalPath,
+			mrs:    []*MRInfo{mr("leaf", "nux", "hq-c1", "gt-3", 2), mr("root", "toast", "hq-c1", "gt-1", 2)},
+			data: &PolicyData{Convoys: map[string]*ConvoyState{
+				"hq-c1": {
+					Open:      map[string]bool{"gt-1": true, "gt-2": true, "gt-3": true},
+					BlockedBy: map[string][]string{"gt-2": {"gt-1"}, "gt-3": {"gt-2"}},
+				},
+			}},
+			want: []string{"root", "leaf"},
+		},
+		{
+			name:   "fair share interleaves workers",
+			policy: PolicyFairShare,
+			mrs: []*MRInfo{
+				mr("nux-1", "nux", "", "", 1), mr("nux-2", "nux", "", "", 1),
+				mr("nux-3", "nux", "", "", 1), mr("toast-1", "toast", "", "", 2),
+			},
+			want: []string{"nux-1", "toast-1", "nux-2", "nux-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := LookupScorePolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ranked := RankMRs(tt.mrs, policy, tt.data, now)
+			got := rankedIDs(ranked)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+			for _, r := range ranked {
+				if r.Score.Policy != tt.policy {
+					t.Errorf("%s scored by %q, want %q", r.MR.ID, r.Score.Policy, tt.policy)
+				}
+			}
+		})
+	}
+}
+
+func TestConvoyStateDownstream(t *testing.T) {
+	c := &ConvoyState{
+		Open: map[string]bool{"a": true, "b": true, "c": true, "d": true},
+		// b and c wait on a; d waits on c
+		BlockedBy: map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"c"}},
+	}
+	depth, fanout := c.downstream("a")
+	if depth != 2 || fanout != 3 {
+		t.Errorf("downstream(a) = depth %d fanout %d, want 2 and 3", depth, fanout)
+	}
+	if depth, fanout := c.downstream("d"); depth != 0 || fanout != 0 {
+		t.Errorf("downstream(d) = depth %d fanout %d, want 0 and 0", depth, fanout)
+	}
+}
+
+func TestScoreBreakdownString(t *testing.T) {
+	b := &ScoreBreakdown{Factors: []ScoreFactor{
+		{Name: "base", Points: 1000},
+		{Name: "retries", Points: -50, Detail: "1 retries"},
+	}}
+	if got, want := b.String(), "base +1000.0, retries -50.0 (1 retries)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
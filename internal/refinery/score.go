@@ -4,6 +4,7 @@
 package refinery
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -85,19 +86,33 @@ type ScoreInput struct {
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
+	score := 0.0
+	for _, f := range ScoreFactors(input, config) {
+		score += f.Points
+	}
+	return score
+}
+
+// ScoreFactors breaks the ScoreMR formula down into its individual terms,
+// in formula order. Factors that contribute nothing are omitted (except the
+// base score). Used by `gt mq list --explain`.
+func ScoreFactors(input ScoreInput, config ScoreConfig) []ScoreFactor {
 	now := input.Now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
-	score := config.BaseScore
+	factors := []ScoreFactor{{Name: "base", Points: config.BaseScore}}
 
 	// Convoy age factor: prevent starvation of old convoys
 	if input.ConvoyCreatedAt != nil {
-		convoyAge := now.Sub(*input.ConvoyCreatedAt)
-		convoyHours := convoyAge.Hours()
+		convoyHours := now.Sub(*input.ConvoyCreatedAt).Hours()
 		if convoyHours > 0 {
-			score += config.ConvoyAgeWeight * convoyHours
+			factors = append(factors, ScoreFactor{
+				Name:   "convoy-age",
+				Points: config.ConvoyAgeWeight * convoyHours,
+				Detail: fmt.Sprintf("convoy %.1fh old", convoyHours),
+			})
 		}
 	}
 
@@ -109,23 +124,38 @@ func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 	if priorityBonus > 4 {
 		priorityBonus = 4 // Clamp for invalid priorities < 0
 	}
-	score += config.PriorityWeight * float64(priorityBonus)
+	if priorityBonus > 0 {
+		factors = append(factors, ScoreFactor{
+			Name:   "priority",
+			Points: config.PriorityWeight * float64(priorityBonus),
+			Detail: fmt.Sprintf("P%d", input.Priority),
+		})
+	}
 
 	// Retry penalty: prevent thrashing on repeatedly failing MRs
 	retryPenalty := config.RetryPenalty * float64(input.RetryCount)
 	if retryPenalty > config.MaxRetryPenalty {
 		retryPenalty = config.MaxRetryPenalty
 	}
-	score -= retryPenalty
+	if retryPenalty > 0 {
+		factors = append(factors, ScoreFactor{
+			Name:   "retries",
+			Points: -retryPenalty,
+			Detail: fmt.Sprintf("%d retries", input.RetryCount),
+		})
+	}
 
 	// MR age factor: FIFO ordering as tiebreaker
-	mrAge := now.Sub(input.MRCreatedAt)
-	mrHours := mrAge.Hours()
+	mrHours := now.Sub(input.MRCreatedAt).Hours()
 	if mrHours > 0 {
-		score += config.MRAgeWeight * mrHours
+		factors = append(factors, ScoreFactor{
+			Name:   "mr-age",
+			Points: config.MRAgeWeight * mrHours,
+			Detail: fmt.Sprintf("queued %.1fh", mrHours),
+		})
 	}
 
-	return score
+	return factors
 }
 
 // ScoreMRWithDefaults is a convenience wrapper using default config.
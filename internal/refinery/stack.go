@@ -0,0 +1,467 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Stacked MRs.
+//
+// An MR may declare a parent MR (parent_mr) whose branch it was built on.
+// A stacked MR is never ready while it has a parent: it waits for the parent
+// to merge, is then rebased onto its target with only its own commits
+// (git rebase --onto <target> <stack_base>), and becomes ready on its own.
+// If the parent closes without merging, the child would lose code it
+// depends on, so it stays held and its owner is told to rebuild it.
+// While the parent is open, rework pushed to the parent branch is carried
+// into the child the same way, and the child's owner is told. Stacks
+// therefore merge bottom-up, one MR at a time.
+
+// Restack outcomes.
+const (
+	RestackUpToDate  = "up-to-date" // Parent tip unchanged
+	RestackRebased   = "rebased"    // Rebased onto the parent's new tip
+	RestackUnstacked = "unstacked"  // Parent merged; rebased onto the target
+	RestackHeld      = "held"       // Parent closed without merging; left for the owner
+	RestackConflict  = "conflict"   // Rebase failed; left for the owner
+	RestackError     = "error"      // Could not check or update the branch
+)
+
+// RestackResult describes what SyncStacks did with one stacked MR.
+type RestackResult struct {
+	MR      *MRInfo `json:"mr"`
+	Parent  string  `json:"parent"`
+	Outcome string  `json:"outcome"`
+	OldBase string  `json:"old_base,omitempty"`
+	NewBase string  `json:"new_base,omitempty"`
+	Detail  string  `json:"detail,omitempty"`
+}
+
+// StackNode is an open MR with the open MRs stacked on it.
+type StackNode struct {
+	MR       *MRInfo      `json:"mr"`
+	Children []*StackNode `json:"children,omitempty"`
+}
+
+// openMRFields returns every open MR bead with its parsed fields.
+func (e *Engineer) openMRFields() (map[string]*beads.Issue, map[string]*beads.MRFields, error) {
+	issues, err := e.beads.List(beads.ListOptions{
+		Status:   "open",
+		Label:    "gt:merge-request",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+	byID := make(map[string]*beads.Issue, len(issues))
+	fields := make(map[string]*beads.MRFields, len(issues))
+	for _, issue := range issues {
+		if issue.Status != "open" {
+			continue
+		}
+		f := beads.ParseMRFields(issue)
+		if f == nil {
+			continue
+		}
+		byID[issue.ID] = issue
+		fields[issue.ID] = f
+	}
+	return byID, fields, nil
+}
+
+// ListStacks returns the rig's MR stacks: trees of open MRs rooted at MRs
+// that have no open parent. MRs that are neither stacked nor stacked on are
+// left out.
+func (e *Engineer) ListStacks() ([]*StackNode, error) {
+	issues, fields, err := e.openMRFields()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[string]*StackNode, len(issues))
+	for id, issue := range issues {
+		nodes[id] = &StackNode{MR: issueToMRInfo(issue, fields[id])}
+	}
+	var roots []*StackNode
+	for _, id := range sortedKeys(nodes) {
+		parent := fields[id].ParentMR
+		if p, ok := nodes[parent]; ok && parent != id {
+			p.Children = append(p.Children, nodes[id])
+			continue
+		}
+		roots = append(roots, nodes[id])
+	}
+	var stacks []*StackNode
+	for _, root := range roots {
+		if len(root.Children) > 0 || fields[root.MR.ID].ParentMR != "" {
+			stacks = append(stacks, root)
+		}
+	}
+	return stacks, nil
+}
+
+// StackDescendants returns the open MRs stacked on mrID, directly or
+// transitively, parents before children.
+func (e *Engineer) StackDescendants(mrID string) ([]*MRInfo, error) {
+	issues, fields, err := e.openMRFields()
+	if err != nil {
+		return nil, err
+	}
+	var out []*MRInfo
+	seen := map[string]bool{mrID: true}
+	queue := []string{mrID}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, id := range sortedKeys(fields) {
+			if fields[id].ParentMR != parent || seen[id] {
+				continue
+			}
+			seen[id] = true
+			out = append(out, issueToMRInfo(issues[id], fields[id]))
+			queue = append(queue, id)
+		}
+	}
+	return out, nil
+}
+
+// SyncStacks brings every stacked MR up to date with its parent:
+//   - parent open, branch unchanged: nothing to do
+//   - parent open, branch moved (rework): rebase onto the parent's new tip
+//     and mail the child's owner
+//   - parent merged: rebase onto the target, drop the parent link so the MR
+//     becomes ready, and mail the child's owner
+//   - parent closed without merging: keep the MR held, record why in
+//     stack_held, and mail the child's owner once
+//
+// Parents are handled before their children, so a whole stack settles in
+// one call. A rebase that conflicts is aborted and left for the owner, who
+// is mailed; the MR stays held.
+func (e *Engineer) SyncStacks(ctx context.Context) ([]RestackResult, error) {
+	issues, fields, err := e.openMRFields()
+	if err != nil {
+		return nil, err
+	}
+
+	var stacked []string
+	for id, f := range fields {
+		if f.ParentMR != "" {
+			stacked = append(stacked, id)
+		}
+	}
+	if len(stacked) == 0 {
+		return nil, nil
+	}
+	if err := e.git.Fetch("origin"); err != nil {
+		return nil, fmt.Errorf("fetching origin: %w", err)
+	}
+
+	order := stackOrder(stacked, fields)
+	results := make([]RestackResult, 0, len(order))
+	for _, id := range order {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		r := e.restack(issues[id], fields[id], fields[fields[id].ParentMR])
+		results = append(results, r)
+		if r.Outcome != RestackUpToDate {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Stack: %s on %s: %s %s\n", id, r.Parent, r.Outcome, r.Detail)
+		}
+	}
+	return results, nil
+}
+
+// stackOrder sorts stacked MR IDs so every MR comes after its stacked
+// parent. Cycles, which only a hand-edited bead can create, are broken
+// arbitrarily.
+func stackOrder(ids []string, fields map[string]*beads.MRFields) []string {
+	sort.Strings(ids)
+	depth := make(map[string]int, len(ids))
+	var depthOf func(id string, seen map[string]bool) int
+	depthOf = func(id string, seen map[string]bool) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		f := fields[id]
+		if f == nil || f.ParentMR == "" || seen[id] {
+			return 0
+		}
+		seen[id] = true
+		d := depthOf(f.ParentMR, seen) + 1
+		depth[id] = d
+		return d
+	}
+	for _, id := range ids {
+		depthOf(id, map[string]bool{})
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return depth[ids[i]] < depth[ids[j]] })
+	return ids
+}
+
+// restack updates one stacked MR against its parent. parentFields is nil
+// when the parent is no longer open.
+func (e *Engineer) restack(issue *beads.Issue, f *beads.MRFields, parentFields *beads.MRFields) RestackResult {
+	mr := issueToMRInfo(issue, f)
+	result := RestackResult{MR: mr, Parent: f.ParentMR, OldBase: f.StackBase}
+
+	parentOpen := parentFields != nil
+	if !parentOpen {
+		landed, why, err := e.parentLanded(f.ParentMR, f.StackBase, mr.Target)
+		if err != nil {
+			result.Outcome = RestackError
+			result.Detail = err.Error()
+			return result
+		}
+		if !landed {
+			return e.holdStack(issue, f, result, why)
+		}
+	}
+	newBaseRef := "origin/" + mr.Target
+	if parentOpen {
+		newBaseRef = "origin/" + parentFields.Branch
+	}
+	newBase, err := e.git.Rev(newBaseRef)
+	if err != nil {
+		result.Outcome = RestackError
+		result.Detail = fmt.Sprintf("resolving %s: %v", newBaseRef, err)
+		return result
+	}
+	result.NewBase = newBase
+	if parentOpen && newBase == f.StackBase {
+		result.Outcome = RestackUpToDate
+		return result
+	}
+
+	childRef := "origin/" + mr.Branch
+	oldTip, err := e.git.Rev(childRef)
+	if err != nil {
+		result.Outcome = RestackError
+		result.Detail = fmt.Sprintf("resolving %s: %v", childRef, err)
+		return result
+	}
+	upstream := f.StackBase
+	if upstream == "" {
+		// Submitted without a recorded base: everything the child shares
+		// with its new base is treated as the parent's.
+		if upstream, err = e.git.MergeBase(childRef, newBaseRef); err != nil {
+			result.Outcome = RestackError
+			result.Detail = fmt.Sprintf("finding merge base of %s and %s: %v", mr.Branch, newBaseRef, err)
+			return result
+		}
+	}
+
+	conflicts, err := e.rebaseBranchOnto(mr.Branch, oldTip, newBase, upstream)
+	if len(conflicts) > 0 {
+		result.Outcome = RestackConflict
+		result.Detail = "conflicts in " + strings.Join(conflicts, ", ")
+		e.notifyStackOwner(mr, result)
+		return result
+	}
+	if err != nil {
+		result.Outcome = RestackError
+		result.Detail = err.Error()
+		e.notifyStackOwner(mr, result)
+		return result
+	}
+
+	if parentOpen {
+		f.StackBase = newBase
+		f.StackHeld = ""
+		result.Outcome = RestackRebased
+		result.Detail = fmt.Sprintf("onto %s@%s", parentFields.Branch, shortSHA(newBase))
+	} else {
+		f.ParentMR = ""
+		f.StackBase = ""
+		f.StackHeld = ""
+		result.Outcome = RestackUnstacked
+		result.Detail = fmt.Sprintf("onto %s@%s", mr.Target, shortSHA(newBase))
+	}
+	desc := beads.SetMRFields(issue, f)
+	if err := e.beads.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		// The branch moved but the bead still records the old base; the
+		// next sync would replay the parent's commits. Surface loudly.
+		result.Outcome = RestackError
+		result.Detail = fmt.Sprintf("branch rebased but MR bead not updated: %v", err)
+	}
+	e.notifyStackOwner(mr, result)
+	return result
+}
+
+// parentLanded reports whether a stacked MR's parent, which is no longer
+// open, made it into the target: the parent tip the child was built on is
+// already part of the target, or the parent's bead was closed as merged
+// (squash merges leave no ancestry). When it did not, why says what became
+// of it.
+func (e *Engineer) parentLanded(parentID, stackBase, target string) (landed bool, why string, err error) {
+	if stackBase != "" {
+		// Merged by hand, or with a strategy that keeps the parent's commits
+		merged, err := e.git.IsAncestor(stackBase, "origin/"+target)
+		if err != nil {
+			return false, "", fmt.Errorf("checking whether %s is in %s: %w", shortSHA(stackBase), target, err)
+		}
+		if merged {
+			return true, "", nil
+		}
+	}
+
+	parent, err := e.beads.Show(parentID)
+	if errors.Is(err, beads.ErrNotFound) {
+		return false, fmt.Sprintf("parent MR %s no longer exists", parentID), nil
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("checking parent MR %s: %w", parentID, err)
+	}
+	reason := "closed without merging"
+	if pf := beads.ParseMRFields(parent); pf != nil && pf.CloseReason != "" {
+		if pf.CloseReason == string(CloseReasonMerged) {
+			return true, "", nil
+		}
+		reason = "closed as " + pf.CloseReason + ", not merged"
+	}
+	return false, fmt.Sprintf("parent MR %s %s", parentID, reason), nil
+}
+
+// holdStack keeps a stacked MR whose parent closed without merging held,
+// and mails its owner the first time.
+func (e *Engineer) holdStack(issue *beads.Issue, f *beads.MRFields, result RestackResult, why string) RestackResult {
+	result.Outcome = RestackHeld
+	result.Detail = why
+	if f.StackHeld == why {
+		return result
+	}
+	e.notifyStackOwner(result.MR, result)
+	f.StackHeld = why
+	desc := beads.SetMRFields(issue, f)
+	if err := e.beads.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		// The owner will be mailed again on the next sync.
+		result.Outcome = RestackError
+		result.Detail = fmt.Sprintf("%s; MR bead not updated: %v", why, err)
+	}
+	return result
+}
+
+// rebaseBranchOnto replays the commits of the remote branch after upstream
+// onto newBase in a scratch worktree and force-pushes the result, provided
+// nobody pushed to the branch since oldTip. Returns the conflicting files if
+// the rebase conflicted; the branch is left untouched in that case.
+func (e *Engineer) rebaseBranchOnto(branch, oldTip, newBase, upstream string) ([]string, error) {
+	dir, err := os.MkdirTemp("", "gt-restack-")
+	if err != nil {
+		return nil, fmt.Errorf("creating worktree dir: %w", err)
+	}
+	_ = os.Remove(dir) // git worktree add wants to create it
+	if err := e.git.WorktreeAddDetached(dir, oldTip); err != nil {
+		return nil, fmt.Errorf("checking out %s: %w", branch, err)
+	}
+	defer func() {
+		if err := e.git.WorktreeRemove(dir, true); err != nil {
+			_ = os.RemoveAll(dir)
+			_ = e.git.WorktreePrune()
+		}
+	}()
+
+	wg := git.NewGit(dir)
+	if err := wg.RebaseOnto(newBase, upstream); err != nil {
+		files, _ := wg.GetConflictingFiles()
+		_ = wg.AbortRebase()
+		if len(files) > 0 {
+			return files, nil
+		}
+		return nil, fmt.Errorf("rebasing onto %s: %w", shortSHA(newBase), err)
+	}
+	if err := wg.PushForceWithLease("origin", "HEAD", branch, oldTip); err != nil {
+		return nil, fmt.Errorf("pushing %s: %w", branch, err)
+	}
+	return nil, nil
+}
+
+// notifyStackOwner mails the owner of a stacked MR after its branch was
+// rebased, or could not be.
+func (e *Engineer) notifyStackOwner(mr *MRInfo, r RestackResult) {
+	if mr.Worker == "" {
+		return
+	}
+	var subject, body string
+	switch r.Outcome {
+	case RestackRebased:
+		subject = fmt.Sprintf("STACK_REBASED %s", mr.Branch)
+		body = fmt.Sprintf("Parent MR %s was reworked. Your branch %s was rebased onto its new tip (%s).\n\n"+
+			"Fetch before pushing again: git fetch origin && git reset --hard origin/%s",
+			r.Parent, mr.Branch, shortSHA(r.NewBase), mr.Branch)
+	case RestackUnstacked:
+		subject = fmt.Sprintf("STACK_REBASED %s", mr.Branch)
+		body = fmt.Sprintf("Parent MR %s merged. Your branch %s was rebased onto %s with only its own commits "+
+			"and is now queued on its own.\n\nFetch before pushing again: git fetch origin && git reset --hard origin/%s",
+			r.Parent, mr.Branch, mr.Target, mr.Branch)
+	case RestackHeld:
+		subject = fmt.Sprintf("STACK_HELD %s", mr.Branch)
+		body = fmt.Sprintf("Your branch %s is stacked on MR %s, which will not land (%s). "+
+			"MR %s stays held: merging it alone would drop the code it depends on.\n\n"+
+			"Either carry the parent's changes into your branch and clear parent_mr and stack_base on %s, "+
+			"or stack it on a replacement MR by setting parent_mr to that MR.",
+			mr.Branch, r.Parent, r.Detail, mr.ID, mr.ID)
+	default:
+		subject = fmt.Sprintf("STACK_CONFLICT %s", mr.Branch)
+		onto := "the parent's new tip"
+		if r.NewBase != "" {
+			onto = shortSHA(r.NewBase)
+		}
+		body = fmt.Sprintf("Parent MR %s changed, but your branch %s could not be rebased onto %s:\n\n  %s\n\n"+
+			"Rebase it yourself (git rebase --onto %s %s), force-push, and update stack_base on %s. "+
+			"The MR stays held until then.",
+			r.Parent, mr.Branch, onto, r.Detail, onto, shortSHA(r.OldBase), mr.ID)
+	}
+	msg := mail.NewMessage(e.rig.Name+"/refinery", fmt.Sprintf("%s/%s", e.rig.Name, mr.Worker), subject, body)
+	if err := e.router.Send(msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to mail %s about stack change: %v\n", mr.Worker, err)
+	}
+}
+
+// notifyStackRework mails the owners of every MR stacked on mr that mr
+// failed to merge and will be reworked, so they expect their branches to
+// move.
+func (e *Engineer) notifyStackRework(mr *MRInfo, reason string) {
+	if mr.ID == "" {
+		return
+	}
+	descendants, err := e.StackDescendants(mr.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list MRs stacked on %s: %v\n", mr.ID, err)
+		return
+	}
+	for _, child := range descendants {
+		if child.Worker == "" {
+			continue
+		}
+		msg := mail.NewMessage(
+			e.rig.Name+"/refinery",
+			fmt.Sprintf("%s/%s", e.rig.Name, child.Worker),
+			fmt.Sprintf("STACK_REWORK %s", child.Branch),
+			fmt.Sprintf("MR %s (%s), which your MR %s is stacked on, failed to merge and goes back for rework:\n\n  %s\n\n"+
+				"Your MR stays held. When the parent branch changes, the refinery rebases %s onto it and tells you.",
+				mr.ID, mr.Branch, child.ID, reason, child.Branch),
+		)
+		if err := e.router.Send(msg); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to mail %s about rework of %s: %v\n", child.Worker, mr.ID, err)
+		}
+	}
+	if len(descendants) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Notified %d stacked MR owner(s) of rework on %s\n", len(descendants), mr.ID)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,206 @@
+package refinery
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestStackOrder(t *testing.T) {
+	fields := map[string]*beads.MRFields{
+		"mr-c": {ParentMR: "mr-b"},
+		"mr-b": {ParentMR: "mr-a"},
+		"mr-a": {},
+		"mr-x": {ParentMR: "mr-a"},
+		"mr-y": {ParentMR: "mr-gone"}, // parent already closed
+	}
+	got := stackOrder([]string{"mr-c", "mr-x", "mr-b", "mr-y"}, fields)
+	pos := make(map[string]int, len(got))
+	for i, id := range got {
+		pos[id] = i
+	}
+	if pos["mr-b"] > pos["mr-c"] {
+		t.Errorf("parent mr-b must come before child mr-c: %v", got)
+	}
+	if len(got) != 4 {
+		t.Errorf("stackOrder dropped MRs: %v", got)
+	}
+
+	// A hand-edited cycle must not hang
+	cyclic := map[string]*beads.MRFields{"a": {ParentMR: "b"}, "b": {ParentMR: "a"}}
+	if got := stackOrder([]string{"a", "b"}, cyclic); len(got) != 2 {
+		t.Errorf("cycle: got %v", got)
+	}
+}
+
+// stackRepo is a clone of a bare origin for exercising restacks.
+type stackRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newStackRepo(t *testing.T) *stackRepo {
+	t.Helper()
+	origin := t.TempDir()
+	clone := filepath.Join(t.TempDir(), "clone")
+	r := &stackRepo{t: t, dir: clone}
+	r.git(filepath.Dir(clone), "init", "-q", "--bare", "-b", "main", origin)
+	r.git(filepath.Dir(clone), "clone", "-q", origin, clone)
+	r.git(clone, "config", "user.email", "test@example.com")
+	r.git(clone, "config", "user.name", "Test")
+	r.git(clone, "checkout", "-q", "-b", "main")
+	r.commit("a.txt", "base\n")
+	r.git(clone, "push", "-q", "origin", "main")
+	return r
+}
+
+func (r *stackRepo) git(dir string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (r *stackRepo) commit(file, content string) {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.dir, file), []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+	r.git(r.dir, "add", file)
+	r.git(r.dir, "commit", "-q", "-m", "edit "+file)
+}
+
+func (r *stackRepo) rev(ref string) string {
+	r.t.Helper()
+	return r.git(r.dir, "rev-parse", ref)
+}
+
+func (r *stackRepo) engineer() *Engineer {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: r.t.TempDir()})
+	e.workDir = r.dir
+	e.git = git.NewGit(r.dir)
+	e.output = io.Discard
+	return e
+}
+
+func TestRebaseBranchOnto_FollowsParentRework(t *testing.T) {
+	r := newStackRepo(t)
+	r.git(r.dir, "checkout", "-q", "-b", "parent")
+	r.commit("parent.txt", "v1\n")
+	r.git(r.dir, "push", "-q", "origin", "parent")
+	base := r.rev("HEAD")
+
+	r.git(r.dir, "checkout", "-q", "-b", "child")
+	r.commit("child.txt", "child\n")
+	r.git(r.dir, "push", "-q", "origin", "child")
+
+	// Rework the parent: rewrite its only commit
+	r.git(r.dir, "checkout", "-q", "parent")
+	if err := os.WriteFile(filepath.Join(r.dir, "parent.txt"), []byte("v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r.git(r.dir, "commit", "-q", "-a", "--amend", "-m", "parent v2")
+	r.git(r.dir, "push", "-q", "-f", "origin", "parent")
+	newParent := r.rev("origin/parent")
+
+	e := r.engineer()
+	conflicts, err := e.rebaseBranchOnto("child", r.rev("origin/child"), newParent, base)
+	if err != nil || len(conflicts) > 0 {
+		t.Fatalf("rebaseBranchOnto: conflicts=%v err=%v", conflicts, err)
+	}
+	r.git(r.dir, "fetch", "-q", "origin")
+	if got := r.rev("origin/child^"); got != newParent {
+		t.Errorf("child should sit directly on the reworked parent: parent of child = %s, want %s", got, newParent)
+	}
+	if got := r.git(r.dir, "show", "origin/child:parent.txt"); got != "v2" {
+		t.Errorf("child should carry the reworked parent.txt, got %q", got)
+	}
+
+	// Parent merged (or closed): move the child onto main with only its own commit
+	conflicts, err = e.rebaseBranchOnto("child", r.rev("origin/child"), r.rev("origin/main"), newParent)
+	if err != nil || len(conflicts) > 0 {
+		t.Fatalf("unstack: conflicts=%v err=%v", conflicts, err)
+	}
+	r.git(r.dir, "fetch", "-q", "origin")
+	if got := r.rev("origin/child^"); got != r.rev("origin/main") {
+		t.Errorf("unstacked child should sit on main")
+	}
+	if out := r.git(r.dir, "ls-tree", "--name-only", "origin/child"); strings.Contains(out, "parent.txt") {
+		t.Errorf("unstacked child must not carry the parent's commits, tree: %s", out)
+	}
+}
+
+func TestRebaseBranchOnto_RefusesToClobberNewPushes(t *testing.T) {
+	r := newStackRepo(t)
+	r.git(r.dir, "checkout", "-q", "-b", "child")
+	r.commit("child.txt", "child\n")
+	r.git(r.dir, "push", "-q", "origin", "child")
+	staleTip := r.rev("HEAD~1") // not what origin/child points at
+
+	_, err := r.engineer().rebaseBranchOnto("child", staleTip, r.rev("origin/main"), r.rev("origin/main"))
+	if err == nil {
+		t.Fatal("push with a stale lease should fail")
+	}
+}
+
+func TestRebaseBranchOnto_ReportsConflicts(t *testing.T) {
+	r := newStackRepo(t)
+	r.git(r.dir, "checkout", "-q", "-b", "parent")
+	r.commit("parent.txt", "v1\n")
+	r.git(r.dir, "push", "-q", "origin", "parent")
+	base := r.rev("HEAD")
+
+	r.git(r.dir, "checkout", "-q", "-b", "child")
+	r.commit("a.txt", "child edit\n")
+	r.git(r.dir, "push", "-q", "origin", "child")
+	childTip := r.rev("HEAD")
+
+	r.git(r.dir, "checkout", "-q", "parent")
+	r.commit("a.txt", "parent edit\n")
+	r.git(r.dir, "push", "-q", "origin", "parent")
+
+	conflicts, err := r.engineer().rebaseBranchOnto("child", childTip, r.rev("origin/parent"), base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "a.txt" {
+		t.Errorf("conflicts = %v, want [a.txt]", conflicts)
+	}
+	r.git(r.dir, "fetch", "-q", "origin")
+	if got := r.rev("origin/child"); got != childTip {
+		t.Error("a conflicting restack must leave the branch untouched")
+	}
+}
+
+func TestParentLanded(t *testing.T) {
+	r := newStackRepo(t)
+	r.git(r.dir, "checkout", "-q", "-b", "parent")
+	r.commit("parent.txt", "v1\n")
+	r.git(r.dir, "push", "-q", "origin", "parent")
+	base := r.rev("HEAD")
+	e := r.engineer()
+
+	// Closed but never merged: the child must not be unstacked, whether the
+	// parent's bead says why or can't be read at all.
+	if landed, _, _ := e.parentLanded("mr-parent", base, "main"); landed {
+		t.Error("a parent whose commits are not on main must not count as landed")
+	}
+
+	r.git(r.dir, "push", "-q", "origin", "parent:main")
+	r.git(r.dir, "fetch", "-q", "origin")
+	landed, _, err := e.parentLanded("mr-parent", base, "main")
+	if err != nil || !landed {
+		t.Errorf("parent merged into main: landed=%v err=%v", landed, err)
+	}
+}
@@ -0,0 +1,639 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Post-merge verification.
+//
+// Gates run on each MR's merged tree, but two MRs that pass on their own can
+// still break the default branch together. When merge_queue.verify_main is
+// set, VerifyMain runs it on the branch tip after merges land. The last tip
+// that passed is remembered; when the tip fails, the commits since then (at
+// most bisect_depth) are bisected to find the first failing one. With
+// auto_revert, the culprit gets a revert MR at the head of the queue and its
+// bead goes back to its author with the failure log. Either way the broken
+// branch is escalated, once per culprit.
+
+// FileMainVerifyJSON is the post-merge verification state in the rig's
+// runtime directory.
+const FileMainVerifyJSON = "main-verify.json"
+
+// DefaultBisectDepth is how many recent commits are searched for a culprit.
+const DefaultBisectDepth = 20
+
+// Verification outcomes.
+const (
+	VerifyGreen         = "green"          // Tip passed
+	VerifyUnchanged     = "unchanged"      // Tip already checked
+	VerifyRed           = "red"            // Tip failed; culprit found and handled
+	VerifyRevertPending = "revert-pending" // Tip failed; culprit already handled
+	VerifyUnattributed  = "unattributed"   // Tip failed; no culprit in the window
+)
+
+// MainVerifyState is what VerifyMain remembers between runs.
+type MainVerifyState struct {
+	Branch      string    `json:"branch"`
+	LastGreen   string    `json:"last_green,omitempty"`
+	LastChecked string    `json:"last_checked,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+
+	// Culprits are the commits found to break the branch since it was last
+	// green, keyed by SHA. Cleared when the branch passes again.
+	Culprits map[string]*Culprit `json:"culprits,omitempty"`
+
+	// Escalated records that an unattributed failure was escalated, so it
+	// is only reported once until the branch passes again.
+	Escalated bool `json:"escalated,omitempty"`
+}
+
+// Culprit is a commit that broke the default branch, and what was done
+// about it.
+type Culprit struct {
+	Commit      string    `json:"commit"`
+	Subject     string    `json:"subject,omitempty"`
+	MR          string    `json:"mr,omitempty"`
+	SourceIssue string    `json:"source_issue,omitempty"`
+	Worker      string    `json:"worker,omitempty"`
+	RevertMR    string    `json:"revert_mr,omitempty"`
+	Escalation  string    `json:"escalation,omitempty"`
+	Reslung     bool      `json:"reslung,omitempty"`
+	FoundAt     time.Time `json:"found_at"`
+}
+
+// BisectStep is one run of the verification check.
+type BisectStep struct {
+	Commit string        `json:"commit"`
+	Passed bool          `json:"passed"`
+	Error  string        `json:"error,omitempty"`
+	Took   time.Duration `json:"took"`
+}
+
+// MainVerifyResult describes one VerifyMain run.
+type MainVerifyResult struct {
+	Branch   string       `json:"branch"`
+	Head     string       `json:"head"`
+	Outcome  string       `json:"outcome"`
+	Detail   string       `json:"detail,omitempty"`
+	Steps    []BisectStep `json:"steps,omitempty"`
+	Culprit  *Culprit     `json:"culprit,omitempty"`
+	Log      string       `json:"log,omitempty"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+// MainVerifyPath returns the verification state path for a rig.
+func MainVerifyPath(rigPath string) string {
+	return filepath.Join(constants.RigRuntimePath(rigPath), FileMainVerifyJSON)
+}
+
+// LoadMainVerifyState reads the rig's verification state. Returns an empty
+// state if none has been recorded yet.
+func LoadMainVerifyState(rigPath string) (*MainVerifyState, error) {
+	data, err := os.ReadFile(MainVerifyPath(rigPath))
+	if os.IsNotExist(err) {
+		return &MainVerifyState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading verification state: %w", err)
+	}
+	var s MainVerifyState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing verification state: %w", err)
+	}
+	return &s, nil
+}
+
+// SaveMainVerifyState writes the rig's verification state.
+func SaveMainVerifyState(rigPath string, s *MainVerifyState) error {
+	return util.EnsureDirAndWriteJSON(MainVerifyPath(rigPath), s)
+}
+
+// VerifyMain runs the verify_main check on the tip of the rig's default
+// branch and, if it fails, finds and handles the commit that broke it. With
+// dryRun the culprit is found but nothing is reverted, escalated or slung.
+func (e *Engineer) VerifyMain(ctx context.Context, dryRun bool) (*MainVerifyResult, error) {
+	check := e.config.VerifyMain
+	if check == nil {
+		return nil, fmt.Errorf("merge_queue.verify_main is not configured for %s", e.rig.Name)
+	}
+	branch := e.rig.DefaultBranch()
+	if err := e.git.Fetch("origin"); err != nil {
+		return nil, fmt.Errorf("fetching origin: %w", err)
+	}
+	head, err := e.git.Rev("origin/" + branch)
+	if err != nil {
+		return nil, fmt.Errorf("resolving origin/%s: %w", branch, err)
+	}
+	state, err := LoadMainVerifyState(e.rig.Path)
+	if err != nil {
+		return nil, err
+	}
+	if state.Branch != branch {
+		state = &MainVerifyState{Branch: branch}
+	}
+
+	result := &MainVerifyResult{Branch: branch, Head: head}
+	if head == state.LastChecked {
+		result.Outcome = VerifyUnchanged
+		if head == state.LastGreen {
+			result.Detail = "passed"
+		} else {
+			result.Detail = "failed"
+		}
+		return result, nil
+	}
+
+	v, err := e.newVerifier(check)
+	if err != nil {
+		return nil, err
+	}
+	defer v.close()
+
+	tip := v.run(ctx, head, result)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	state.LastChecked, state.CheckedAt = head, time.Now().UTC()
+	if tip.Success {
+		state.LastGreen = head
+		state.Culprits = nil
+		state.Escalated = false
+		result.Outcome = VerifyGreen
+		return result, e.saveVerifyState(state, dryRun)
+	}
+	result.Log = verifyLog(tip)
+
+	idx, commits, detail, err := e.bisectMain(ctx, v, state.LastGreen, head, result)
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		result.Outcome = VerifyUnattributed
+		result.Detail = detail
+		if !dryRun {
+			e.escalateUnattributed(state, result)
+		}
+		return result, e.saveVerifyState(state, dryRun)
+	}
+
+	sha := commits[idx]
+	if c, ok := state.Culprits[sha]; ok {
+		result.Culprit = c
+		if c.RevertMR == "" || e.revertPending(c) {
+			result.Outcome = VerifyRevertPending
+			result.Detail = fmt.Sprintf("%s already handled", shortSHA(sha))
+			if c.RevertMR != "" {
+				result.Detail += fmt.Sprintf(" (revert %s)", c.RevertMR)
+			}
+			return result, e.saveVerifyState(state, dryRun)
+		}
+		// The revert landed and the branch still fails: something else
+		// broke it too, hidden behind the first culprit.
+		result.Outcome = VerifyUnattributed
+		result.Detail = fmt.Sprintf("still failing after reverting %s in %s", shortSHA(sha), c.RevertMR)
+		if !dryRun {
+			e.escalateUnattributed(state, result)
+		}
+		return result, e.saveVerifyState(state, dryRun)
+	}
+
+	c := e.identifyCulprit(sha)
+	result.Culprit = c
+	result.Outcome = VerifyRed
+	result.Detail = fmt.Sprintf("first failing commit %s %s", shortSHA(sha), c.Subject)
+	if culpritStep := findStep(result.Steps, sha); culpritStep != nil && culpritStep.Error != "" {
+		result.Log = culpritStep.Error
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	e.handleCulprit(ctx, c, result)
+	if state.Culprits == nil {
+		state.Culprits = make(map[string]*Culprit)
+	}
+	state.Culprits[sha] = c
+	return result, e.saveVerifyState(state, false)
+}
+
+// escalateUnattributed escalates a failure with no culprit to act on, once
+// per red streak.
+func (e *Engineer) escalateUnattributed(state *MainVerifyState, result *MainVerifyResult) {
+	if state.Escalated {
+		return
+	}
+	id, err := e.escalateBrokenMain(result, nil)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Escalated broken %s: %s\n", result.Branch, id)
+	state.Escalated = true
+}
+
+func (e *Engineer) saveVerifyState(s *MainVerifyState, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	if err := SaveMainVerifyState(e.rig.Path, s); err != nil {
+		return fmt.Errorf("saving verification state: %w", err)
+	}
+	return nil
+}
+
+// revertPending reports whether c's revert MR is still open (or can't be
+// checked).
+func (e *Engineer) revertPending(c *Culprit) bool {
+	issue, err := e.beads.Show(c.RevertMR)
+	if err != nil {
+		return true
+	}
+	return issue.Status != "closed"
+}
+
+// bisectMain finds the first failing commit between lastGreen and head
+// (which failed). It returns the culprit's index in commits, or -1 with a
+// reason when the failure predates the searched window.
+func (e *Engineer) bisectMain(ctx context.Context, v *verifier, lastGreen, head string, result *MainVerifyResult) (int, []string, string, error) {
+	depth := e.config.BisectDepth
+	if depth < 1 {
+		depth = DefaultBisectDepth
+	}
+	base := lastGreen
+	if base != "" {
+		if ok, err := e.git.IsAncestor(base, head); err != nil || !ok {
+			base = "" // Branch was rewritten; the old green tip tells us nothing
+		}
+	}
+	commits, err := e.git.FirstParentCommits(base, head, depth)
+	if err != nil {
+		return -1, nil, "", fmt.Errorf("listing commits on %s: %w", result.Branch, err)
+	}
+	if len(commits) == 0 {
+		return -1, nil, "no commits to bisect", nil
+	}
+
+	// The commit before the window is only known to pass if it is the
+	// last green tip; otherwise check it first. A window reaching back to
+	// the root commit has nothing before it.
+	if before, err := e.git.Rev(commits[0] + "^"); err == nil && before != lastGreen {
+		r := v.run(ctx, before, result)
+		if ctx.Err() != nil {
+			return -1, nil, "", ctx.Err()
+		}
+		if !r.Success {
+			return -1, nil, fmt.Sprintf("already failing at %s, before the last %d commit(s)", shortSHA(before), len(commits)), nil
+		}
+	}
+
+	idx := bisectCulprit(commits, func(sha string) bool {
+		if sha == result.Head {
+			return false
+		}
+		return v.run(ctx, sha, result).Success
+	})
+	if ctx.Err() != nil {
+		return -1, nil, "", ctx.Err()
+	}
+	return idx, commits, "", nil
+}
+
+// bisectCulprit returns the index of the first commit for which pass is
+// false, given that the commit before commits[0] passes and the last one
+// fails.
+func bisectCulprit(commits []string, pass func(string) bool) int {
+	lo, hi := 0, len(commits)-1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if pass(commits[mid]) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// verifier runs the verify_main check on arbitrary commits in a scratch
+// worktree, so the refinery's own worktree is left alone.
+type verifier struct {
+	e     *Engineer
+	check *GateConfig
+	dir   string
+	g     *git.Git
+	seen  map[string]GateResult
+}
+
+func (e *Engineer) newVerifier(check *GateConfig) (*verifier, error) {
+	dir, err := os.MkdirTemp("", "gt-verify-main-")
+	if err != nil {
+		return nil, fmt.Errorf("creating worktree dir: %w", err)
+	}
+	_ = os.Remove(dir) // git worktree add wants to create it
+	if err := e.git.WorktreeAddDetached(dir, "HEAD"); err != nil {
+		return nil, fmt.Errorf("creating verification worktree: %w", err)
+	}
+	return &verifier{e: e, check: check, dir: dir, g: git.NewGit(dir), seen: make(map[string]GateResult)}, nil
+}
+
+func (v *verifier) close() {
+	if err := v.e.git.WorktreeRemove(v.dir, true); err != nil {
+		_ = os.RemoveAll(v.dir)
+		_ = v.e.git.WorktreePrune()
+	}
+}
+
+// run checks out sha and runs the check there, recording a step in result.
+func (v *verifier) run(ctx context.Context, sha string, result *MainVerifyResult) GateResult {
+	if r, ok := v.seen[sha]; ok {
+		return r
+	}
+	e := v.e
+	var r GateResult
+	if err := v.g.ResetHard(sha); err != nil {
+		r = GateResult{Name: "verify_main", Error: fmt.Sprintf("checking out %s: %v", shortSHA(sha), err)}
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Verifying %s at %s...\n", result.Branch, shortSHA(sha))
+		r = e.runGateIn(ctx, v.dir, "verify_main", v.check)
+	}
+	if ctx.Err() != nil {
+		return r
+	}
+	step := BisectStep{Commit: sha, Passed: r.Success, Took: r.Elapsed}
+	if !r.Success {
+		step.Error = verifyLog(r)
+		_, _ = fmt.Fprintf(e.output, "[Engineer]   %s: FAILED\n", shortSHA(sha))
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer]   %s: passed\n", shortSHA(sha))
+	}
+	result.Steps = append(result.Steps, step)
+	v.seen[sha] = r
+	return r
+}
+
+func findStep(steps []BisectStep, sha string) *BisectStep {
+	for i := range steps {
+		if steps[i].Commit == sha {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// verifyLog summarizes a failed check for escalations and re-slings.
+func verifyLog(r GateResult) string {
+	log := r.Error
+	if len(r.FailingTests) > 0 {
+		log += "\n\nFailing tests:\n  " + strings.Join(r.FailingTests, "\n  ")
+	}
+	return log
+}
+
+// issueRefPattern matches the "(gt-abc)" bead reference polecats end their
+// commit subjects with.
+var issueRefPattern = regexp.MustCompile(`\(([a-z0-9]+-[a-z0-9.]+)\)\s*$`)
+
+// identifyCulprit looks up the MR that landed sha: a closed MR recording it
+// as its merge commit, or else the most recent closed MR for the bead named
+// in the commit subject.
+func (e *Engineer) identifyCulprit(sha string) *Culprit {
+	c := &Culprit{Commit: sha, FoundAt: time.Now().UTC()}
+	if msg, err := e.git.GetBranchCommitMessage(sha); err == nil {
+		c.Subject = strings.SplitN(strings.TrimSpace(msg), "\n", 2)[0]
+	}
+	issueRef := ""
+	if m := issueRefPattern.FindStringSubmatch(c.Subject); m != nil {
+		issueRef = m[1]
+	}
+
+	mrs, err := e.beads.List(beads.ListOptions{
+		Status:   "closed",
+		Label:    "gt:merge-request",
+		Priority: -1,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list merged MRs: %v\n", err)
+	}
+	var byIssue *beads.Issue
+	for _, issue := range mrs {
+		f := beads.ParseMRFields(issue)
+		if f == nil {
+			continue
+		}
+		if f.MergeCommit != "" && (strings.HasPrefix(sha, f.MergeCommit) || strings.HasPrefix(f.MergeCommit, sha)) {
+			c.MR, c.SourceIssue, c.Worker = issue.ID, f.SourceIssue, f.Worker
+			return c
+		}
+		if issueRef != "" && f.SourceIssue == issueRef && (byIssue == nil || issue.UpdatedAt > byIssue.UpdatedAt) {
+			byIssue = issue
+		}
+	}
+	if byIssue != nil {
+		f := beads.ParseMRFields(byIssue)
+		c.MR, c.SourceIssue, c.Worker = byIssue.ID, f.SourceIssue, f.Worker
+	} else {
+		c.SourceIssue = issueRef
+	}
+	return c
+}
+
+// handleCulprit reverts, escalates and re-slings a newly found culprit,
+// recording what was done on c. Failures are collected as warnings so one
+// failed step doesn't stop the others.
+func (e *Engineer) handleCulprit(ctx context.Context, c *Culprit, result *MainVerifyResult) {
+	warn := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		result.Warnings = append(result.Warnings, msg)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %s\n", msg)
+	}
+
+	if e.config.AutoRevert {
+		if id, err := e.openRevertMR(ctx, c, result.Branch); err != nil {
+			warn("could not open revert MR for %s: %v", shortSHA(c.Commit), err)
+		} else {
+			c.RevertMR = id
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Opened revert MR %s for %s\n", id, shortSHA(c.Commit))
+		}
+	}
+
+	if id, err := e.escalateBrokenMain(result, c); err != nil {
+		warn("could not escalate broken %s: %v", result.Branch, err)
+	} else {
+		c.Escalation = id
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Escalated broken %s: %s\n", result.Branch, id)
+	}
+
+	if e.config.AutoRevert && c.RevertMR != "" && c.SourceIssue != "" {
+		if err := e.reslingCulprit(c, result); err != nil {
+			warn("could not re-sling %s: %v", c.SourceIssue, err)
+		} else {
+			c.Reslung = true
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Re-slung %s to %s\n", c.SourceIssue, e.reslingTarget(c))
+		}
+	}
+}
+
+// openRevertMR pushes a branch reverting the culprit on top of the branch
+// tip and submits it as a top-priority MR. An open revert MR for the same
+// branch is reused.
+func (e *Engineer) openRevertMR(_ context.Context, c *Culprit, target string) (string, error) {
+	revertBranch := "revert/" + shortSHA(c.Commit)
+	if existing, err := e.beads.FindMRForBranch(revertBranch); err == nil && existing != nil {
+		return existing.ID, nil
+	}
+
+	dir, err := os.MkdirTemp("", "gt-revert-")
+	if err != nil {
+		return "", fmt.Errorf("creating worktree dir: %w", err)
+	}
+	_ = os.Remove(dir) // git worktree add wants to create it
+	if err := e.git.WorktreeAddDetached(dir, "origin/"+target); err != nil {
+		return "", fmt.Errorf("checking out %s: %w", target, err)
+	}
+	defer func() {
+		if err := e.git.WorktreeRemove(dir, true); err != nil {
+			_ = os.RemoveAll(dir)
+			_ = e.git.WorktreePrune()
+		}
+	}()
+	wg := git.NewGit(dir)
+	if err := wg.Revert(c.Commit); err != nil {
+		_ = exec.Command("git", "-C", dir, "revert", "--abort").Run() //nolint:gosec // G204: fixed arguments
+		return "", fmt.Errorf("reverting %s: %w", shortSHA(c.Commit), err)
+	}
+	// The refinery merges from local branches, so keep one alongside the push
+	if err := wg.ResetBranch(revertBranch, "HEAD"); err != nil {
+		return "", fmt.Errorf("creating %s: %w", revertBranch, err)
+	}
+	if err := wg.Push("origin", revertBranch, true); err != nil {
+		return "", fmt.Errorf("pushing %s: %w", revertBranch, err)
+	}
+
+	fields := &beads.MRFields{
+		Branch:  revertBranch,
+		Target:  target,
+		Rig:     e.rig.Name,
+		Reverts: c.Commit,
+	}
+	what := shortSHA(c.Commit)
+	if c.MR != "" {
+		what = fmt.Sprintf("%s (%s)", what, c.MR)
+	}
+	desc := beads.FormatMRFields(fields) + "\n\n" +
+		fmt.Sprintf("Reverts %s %s, which broke %s after merging.", shortSHA(c.Commit), c.Subject, target)
+	issue, err := e.beads.Create(beads.CreateOptions{
+		Title:       "Revert: " + what,
+		Type:        "merge-request",
+		Priority:    0,
+		Description: desc,
+		Actor:       e.rig.Name + "/refinery",
+		Ephemeral:   true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating revert MR bead: %w", err)
+	}
+	return issue.ID, nil
+}
+
+// escalateBrokenMain files an escalation for a failing default branch. c is
+// nil when no culprit could be identified.
+func (e *Engineer) escalateBrokenMain(result *MainVerifyResult, c *Culprit) (string, error) {
+	title := fmt.Sprintf("%s: %s is broken after merges", e.rig.Name, result.Branch)
+	var reason strings.Builder
+	fmt.Fprintf(&reason, "verify_main fails at %s@%s.\n", result.Branch, shortSHA(result.Head))
+	related := ""
+	if c != nil {
+		title = fmt.Sprintf("%s: %s broken by %s", e.rig.Name, result.Branch, shortSHA(c.Commit))
+		fmt.Fprintf(&reason, "First failing commit: %s %s\n", shortSHA(c.Commit), c.Subject)
+		if c.MR != "" {
+			fmt.Fprintf(&reason, "Merged in: %s (worker %s, issue %s)\n", c.MR, c.Worker, c.SourceIssue)
+			related = c.MR
+		}
+		if c.RevertMR != "" {
+			fmt.Fprintf(&reason, "Revert MR: %s (queued at P0)\n", c.RevertMR)
+			related = c.RevertMR
+		} else if e.config.AutoRevert {
+			reason.WriteString("Revert MR: could not be opened; revert manually.\n")
+		}
+	} else {
+		fmt.Fprintf(&reason, "No culprit found: %s\n", result.Detail)
+	}
+	fmt.Fprintf(&reason, "\n%s", truncateLog(result.Log))
+
+	args := []string{"escalate", title, "--severity", "high", "--source", "refinery:" + e.rig.Name, "--stdin", "--json"}
+	if related != "" {
+		args = append(args, "--related", related)
+	}
+	cmd := exec.Command("gt", args...) //nolint:gosec // G204: arguments are built from refinery state
+	cmd.Dir = filepath.Dir(e.rig.Path)
+	cmd.Stdin = strings.NewReader(reason.String())
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("gt escalate: %v (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil || out.ID == "" {
+		return "", fmt.Errorf("parsing gt escalate output: %q", strings.TrimSpace(stdout.String()))
+	}
+	return out.ID, nil
+}
+
+// reslingTarget is where a culprit's bead goes back to: its author if
+// known, otherwise any polecat in the rig.
+func (e *Engineer) reslingTarget(c *Culprit) string {
+	if c.Worker != "" {
+		return e.rig.Name + "/" + c.Worker
+	}
+	return e.rig.Name
+}
+
+// reslingCulprit reopens the culprit's source bead and slings it back to its
+// author with the failure log.
+func (e *Engineer) reslingCulprit(c *Culprit, result *MainVerifyResult) error {
+	open := "open"
+	if err := e.beads.Update(c.SourceIssue, beads.UpdateOptions{Status: &open}); err != nil {
+		return fmt.Errorf("reopening %s: %w", c.SourceIssue, err)
+	}
+	msg := fmt.Sprintf("Your change %s %s (MR %s) passed its gates but broke %s after merging, together with "+
+		"later merges. It is being reverted in %s. Rebase onto %s once the revert lands, fix the interaction, "+
+		"and resubmit.\n\nverify_main failure at %s:\n%s",
+		shortSHA(c.Commit), c.Subject, c.MR, result.Branch, c.RevertMR, result.Branch, shortSHA(c.Commit), truncateLog(result.Log))
+
+	cmd := exec.Command("gt", "sling", c.SourceIssue, e.reslingTarget(c), "--create", "--no-convoy", "--stdin") //nolint:gosec // G204: arguments are built from refinery state
+	cmd.Dir = filepath.Dir(e.rig.Path)
+	cmd.Stdin = strings.NewReader(msg)
+	var stderr bytes.Buffer
+	cmd.Stdout = e.output
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gt sling: %v (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// truncateLog caps a failure log for mail and bead bodies.
+func truncateLog(log string) string {
+	const max = 4000
+	if len(log) > max {
+		return util.TruncateUTF8(log, max) + "\n... (truncated)"
+	}
+	return log
+}
@@ -0,0 +1,179 @@
+package refinery
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBisectCulprit(t *testing.T) {
+	commits := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for culprit := range commits {
+		var tested []string
+		got := bisectCulprit(commits, func(sha string) bool {
+			tested = append(tested, sha)
+			return sha < commits[culprit]
+		})
+		if got != culprit {
+			t.Errorf("culprit %s: bisectCulprit = %d", commits[culprit], got)
+		}
+		if len(tested) > 3 {
+			t.Errorf("culprit %s: %d checks for 7 commits, want at most 3", commits[culprit], len(tested))
+		}
+	}
+	if got := bisectCulprit([]string{"only"}, func(string) bool { t.Fatal("single commit needs no checks"); return false }); got != 0 {
+		t.Errorf("single commit: bisectCulprit = %d", got)
+	}
+}
+
+func TestIssueRefPattern(t *testing.T) {
+	tests := map[string]string{
+		"feat: add widgets (gt-abc12)":   "gt-abc12",
+		"fix: retry on 503 (hq-x7.2)":    "hq-x7.2",
+		"Revert \"feat: add widgets\"":   "",
+		"chore: tidy (see #12) trailing": "",
+	}
+	for subject, want := range tests {
+		got := ""
+		if m := issueRefPattern.FindStringSubmatch(subject); m != nil {
+			got = m[1]
+		}
+		if got != want {
+			t.Errorf("%q: got %q, want %q", subject, got, want)
+		}
+	}
+}
+
+// verifyRepo builds main as: base, good1, good2, bad (adds "broken"),
+// after1, after2, and returns the engineer and the commit SHAs by name.
+func verifyRepo(t *testing.T) (*Engineer, map[string]string) {
+	t.Helper()
+	r := newStackRepo(t)
+	shas := map[string]string{"base": r.rev("HEAD")}
+	for _, name := range []string{"good1", "good2", "bad", "after1", "after2"} {
+		file := name + ".txt"
+		if name == "bad" {
+			file = "broken"
+		}
+		r.commit(file, name+"\n")
+		shas[name] = r.rev("HEAD")
+	}
+	r.git(r.dir, "push", "-q", "origin", "main")
+
+	e := r.engineer()
+	e.config.VerifyMain = &GateConfig{Cmd: "test ! -f broken"}
+	return e, shas
+}
+
+func TestVerifyMain_FindsCulprit(t *testing.T) {
+	e, shas := verifyRepo(t)
+
+	result, err := e.VerifyMain(context.Background(), true)
+	if err != nil {
+		t.Fatalf("VerifyMain: %v", err)
+	}
+	if result.Outcome != VerifyRed {
+		t.Fatalf("Outcome = %q (%s), want %q", result.Outcome, result.Detail, VerifyRed)
+	}
+	if result.Culprit == nil || result.Culprit.Commit != shas["bad"] {
+		t.Fatalf("Culprit = %+v, want commit %s", result.Culprit, shas["bad"])
+	}
+	if result.Culprit.Subject != "edit broken" {
+		t.Errorf("Culprit.Subject = %q", result.Culprit.Subject)
+	}
+	if len(result.Steps) == 0 || result.Steps[0].Commit != shas["after2"] || result.Steps[0].Passed {
+		t.Errorf("first step should be the failing tip, got %+v", result.Steps)
+	}
+	if !strings.Contains(result.Log, "exit status 1") {
+		t.Errorf("Log = %q, want the check's failure", result.Log)
+	}
+
+	// Dry runs leave no state behind
+	state, err := LoadMainVerifyState(e.rig.Path)
+	if err != nil || state.LastChecked != "" {
+		t.Errorf("dry run saved state: %+v, %v", state, err)
+	}
+}
+
+func TestVerifyMain_BisectsOnlySinceLastGreen(t *testing.T) {
+	e, shas := verifyRepo(t)
+	if err := SaveMainVerifyState(e.rig.Path, &MainVerifyState{Branch: "main", LastGreen: shas["good2"], LastChecked: shas["good2"]}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := e.VerifyMain(context.Background(), true)
+	if err != nil {
+		t.Fatalf("VerifyMain: %v", err)
+	}
+	if result.Culprit == nil || result.Culprit.Commit != shas["bad"] {
+		t.Fatalf("Culprit = %+v, want %s", result.Culprit, shas["bad"])
+	}
+	for _, step := range result.Steps {
+		if step.Commit == shas["good1"] || step.Commit == shas["good2"] || step.Commit == shas["base"] {
+			t.Errorf("checked %s, which is at or before the last green tip", shortSHA(step.Commit))
+		}
+	}
+}
+
+func TestVerifyMain_FailureBeforeWindowIsUnattributed(t *testing.T) {
+	e, _ := verifyRepo(t)
+	e.config.BisectDepth = 2 // after1, after2: the breakage is older
+
+	result, err := e.VerifyMain(context.Background(), true)
+	if err != nil {
+		t.Fatalf("VerifyMain: %v", err)
+	}
+	if result.Outcome != VerifyUnattributed || result.Culprit != nil {
+		t.Errorf("Outcome = %q, Culprit = %+v; want unattributed", result.Outcome, result.Culprit)
+	}
+	if !strings.Contains(result.Detail, "already failing") {
+		t.Errorf("Detail = %q", result.Detail)
+	}
+}
+
+func TestVerifyMain_GreenRecordsState(t *testing.T) {
+	e, shas := verifyRepo(t)
+	e.config.VerifyMain = &GateConfig{Cmd: "true"}
+
+	result, err := e.VerifyMain(context.Background(), false)
+	if err != nil {
+		t.Fatalf("VerifyMain: %v", err)
+	}
+	if result.Outcome != VerifyGreen || len(result.Steps) != 1 {
+		t.Fatalf("result = %+v, want green after one check", result)
+	}
+	state, err := LoadMainVerifyState(e.rig.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.LastGreen != shas["after2"] || state.LastChecked != shas["after2"] {
+		t.Errorf("state = %+v, want after2 recorded as green", state)
+	}
+
+	again, err := e.VerifyMain(context.Background(), false)
+	if err != nil {
+		t.Fatalf("VerifyMain: %v", err)
+	}
+	if again.Outcome != VerifyUnchanged || len(again.Steps) != 0 {
+		t.Errorf("second run = %+v, want unchanged without re-checking", again)
+	}
+}
+
+func TestVerifyMain_NotConfigured(t *testing.T) {
+	e, _ := verifyRepo(t)
+	e.config.VerifyMain = nil
+	if _, err := e.VerifyMain(context.Background(), true); err == nil {
+		t.Error("VerifyMain without verify_main should fail")
+	}
+}
+
+func TestTruncateLogKeepsUTF8(t *testing.T) {
+	got := truncateLog("x" + strings.Repeat("é", 3000))
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "... (truncated)") {
+		t.Errorf("truncateLog produced %d bytes, valid UTF-8 = %v", len(got), utf8.ValidString(got))
+	}
+	if got := truncateLog("short"); got != "short" {
+		t.Errorf("truncateLog(short) = %q", got)
+	}
+}
@@ -921,6 +921,12 @@ func (m *Manager) initAgentBeads(rigPath, rigName, prefix string) error {
 
 // ensureGitignoreEntry adds an entry to .gitignore if it doesn't already exist.
 func (m *Manager) ensureGitignoreEntry(gitignorePath, entry string) error {
+	return EnsureGitignoreEntry(gitignorePath, entry)
+}
+
+// EnsureGitignoreEntry adds an entry to a .gitignore if it doesn't already
+// exist, creating the file if needed.
+func EnsureGitignoreEntry(gitignorePath, entry string) error {
 	// Read existing content
 	content, err := os.ReadFile(gitignorePath)
 	if err != nil && !os.IsNotExist(err) {
@@ -1508,7 +1514,7 @@ See docs/deacon-plugins.md for full documentation.
 		return fmt.Errorf("creating rig plugins directory: %w", err)
 	}
 
-	// Add plugins/, .repo.git/, and .land-worktree/ to rig .gitignore
+	// Add plugins/, .repo.git/, .land-worktree/ and .worktree-pool/ to rig .gitignore
 	gitignorePath := filepath.Join(rigPath, ".gitignore")
 	if err := m.ensureGitignoreEntry(gitignorePath, "plugins/"); err != nil {
 		return err
@@ -1516,5 +1522,8 @@ See docs/deacon-plugins.md for full documentation.
 	if err := m.ensureGitignoreEntry(gitignorePath, ".repo.git/"); err != nil {
 		return err
 	}
-	return m.ensureGitignoreEntry(gitignorePath, ".land-worktree/")
+	if err := m.ensureGitignoreEntry(gitignorePath, ".land-worktree/"); err != nil {
+		return err
+	}
+	return m.ensureGitignoreEntry(gitignorePath, ".worktree-pool/")
 }
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Usage counts the polecats currently running, by scope.
+type Usage struct {
+	Total     int            `json:"total"`
+	ByRig     map[string]int `json:"by_rig"`
+	ByAccount map[string]int `json:"by_account"`
+}
+
+// Constraints are everything besides the queue that decides what may start.
+type Constraints struct {
+	Limits          *config.SchedulerConfig // nil = no polecat limits
+	DefaultAccount  string                  // account used by entries without an explicit one
+	LimitedAccounts map[string]bool         // accounts currently rate-limited (no quota headroom)
+	DoltAtCapacity  bool                    // Dolt server is near its connection limit
+	Now             time.Time               // for retry backoff; zero = time.Now()
+}
+
+// Decision is the scheduler's verdict for one queued entry.
+type Decision struct {
+	Entry    *Entry `json:"entry"`
+	Dispatch bool   `json:"dispatch"`
+	Reason   string `json:"reason,omitempty"` // why the entry must keep waiting
+}
+
+// Plan walks the queue in dispatch order and decides which entries can start
+// now. Each dispatched entry consumes capacity for the entries after it. An
+// entry blocked by its rig or account does not hold up entries for other
+// rigs or accounts. Entries that recently failed wait out their retry
+// backoff, and parked entries never dispatch.
+func Plan(q *Queue, usage Usage, c Constraints) []Decision {
+	total := usage.Total
+	byRig := copyCounts(usage.ByRig)
+	byAccount := copyCounts(usage.ByAccount)
+
+	now := c.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	decisions := make([]Decision, 0, len(q.Entries))
+	for _, e := range q.Entries {
+		account := e.Account
+		if account == "" {
+			account = c.DefaultAccount
+		}
+
+		reason := ""
+		switch {
+		case e.Parked:
+			reason = fmt.Sprintf("parked after %d failed attempts (re-queue with gt sling --queue)", e.Attempts)
+		case e.Attempts > 0 && now.Before(e.LastAttempt.Add(e.RetryBackoff())):
+			reason = fmt.Sprintf("retrying in %s after a failed attempt", e.LastAttempt.Add(e.RetryBackoff()).Sub(now).Round(time.Second))
+		case c.DoltAtCapacity:
+			reason = "dolt server at capacity"
+		case atLimit(total, townLimit(c.Limits)):
+			reason = fmt.Sprintf("town at %d polecats", total)
+		case atLimit(byRig[e.Rig], c.Limits.RigLimit(e.Rig)):
+			reason = fmt.Sprintf("%s at %d polecats", e.Rig, byRig[e.Rig])
+		case account != "" && c.LimitedAccounts[account]:
+			reason = fmt.Sprintf("account %s rate-limited", account)
+		case account != "" && atLimit(byAccount[account], c.Limits.AccountLimit(account)):
+			reason = fmt.Sprintf("account %s at %d polecats", account, byAccount[account])
+		}
+
+		if reason != "" {
+			decisions = append(decisions, Decision{Entry: e, Reason: reason})
+			continue
+		}
+		total++
+		byRig[e.Rig]++
+		if account != "" {
+			byAccount[account]++
+		}
+		decisions = append(decisions, Decision{Entry: e, Dispatch: true})
+	}
+	return decisions
+}
+
+func townLimit(c *config.SchedulerConfig) int {
+	if c == nil {
+		return 0
+	}
+	return c.MaxPolecats
+}
+
+// atLimit reports whether count has reached limit (0 = unlimited).
+func atLimit(count, limit int) bool {
+	return limit > 0 && count >= limit
+}
+
+func copyCounts(m map[string]int) map[string]int {
+	out := make(map[string]int, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,261 @@
+// Package scheduler owns the town-wide queue of sling work waiting for
+// polecat capacity.
+//
+// `gt sling --queue` enqueues work instead of spawning a polecat immediately.
+// The queue is drained by `gt schedule run` (called on every daemon
+// heartbeat), which dispatches entries in priority order while per-rig,
+// per-account and town-wide polecat limits, Dolt connection capacity and
+// account quota allow. State is persisted to mayor/sling-queue.json with
+// atomic writes and file-level locking.
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// CurrentQueueVersion is the current schema version for Queue.
+const CurrentQueueVersion = 1
+
+// Entry is one piece of work waiting to be slung to a rig.
+type Entry struct {
+	BeadID     string    `json:"bead_id"`
+	Rig        string    `json:"rig"`
+	Priority   int       `json:"priority"`          // bead priority (0 = highest)
+	Account    string    `json:"account,omitempty"` // explicit --account; empty = default account
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	EnqueuedBy string    `json:"enqueued_by,omitempty"`
+
+	// SlingArgs is the full `gt sling` argument list (bead, rig and flags)
+	// replayed at dispatch.
+	SlingArgs []string `json:"sling_args,omitempty"`
+
+	Attempts    int       `json:"attempts,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+
+	// Parked is set once Attempts reaches MaxAttempts; the entry stays
+	// queued for inspection but is no longer dispatched. Re-queuing the
+	// bead with `gt sling --queue` resets it.
+	Parked bool `json:"parked,omitempty"`
+}
+
+// MaxAttempts is how many failed dispatches park an entry.
+const MaxAttempts = 5
+
+// Retry backoff after a failed dispatch doubles per attempt up to maxRetryBackoff.
+const (
+	baseRetryBackoff = time.Minute
+	maxRetryBackoff  = 30 * time.Minute
+)
+
+// RetryBackoff returns how long after its last failed attempt the entry
+// must wait before it is dispatched again.
+func (e *Entry) RetryBackoff() time.Duration {
+	if e.Attempts <= 0 {
+		return 0
+	}
+	backoff := baseRetryBackoff
+	for i := 1; i < e.Attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// RecordFailure notes a failed dispatch at now, parking the entry once it
+// reaches MaxAttempts.
+func (e *Entry) RecordFailure(msg string, now time.Time) {
+	e.Attempts++
+	e.LastError = msg
+	e.LastAttempt = now.UTC()
+	if e.Attempts >= MaxAttempts {
+		e.Parked = true
+	}
+}
+
+// Queue is the persisted scheduler state.
+type Queue struct {
+	Version int      `json:"version"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Sort orders entries by priority, then by enqueue time (FIFO within a priority).
+func (q *Queue) Sort() {
+	sort.SliceStable(q.Entries, func(i, j int) bool {
+		a, b := q.Entries[i], q.Entries[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.EnqueuedAt.Before(b.EnqueuedAt)
+	})
+}
+
+// Find returns the entry for beadID, or nil.
+func (q *Queue) Find(beadID string) *Entry {
+	for _, e := range q.Entries {
+		if e.BeadID == beadID {
+			return e
+		}
+	}
+	return nil
+}
+
+// Remove drops the entry for beadID. Returns false if it wasn't queued.
+func (q *Queue) Remove(beadID string) bool {
+	for i, e := range q.Entries {
+		if e.BeadID == beadID {
+			q.Entries = append(q.Entries[:i], q.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Positions maps each queued bead ID to its 1-based dispatch position.
+func (q *Queue) Positions() map[string]int {
+	sorted := &Queue{Entries: append([]*Entry(nil), q.Entries...)}
+	sorted.Sort()
+	positions := make(map[string]int, len(sorted.Entries))
+	for i, e := range sorted.Entries {
+		positions[e.BeadID] = i + 1
+	}
+	return positions
+}
+
+// Manager handles queue persistence with file locking.
+type Manager struct {
+	townRoot string
+}
+
+// NewManager creates a queue manager for the given town root.
+func NewManager(townRoot string) *Manager {
+	return &Manager{townRoot: townRoot}
+}
+
+// queuePath returns the path to sling-queue.json.
+func (m *Manager) queuePath() string {
+	return constants.MayorSlingQueuePath(m.townRoot)
+}
+
+// lockPath returns the path to the flock file for queue state.
+func (m *Manager) lockPath() string {
+	return filepath.Join(m.townRoot, constants.DirMayor, constants.DirRuntime, "sling-queue.lock")
+}
+
+// lock acquires an exclusive file lock for queue operations.
+// Caller must call the returned unlock function.
+func (m *Manager) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(m.lockPath()), 0755); err != nil {
+		return nil, fmt.Errorf("creating queue lock dir: %w", err)
+	}
+	fl := flock.New(m.lockPath())
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring queue lock: %w", err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// ErrDispatchBusy is returned when another process is already dispatching.
+var ErrDispatchBusy = errors.New("queue dispatch already in progress")
+
+// LockDispatch acquires the dispatch lock without blocking, so the daemon and
+// a manual `gt schedule run` never start the same entry twice. The queue lock
+// is separate and stays free while spawns run. Returns ErrDispatchBusy if
+// another dispatcher holds the lock.
+func (m *Manager) LockDispatch() (func(), error) {
+	path := filepath.Join(filepath.Dir(m.lockPath()), "sling-dispatch.lock")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating queue lock dir: %w", err)
+	}
+	fl := flock.New(path)
+	locked, err := fl.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring dispatch lock: %w", err)
+	}
+	if !locked {
+		return nil, ErrDispatchBusy
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// Load reads the queue from disk, sorted in dispatch order. Returns an empty
+// queue if the file doesn't exist yet.
+func (m *Manager) Load() (*Queue, error) {
+	data, err := os.ReadFile(m.queuePath())
+	if os.IsNotExist(err) {
+		return &Queue{Version: CurrentQueueVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sling queue: %w", err)
+	}
+
+	var q Queue
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("parsing sling queue: %w", err)
+	}
+	q.Sort()
+	return &q, nil
+}
+
+// Update loads the queue under the lock, applies fn, and saves the result
+// if fn returns nil.
+func (m *Manager) Update(fn func(q *Queue) error) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	q, err := m.Load()
+	if err != nil {
+		return err
+	}
+	if err := fn(q); err != nil {
+		return err
+	}
+	q.Version = CurrentQueueVersion
+	q.Sort()
+	return util.EnsureDirAndWriteJSON(m.queuePath(), q)
+}
+
+// Enqueue adds e to the queue, replacing any existing entry for the same bead
+// (re-queuing keeps the original enqueue time so the bead keeps its place).
+// Returns the entry's 1-based position.
+func (m *Manager) Enqueue(e *Entry) (int, error) {
+	if e.BeadID == "" || e.Rig == "" {
+		return 0, fmt.Errorf("queue entry needs a bead and a rig")
+	}
+	if e.EnqueuedAt.IsZero() {
+		e.EnqueuedAt = time.Now().UTC()
+	}
+	var position int
+	err := m.Update(func(q *Queue) error {
+		if existing := q.Find(e.BeadID); existing != nil {
+			e.EnqueuedAt = existing.EnqueuedAt
+			q.Remove(e.BeadID)
+		}
+		q.Entries = append(q.Entries, e)
+		position = q.Positions()[e.BeadID]
+		return nil
+	})
+	return position, err
+}
+
+// Remove drops beadID from the queue. Returns false if it wasn't queued.
+func (m *Manager) Remove(beadID string) (bool, error) {
+	var removed bool
+	err := m.Update(func(q *Queue) error {
+		removed = q.Remove(beadID)
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestEnqueueOrdersByPriorityThenAge(t *testing.T) {
+	m := NewManager(t.TempDir())
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, e := range []*Entry{
+		{BeadID: "gt-low", Rig: "gastown", Priority: 3},
+		{BeadID: "gt-old", Rig: "gastown", Priority: 1},
+		{BeadID: "gt-new", Rig: "gastown", Priority: 1},
+		{BeadID: "gt-p0", Rig: "beads", Priority: 0},
+	} {
+		e.EnqueuedAt = base.Add(time.Duration(i) * time.Minute)
+		if _, err := m.Enqueue(e); err != nil {
+			t.Fatalf("Enqueue(%s): %v", e.BeadID, err)
+		}
+	}
+
+	q, err := m.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{"gt-p0", "gt-old", "gt-new", "gt-low"}
+	for i, id := range want {
+		if q.Entries[i].BeadID != id {
+			t.Fatalf("order[%d] = %s, want %s", i, q.Entries[i].BeadID, id)
+		}
+	}
+	if pos := q.Positions()["gt-new"]; pos != 3 {
+		t.Errorf("position of gt-new = %d, want 3", pos)
+	}
+
+	// Re-queuing keeps the original enqueue time (and so the bead's place)
+	pos, err := m.Enqueue(&Entry{BeadID: "gt-old", Rig: "gastown", Priority: 1})
+	if err != nil {
+		t.Fatalf("re-Enqueue: %v", err)
+	}
+	if pos != 2 {
+		t.Errorf("re-queued position = %d, want 2", pos)
+	}
+
+	if removed, err := m.Remove("gt-p0"); err != nil || !removed {
+		t.Fatalf("Remove: removed=%v err=%v", removed, err)
+	}
+	if removed, _ := m.Remove("gt-p0"); removed {
+		t.Error("second Remove should report not queued")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	q := &Queue{Entries: []*Entry{
+		{BeadID: "a", Rig: "gastown", Priority: 0},
+		{BeadID: "b", Rig: "gastown", Priority: 1},
+		{BeadID: "c", Rig: "beads", Priority: 1, Account: "work"},
+		{BeadID: "d", Rig: "beads", Priority: 2, Account: "personal"},
+		{BeadID: "e", Rig: "beads", Priority: 2},
+	}}
+	usage := Usage{Total: 2, ByRig: map[string]int{"gastown": 1, "beads": 1}}
+	c := Constraints{
+		Limits: &config.SchedulerConfig{
+			MaxPolecats:           5,
+			MaxPolecatsPerRig:     3,
+			Rigs:                  map[string]int{"gastown": 2},
+			MaxPolecatsPerAccount: 2,
+		},
+		DefaultAccount:  "work",
+		LimitedAccounts: map[string]bool{"personal": true},
+	}
+
+	got := map[string]Decision{}
+	for _, d := range Plan(q, usage, c) {
+		got[d.Entry.BeadID] = d
+	}
+
+	if !got["a"].Dispatch {
+		t.Errorf("a should dispatch: %+v", got["a"])
+	}
+	if got["b"].Dispatch {
+		t.Error("b should wait: gastown is at its limit of 2 after a")
+	}
+	if !got["c"].Dispatch {
+		t.Errorf("c should dispatch: %+v", got["c"])
+	}
+	if got["d"].Dispatch || got["d"].Reason != "account personal rate-limited" {
+		t.Errorf("d should wait on quota, got %+v", got["d"])
+	}
+	if got["e"].Dispatch || got["e"].Reason != "account work at 2 polecats" {
+		t.Errorf("e should wait on default account limit, got %+v", got["e"])
+	}
+}
+
+func TestPlanDoltAtCapacity(t *testing.T) {
+	q := &Queue{Entries: []*Entry{{BeadID: "a", Rig: "gastown"}}}
+	for _, d := range Plan(q, Usage{}, Constraints{DoltAtCapacity: true}) {
+		if d.Dispatch {
+			t.Errorf("nothing should dispatch when Dolt is at capacity")
+		}
+	}
+	for _, d := range Plan(q, Usage{}, Constraints{}) {
+		if !d.Dispatch {
+			t.Errorf("no limits should dispatch everything, got %+v", d)
+		}
+	}
+}
+
+func TestPlanRetryBackoffAndParking(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	fresh := &Entry{BeadID: "fresh", Rig: "gastown"}
+	backingOff := &Entry{BeadID: "backing-off", Rig: "gastown"}
+	backingOff.RecordFailure("boom", now.Add(-30*time.Second))
+	retry := &Entry{BeadID: "retry", Rig: "gastown"}
+	retry.RecordFailure("boom", now.Add(-2*time.Minute))
+	parked := &Entry{BeadID: "parked", Rig: "gastown"}
+	for i := 0; i < MaxAttempts; i++ {
+		parked.RecordFailure("boom", now.Add(-time.Hour))
+	}
+
+	q := &Queue{Entries: []*Entry{fresh, backingOff, retry, parked}}
+	got := map[string]Decision{}
+	for _, d := range Plan(q, Usage{}, Constraints{Now: now}) {
+		got[d.Entry.BeadID] = d
+	}
+	if !got["fresh"].Dispatch || !got["retry"].Dispatch {
+		t.Errorf("fresh and retry should dispatch: %+v", got)
+	}
+	if got["backing-off"].Dispatch {
+		t.Error("entry within its backoff should wait")
+	}
+	if !parked.Parked || got["parked"].Dispatch {
+		t.Errorf("entry should be parked after %d attempts: %+v", MaxAttempts, got["parked"])
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	e := &Entry{}
+	want := []time.Duration{0, time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, w := range want {
+		e.Attempts = i
+		if got := e.RetryBackoff(); got != w {
+			t.Errorf("attempts %d: backoff = %v, want %v", i, got, w)
+		}
+	}
+	e.Attempts = 20
+	if got := e.RetryBackoff(); got != maxRetryBackoff {
+		t.Errorf("backoff should cap at %v, got %v", maxRetryBackoff, got)
+	}
+}
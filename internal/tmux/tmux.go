@@ -2352,6 +2352,32 @@ func (t *Tmux) SetPaneDiedHook(session, agentID string) error {
 	return err
 }
 
+// StartPaneRecording pipes a session's pane output to 'gt session record-pipe',
+// which writes it as asciicast segments under the town's recordings directory.
+// The recorder exits when the pane closes. Does nothing if the pane is
+// already being piped.
+func (t *Tmux) StartPaneRecording(session, townRoot, agentID string, segmentBytes int64, segmentDuration time.Duration) error {
+	if err := validateSessionName(session); err != nil {
+		return err
+	}
+	size, err := t.run("display-message", "-t", session, "-p", "#{pane_width} #{pane_height}")
+	if err != nil {
+		return err
+	}
+	var width, height int
+	if _, err := fmt.Sscanf(size, "%d %d", &width, &height); err != nil {
+		return fmt.Errorf("parsing pane size %q: %w", size, err)
+	}
+
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'" }
+	pipeCmd := fmt.Sprintf("exec gt session record-pipe --town %s --agent %s --width %d --height %d --segment-bytes %d --segment-duration %s",
+		quote(townRoot), quote(agentID), width, height, segmentBytes, segmentDuration)
+
+	// -o only opens a pipe if none is open, so a restart doesn't double-record.
+	_, err = t.run("pipe-pane", "-o", "-t", session, pipeCmd)
+	return err
+}
+
 // SetAutoRespawnHook configures a session to automatically respawn when the pane dies.
 // This is used for persistent agents like Deacon that should never exit.
 // PATCH-010: Fixes Deacon crash loop by respawning at tmux level.
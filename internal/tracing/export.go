@@ -0,0 +1,254 @@
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// TracesFile is the local span log, relative to the town root.
+const TracesFile = "logs/traces.jsonl"
+
+// serviceName is reported as the OTLP resource's service.name.
+const serviceName = "gastown"
+
+// otlpTimeout bounds each export so an unreachable collector can't stall gt.
+const otlpTimeout = 2 * time.Second
+
+// TracesPath returns the local span log for a town.
+func TracesPath(townRoot string) string {
+	return filepath.Join(townRoot, TracesFile)
+}
+
+// appendSpan appends a span to the town's span log under a cross-process lock.
+func appendSpan(townRoot string, s SpanRecord) error {
+	if townRoot == "" {
+		return nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshaling span: %w", err)
+	}
+	data = append(data, '\n')
+
+	path := TracesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating logs directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring traces file lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: spans are non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening traces file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing span: %w", err)
+	}
+	return nil
+}
+
+// ReadSpans reads every span in the town's span log, skipping malformed lines.
+func ReadSpans(townRoot string) ([]SpanRecord, error) {
+	f, err := os.Open(TracesPath(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening traces file: %w", err)
+	}
+	defer f.Close()
+
+	var spans []SpanRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var s SpanRecord
+		if json.Unmarshal(scanner.Bytes(), &s) == nil && s.TraceID != "" {
+			spans = append(spans, s)
+		}
+	}
+	return spans, scanner.Err()
+}
+
+// Trace returns the spans of one trace, ordered by start time.
+func Trace(spans []SpanRecord, traceID string) []SpanRecord {
+	var out []SpanRecord
+	for _, s := range spans {
+		if s.TraceID == traceID {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// TraceForBead returns the ID of the most recent trace with a span
+// recorded for bead (the "bead" attribute), or "" if there is none.
+func TraceForBead(spans []SpanRecord, bead string) string {
+	var traceID string
+	var latest time.Time
+	for _, s := range spans {
+		if s.Attrs["bead"] == bead && !s.Start.Before(latest) {
+			traceID, latest = s.TraceID, s.Start
+		}
+	}
+	return traceID
+}
+
+// otlpEndpoint resolves the OTLP/HTTP traces URL from the standard
+// OpenTelemetry environment variables. Returns "" if none is set.
+func otlpEndpoint() string {
+	if ep := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); ep != "" {
+		return ep
+	}
+	if ep := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); ep != "" {
+		return strings.TrimRight(ep, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// otlpHeaders parses OTEL_EXPORTER_OTLP_HEADERS ("k1=v1,k2=v2").
+func otlpHeaders() map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if k, v, ok := strings.Cut(pair, "="); ok && strings.TrimSpace(k) != "" {
+			headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return headers
+}
+
+// exportOTLP sends a span to the configured collector, if any.
+func exportOTLP(s SpanRecord) error {
+	endpoint := otlpEndpoint()
+	if endpoint == "" {
+		return nil
+	}
+	body, err := json.Marshal(otlpRequest(s))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range otlpHeaders() {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: otlpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP export: %s", resp.Status)
+	}
+	return nil
+}
+
+// OTLP/JSON encoding (opentelemetry-proto ExportTraceServiceRequest).
+// IDs are hex strings and timestamps decimal strings, per the spec's JSON
+// mapping.
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+func keyValue(k, v string) otlpKeyValue {
+	kv := otlpKeyValue{Key: k}
+	kv.Value.StringValue = v
+	return kv
+}
+
+func otlpRequest(s SpanRecord) otlpExportRequest {
+	span := otlpSpan{
+		TraceID:           s.TraceID,
+		SpanID:            s.SpanID,
+		ParentSpanID:      s.ParentID,
+		Name:              s.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+	}
+	keys := make([]string, 0, len(s.Attrs))
+	for k := range s.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		span.Attributes = append(span.Attributes, keyValue("gt."+k, s.Attrs[k]))
+	}
+	span.Status.Code = otlpStatusOK
+	if s.Error != "" {
+		span.Status.Code = otlpStatusError
+		span.Status.Message = s.Error
+	}
+
+	scope := otlpScopeSpans{Spans: []otlpSpan{span}}
+	scope.Scope.Name = "github.com/steveyegge/gastown/internal/tracing"
+	rs := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scope}}
+	rs.Resource.Attributes = []otlpKeyValue{keyValue("service.name", serviceName)}
+	return otlpExportRequest{ResourceSpans: []otlpResourceSpans{rs}}
+}
@@ -0,0 +1,234 @@
+// Package tracing correlates one piece of work across the processes that
+// handle it: gt sling mints a trace, the polecat session inherits it through
+// GT_TRACEPARENT, the bead and MR carry it as trace_id, and POLECAT_DONE /
+// MERGE_READY mail carry it as a Trace: line. Each stage records a span.
+//
+// Spans are appended to <town>/logs/traces.jsonl and, when
+// OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) is
+// set, also sent to an OpenTelemetry collector as OTLP/HTTP JSON. Set
+// GT_TRACE=off to disable tracing entirely.
+//
+// IDs follow W3C Trace Context: 32 hex digits for a trace, 16 for a span.
+// The root (sling) span's ID is the first half of the trace ID, so any stage
+// that only knows a bead's trace_id can still parent its span to the root.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvTraceParent carries the parent span to child processes, in W3C
+// traceparent format ("00-<trace-id>-<span-id>-01").
+const EnvTraceParent = "GT_TRACEPARENT"
+
+// EnvTrace disables tracing when set to "off".
+const EnvTrace = "GT_TRACE"
+
+var (
+	traceIDRe = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	spanIDRe  = regexp.MustCompile(`^[0-9a-f]{16}$`)
+)
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// IsValid reports whether sc names a span.
+func (sc SpanContext) IsValid() bool {
+	return IsTraceID(sc.TraceID) && spanIDRe.MatchString(sc.SpanID) && sc.SpanID != strings.Repeat("0", 16)
+}
+
+// TraceParent formats sc as a W3C traceparent header value.
+// Returns "" for an invalid context.
+func (sc SpanContext) TraceParent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// IsTraceID reports whether s is a well-formed, non-zero trace ID.
+func IsTraceID(s string) bool {
+	return traceIDRe.MatchString(s) && s != strings.Repeat("0", 32)
+}
+
+// ParseTraceParent parses a W3C traceparent value. Returns the zero
+// SpanContext if s is empty or malformed.
+func ParseTraceParent(s string) SpanContext {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return SpanContext{}
+	}
+	sc := SpanContext{TraceID: parts[1], SpanID: parts[2]}
+	if !sc.IsValid() {
+		return SpanContext{}
+	}
+	return sc
+}
+
+// Root returns the context of a trace's root span.
+// Returns the zero SpanContext if traceID is malformed.
+func Root(traceID string) SpanContext {
+	if !IsTraceID(traceID) {
+		return SpanContext{}
+	}
+	return SpanContext{TraceID: traceID, SpanID: traceID[:16]}
+}
+
+// NewTraceID returns a random trace ID.
+func NewTraceID() string {
+	return randomHex(16)
+}
+
+func newSpanID() string {
+	return randomHex(8)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to the clock.
+		ts := fmt.Sprintf("%0*x", n*2, time.Now().UnixNano())
+		return ts[len(ts)-n*2:]
+	}
+	return hex.EncodeToString(b)
+}
+
+// FromEnv returns the parent span passed in GT_TRACEPARENT, if any.
+func FromEnv() SpanContext {
+	return ParseTraceParent(os.Getenv(EnvTraceParent))
+}
+
+// Enabled reports whether tracing is on (GT_TRACE is not "off").
+func Enabled() bool {
+	return !strings.EqualFold(os.Getenv(EnvTrace), "off")
+}
+
+// SpanRecord is a finished span as stored in the span log.
+type SpanRecord struct {
+	TraceID  string            `json:"trace_id"`
+	SpanID   string            `json:"span_id"`
+	ParentID string            `json:"parent_id,omitempty"`
+	Name     string            `json:"name"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Duration returns how long the span ran.
+func (r SpanRecord) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Span is an in-progress stage of a trace. A nil *Span is valid and does
+// nothing, so callers need not check whether tracing is enabled.
+type Span struct {
+	townRoot string
+
+	mu     sync.Mutex
+	record SpanRecord
+	ended  bool
+}
+
+// Start begins a span under parent. If parent is not yet known (e.g. it must
+// be read from a bead first), set it later with SetParent; a span that still
+// has no trace when it ends is dropped. Returns nil if tracing is disabled.
+func Start(townRoot, name string, parent SpanContext) *Span {
+	if !Enabled() {
+		return nil
+	}
+	s := &Span{
+		townRoot: townRoot,
+		record:   SpanRecord{SpanID: newSpanID(), Name: name, Start: time.Now()},
+	}
+	s.SetParent(parent)
+	return s
+}
+
+// StartRoot begins the root span of a new trace (see Root).
+// Returns nil if tracing is disabled.
+func StartRoot(townRoot, name, traceID string) *Span {
+	root := Root(traceID)
+	if !Enabled() || !root.IsValid() {
+		return nil
+	}
+	return &Span{
+		townRoot: townRoot,
+		record:   SpanRecord{TraceID: root.TraceID, SpanID: root.SpanID, Name: name, Start: time.Now()},
+	}
+}
+
+// SetParent attaches the span to parent's trace, unless it already has one.
+func (s *Span) SetParent(parent SpanContext) {
+	if s == nil || !parent.IsValid() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record.TraceID == "" {
+		s.record.TraceID = parent.TraceID
+		s.record.ParentID = parent.SpanID
+	}
+}
+
+// Context returns the span's own context, for parenting child spans.
+// Zero if the span has no trace.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record.TraceID == "" {
+		return SpanContext{}
+	}
+	return SpanContext{TraceID: s.record.TraceID, SpanID: s.record.SpanID}
+}
+
+// SetAttr records an attribute. Empty values are ignored.
+func (s *Span) SetAttr(key, value string) {
+	if s == nil || value == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record.Attrs == nil {
+		s.record.Attrs = make(map[string]string)
+	}
+	s.record.Attrs[key] = value
+}
+
+// End ends the span, marking it failed if err is non-nil, and exports it.
+// Export errors are ignored: tracing must never fail the work it observes.
+// Only the first call has any effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended || s.record.TraceID == "" {
+		s.ended = true
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.record.End = time.Now()
+	if err != nil {
+		s.record.Error = err.Error()
+	}
+	record := s.record
+	s.mu.Unlock()
+
+	_ = appendSpan(s.townRoot, record)
+	_ = exportOTLP(record)
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	traceID := NewTraceID()
+	if !IsTraceID(traceID) {
+		t.Fatalf("NewTraceID() = %q", traceID)
+	}
+	sc := SpanContext{TraceID: traceID, SpanID: newSpanID()}
+	if got := ParseTraceParent(sc.TraceParent()); got != sc {
+		t.Errorf("round trip = %+v, want %+v", got, sc)
+	}
+
+	for _, bad := range []string{
+		"",
+		"01-" + traceID + "-" + sc.SpanID + "-01",
+		"00-" + traceID + "-0000000000000000-01",
+		"00-00000000000000000000000000000000-" + sc.SpanID + "-01",
+		"00-" + traceID[:31] + "-" + sc.SpanID + "-01",
+	} {
+		if got := ParseTraceParent(bad); got.IsValid() {
+			t.Errorf("ParseTraceParent(%q) = %+v, want invalid", bad, got)
+		}
+	}
+
+	if root := Root(traceID); root.SpanID != traceID[:16] || !root.IsValid() {
+		t.Errorf("Root = %+v", root)
+	}
+}
+
+func TestSpan_RecordsToTownLog(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	traceID := NewTraceID()
+
+	root := StartRoot(townRoot, "sling", traceID)
+	root.SetAttr("bead", "gt-abc")
+	child := Start(townRoot, "hook", root.Context())
+	child.End(errors.New("hook failed"))
+	child.End(nil) // second End is a no-op
+	root.End(nil)
+
+	// A span with no trace is dropped.
+	Start(townRoot, "orphan", SpanContext{}).End(nil)
+
+	spans, err := ReadSpans(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2: %+v", len(spans), spans)
+	}
+	trace := Trace(spans, traceID)
+	if trace[0].Name != "sling" || trace[0].ParentID != "" || trace[1].ParentID != trace[0].SpanID {
+		t.Errorf("trace = %+v", trace)
+	}
+	if trace[1].Error != "hook failed" {
+		t.Errorf("child error = %q", trace[1].Error)
+	}
+	if got := TraceForBead(spans, "gt-abc"); got != traceID {
+		t.Errorf("TraceForBead = %q", got)
+	}
+}
+
+func TestSpan_LateParent(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	s := Start(townRoot, "done", SpanContext{})
+	if s.Context().IsValid() {
+		t.Error("span without parent should have no context")
+	}
+	parent := Root(NewTraceID())
+	s.SetParent(parent)
+	s.SetParent(Root(NewTraceID())) // ignored once attached
+	s.End(nil)
+
+	spans, _ := ReadSpans(townRoot)
+	if len(spans) != 1 || spans[0].TraceID != parent.TraceID || spans[0].ParentID != parent.SpanID {
+		t.Errorf("spans = %+v", spans)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	t.Setenv(EnvTrace, "off")
+	if s := StartRoot(t.TempDir(), "sling", NewTraceID()); s != nil {
+		t.Error("expected nil span when tracing is off")
+	}
+	var s *Span
+	s.SetAttr("k", "v") // nil spans are safe to use
+	s.End(nil)
+}
+
+func TestExportOTLP(t *testing.T) {
+	var got otlpExportRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL+"/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer x")
+
+	s := StartRoot(t.TempDir(), "merge", NewTraceID())
+	s.SetAttr("bead", "gt-abc")
+	s.End(errors.New("conflict"))
+
+	if auth != "Bearer x" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+		t.Fatalf("request = %+v", got)
+	}
+	span := got.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if span.Name != "merge" || span.Status.Code != otlpStatusError || span.Attributes[0].Key != "gt.bead" {
+		t.Errorf("span = %+v", span)
+	}
+}
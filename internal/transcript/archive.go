@@ -0,0 +1,249 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ArchiveDir is the town-level directory holding the transcript archive.
+const ArchiveDir = ".seance"
+
+// Record describes one archived session in the archive index.
+type Record struct {
+	SessionID string `json:"session_id"`
+	Actor     string `json:"actor"` // e.g., "gastown/polecats/Toast"
+	Role      string `json:"role,omitempty"`
+	Rig       string `json:"rig,omitempty"`
+	Polecat   string `json:"polecat,omitempty"` // polecat or crew member name
+	Bead      string `json:"bead,omitempty"`    // bead hooked during the session
+	Cwd       string `json:"cwd,omitempty"`
+
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended,omitempty"` // last transcript entry
+
+	// Source is the transcript the record was built from, and SourceModTime
+	// its mtime, so re-indexing only re-parses transcripts that grew.
+	Source        string    `json:"source"`
+	SourceModTime time.Time `json:"source_mod_time"`
+	ArchivedAt    time.Time `json:"archived_at"`
+
+	Turns        int            `json:"turns"`
+	Tools        map[string]int `json:"tools,omitempty"`
+	FilesTouched []string       `json:"files_touched,omitempty"`
+}
+
+// Archive is the on-disk transcript archive for a town.
+//
+// Layout:
+//
+//	<town>/.seance/index.jsonl            one Record per archived session
+//	<town>/.seance/sessions/<id>.jsonl    the session's entries
+type Archive struct {
+	dir string
+}
+
+// Open returns the archive for a town. The directory is created lazily.
+func Open(townRoot string) *Archive {
+	return &Archive{dir: filepath.Join(townRoot, ArchiveDir)}
+}
+
+func (a *Archive) indexPath() string {
+	return filepath.Join(a.dir, "index.jsonl")
+}
+
+func (a *Archive) sessionPath(sessionID string) string {
+	return filepath.Join(a.dir, "sessions", filepath.Base(sessionID)+".jsonl")
+}
+
+// List returns all archived records, most recently started first.
+func (a *Archive) List() ([]Record, error) {
+	f, err := os.Open(a.indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening archive index: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading archive index: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Started.After(records[j].Started)
+	})
+	return records, nil
+}
+
+// Get returns the record for a session, or nil if it isn't archived.
+func (a *Archive) Get(sessionID string) (*Record, error) {
+	records, err := a.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].SessionID == sessionID {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Entries returns the archived entries for a session.
+func (a *Archive) Entries(sessionID string) ([]Entry, error) {
+	f, err := os.Open(a.sessionPath(sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening archived session: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Put archives a session's entries and upserts its record in the index.
+// Summary fields on rec (turns, tools, files, ended) are derived from entries.
+func (a *Archive) Put(rec Record, entries []Entry) error {
+	rec, err := a.WriteSession(rec, entries)
+	if err != nil {
+		return err
+	}
+	return a.Index(rec)
+}
+
+// WriteSession archives a session's entries without touching the index and
+// returns rec with its summary fields (turns, tools, files, ended) derived
+// from entries. Pass the result to Index to make the session queryable;
+// callers archiving many sessions index them in one write.
+func (a *Archive) WriteSession(rec Record, entries []Entry) (Record, error) {
+	if rec.SessionID == "" {
+		return rec, fmt.Errorf("record missing session_id")
+	}
+
+	rec.Tools = ToolCounts(entries)
+	rec.FilesTouched = FilesTouched(entries)
+	rec.Turns = 0
+	for _, e := range entries {
+		if e.Kind == KindUser {
+			rec.Turns++
+		}
+		if e.Timestamp.After(rec.Ended) {
+			rec.Ended = e.Timestamp
+		}
+	}
+	if rec.ArchivedAt.IsZero() {
+		rec.ArchivedAt = time.Now().UTC()
+	}
+
+	if err := os.MkdirAll(filepath.Join(a.dir, "sessions"), 0755); err != nil {
+		return rec, fmt.Errorf("creating archive directory: %w", err)
+	}
+
+	fl := flock.New(a.indexPath() + ".lock")
+	if err := fl.Lock(); err != nil {
+		return rec, fmt.Errorf("acquiring archive lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	if err := writeJSONLines(a.sessionPath(rec.SessionID), entries); err != nil {
+		return rec, fmt.Errorf("writing archived session: %w", err)
+	}
+	return rec, nil
+}
+
+// Index upserts records in the archive index with a single rewrite.
+func (a *Archive) Index(recs ...Record) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		return fmt.Errorf("creating archive directory: %w", err)
+	}
+
+	fl := flock.New(a.indexPath() + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring archive lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	records, err := a.List()
+	if err != nil {
+		return err
+	}
+	pos := make(map[string]int, len(records))
+	for i, r := range records {
+		pos[r.SessionID] = i
+	}
+	for _, rec := range recs {
+		if i, ok := pos[rec.SessionID]; ok {
+			records[i] = rec
+			continue
+		}
+		pos[rec.SessionID] = len(records)
+		records = append(records, rec)
+	}
+
+	if err := writeJSONLines(a.indexPath(), records); err != nil {
+		return fmt.Errorf("writing archive index: %w", err)
+	}
+	return nil
+}
+
+// writeJSONLines atomically writes items as JSON lines via a temp file.
+func writeJSONLines[T any](path string, items []T) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			f.Close()
+			_ = os.Remove(tmp)
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
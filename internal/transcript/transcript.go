@@ -0,0 +1,234 @@
+// Package transcript parses Claude Code session transcripts and keeps a
+// local, searchable archive of them per agent identity.
+//
+// The archive lets `gt seance` answer common handoff questions (what did my
+// predecessor run, which files did it touch, what did it decide) without
+// resuming the predecessor session in an LLM.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Entry kinds.
+const (
+	KindUser      = "user"
+	KindAssistant = "assistant"
+	KindTool      = "tool"
+)
+
+// maxEntryText caps the text kept per entry so archives stay small.
+const maxEntryText = 4000
+
+// fileTools are the tools whose file_path input counts as a touched file.
+var fileTools = map[string]bool{
+	"Write":        true,
+	"Edit":         true,
+	"MultiEdit":    true,
+	"NotebookEdit": true,
+}
+
+// decisionPattern marks assistant sentences that record a decision.
+var decisionPattern = regexp.MustCompile(`(?i)\b(decided|decision|chose|choosing|going with|opted|instead of|rather than|trade-?off|the plan is|i'll use|we'll use)\b`)
+
+// Entry is one searchable item from a transcript.
+type Entry struct {
+	Timestamp time.Time `json:"ts,omitempty"`
+	Kind      string    `json:"kind"`           // user, assistant, tool
+	Tool      string    `json:"tool,omitempty"` // tool name for tool entries
+	File      string    `json:"file,omitempty"` // file path for file-writing tools
+	Text      string    `json:"text"`
+}
+
+// rawLine is the subset of a Claude Code transcript line we read.
+type rawLine struct {
+	Type      string      `json:"type"`
+	Timestamp string      `json:"timestamp"`
+	Message   *rawMessage `json:"message,omitempty"`
+}
+
+type rawMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type rawBlock struct {
+	Type  string                 `json:"type"`
+	Text  string                 `json:"text,omitempty"`
+	Name  string                 `json:"name,omitempty"`
+	Input map[string]interface{} `json:"input,omitempty"`
+}
+
+// Parse reads a Claude Code transcript (.jsonl) into archive entries.
+// Tool results and system lines are skipped; malformed lines are ignored.
+func Parse(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from session discovery
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	// Transcript lines can be very large (tool results, file contents)
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var line rawLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Message == nil || (line.Type != KindUser && line.Type != KindAssistant) {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, line.Timestamp)
+		entries = append(entries, parseMessage(line.Type, ts, line.Message.Content)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("reading transcript: %w", err)
+	}
+	return entries, nil
+}
+
+// parseMessage converts message content (a string or a block array) into entries.
+func parseMessage(kind string, ts time.Time, content json.RawMessage) []Entry {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		if text = strings.TrimSpace(text); text == "" {
+			return nil
+		}
+		return []Entry{{Timestamp: ts, Kind: kind, Text: truncate(text)}}
+	}
+
+	var blocks []rawBlock
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return nil
+	}
+
+	var entries []Entry
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			if t := strings.TrimSpace(b.Text); t != "" {
+				entries = append(entries, Entry{Timestamp: ts, Kind: kind, Text: truncate(t)})
+			}
+		case "tool_use":
+			e := Entry{Timestamp: ts, Kind: KindTool, Tool: b.Name, Text: truncate(describeToolInput(b.Input))}
+			if fileTools[b.Name] {
+				e.File = inputString(b.Input, "file_path", "notebook_path")
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// describeToolInput renders the interesting part of a tool call's input.
+func describeToolInput(input map[string]interface{}) string {
+	if s := inputString(input, "command", "file_path", "notebook_path", "pattern", "url", "query", "prompt"); s != "" {
+		return s
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func inputString(input map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := input[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func truncate(s string) string {
+	if len(s) <= maxEntryText {
+		return s
+	}
+	// Cut on a rune boundary so the archive stays valid UTF-8
+	return util.TruncateUTF8(s, maxEntryText) + "…"
+}
+
+// ToolCounts returns how many times each tool was called.
+func ToolCounts(entries []Entry) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.Kind == KindTool && e.Tool != "" {
+			counts[e.Tool]++
+		}
+	}
+	return counts
+}
+
+// FilesTouched returns the sorted, de-duplicated files written or edited.
+func FilesTouched(entries []Entry) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, e := range entries {
+		if e.File != "" && !seen[e.File] {
+			seen[e.File] = true
+			files = append(files, e.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Decisions returns assistant sentences that look like recorded decisions.
+func Decisions(entries []Entry) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.Kind != KindAssistant {
+			continue
+		}
+		for _, sentence := range splitSentences(e.Text) {
+			if decisionPattern.MatchString(sentence) {
+				out = append(out, Entry{Timestamp: e.Timestamp, Kind: KindAssistant, Text: sentence})
+			}
+		}
+	}
+	return out
+}
+
+// Grep returns entries whose text, tool or file matches re.
+func Grep(entries []Entry, re *regexp.Regexp) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if re.MatchString(e.Text) || (e.Tool != "" && re.MatchString(e.Tool)) || (e.File != "" && re.MatchString(e.File)) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// splitSentences splits text on line breaks and sentence-ending punctuation.
+func splitSentences(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		start := 0
+		for i := 0; i < len(line); i++ {
+			if (line[i] == '.' || line[i] == '!' || line[i] == '?') && (i+1 == len(line) || line[i+1] == ' ') {
+				if s := strings.TrimSpace(line[start : i+1]); s != "" {
+					out = append(out, s)
+				}
+				start = i + 1
+			}
+		}
+		if s := strings.TrimSpace(line[start:]); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+const sampleTranscript = `{"type":"summary","summary":"ignored"}
+{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"Fix the flaky migration test"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Looking at it. I decided to pin the schema version rather than regenerate it. Running tests next."},{"type":"tool_use","name":"Bash","input":{"command":"go test ./internal/migrate/..."}}]}}
+{"type":"user","timestamp":"2026-01-02T10:00:10Z","message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:20Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Edit","input":{"file_path":"/work/migrate/schema.go","old_string":"a","new_string":"b"}},{"type":"tool_use","name":"Write","input":{"file_path":"/work/migrate/schema_test.go","content":"x"}},{"type":"tool_use","name":"Edit","input":{"file_path":"/work/migrate/schema.go","old_string":"b","new_string":"c"}}]}}
+not json
+`
+
+func writeSample(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(sampleTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(writeSample(t))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// user text, assistant text, Bash, Edit, Write, Edit (tool_result skipped)
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Kind != KindUser || entries[0].Text != "Fix the flaky migration test" {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	if entries[2].Kind != KindTool || entries[2].Tool != "Bash" || entries[2].Text != "go test ./internal/migrate/..." {
+		t.Errorf("entries[2] = %+v", entries[2])
+	}
+	if entries[3].File != "/work/migrate/schema.go" {
+		t.Errorf("entries[3].File = %q", entries[3].File)
+	}
+	if entries[2].File != "" {
+		t.Errorf("Bash entry should not record a file, got %q", entries[2].File)
+	}
+	want := time.Date(2026, 1, 2, 10, 0, 5, 0, time.UTC)
+	if !entries[1].Timestamp.Equal(want) {
+		t.Errorf("entries[1].Timestamp = %v, want %v", entries[1].Timestamp, want)
+	}
+}
+
+func TestTruncateKeepsUTF8(t *testing.T) {
+	s := strings.Repeat("a", maxEntryText-1) + "é and more"
+	got := truncate(s)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncate split a rune: %q", got[len(got)-8:])
+	}
+	if got != strings.Repeat("a", maxEntryText-1)+"…" {
+		t.Errorf("truncate = ...%q", got[len(got)-8:])
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	entries, err := Parse(writeSample(t))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	counts := ToolCounts(entries)
+	if counts["Edit"] != 2 || counts["Bash"] != 1 || counts["Write"] != 1 {
+		t.Errorf("ToolCounts = %v", counts)
+	}
+
+	files := FilesTouched(entries)
+	if len(files) != 2 || files[0] != "/work/migrate/schema.go" || files[1] != "/work/migrate/schema_test.go" {
+		t.Errorf("FilesTouched = %v", files)
+	}
+
+	decisions := Decisions(entries)
+	if len(decisions) != 1 || decisions[0].Text != "I decided to pin the schema version rather than regenerate it." {
+		t.Errorf("Decisions = %+v", decisions)
+	}
+
+	matches := Grep(entries, regexp.MustCompile("(?i)SCHEMA_test"))
+	if len(matches) != 1 || matches[0].Tool != "Write" {
+		t.Errorf("Grep = %+v", matches)
+	}
+}
+
+func TestArchiveWriteSessionsThenIndex(t *testing.T) {
+	archive := Open(t.TempDir())
+	entries, err := Parse(writeSample(t))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var written []Record
+	for _, id := range []string{"aaa", "bbb"} {
+		rec, err := archive.WriteSession(Record{SessionID: id}, entries)
+		if err != nil {
+			t.Fatalf("WriteSession: %v", err)
+		}
+		if rec.Turns != 1 || rec.Tools["Edit"] != 2 {
+			t.Errorf("derived summary wrong: %+v", rec)
+		}
+		written = append(written, rec)
+	}
+	if records, _ := archive.List(); len(records) != 0 {
+		t.Fatalf("sessions indexed before Index: %+v", records)
+	}
+
+	if err := archive.Index(written...); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if err := archive.Index(written[0]); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if records, _ := archive.List(); len(records) != 2 {
+		t.Errorf("expected 2 indexed sessions, got %+v", records)
+	}
+}
+
+func TestArchivePutAndList(t *testing.T) {
+	townRoot := t.TempDir()
+	archive := Open(townRoot)
+
+	records, err := archive.List()
+	if err != nil || records != nil {
+		t.Fatalf("empty archive: records=%v err=%v", records, err)
+	}
+
+	entries, err := Parse(writeSample(t))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	older := Record{SessionID: "aaa", Actor: "gastown/polecats/Toast", Started: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newer := Record{SessionID: "bbb", Actor: "gastown/crew/max", Started: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+	if err := archive.Put(older, entries); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := archive.Put(newer, entries[:1]); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	records, err = archive.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(records) != 2 || records[0].SessionID != "bbb" {
+		t.Fatalf("expected newest first, got %+v", records)
+	}
+
+	rec, err := archive.Get("aaa")
+	if err != nil || rec == nil {
+		t.Fatalf("Get: rec=%v err=%v", rec, err)
+	}
+	if rec.Turns != 1 || rec.Tools["Edit"] != 2 || len(rec.FilesTouched) != 2 {
+		t.Errorf("derived summary wrong: %+v", rec)
+	}
+	if !rec.Ended.Equal(time.Date(2026, 1, 2, 10, 0, 20, 0, time.UTC)) {
+		t.Errorf("Ended = %v", rec.Ended)
+	}
+
+	// Re-putting a session replaces it rather than duplicating it
+	older.Bead = "gt-abc"
+	if err := archive.Put(older, entries[:2]); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	records, _ = archive.List()
+	if len(records) != 2 {
+		t.Fatalf("expected upsert, got %d records", len(records))
+	}
+	got, _ := archive.Entries("aaa")
+	if len(got) != 2 {
+		t.Errorf("expected 2 archived entries after upsert, got %d", len(got))
+	}
+	if rec, _ := archive.Get("aaa"); rec.Bead != "gt-abc" {
+		t.Errorf("Bead = %q, want gt-abc", rec.Bead)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/krc"
 )
 
 // PrintOptions controls filtering and behavior for PrintGtEvents.
@@ -23,7 +25,8 @@ type PrintOptions struct {
 	Ctx    context.Context // optional: controls follow-mode lifecycle; nil uses signal.NotifyContext
 }
 
-// PrintGtEvents reads .events.jsonl and prints events to stdout.
+// PrintGtEvents reads .events.jsonl and prints events to stdout. With
+// opts.Since, events KRC has moved to the archive are included.
 // When opts.Follow is true, it tails the file for new events after printing
 // the initial batch, polling every 200ms. Canceled via opts.Ctx or SIGINT.
 func PrintGtEvents(townRoot string, opts PrintOptions) error {
@@ -45,6 +48,20 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 	}
 
 	var events []Event
+	if !sinceTime.IsZero() {
+		err := krc.ScanArchive(townRoot, krc.ArchiveSource(eventsPath), sinceTime, func(line []byte) error {
+			if event := parseGtEventLine(string(line)); event != nil {
+				if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
+					events = append(events, *event)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("reading archived events: %w", err)
+		}
+	}
+
 	scanner := bufio.NewScanner(file)
 	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
 
@@ -9,6 +9,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/krc"
 )
 
 // writeTestEvents writes GtEvent JSON lines to a temporary .events.jsonl file
@@ -144,6 +146,40 @@ func TestPrintGtEvents_SinceFilter(t *testing.T) {
 	}
 }
 
+func TestPrintGtEvents_SinceReadsArchive(t *testing.T) {
+	now := time.Now()
+	townRoot := writeTestEvents(t, []GtEvent{
+		{Timestamp: now.Add(-48 * time.Hour).Format(time.RFC3339), Source: "test", Type: "create", Actor: "old", Visibility: "feed", Payload: map[string]interface{}{"message": "archived event"}},
+		{Timestamp: now.Add(-30 * time.Second).Format(time.RFC3339), Source: "test", Type: "create", Actor: "new", Visibility: "feed", Payload: map[string]interface{}{"message": "new event"}},
+	})
+	config := &krc.Config{DefaultTTL: 24 * time.Hour, ColdTTL: 90 * 24 * time.Hour, PruneInterval: time.Hour}
+	if result, err := krc.NewPruner(townRoot, config).Prune(); err != nil || result.EventsArchived != 1 {
+		t.Fatalf("prune = %+v, %v", result, err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := PrintGtEvents(townRoot, PrintOptions{Limit: 100, Since: "72h"})
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("PrintGtEvents returned error: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	n, _ := r.Read(buf)
+	output := string(buf[:n])
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "archived event") {
+		t.Fatalf("expected the archived event before the live one, got %q", output)
+	}
+}
+
 func TestPrintGtEvents_TypeFilter(t *testing.T) {
 	now := time.Now()
 	townRoot := writeTestEvents(t, []GtEvent{
@@ -0,0 +1,16 @@
+package util
+
+import "unicode/utf8"
+
+// TruncateUTF8 returns s cut to at most max bytes. The cut backs up to a
+// rune boundary so the result stays valid UTF-8.
+func TruncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
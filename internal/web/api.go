@@ -15,7 +15,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/recording"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 
@@ -123,8 +125,14 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleReady(w, r)
 	case path == "/events" && r.Method == http.MethodGet:
 		h.handleSSE(w, r)
+	case path == "/events/query" && r.Method == http.MethodGet:
+		h.handleEventsQuery(w, r)
 	case path == "/session/preview" && r.Method == http.MethodGet:
 		h.handleSessionPreview(w, r)
+	case path == "/recordings" && r.Method == http.MethodGet:
+		h.handleRecordings(w, r)
+	case path == "/recordings/cast" && r.Method == http.MethodGet:
+		h.handleRecordingCast(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -1947,6 +1955,52 @@ func (h *APIHandler) handleReady(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// maxEventsQueryLen bounds /api/events/query input.
+const maxEventsQueryLen = 1000
+
+// handleEventsQuery runs an event query (see gt events query --help) and
+// returns its JSON result.
+func (h *APIHandler) handleEventsQuery(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		h.sendError(w, "Missing query (q)", http.StatusBadRequest)
+		return
+	}
+	if len(q) > maxEventsQueryLen || strings.ContainsAny(q, "\x00\r\n") {
+		h.sendError(w, "Invalid query", http.StatusBadRequest)
+		return
+	}
+
+	// "--" keeps a leading negated filter (-actor:...) from parsing as a flag.
+	output, err := h.runGtCommand(r.Context(), 20*time.Second, []string{"events", "query", "--json", "--", q})
+	if err != nil {
+		h.sendError(w, "Query failed: "+commandErrorLine(output, err), http.StatusBadRequest)
+		return
+	}
+
+	// Output is stdout followed by any stderr warnings; decode just the
+	// leading JSON document.
+	var result json.RawMessage
+	if err := json.NewDecoder(strings.NewReader(output)).Decode(&result); err != nil {
+		h.sendError(w, "Failed to parse query result", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(result)
+}
+
+// commandErrorLine picks the "Error: ..." line cobra prints from command
+// output, falling back to err.
+func commandErrorLine(output string, err error) string {
+	for _, line := range strings.Split(output, "\n") {
+		if msg, ok := strings.CutPrefix(strings.TrimSpace(line), "Error: "); ok {
+			return msg
+		}
+	}
+	return err.Error()
+}
+
 // SessionPreviewResponse is the response for /api/session/preview.
 type SessionPreviewResponse struct {
 	Session   string `json:"session"`
@@ -2001,6 +2055,74 @@ func (h *APIHandler) handleSessionPreview(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// RecordingsResponse is the response for /api/recordings.
+type RecordingsResponse struct {
+	Agents []RecordingAgent `json:"agents"`
+}
+
+// RecordingAgent lists one agent's recorded segments, oldest first.
+type RecordingAgent struct {
+	Agent    string              `json:"agent"`
+	Segments []recording.Segment `json:"segments"`
+}
+
+// handleRecordings lists pane recordings (see gt session replay).
+func (h *APIHandler) handleRecordings(w http.ResponseWriter, r *http.Request) {
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusNotFound)
+		return
+	}
+	agents, err := recording.ListAgents(townRoot)
+	if err != nil {
+		h.sendError(w, "Failed to list recordings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := RecordingsResponse{Agents: []RecordingAgent{}}
+	for _, agent := range agents {
+		segs, err := recording.ListSegments(townRoot, agent)
+		if err != nil || len(segs) == 0 {
+			continue
+		}
+		resp.Agents = append(resp.Agents, RecordingAgent{Agent: agent, Segments: segs})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleRecordingCast serves one recording segment as asciicast v2. Only
+// segments listed for the agent are served, so the parameters can't
+// reach other files.
+func (h *APIHandler) handleRecordingCast(w http.ResponseWriter, r *http.Request) {
+	agent := r.URL.Query().Get("agent")
+	name := r.URL.Query().Get("segment")
+	if !recording.ValidAgent(agent) || name == "" {
+		h.sendError(w, "Invalid agent or segment", http.StatusBadRequest)
+		return
+	}
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusNotFound)
+		return
+	}
+	seg, err := recording.FindSegment(townRoot, agent, name)
+	if err != nil {
+		h.sendError(w, "Recording not found", http.StatusNotFound)
+		return
+	}
+	f, err := os.Open(seg.Path)
+	if err != nil {
+		h.sendError(w, "Recording not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	w.Header().Set("Cache-Control", "no-cache") // the latest segment may still be growing
+	http.ServeContent(w, r, seg.Name, seg.End, f)
+}
+
 // parseCommandArgs splits a command string into args, respecting quotes.
 func parseCommandArgs(command string) []string {
 	var args []string
@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/recording"
 )
 
 func TestValidateCommand(t *testing.T) {
@@ -360,6 +365,80 @@ func TestAPIHandler_NotFound(t *testing.T) {
 	}
 }
 
+func TestAPIHandler_EventsQuery_InvalidInput(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+
+	for _, q := range []string{"", "%20%20", "type:done%0A--help", strings.Repeat("a", maxEventsQueryLen+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/events/query?q="+q, nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("q=%.20q: status = %d, want %d", q, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleRecordings(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644)
+	rec, err := recording.NewRecorder(townRoot, "gastown/nux", recording.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = rec.Write([]byte("hello"))
+	_ = rec.Close()
+	// A file next to the recordings that must never be served.
+	_ = os.WriteFile(filepath.Join(townRoot, recording.Dir, "secret.cast"), []byte("x"), 0644)
+
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler.workDir = townRoot
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/recordings", nil))
+	var resp RecordingsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Agents) != 1 || resp.Agents[0].Agent != "gastown/nux" || len(resp.Agents[0].Segments) != 1 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	segment := resp.Agents[0].Segments[0].Name
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/recordings/cast?agent=gastown/nux&segment="+segment, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"o","hello"`) {
+		t.Errorf("cast: status %d, body %q", w.Code, w.Body.String())
+	}
+
+	for _, q := range []string{
+		"agent=gastown/nux&segment=../secret.cast",
+		"agent=gastown/..&segment=secret.cast",
+		"agent=../..&segment=" + segment,
+		"agent=gastown/nux&segment=",
+	} {
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/recordings/cast?"+q, nil))
+		if w.Code == http.StatusOK {
+			t.Errorf("%s: served %q", q, w.Body.String())
+		}
+	}
+}
+
+func TestCommandErrorLine(t *testing.T) {
+	output := "WARNING: something\nError: parsing query: type: missing value\nUsage:\n  gt events query <query> [flags]\n"
+	if got := commandErrorLine(output, errors.New("command failed")); got != "parsing query: type: missing value" {
+		t.Errorf("commandErrorLine = %q", got)
+	}
+	if got := commandErrorLine("", errors.New("command timed out")); got != "command timed out" {
+		t.Errorf("fallback = %q", got)
+	}
+}
+
 func TestGetCommandList(t *testing.T) {
 	commands := GetCommandList()
 
@@ -42,6 +42,8 @@ var AllowedCommands = map[string]CommandMeta{
 	"info":        {Safe: true, Desc: "Show workspace info", Category: "Status"},
 	"log":         {Safe: true, Desc: "View logs", Category: "Diagnostics"},
 	"audit":       {Safe: true, Desc: "View audit log", Category: "Diagnostics"},
+	"audit verify": {Safe: true, Desc: "Verify the audit trail's hash chain", Category: "Diagnostics"},
+	"events query": {Safe: true, Desc: "Query the event logs", Category: "Diagnostics", Args: "<query>"},
 
 	// Polecat read-only
 	"polecat list --all": {Safe: true, Desc: "List all polecats", Category: "Polecats"},
@@ -16,6 +16,8 @@ import (
 	"github.com/steveyegge/gastown/internal/activity"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -531,6 +533,80 @@ func (f *LiveConvoyFetcher) FetchMergeQueue() ([]MergeQueueRow, error) {
 	return result, nil
 }
 
+// mqStatsWindow is the window the dashboard's merge queue stats cover.
+const mqStatsWindow = 7 * 24 * time.Hour
+
+// FetchMQStats summarizes each registered rig's merge queue over the last
+// week from the refinery's metrics (see gt mq stats).
+func (f *LiveConvoyFetcher) FetchMQStats() ([]MQStatsRow, error) {
+	rigsConfigPath := filepath.Join(f.townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	now := time.Now()
+	since := now.Add(-mqStatsWindow)
+	var result []MQStatsRow
+	for rigName := range rigsConfig.Rigs {
+		rigPath := filepath.Join(f.townRoot, rigName)
+		attempts, err := refinery.LoadMQAttempts(rigPath, since)
+		if err != nil {
+			log.Printf("dashboard: loading merge queue metrics for %s: %v", rigName, err)
+			continue
+		}
+		if len(attempts) == 0 {
+			continue
+		}
+		eng := refinery.NewEngineer(&rig.Rig{Name: rigName, Path: rigPath})
+		if err := eng.LoadConfig(); err != nil {
+			log.Printf("dashboard: loading merge queue config for %s: %v", rigName, err)
+		}
+		result = append(result, mqStatsRow(rigName, refinery.ComputeMQStats(attempts, since, now, eng.Config().LeadTimeSLA)))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Rig < result[j].Rig })
+	return result, nil
+}
+
+// mqStatsRow formats merge queue stats for the dashboard.
+func mqStatsRow(rigName string, s *refinery.MQStats) MQStatsRow {
+	row := MQStatsRow{
+		Rig:         rigName,
+		Merged:      s.Merged,
+		Throughput:  fmt.Sprintf("%.1f", s.ThroughputPerDay),
+		LeadTimeP50: formatMQDuration(s.LeadTimeP50),
+		LeadTimeP95: formatMQDuration(s.LeadTimeP95),
+		FailureRate: fmt.Sprintf("%.0f%%", s.FailureRate*100),
+		ColorClass:  "mq-green",
+	}
+	if s.SLA > 0 && s.Merged > 0 {
+		row.WithinSLA = fmt.Sprintf("%.0f%%", s.WithinSLA*100)
+	}
+	switch {
+	case s.FailureRate >= 0.5:
+		row.ColorClass = "mq-red"
+	case s.FailureRate >= 0.2 || (s.SLA > 0 && s.Merged > 0 && s.WithinSLA < 0.9):
+		row.ColorClass = "mq-yellow"
+	}
+	return row
+}
+
+// formatMQDuration formats a lead time compactly ("-", "45s", "12m", "3.5h", "2.1d").
+func formatMQDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+}
+
 // gitURLToRepoPath converts a git URL to owner/repo format.
 // Supports HTTPS (https://github.com/owner/repo.git) and
 // SSH (git@github.com:owner/repo.git) formats.
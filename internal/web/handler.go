@@ -21,6 +21,7 @@ var staticFiles embed.FS
 type ConvoyFetcher interface {
 	FetchConvoys() ([]ConvoyRow, error)
 	FetchMergeQueue() ([]MergeQueueRow, error)
+	FetchMQStats() ([]MQStatsRow, error)
 	FetchWorkers() ([]WorkerRow, error)
 	FetchMail() ([]MailRow, error)
 	FetchRigs() ([]RigRow, error)
@@ -68,6 +69,7 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var (
 		convoys     []ConvoyRow
 		mergeQueue  []MergeQueueRow
+		mqStats     []MQStatsRow
 		workers     []WorkerRow
 		mail        []MailRow
 		rigs        []RigRow
@@ -84,7 +86,7 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Run all fetches in parallel with error logging
-	wg.Add(14)
+	wg.Add(15)
 
 	go func() {
 		defer wg.Done()
@@ -102,6 +104,14 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			log.Printf("dashboard: FetchMergeQueue failed: %v", err)
 		}
 	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		mqStats, err = h.fetcher.FetchMQStats()
+		if err != nil {
+			log.Printf("dashboard: FetchMQStats failed: %v", err)
+		}
+	}()
 	go func() {
 		defer wg.Done()
 		var err error
@@ -222,6 +232,7 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	data := ConvoyData{
 		Convoys:     convoys,
 		MergeQueue:  mergeQueue,
+		MQStats:     mqStats,
 		Workers:     workers,
 		Mail:        mail,
 		Rigs:        rigs,
@@ -20,6 +20,7 @@ var errFetchFailed = errors.New("fetch failed")
 type MockConvoyFetcher struct {
 	Convoys     []ConvoyRow
 	MergeQueue  []MergeQueueRow
+	MQStats     []MQStatsRow
 	Workers     []WorkerRow
 	Mail        []MailRow
 	Rigs        []RigRow
@@ -43,6 +44,10 @@ func (m *MockConvoyFetcher) FetchMergeQueue() ([]MergeQueueRow, error) {
 	return m.MergeQueue, nil
 }
 
+func (m *MockConvoyFetcher) FetchMQStats() ([]MQStatsRow, error) {
+	return m.MQStats, nil
+}
+
 func (m *MockConvoyFetcher) FetchWorkers() ([]WorkerRow, error) {
 	return m.Workers, nil
 }
@@ -374,6 +379,37 @@ func TestConvoyHandler_EmptyMergeQueue(t *testing.T) {
 	}
 }
 
+func TestConvoyHandler_MQStatsRendering(t *testing.T) {
+	mock := &MockConvoyFetcher{
+		MQStats: []MQStatsRow{
+			{Rig: "gastown", Merged: 24, Throughput: "3.4", LeadTimeP50: "42m", LeadTimeP95: "3.5h", FailureRate: "12%", WithinSLA: "96%", ColorClass: "mq-green"},
+			{Rig: "roxas", Merged: 2, Throughput: "0.3", LeadTimeP50: "2.1d", LeadTimeP95: "2.1d", FailureRate: "60%", ColorClass: "mq-red"},
+		},
+	}
+
+	handler, err := NewConvoyHandler(mock, 8*time.Second)
+	if err != nil {
+		t.Fatalf("NewConvoyHandler() error = %v", err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	body := w.Body.String()
+	for _, want := range []string{"Lead p95", "3.5h", "12%", "96%", "2.1d", "mq-red"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Response should contain %q", want)
+		}
+	}
+
+	// No stats table when no rig has processed MRs
+	handler, _ = NewConvoyHandler(&MockConvoyFetcher{}, 8*time.Second)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if strings.Contains(w.Body.String(), "Lead p95") {
+		t.Error("Response should omit merge queue stats when there are none")
+	}
+}
+
 // Integration tests for polecat workers rendering
 
 func TestConvoyHandler_PolecatWorkersRendering(t *testing.T) {
@@ -1059,6 +1095,10 @@ func (m *MockConvoyFetcherWithErrors) FetchActivity() ([]ActivityRow, error) {
 	return nil, nil
 }
 
+func (m *MockConvoyFetcherWithErrors) FetchMQStats() ([]MQStatsRow, error) {
+	return nil, nil
+}
+
 // TestConvoyHandler_TemplateErrorReturns500 verifies that template execution errors
 // return a proper 500 status code, not 200 (which would happen if we wrote directly
 // to the ResponseWriter and it failed mid-execution).
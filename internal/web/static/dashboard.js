@@ -2965,6 +2965,209 @@
         initTimelineFilters();
     });
 
+    // ============================================
+    // EVENT QUERY PANEL
+    // ============================================
+    var lastEventsQueryHTML = null;
+
+    function runEventsQuery(e) {
+        if (e) e.preventDefault();
+        var input = document.getElementById('events-query-input');
+        var results = document.getElementById('events-query-results');
+        var q = input ? input.value.trim() : '';
+        if (!q || !results) return;
+
+        results.innerHTML = '<div class="loading-state">Running query...</div>';
+        fetch('/api/events/query?q=' + encodeURIComponent(q))
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                if (data.error) {
+                    lastEventsQueryHTML = '<div class="events-query-error">' + escapeHtml(data.error) + '</div>';
+                } else {
+                    lastEventsQueryHTML = renderEventsQuery(data);
+                }
+                results.innerHTML = lastEventsQueryHTML;
+            })
+            .catch(function(err) {
+                results.innerHTML = '<div class="events-query-error">' + escapeHtml(err.message) + '</div>';
+            });
+    }
+    window.runEventsQuery = runEventsQuery;
+
+    function renderEventsQuery(data) {
+        var summary = '<div class="events-query-summary">' + data.matched + ' matching events</div>';
+
+        if (data.counted) {
+            var by = data.by || [];
+            if (by.length === 0 && !data.per) {
+                return '<div class="events-query-total">' + data.groups[0].count + '</div>' + summary;
+            }
+            var html = '<table><thead><tr>';
+            if (data.per) html += '<th>Bucket (' + escapeHtml(data.per) + ')</th>';
+            by.forEach(function(f) { html += '<th>' + escapeHtml(f) + '</th>'; });
+            html += '<th>Count</th></tr></thead><tbody>';
+            (data.groups || []).forEach(function(g) {
+                html += '<tr>';
+                if (data.per) html += '<td>' + escapeHtml(new Date(g.bucket).toLocaleString()) + '</td>';
+                (g.keys || []).forEach(function(k) { html += '<td>' + escapeHtml(k || '-') + '</td>'; });
+                html += '<td>' + g.count + '</td></tr>';
+            });
+            return html + '</tbody></table>' + summary;
+        }
+
+        var records = data.records || [];
+        if (records.length === 0) {
+            return '<div class="empty-state"><p>No matching events</p></div>';
+        }
+        var rows = '<table><thead><tr><th>Time</th><th>Type</th><th>Actor</th><th>Detail</th></tr></thead><tbody>';
+        records.slice().reverse().forEach(function(r) {
+            var detail = r.context || '';
+            if (!detail && r.payload) {
+                detail = Object.keys(r.payload).sort().map(function(k) {
+                    return k + '=' + (typeof r.payload[k] === 'object' ? JSON.stringify(r.payload[k]) : r.payload[k]);
+                }).join(' ');
+            }
+            rows += '<tr>' +
+                '<td>' + escapeHtml(new Date(r.time).toLocaleString()) + '</td>' +
+                '<td>' + escapeHtml(r.type) + '</td>' +
+                '<td>' + escapeHtml(r.actor) + '</td>' +
+                '<td class="events-query-detail">' + escapeHtml(detail) + '</td></tr>';
+        });
+        return rows + '</tbody></table>' + summary;
+    }
+
+    // Morph resets the results area to its server-rendered state; restore it.
+    document.body.addEventListener('htmx:afterSwap', function() {
+        var results = document.getElementById('events-query-results');
+        if (results && lastEventsQueryHTML !== null) {
+            results.innerHTML = lastEventsQueryHTML;
+        }
+    });
+
+    // ============================================
+    // SESSION RECORDINGS
+    // ============================================
+    var lastRecordingsHTML = null;
+    var recordingRows = [];
+    var recordingPlayer = null;
+
+    function loadRecordings(e) {
+        if (e) e.preventDefault();
+        var list = document.getElementById('recordings-list');
+        var agentInput = document.getElementById('recordings-agent');
+        var atInput = document.getElementById('recordings-at');
+        if (!list) return;
+        var filter = agentInput ? agentInput.value.trim() : '';
+        var at = atInput && atInput.value ? new Date(atInput.value) : null;
+
+        list.innerHTML = '<div class="loading-state">Loading recordings...</div>';
+        fetch('/api/recordings')
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                if (data.error) {
+                    lastRecordingsHTML = '<div class="events-query-error">' + escapeHtml(data.error) + '</div>';
+                } else {
+                    lastRecordingsHTML = renderRecordings(data.agents || [], filter, at);
+                }
+                list.innerHTML = lastRecordingsHTML;
+            })
+            .catch(function(err) {
+                list.innerHTML = '<div class="events-query-error">' + escapeHtml(err.message) + '</div>';
+            });
+    }
+    window.loadRecordings = loadRecordings;
+
+    // renderRecordings lists segments newest first. With a time, only the
+    // segment recording at that time is listed, set to start there.
+    function renderRecordings(agents, filter, at) {
+        recordingRows = [];
+        agents.forEach(function(a) {
+            if (filter && a.agent.indexOf(filter) === -1) return;
+            (a.segments || []).forEach(function(seg) {
+                var start = new Date(seg.start), end = new Date(seg.end);
+                if (at && (at < start || at > end)) return;
+                recordingRows.push({
+                    agent: a.agent,
+                    segment: seg,
+                    offset: at ? (at - start) / 1000 : 0
+                });
+            });
+        });
+        if (recordingRows.length === 0) {
+            return '<div class="empty-state"><p>' + (at ? 'Nothing recorded at that time' : 'No recordings') + '</p></div>';
+        }
+        recordingRows.sort(function(x, y) { return new Date(y.segment.start) - new Date(x.segment.start); });
+
+        var rows = '<table><thead><tr><th>Agent</th><th>Started</th><th>Last output</th><th>Size</th><th></th></tr></thead><tbody>';
+        recordingRows.forEach(function(r, i) {
+            rows += '<tr>' +
+                '<td>' + escapeHtml(r.agent) + '</td>' +
+                '<td>' + escapeHtml(new Date(r.segment.start).toLocaleString()) + '</td>' +
+                '<td>' + escapeHtml(new Date(r.segment.end).toLocaleTimeString()) + '</td>' +
+                '<td class="events-query-detail">' + Math.max(1, Math.round(r.segment.bytes / 1024)) + ' KB</td>' +
+                '<td><button class="recording-play-btn" data-recording="' + i + '">▶ Play</button></td></tr>';
+        });
+        return rows + '</tbody></table>';
+    }
+
+    document.addEventListener('click', function(e) {
+        var btn = e.target.closest('.recording-play-btn');
+        if (!btn) return;
+        var r = recordingRows[parseInt(btn.getAttribute('data-recording'), 10)];
+        if (r) openRecordingPlayer(r);
+    });
+
+    function openRecordingPlayer(r) {
+        var modal = document.getElementById('recording-modal');
+        var container = document.getElementById('recording-player');
+        var title = document.getElementById('recording-modal-title');
+        if (!modal || !container) return;
+        if (typeof AsciinemaPlayer === 'undefined') {
+            showToast('error', 'Player unavailable', 'asciinema-player failed to load');
+            return;
+        }
+        closeRecordingPlayer();
+        if (title) title.textContent = '🎬 ' + r.agent + ' — ' + new Date(r.segment.start).toLocaleString();
+        var src = '/api/recordings/cast?agent=' + encodeURIComponent(r.agent) +
+            '&segment=' + encodeURIComponent(r.segment.name);
+        recordingPlayer = AsciinemaPlayer.create(src, container, {
+            autoPlay: true,
+            startAt: Math.floor(r.offset),
+            idleTimeLimit: 2,
+            fit: 'width',
+            theme: 'monokai'
+        });
+        modal.style.display = 'flex';
+        window.pauseRefresh = true;
+    }
+
+    function closeRecordingPlayer() {
+        var modal = document.getElementById('recording-modal');
+        if (recordingPlayer) {
+            recordingPlayer.dispose();
+            recordingPlayer = null;
+        }
+        var container = document.getElementById('recording-player');
+        if (container) container.innerHTML = '';
+        if (modal && modal.style.display !== 'none') {
+            modal.style.display = 'none';
+            window.pauseRefresh = false;
+        }
+    }
+    window.closeRecordingPlayer = closeRecordingPlayer;
+
+    document.addEventListener('keydown', function(e) {
+        if (e.key === 'Escape') closeRecordingPlayer();
+    });
+
+    // Morph resets the list to its server-rendered state; restore it.
+    document.body.addEventListener('htmx:afterSwap', function() {
+        var list = document.getElementById('recordings-list');
+        if (list && lastRecordingsHTML !== null) {
+            list.innerHTML = lastRecordingsHTML;
+        }
+    });
+
     // ============================================
     // SESSION TERMINAL PREVIEW
     // ============================================
@@ -17,6 +17,7 @@ var templateFS embed.FS
 type ConvoyData struct {
 	Convoys     []ConvoyRow
 	MergeQueue  []MergeQueueRow
+	MQStats     []MQStatsRow
 	Workers     []WorkerRow
 	Mail        []MailRow
 	Rigs        []RigRow
@@ -194,6 +195,18 @@ type MergeQueueRow struct {
 	ColorClass string // "mq-green", "mq-yellow", "mq-red"
 }
 
+// MQStatsRow summarizes a rig's merge queue over the last week.
+type MQStatsRow struct {
+	Rig         string
+	Merged      int
+	Throughput  string // merges per day, e.g. "3.4"
+	LeadTimeP50 string // formatted, e.g. "42m"
+	LeadTimeP95 string
+	FailureRate string // e.g. "12%"
+	WithinSLA   string // e.g. "95%"; empty when the rig has no SLA
+	ColorClass  string // "mq-green", "mq-yellow", "mq-red" by failure rate
+}
+
 // ConvoyRow represents a single convoy in the dashboard.
 type ConvoyRow struct {
 	ID            string
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -75,6 +76,17 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 		return result
 	}
 
+	// Continue the trace gt done started, if the message carries one.
+	if parent := tracing.ParseTraceParent(payload.TraceParent); parent.IsValid() {
+		townRoot, _ := workspace.Find(workDir)
+		span := tracing.Start(townRoot, "witness.polecat_done", parent)
+		span.SetAttr("bead", payload.IssueID)
+		span.SetAttr("agent", fmt.Sprintf("%s/polecats/%s", rigName, payload.PolecatName))
+		span.SetAttr("mr", payload.MRID)
+		defer func() { span.End(result.Error) }()
+		payload.TraceParent = span.Context().TraceParent()
+	}
+
 	if stale, reason := isStalePolecatDone(workDir, rigName, payload.PolecatName, msg); stale {
 		result.Handled = true
 		result.Action = fmt.Sprintf("ignored stale POLECAT_DONE for %s (%s)", payload.PolecatName, reason)
@@ -361,7 +373,15 @@ func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.
 		return result
 	}
 
-	// Notify the polecat about the failure
+	// Notify the polecat about the failure. When the refinery parsed failing
+	// tests from gate reports, list those instead of the raw error.
+	detail := fmt.Sprintf("Error: %s\n", payload.Error)
+	if len(payload.FailingTests) > 0 {
+		detail = "Failing tests:\n"
+		for _, t := range payload.FailingTests {
+			detail += fmt.Sprintf("  - %s\n", t)
+		}
+	}
 	polecatAddr := fmt.Sprintf("%s/polecats/%s", rigName, payload.PolecatName)
 	notification := &mail.Message{
 		From:     fmt.Sprintf("%s/witness", rigName),
@@ -374,13 +394,12 @@ func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.
 Branch: %s
 Issue: %s
 Failure: %s
-Error: %s
-
+%s
 Please fix the issue and resubmit with 'gt done'.`,
 			payload.Branch,
 			payload.IssueID,
 			payload.FailureType,
-			payload.Error,
+			detail,
 		),
 	}
 
@@ -594,6 +613,9 @@ Verified: clean git state`,
 			payload.PolecatName,
 		),
 	)
+	if payload.TraceParent != "" {
+		msg.Body += "\nTrace: " + payload.TraceParent
+	}
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
 
@@ -58,6 +58,7 @@ type PolecatDonePayload struct {
 	MRID        string
 	Branch      string
 	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+	TraceParent string // gt done's span (W3C traceparent), if traced
 }
 
 // HelpPayload contains parsed data from a HELP message.
@@ -85,6 +86,7 @@ type MergeReadyPayload struct {
 	Branch      string
 	IssueID     string
 	MRID        string
+	TraceParent string // Witness's span (W3C traceparent), if traced
 	ReadyAt     time.Time
 }
 
@@ -96,6 +98,9 @@ type MergeFailedPayload struct {
 	FailureType string // "build", "test", "lint", etc.
 	Error       string
 	FailedAt    time.Time
+
+	// FailingTests lists failing tests parsed from gate reports, if any.
+	FailingTests []string
 }
 
 // SwarmStartPayload contains parsed data from a SWARM_START message.
@@ -139,6 +144,7 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//	Trace: <traceparent>
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -162,6 +168,8 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
 		} else if strings.HasPrefix(line, "Branch:") {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
+		} else if strings.HasPrefix(line, "Trace:") {
+			payload.TraceParent = strings.TrimSpace(strings.TrimPrefix(line, "Trace:"))
 		}
 	}
 
@@ -268,8 +276,15 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
 		case strings.HasPrefix(line, "FailureType:"):
 			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
+		case strings.HasPrefix(line, "Failure-Type:"):
+			// Format written by the refinery (protocol.NewMergeFailedMessage)
+			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "Failure-Type:"))
 		case strings.HasPrefix(line, "Error:"):
 			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
+		case strings.HasPrefix(line, "Failing-Tests:"):
+			if tests := strings.TrimSpace(strings.TrimPrefix(line, "Failing-Tests:")); tests != "" {
+				payload.FailingTests = strings.Split(tests, "; ")
+			}
 		}
 	}
 
@@ -284,6 +299,7 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 //	Issue: <issue-id>
 //	MR: <mr-id>
 //	Verified: clean git state
+//	Trace: <traceparent>
 func ParseMergeReady(subject, body string) (*MergeReadyPayload, error) {
 	matches := PatternMergeReady.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -305,6 +321,8 @@ func ParseMergeReady(subject, body string) (*MergeReadyPayload, error) {
 			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
 		case strings.HasPrefix(line, "MR:"):
 			payload.MRID = strings.TrimSpace(strings.TrimPrefix(line, "MR:"))
+		case strings.HasPrefix(line, "Trace:"):
+			payload.TraceParent = strings.TrimSpace(strings.TrimPrefix(line, "Trace:"))
 		}
 	}
 
@@ -1,6 +1,7 @@
 package witness
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -42,7 +43,8 @@ func TestParsePolecatDone(t *testing.T) {
 	body := `Exit: MERGED
 Issue: gt-abc123
 MR: gt-mr-xyz
-Branch: feature-branch`
+Branch: feature-branch
+Trace: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`
 
 	payload, err := ParsePolecatDone(subject, body)
 	if err != nil {
@@ -64,6 +66,9 @@ Branch: feature-branch`
 	if payload.Branch != "feature-branch" {
 		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
 	}
+	if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; payload.TraceParent != want {
+		t.Errorf("TraceParent = %q, want %q", payload.TraceParent, want)
+	}
 }
 
 func TestParsePolecatDone_MinimalBody(t *testing.T) {
@@ -193,6 +198,27 @@ Error: unit tests failed with 3 errors`
 	}
 }
 
+func TestParseMergeFailed_RefineryFormat(t *testing.T) {
+	subject := "MERGE_FAILED nux"
+	body := `Branch: polecat/nux/gt-abc
+Issue: gt-abc
+Failure-Type: tests
+Error: quality gates failed: unit: 2 failing test(s)
+Failing-Tests: unit: pkg.TestFoo; unit: pkg.TestBar`
+
+	payload, err := ParseMergeFailed(subject, body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+	if payload.FailureType != "tests" {
+		t.Errorf("FailureType = %q, want %q", payload.FailureType, "tests")
+	}
+	want := []string{"unit: pkg.TestFoo", "unit: pkg.TestBar"}
+	if strings.Join(payload.FailingTests, ",") != strings.Join(want, ",") {
+		t.Errorf("FailingTests = %v, want %v", payload.FailingTests, want)
+	}
+}
+
 func TestParseMergeFailed_MinimalBody(t *testing.T) {
 	subject := "MERGE_FAILED ace"
 	body := "FailureType: build"